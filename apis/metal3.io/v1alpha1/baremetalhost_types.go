@@ -16,6 +16,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"slices"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -61,6 +62,23 @@ const (
 	// is disabled.
 	HardwareDetailsAnnotation = InspectAnnotationPrefix + "/hardwaredetails"
 
+	// ProvisioningBackendAnnotation selects the provisioning backend
+	// managing the host when spec.provisioningBackend is not set.
+	ProvisioningBackendAnnotation = "baremetalhost.metal3.io/provisioning-backend"
+
+	// DefaultProvisioningBackend is the provisioning backend managing
+	// hosts that do not request one.
+	DefaultProvisioningBackend = "ironic"
+
+	// ServicingAnnotation allows pending changes of the firmware settings,
+	// firmware components and BIOS configuration to be applied to a
+	// provisioned host in place, rebooting it.
+	ServicingAnnotation = "baremetalhost.metal3.io/servicing"
+
+	// IronicEndpointLabel is the label assigning the host to one of the
+	// named Ironic endpoints the operator is configured with.
+	IronicEndpointLabel = "baremetalhost.metal3.io/ironic-endpoint"
+
 	// InspectAnnotationValueDisabled is a constant string="disabled"
 	// This is particularly useful to check if inspect annotation is disabled
 	// inspect.metal3.io=disabled.
@@ -147,10 +165,14 @@ const (
 	// OperationalStatusDetached is the status value when the host is
 	// marked unmanaged via the detached annotation.
 	OperationalStatusDetached OperationalStatus = "detached"
+
+	// OperationalStatusServicing is the status value when changes are
+	// being applied to the provisioned host.
+	OperationalStatusServicing OperationalStatus = "servicing"
 )
 
 // OperationalStatusAllowed represents the allowed values of OperationalStatus.
-var OperationalStatusAllowed = []string{"", string(OperationalStatusOK), string(OperationalStatusDiscovered), string(OperationalStatusError), string(OperationalStatusDelayed), string(OperationalStatusDetached)}
+var OperationalStatusAllowed = []string{"", string(OperationalStatusOK), string(OperationalStatusDiscovered), string(OperationalStatusError), string(OperationalStatusDelayed), string(OperationalStatusDetached), string(OperationalStatusServicing)}
 
 // ErrorType indicates the class of problem that has caused the Host resource
 // to enter an error state.
@@ -179,10 +201,52 @@ const (
 	// DetachError is an error condition occurring when the
 	// controller is unable to detatch the host from the provisioner.
 	DetachError ErrorType = "detach error"
+	// ServicingError is an error condition occurring when the controller
+	// fails to apply changes to a provisioned Host.
+	ServicingError ErrorType = "servicing error"
+	// RescueError is an error condition occurring when the controller
+	// fails to boot the Host into or out of the rescue ramdisk.
+	RescueError ErrorType = "rescue error"
 )
 
 // ErrorTypeAllowed represents the allowed values of ErrorType.
-var ErrorTypeAllowed = []string{"", string(ProvisionedRegistrationError), string(RegistrationError), string(InspectionError), string(PreparationError), string(ProvisioningError), string(PowerManagementError)}
+var ErrorTypeAllowed = []string{"", string(ProvisionedRegistrationError), string(RegistrationError), string(InspectionError), string(PreparationError), string(ProvisioningError), string(PowerManagementError), string(ServicingError), string(RescueError)}
+
+// HostConditionType is the type of a condition of a BareMetalHost.
+type HostConditionType string
+
+const (
+	// ConditionRegistered indicates that the host is registered with
+	// the provisioning backend.
+	ConditionRegistered HostConditionType = "Registered"
+
+	// ConditionBMCAccessible indicates that the BMC of the host could
+	// be reached with the credentials provided.
+	ConditionBMCAccessible HostConditionType = "BMCAccessible"
+
+	// ConditionInspected indicates that the hardware details of the
+	// host are known.
+	ConditionInspected HostConditionType = "Inspected"
+
+	// ConditionPrepared indicates that the RAID and firmware settings
+	// have been applied to the host for the current provisioning.
+	ConditionPrepared HostConditionType = "Prepared"
+
+	// ConditionProvisioned indicates that an image or custom deployment
+	// is on the host.
+	ConditionProvisioned HostConditionType = "Provisioned"
+
+	// ConditionPoweredOn indicates that the host is powered on.
+	ConditionPoweredOn HostConditionType = "PoweredOn"
+
+	// ConditionReady indicates that the host has settled in the
+	// available or provisioned state without error.
+	ConditionReady HostConditionType = "Ready"
+
+	// ConditionTainted indicates that a NoExecute taint applies to the
+	// provisioned host, so its consumer should move its workload away.
+	ConditionTainted HostConditionType = "Tainted"
+)
 
 // ProvisioningState defines the states the provisioner will report
 // the host has having.
@@ -224,6 +288,14 @@ const (
 	// image on the host.
 	StateExternallyProvisioned ProvisioningState = "externally provisioned"
 
+	// StateRescuing means we are booting the provisioned host into a
+	// rescue ramdisk.
+	StateRescuing ProvisioningState = "rescuing"
+
+	// StateRescued means the provisioned host is running a rescue
+	// ramdisk.
+	StateRescued ProvisioningState = "rescued"
+
 	// StateDeprovisioning means we are removing an image from the
 	// host's disk(s).
 	StateDeprovisioning ProvisioningState = "deprovisioning"
@@ -429,12 +501,18 @@ type BareMetalHostSpec struct {
 	ExternallyProvisioned bool `json:"externallyProvisioned,omitempty"`
 
 	// When set to disabled, automated cleaning will be avoided
-	// during provisioning and deprovisioning.
+	// during provisioning and deprovisioning. When set to full, all
+	// disks of the host are securely erased during deprovisioning.
 	// +optional
 	// +kubebuilder:default:=metadata
 	// +kubebuilder:validation:Optional
 	AutomatedCleaningMode AutomatedCleaningMode `json:"automatedCleaningMode,omitempty"`
 
+	// Disks that are not erased when AutomatedCleaningMode is full,
+	// selected in the same way as the root device.
+	// +optional
+	PreservedDisks []RootDeviceHints `json:"preservedDisks,omitempty"`
+
 	// A custom deploy procedure.
 	// +optional
 	CustomDeploy *CustomDeploy `json:"customDeploy,omitempty"`
@@ -442,16 +520,273 @@ type BareMetalHostSpec struct {
 	// CPU architecture of the host, e.g. "x86_64" or "aarch64". If unset, eventually populated by inspection.
 	// +optional
 	Architecture string `json:"architecture,omitempty"`
+
+	// Name of the provisioning backend managing the host, e.g. "ironic"
+	// or "redfish". If unset, the operator's default backend is used.
+	// Can only be changed while the host is registering or detached.
+	// +optional
+	ProvisioningBackend string `json:"provisioningBackend,omitempty"`
+
+	// Request to boot the provisioned host into a rescue ramdisk,
+	// keeping its disks intact. Removing it boots the host back into
+	// its image.
+	// +optional
+	Rescue *RescueConfig `json:"rescue,omitempty"`
+
+	// Request to boot the host from a specific device, either on the
+	// next boot only or persistently. Only applied while the host is
+	// available or provisioned.
+	// +optional
+	BootDevice *BootDeviceConfig `json:"bootDevice,omitempty"`
+
+	// Policy for powering the host off while it is available and idle.
+	// Defaults to the policy of its hardware profile.
+	// +optional
+	IdlePowerOff *IdlePowerOffPolicy `json:"idlePowerOff,omitempty"`
+
+	// The priority of the host when waiting for provisioning capacity.
+	// Hosts with a higher priority are inspected, provisioned or
+	// deprovisioned first.
+	// +optional
+	ProvisioningPriority int `json:"provisioningPriority,omitempty"`
+
+	// Policy for remediating the host automatically when it keeps
+	// failing. Defaults to the policy of its hardware profile.
+	// +optional
+	Remediation *RemediationPolicy `json:"remediation,omitempty"`
+}
+
+// BootDevice is a device a host can boot from.
+// +kubebuilder:validation:Enum=pxe;cdrom;disk;bios
+type BootDevice string
+
+// Allowed boot devices.
+const (
+	BootDevicePXE   BootDevice = "pxe"
+	BootDeviceCDROM BootDevice = "cdrom"
+	BootDeviceDisk  BootDevice = "disk"
+	BootDeviceBIOS  BootDevice = "bios"
+)
+
+// BootDeviceConfig holds the details of a boot device request.
+type BootDeviceConfig struct {
+	// The device to boot from.
+	Device BootDevice `json:"device"`
+
+	// Whether the host keeps booting from the device. By default, it
+	// is only used for the next boot.
+	// +optional
+	Persistent bool `json:"persistent,omitempty"`
+}
+
+// BootDeviceResult is the outcome of a boot device request.
+// +kubebuilder:validation:Enum=applied;failed
+type BootDeviceResult string
+
+const (
+	// BootDeviceApplied means the requested boot device was set.
+	BootDeviceApplied BootDeviceResult = "applied"
+	// BootDeviceFailed means the requested boot device could not be set.
+	BootDeviceFailed BootDeviceResult = "failed"
+)
+
+// BootDeviceStatus holds the result of the boot device requests.
+type BootDeviceStatus struct {
+	// The boot device request handled last.
+	// +optional
+	Requested *BootDeviceConfig `json:"requested,omitempty"`
+
+	// Result of the boot device request handled last.
+	// +optional
+	Result BootDeviceResult `json:"result,omitempty"`
+
+	// Error reported when the requested boot device could not be set.
+	// +optional
+	ErrorMessage string `json:"errorMessage,omitempty"`
+
+	// The boot device that was last set successfully.
+	// +optional
+	LastApplied *BootDeviceConfig `json:"lastApplied,omitempty"`
+
+	// Time the boot device was last set successfully.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+}
+
+// IdlePowerOffPolicy holds the conditions under which an available host
+// is powered off.
+type IdlePowerOffPolicy struct {
+	// How long the host must have been available before it is powered
+	// off.
+	After metav1.Duration `json:"after"`
+
+	// Periods during which idle hosts are kept powered on, e.g. so that
+	// they can be provisioned quickly during working hours.
+	// +optional
+	PowerOnWindows []PowerWindow `json:"powerOnWindows,omitempty"`
+}
+
+// Weekday is a day of the week.
+// +kubebuilder:validation:Enum=Sunday;Monday;Tuesday;Wednesday;Thursday;Friday;Saturday
+type Weekday string
+
+// PowerWindow is a weekly recurring period of time, in UTC.
+type PowerWindow struct {
+	// The days of the week on which the window starts. Every day when
+	// not set.
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// The time of the day at which the window starts, as HH:MM.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// The time of the day at which the window ends, as HH:MM. A window
+	// ending before it starts ends on the next day, and a window ending
+	// when it starts lasts 24 hours.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+}
+
+// minuteOfDay parses a time of the day in the HH:MM format.
+func minuteOfDay(value string) int {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0
+	}
+	return parsed.Hour()*60 + parsed.Minute()
+}
+
+// startsOn returns whether the window starts on the given day.
+func (w *PowerWindow) startsOn(day time.Weekday) bool {
+	return len(w.Days) == 0 || slices.Contains(w.Days, Weekday(day.String()))
+}
+
+// Contains returns whether the given time is within the window.
+func (w *PowerWindow) Contains(t time.Time) bool {
+	t = t.UTC()
+	start, end := minuteOfDay(w.Start), minuteOfDay(w.End)
+	minute := t.Hour()*60 + t.Minute()
+
+	if start < end {
+		return minute >= start && minute < end && w.startsOn(t.Weekday())
+	}
+	if minute >= start && w.startsOn(t.Weekday()) {
+		return true
+	}
+	// The window may have started on the previous day
+	return minute < end && w.startsOn(t.AddDate(0, 0, -1).Weekday())
+}
+
+// PowersOff returns whether a host that has been available since the
+// given time should be powered off at the given time.
+func (p *IdlePowerOffPolicy) PowersOff(idleSince, now time.Time) bool {
+	if p == nil || now.Sub(idleSince) < p.After.Duration {
+		return false
+	}
+	for i := range p.PowerOnWindows {
+		if p.PowerOnWindows[i].Contains(now) {
+			return false
+		}
+	}
+	return true
+}
+
+// RescueConfig holds the details of a rescue request.
+type RescueConfig struct {
+	// The name of the Secret in the namespace of the host holding the
+	// password of the rescue user under the key "password".
+	// +kubebuilder:validation:MinLength=1
+	PasswordSecretName string `json:"passwordSecretName"`
+}
+
+// RemediationAction is an action taken automatically on a failing host.
+// +kubebuilder:validation:Enum=PowerCycle;Reregister;Reinspect;Detach;Taint
+type RemediationAction string
+
+// Allowed remediation actions.
+const (
+	// RemediationPowerCycle reboots the host with a hard power cycle.
+	RemediationPowerCycle RemediationAction = "PowerCycle"
+
+	// RemediationReregister registers the host with the provisioner
+	// again, with its current BMC details.
+	RemediationReregister RemediationAction = "Reregister"
+
+	// RemediationReinspect inspects the host again, once it is
+	// available.
+	RemediationReinspect RemediationAction = "Reinspect"
+
+	// RemediationDetach sets the detached annotation of the host, so
+	// that it is no longer managed.
+	RemediationDetach RemediationAction = "Detach"
+
+	// RemediationTaint taints the host, so that it is no longer bound by
+	// claims.
+	RemediationTaint RemediationAction = "Taint"
+)
+
+// RemediationTaintKey is the key of the taint set by the Taint
+// remediation action.
+const RemediationTaintKey = "metal3.io/remediation"
+
+// RemediationRule maps the errors of a host to a remediation action.
+type RemediationRule struct {
+	// The type of error the rule applies to.
+	// +kubebuilder:validation:Enum=provisioned registration error;registration error;inspection error;preparation error;provisioning error;power management error;servicing error;rescue error
+	ErrorType ErrorType `json:"errorType"`
+
+	// The number of errors after which the action is taken.
+	// +kubebuilder:validation:Minimum=1
+	ErrorCount int `json:"errorCount"`
+
+	// The action to take.
+	Action RemediationAction `json:"action"`
+
+	// The effect of the taint set by the Taint action. Defaults to
+	// NoSchedule.
+	// +kubebuilder:validation:Enum=NoSchedule;PreferNoSchedule;NoExecute
+	// +optional
+	TaintEffect corev1.TaintEffect `json:"taintEffect,omitempty"`
+}
+
+// RemediationPolicy holds the actions taken automatically when a host
+// keeps failing.
+type RemediationPolicy struct {
+	// The remediation rules. Each rule is applied once each time the
+	// host keeps failing with its error type, when the error count of
+	// the host reaches the count of the rule.
+	// +kubebuilder:validation:MinItems=1
+	Rules []RemediationRule `json:"rules"`
+}
+
+// NextRule returns the rule to apply to a host failing with the given
+// error type and count, once the rules up to the remediated count have
+// been applied, or nil if there is none. When several rules are due, the
+// one with the highest count is applied.
+func (p *RemediationPolicy) NextRule(errorType ErrorType, errorCount, remediatedCount int) *RemediationRule {
+	var next *RemediationRule
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if rule.ErrorType != errorType || rule.ErrorCount > errorCount || rule.ErrorCount <= remediatedCount {
+			continue
+		}
+		if next == nil || rule.ErrorCount > next.ErrorCount {
+			next = rule
+		}
+	}
+	return next
 }
 
 // AutomatedCleaningMode is the interface to enable/disable automated cleaning
-// +kubebuilder:validation:Enum:=metadata;disabled
+// +kubebuilder:validation:Enum:=metadata;disabled;full
 type AutomatedCleaningMode string
 
 // Allowed automated cleaning modes.
 const (
 	CleaningModeDisabled AutomatedCleaningMode = "disabled"
 	CleaningModeMetadata AutomatedCleaningMode = "metadata"
+	CleaningModeFull     AutomatedCleaningMode = "full"
 )
 
 // ChecksumType holds the algorithm name for the checksum
@@ -644,6 +979,28 @@ type NIC struct {
 
 	// Whether the NIC is PXE Bootable
 	PXE bool `json:"pxe,omitempty"`
+
+	// The switch port the NIC is connected to, as reported by LLDP
+	LLDP *LLDP `json:"lldp,omitempty"`
+}
+
+// LLDP describes the neighbour of a NIC as discovered from the Link
+// Layer Discovery Protocol packets received on it.
+type LLDP struct {
+	// The chassis ID of the switch
+	SwitchID string `json:"switchID,omitempty"`
+
+	// The system name of the switch
+	SwitchSystemName string `json:"switchSystemName,omitempty"`
+
+	// The management addresses of the switch
+	SwitchManagementAddresses []string `json:"switchManagementAddresses,omitempty"`
+
+	// The ID of the switch port
+	PortID string `json:"portID,omitempty"`
+
+	// The description of the switch port
+	PortDescription string `json:"portDescription,omitempty"`
 }
 
 // Firmware describes the firmware on the host.
@@ -664,16 +1021,88 @@ type BIOS struct {
 	Version string `json:"version,omitempty"`
 }
 
+// PCIDevice describes a device on the PCI bus of the host.
+type PCIDevice struct {
+	// The PCI address of the device, e.g. "0000:3b:00.0"
+	Address string `json:"address,omitempty"`
+
+	// The vendor ID of the device, e.g. "10de"
+	VendorID string `json:"vendorID,omitempty"`
+
+	// The product ID of the device, e.g. "20b0"
+	ProductID string `json:"productID,omitempty"`
+
+	// The class code of the device, e.g. "030200"
+	Class string `json:"class,omitempty"`
+
+	// The revision of the device
+	Revision string `json:"revision,omitempty"`
+}
+
+// AcceleratorType is the kind of an accelerator.
+type AcceleratorType string
+
+const (
+	// GPU is a graphics processing unit
+	GPU AcceleratorType = "GPU"
+
+	// ProcessingAccelerator is any other device offloading computations
+	// from the CPUs, e.g. an FPGA or an AI accelerator
+	ProcessingAccelerator AcceleratorType = "ProcessingAccelerator"
+)
+
+// Accelerator describes a PCI device offloading computations from the
+// CPUs.
+type Accelerator struct {
+	// The kind of accelerator
+	Type AcceleratorType `json:"type,omitempty"`
+
+	// The vendor and product IDs of the accelerator, e.g. "0x10de 0x20b0"
+	Model string `json:"model,omitempty"`
+
+	// The PCI address of the accelerator
+	PCIAddress string `json:"pciAddress,omitempty"`
+}
+
+// MemoryModule describes a memory slot of the host and the module
+// installed in it, if any.
+type MemoryModule struct {
+	// The name of the slot, e.g. "DIMM_A1"
+	Slot string `json:"slot,omitempty"`
+
+	// The size of the module in Mebibytes, zero for an empty slot
+	SizeMebibytes int `json:"sizeMebibytes,omitempty"`
+
+	// The description of the module, e.g. "DIMM DDR4 Synchronous 2933 MHz"
+	Description string `json:"description,omitempty"`
+
+	// The clock speed of the module
+	ClockMegahertz ClockSpeed `json:"clockMegahertz,omitempty"`
+
+	// The vendor of the module
+	Vendor string `json:"vendor,omitempty"`
+
+	// The part number of the module
+	PartNumber string `json:"partNumber,omitempty"`
+
+	// The serial number of the module
+	SerialNumber string `json:"serialNumber,omitempty"`
+}
+
 // HardwareDetails collects all of the information about hardware
 // discovered on the host.
 type HardwareDetails struct {
-	SystemVendor HardwareSystemVendor `json:"systemVendor,omitempty"`
-	Firmware     Firmware             `json:"firmware,omitempty"`
-	RAMMebibytes int                  `json:"ramMebibytes,omitempty"`
-	NIC          []NIC                `json:"nics,omitempty"`
-	Storage      []Storage            `json:"storage,omitempty"`
-	CPU          CPU                  `json:"cpu,omitempty"`
-	Hostname     string               `json:"hostname,omitempty"`
+	SystemVendor  HardwareSystemVendor `json:"systemVendor,omitempty"`
+	SystemUUID    string               `json:"systemUUID,omitempty"`
+	Firmware      Firmware             `json:"firmware,omitempty"`
+	RAMMebibytes  int                  `json:"ramMebibytes,omitempty"`
+	MemoryModules []MemoryModule       `json:"memoryModules,omitempty"`
+	NIC           []NIC                `json:"nics,omitempty"`
+	Storage       []Storage            `json:"storage,omitempty"`
+	CPU           CPU                  `json:"cpu,omitempty"`
+	PCIDevices    []PCIDevice          `json:"pciDevices,omitempty"`
+	Accelerators  []Accelerator        `json:"accelerators,omitempty"`
+	Hostname      string               `json:"hostname,omitempty"`
 }
 
 // HardwareSystemVendor stores details about the whole hardware system.
@@ -752,6 +1181,57 @@ func (om OperationMetric) Duration() time.Duration {
 	return om.End.Time.Sub(om.Start.Time)
 }
 
+// DiskErasureResult is the outcome of the erasure of the disks.
+// +kubebuilder:validation:Enum=erased;failed
+type DiskErasureResult string
+
+const (
+	// DiskErased means the disks were securely erased.
+	DiskErased DiskErasureResult = "erased"
+	// DiskErasureFailed means the erasure did not finish.
+	DiskErasureFailed DiskErasureResult = "failed"
+)
+
+// DiskErasure holds information about the secure erasure of the disks
+// of the host during deprovisioning.
+type DiskErasure struct {
+	// Time the erasure was started.
+	Started metav1.Time `json:"started"`
+
+	// Time the erasure finished or failed.
+	// +optional
+	Finished *metav1.Time `json:"finished,omitempty"`
+
+	// Error reported when the erasure failed.
+	// +optional
+	ErrorMessage string `json:"errorMessage,omitempty"`
+
+	// Result of the erasure of all the disks together, recorded once it
+	// has finished. There is no result per disk.
+	// +optional
+	Result DiskErasureResult `json:"result,omitempty"`
+
+	// Time spent erasing all the disks together, recorded once the
+	// erasure has finished. There is no duration per disk.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Names of the disks, as reported in the hardware details, that the
+	// erasure applied to.
+	// +optional
+	Disks []string `json:"disks,omitempty"`
+
+	// Names of the disks left intact because they matched one of the
+	// preserved disks.
+	// +optional
+	PreservedDisks []string `json:"preservedDisks,omitempty"`
+}
+
+// Failed returns whether the erasure has failed.
+func (de *DiskErasure) Failed() bool {
+	return de.ErrorMessage != ""
+}
+
 // OperationHistory holds information about operations performed on a
 // host.
 type OperationHistory struct {
@@ -761,18 +1241,131 @@ type OperationHistory struct {
 	Deprovision OperationMetric `json:"deprovision,omitempty"`
 }
 
+// MaxStateHistory is the number of state transitions kept in the state
+// history of a host.
+const MaxStateHistory = 20
+
+// StateTransitionTrigger describes what caused a state transition.
+type StateTransitionTrigger string
+
+const (
+	// TriggerCompleted means that the work of the old state finished.
+	TriggerCompleted StateTransitionTrigger = "Completed"
+
+	// TriggerDeletion means that the host is being deleted.
+	TriggerDeletion StateTransitionTrigger = "Deletion"
+
+	// TriggerError means that the work of the old state failed.
+	TriggerError StateTransitionTrigger = "Error"
+
+	// TriggerInspectionRequested means that the inspect annotation was
+	// added.
+	TriggerInspectionRequested StateTransitionTrigger = "InspectionRequested"
+
+	// TriggerConfigurationChanged means that the configuration of the
+	// host, e.g. its RAID or firmware settings, was changed.
+	TriggerConfigurationChanged StateTransitionTrigger = "ConfigurationChanged"
+
+	// TriggerExternallyProvisioned means that the externallyProvisioned
+	// field was changed.
+	TriggerExternallyProvisioned StateTransitionTrigger = "ExternallyProvisioned"
+
+	// TriggerProvisioningRequested means that an image or a custom
+	// deploy was set.
+	TriggerProvisioningRequested StateTransitionTrigger = "ProvisioningRequested"
+
+	// TriggerProvisioningCancelled means that the image or the custom
+	// deploy was removed or changed.
+	TriggerProvisioningCancelled StateTransitionTrigger = "ProvisioningCancelled"
+
+	// TriggerRescueRequested means that the rescue field was set.
+	TriggerRescueRequested StateTransitionTrigger = "RescueRequested"
+)
+
+// StateTransition records a change of the provisioning state of a host.
+type StateTransition struct {
+	// The time of the transition.
+	Time metav1.Time `json:"time"`
+
+	// The state the host left.
+	OldState ProvisioningState `json:"oldState"`
+
+	// The state the host entered.
+	NewState ProvisioningState `json:"newState"`
+
+	// What caused the transition.
+	Trigger StateTransitionTrigger `json:"trigger"`
+
+	// The error of the host at the time of the transition, if any.
+	// +optional
+	ErrorType ErrorType `json:"errorType,omitempty"`
+
+	// The error message of the host at the time of the transition.
+	// +optional
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+// MaxRemediationAttempts is the number of remediation attempts kept in
+// the status of a host.
+const MaxRemediationAttempts = 10
+
+// RemediationAttempt records an automatic remediation of a host.
+type RemediationAttempt struct {
+	// The time of the attempt.
+	Time metav1.Time `json:"time"`
+
+	// The error type of the host.
+	ErrorType ErrorType `json:"errorType"`
+
+	// The error count of the host.
+	ErrorCount int `json:"errorCount"`
+
+	// The action taken.
+	Action RemediationAction `json:"action"`
+
+	// Why the action could not be taken, if it failed.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// RemediationStatus holds the automatic remediation of a host.
+type RemediationStatus struct {
+	// The error count of the host when it was last remediated, while it
+	// keeps failing. The rules up to this count have been applied.
+	// +optional
+	ErrorCount int `json:"errorCount,omitempty"`
+
+	// The last remediation attempts, the oldest first.
+	// +optional
+	Attempts []RemediationAttempt `json:"attempts,omitempty"`
+}
+
+// ProvisioningQueueStatus holds the place of a host in the queue of hosts
+// waiting for provisioning capacity.
+type ProvisioningQueueStatus struct {
+	// The position of the host in the queue, starting at 1, when it
+	// last checked for capacity.
+	Position int `json:"position"`
+
+	// When the host started waiting.
+	Since metav1.Time `json:"since"`
+
+	// The bucket the host is waiting for capacity in.
+	Bucket string `json:"bucket"`
+}
+
 // BareMetalHostStatus defines the observed state of BareMetalHost.
 type BareMetalHostStatus struct {
 	// Important: Run "make generate manifests" to regenerate code
 	// after modifying this file
 
 	// OperationalStatus holds the status of the host
-	// +kubebuilder:validation:Enum="";OK;discovered;error;delayed;detached
+	// +kubebuilder:validation:Enum="";OK;discovered;error;delayed;detached;servicing
 	OperationalStatus OperationalStatus `json:"operationalStatus"`
 
 	// ErrorType indicates the type of failure encountered when the
 	// OperationalStatus is OperationalStatusError
-	// +kubebuilder:validation:Enum=provisioned registration error;registration error;inspection error;preparation error;provisioning error;power management error
+	// +kubebuilder:validation:Enum=provisioned registration error;registration error;inspection error;preparation error;provisioning error;power management error;servicing error;rescue error
 	ErrorType ErrorType `json:"errorType,omitempty"`
 
 	// LastUpdated identifies when this status was last observed.
@@ -804,9 +1397,53 @@ type BareMetalHostStatus struct {
 	// on this host.
 	OperationHistory OperationHistory `json:"operationHistory,omitempty"`
 
+	// StateHistory holds the last state transitions of the host, the
+	// oldest first.
+	// +optional
+	StateHistory []StateTransition `json:"stateHistory,omitempty"`
+
+	// DiskErasure holds the results of the last secure erasure of the
+	// disks of the host, when AutomatedCleaningMode is full. Ironic erases
+	// all disks in a single step, so the result and duration cover all
+	// the erased disks together and are not available per disk.
+	// +optional
+	DiskErasure *DiskErasure `json:"diskErasure,omitempty"`
+
+	// BootDevice holds the results of the boot device requests of the
+	// host.
+	// +optional
+	BootDevice *BootDeviceStatus `json:"bootDevice,omitempty"`
+
+	// Remediation holds the automatic remediation of the host, if any.
+	// +optional
+	Remediation *RemediationStatus `json:"remediation,omitempty"`
+
+	// ProvisioningQueue holds the place of the host in the queue of
+	// hosts waiting for provisioning capacity, while it is delayed.
+	// +optional
+	ProvisioningQueue *ProvisioningQueueStatus `json:"provisioningQueue,omitempty"`
+
+	// IdleSince is the time at which the host became available.
+	// +optional
+	IdleSince *metav1.Time `json:"idleSince,omitempty"`
+
+	// IdlePoweredOffSince is the time at which the host was powered off
+	// by its idle power-off policy, while it is kept powered off.
+	// +optional
+	IdlePoweredOffSince *metav1.Time `json:"idlePoweredOffSince,omitempty"`
+
 	// ErrorCount records how many times the host has encoutered an error since the last successful operation
 	// +kubebuilder:default:=0
 	ErrorCount int `json:"errorCount"`
+
+	// Conditions summarise the status of the host, following the
+	// standard semantics of Kubernetes conditions.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
 }
 
 // ProvisionStatus holds the state information for a single target.
@@ -835,6 +1472,10 @@ type ProvisionStatus struct {
 
 	// Custom deploy procedure applied to the host.
 	CustomDeploy *CustomDeploy `json:"customDeploy,omitempty"`
+
+	// The name of the provisioner endpoint the host is assigned to,
+	// when the provisioner is configured with more than one.
+	Endpoint string `json:"endpoint,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -870,6 +1511,16 @@ func (host *BareMetalHost) BootMode() BootMode {
 	return mode
 }
 
+// ProvisioningBackend returns the name of the provisioning backend
+// requested for the host, either in the spec or through the
+// provisioning-backend annotation. An empty string means the default.
+func (host *BareMetalHost) ProvisioningBackend() string {
+	if host.Spec.ProvisioningBackend != "" {
+		return host.Spec.ProvisioningBackend
+	}
+	return host.Annotations[ProvisioningBackendAnnotation]
+}
+
 // HasBMCDetails returns true if the BMC details are set.
 func (host *BareMetalHost) HasBMCDetails() bool {
 	return host.Spec.BMC.Address != "" || host.Spec.BMC.CredentialsName != ""
@@ -1045,6 +1696,29 @@ func (host *BareMetalHost) NewEvent(reason, message string) corev1.Event {
 	}
 }
 
+// UntoleratedTaints returns the taints of the host with one of the
+// given effects that none of the tolerations tolerates.
+func (host *BareMetalHost) UntoleratedTaints(tolerations []corev1.Toleration, effects ...corev1.TaintEffect) []corev1.Taint {
+	var taints []corev1.Taint
+	for i := range host.Spec.Taints {
+		taint := &host.Spec.Taints[i]
+		if !slices.Contains(effects, taint.Effect) {
+			continue
+		}
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			taints = append(taints, *taint)
+		}
+	}
+	return taints
+}
+
 // OperationMetricForState returns a pointer to the metric for the given
 // provisioning state.
 func (host *BareMetalHost) OperationMetricForState(operation ProvisioningState) (metric *OperationMetric) {
@@ -1062,6 +1736,31 @@ func (host *BareMetalHost) OperationMetricForState(operation ProvisioningState)
 	return
 }
 
+// RecordStateTransition adds a transition to the state history of the
+// host, dropping the oldest transitions beyond MaxStateHistory.
+func (host *BareMetalHost) RecordStateTransition(transition StateTransition) {
+	history := append(host.Status.StateHistory, transition)
+	if len(history) > MaxStateHistory {
+		history = slices.Clone(history[len(history)-MaxStateHistory:])
+	}
+	host.Status.StateHistory = history
+}
+
+// RecordRemediationAttempt appends an attempt to the remediation status
+// of the host, dropping the oldest attempts beyond
+// MaxRemediationAttempts.
+func (host *BareMetalHost) RecordRemediationAttempt(attempt RemediationAttempt) {
+	if host.Status.Remediation == nil {
+		host.Status.Remediation = &RemediationStatus{}
+	}
+	attempts := append(host.Status.Remediation.Attempts, attempt)
+	if len(attempts) > MaxRemediationAttempts {
+		attempts = slices.Clone(attempts[len(attempts)-MaxRemediationAttempts:])
+	}
+	host.Status.Remediation.Attempts = attempts
+	host.Status.Remediation.ErrorCount = attempt.ErrorCount
+}
+
 // GetChecksum method returns the checksum of an image.
 func (image *Image) GetChecksum() (checksum, checksumType string, ok bool) {
 	if image == nil {
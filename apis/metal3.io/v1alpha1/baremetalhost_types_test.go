@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -665,3 +666,248 @@ func TestHasBMCDetails(t *testing.T) {
 		})
 	}
 }
+
+func TestUntoleratedTaints(t *testing.T) {
+	host := BareMetalHost{
+		Spec: BareMetalHostSpec{
+			Taints: []corev1.Taint{
+				{Key: "suspect", Value: "dimm", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "suspect", Value: "dimm", Effect: corev1.TaintEffectNoExecute},
+				{Key: "old", Effect: corev1.TaintEffectPreferNoSchedule},
+			},
+		},
+	}
+
+	testCases := []struct {
+		Scenario    string
+		Tolerations []corev1.Toleration
+		Effects     []corev1.TaintEffect
+		Expected    []string
+	}{
+		{
+			Scenario: "no tolerations",
+			Effects:  []corev1.TaintEffect{corev1.TaintEffectNoSchedule, corev1.TaintEffectNoExecute},
+			Expected: []string{"suspect=dimm:NoSchedule", "suspect=dimm:NoExecute"},
+		},
+		{
+			Scenario: "other effect",
+			Effects:  []corev1.TaintEffect{corev1.TaintEffectPreferNoSchedule},
+			Expected: []string{"old:PreferNoSchedule"},
+		},
+		{
+			Scenario: "tolerated key",
+			Tolerations: []corev1.Toleration{
+				{Key: "suspect", Operator: corev1.TolerationOpExists},
+			},
+			Effects: []corev1.TaintEffect{corev1.TaintEffectNoSchedule, corev1.TaintEffectNoExecute},
+		},
+		{
+			Scenario: "tolerated effect",
+			Tolerations: []corev1.Toleration{
+				{Key: "suspect", Operator: corev1.TolerationOpEqual, Value: "dimm", Effect: corev1.TaintEffectNoSchedule},
+			},
+			Effects:  []corev1.TaintEffect{corev1.TaintEffectNoSchedule, corev1.TaintEffectNoExecute},
+			Expected: []string{"suspect=dimm:NoExecute"},
+		},
+		{
+			Scenario: "other value",
+			Tolerations: []corev1.Toleration{
+				{Key: "suspect", Operator: corev1.TolerationOpEqual, Value: "cpu"},
+			},
+			Effects:  []corev1.TaintEffect{corev1.TaintEffectNoExecute},
+			Expected: []string{"suspect=dimm:NoExecute"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			var actual []string
+			for _, taint := range host.UntoleratedTaints(tc.Tolerations, tc.Effects...) {
+				actual = append(actual, taint.ToString())
+			}
+			assert.Equal(t, tc.Expected, actual)
+		})
+	}
+}
+
+func TestPowerWindowContains(t *testing.T) {
+	// 2024-01-01 is a Monday
+	monday := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		Scenario string
+		Window   PowerWindow
+		Time     time.Time
+		Expected bool
+	}{
+		{
+			Scenario: "every day",
+			Window:   PowerWindow{Start: "08:00", End: "18:00"},
+			Time:     monday(8, 0),
+			Expected: true,
+		},
+		{
+			Scenario: "end excluded",
+			Window:   PowerWindow{Start: "08:00", End: "18:00"},
+			Time:     monday(18, 0),
+			Expected: false,
+		},
+		{
+			Scenario: "other day",
+			Window:   PowerWindow{Days: []Weekday{"Tuesday"}, Start: "08:00", End: "18:00"},
+			Time:     monday(12, 0),
+			Expected: false,
+		},
+		{
+			Scenario: "other timezone",
+			Window:   PowerWindow{Days: []Weekday{"Monday"}, Start: "08:00", End: "18:00"},
+			Time:     monday(12, 0).In(time.FixedZone("UTC+10", 10*3600)),
+			Expected: true,
+		},
+		{
+			Scenario: "overnight before midnight",
+			Window:   PowerWindow{Days: []Weekday{"Monday"}, Start: "22:00", End: "06:00"},
+			Time:     monday(23, 0),
+			Expected: true,
+		},
+		{
+			Scenario: "overnight after midnight",
+			Window:   PowerWindow{Days: []Weekday{"Sunday"}, Start: "22:00", End: "06:00"},
+			Time:     monday(5, 59),
+			Expected: true,
+		},
+		{
+			Scenario: "overnight started on other day",
+			Window:   PowerWindow{Days: []Weekday{"Monday"}, Start: "22:00", End: "06:00"},
+			Time:     monday(5, 0),
+			Expected: false,
+		},
+		{
+			Scenario: "24 hours",
+			Window:   PowerWindow{Days: []Weekday{"Sunday"}, Start: "12:00", End: "12:00"},
+			Time:     monday(11, 0),
+			Expected: true,
+		},
+		{
+			Scenario: "24 hours not started",
+			Window:   PowerWindow{Days: []Weekday{"Monday"}, Start: "12:00", End: "12:00"},
+			Time:     monday(11, 0),
+			Expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, tc.Window.Contains(tc.Time))
+		})
+	}
+}
+
+func TestIdlePowerOffPolicyPowersOff(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	policy := &IdlePowerOffPolicy{After: metav1.Duration{Duration: time.Hour}}
+
+	assert.False(t, (*IdlePowerOffPolicy)(nil).PowersOff(now.Add(-2*time.Hour), now))
+	assert.False(t, policy.PowersOff(now.Add(-time.Minute), now))
+	assert.True(t, policy.PowersOff(now.Add(-2*time.Hour), now))
+
+	policy.PowerOnWindows = []PowerWindow{{Start: "20:00", End: "21:00"}, {Start: "08:00", End: "18:00"}}
+	assert.False(t, policy.PowersOff(now.Add(-2*time.Hour), now))
+}
+
+func TestRecordStateTransition(t *testing.T) {
+	host := BareMetalHost{}
+	for i := 0; i < MaxStateHistory+5; i++ {
+		host.RecordStateTransition(StateTransition{
+			Time:     metav1.Unix(int64(i), 0),
+			OldState: StateProvisioned,
+			NewState: StateDeprovisioning,
+			Trigger:  TriggerProvisioningCancelled,
+		})
+	}
+
+	assert.Len(t, host.Status.StateHistory, MaxStateHistory)
+	assert.Equal(t, metav1.Unix(5, 0), host.Status.StateHistory[0].Time)
+	assert.Equal(t, metav1.Unix(MaxStateHistory+4, 0), host.Status.StateHistory[MaxStateHistory-1].Time)
+}
+
+func TestRemediationPolicyNextRule(t *testing.T) {
+	policy := RemediationPolicy{
+		Rules: []RemediationRule{
+			{ErrorType: RegistrationError, ErrorCount: 2, Action: RemediationReregister},
+			{ErrorType: RegistrationError, ErrorCount: 5, Action: RemediationDetach},
+			{ErrorType: PowerManagementError, ErrorCount: 1, Action: RemediationPowerCycle},
+		},
+	}
+
+	for _, tc := range []struct {
+		Scenario        string
+		ErrorType       ErrorType
+		ErrorCount      int
+		RemediatedCount int
+		Expected        RemediationAction
+	}{
+		{
+			Scenario:   "below threshold",
+			ErrorType:  RegistrationError,
+			ErrorCount: 1,
+		},
+		{
+			Scenario:   "threshold reached",
+			ErrorType:  RegistrationError,
+			ErrorCount: 2,
+			Expected:   RemediationReregister,
+		},
+		{
+			Scenario:        "already applied",
+			ErrorType:       RegistrationError,
+			ErrorCount:      4,
+			RemediatedCount: 2,
+		},
+		{
+			Scenario:        "escalation",
+			ErrorType:       RegistrationError,
+			ErrorCount:      5,
+			RemediatedCount: 2,
+			Expected:        RemediationDetach,
+		},
+		{
+			Scenario:   "several rules due",
+			ErrorType:  RegistrationError,
+			ErrorCount: 6,
+			Expected:   RemediationDetach,
+		},
+		{
+			Scenario:   "other error type",
+			ErrorType:  InspectionError,
+			ErrorCount: 6,
+		},
+	} {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			rule := policy.NextRule(tc.ErrorType, tc.ErrorCount, tc.RemediatedCount)
+			if tc.Expected == "" {
+				assert.Nil(t, rule)
+			} else if assert.NotNil(t, rule) {
+				assert.Equal(t, tc.Expected, rule.Action)
+			}
+		})
+	}
+}
+
+func TestRecordRemediationAttempt(t *testing.T) {
+	host := BareMetalHost{}
+	for i := 1; i <= MaxRemediationAttempts+3; i++ {
+		host.RecordRemediationAttempt(RemediationAttempt{
+			Time:       metav1.Unix(int64(i), 0),
+			ErrorType:  PowerManagementError,
+			ErrorCount: i,
+			Action:     RemediationPowerCycle,
+		})
+	}
+
+	assert.Len(t, host.Status.Remediation.Attempts, MaxRemediationAttempts)
+	assert.Equal(t, 4, host.Status.Remediation.Attempts[0].ErrorCount)
+	assert.Equal(t, MaxRemediationAttempts+3, host.Status.Remediation.ErrorCount)
+}
@@ -52,6 +52,12 @@ func (host *BareMetalHost) validateHost() []error {
 		errs = append(errs, err)
 	}
 
+	for i := range host.Spec.PreservedDisks {
+		if err := validateRootDeviceHints(&host.Spec.PreservedDisks[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	if host.Spec.Image != nil {
 		if err := validateImageURL(host.Spec.Image.URL); err != nil {
 			errs = append(errs, err)
@@ -85,9 +91,28 @@ func (host *BareMetalHost) validateChanges(old *BareMetalHost) []error {
 		errs = append(errs, errors.New("bootMACAddress can not be changed once it is set"))
 	}
 
+	if !sameProvisioningBackend(host.ProvisioningBackend(), old.ProvisioningBackend()) &&
+		host.Status.OperationalStatus != OperationalStatusDetached &&
+		host.Status.Provisioning.State != StateNone &&
+		host.Status.Provisioning.State != StateRegistering {
+		errs = append(errs, errors.New("provisioning backend can not be changed if the BMH is not in the Registering state, or if the BMH is not detached"))
+	}
+
 	return errs
 }
 
+// sameProvisioningBackend returns whether two requested provisioning
+// backends are the same, an empty name meaning the default backend.
+func sameProvisioningBackend(a, b string) bool {
+	if a == "" {
+		a = DefaultProvisioningBackend
+	}
+	if b == "" {
+		b = DefaultProvisioningBackend
+	}
+	return a == b
+}
+
 func validateBMCAccess(s BareMetalHostSpec, bmcAccess bmc.AccessDetails) []error {
 	var errs []error
 
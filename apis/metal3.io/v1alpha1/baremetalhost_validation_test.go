@@ -494,6 +494,22 @@ func TestValidateCreate(t *testing.T) {
 			oldBMH:    nil,
 			wantedErr: "device name of root device hint must be a /dev/ path, not \"sda\"",
 		},
+		{
+			name: "invalidPreservedDiskNoPath",
+			newBMH: &BareMetalHost{
+				TypeMeta:   tm,
+				ObjectMeta: om,
+				Spec: BareMetalHostSpec{
+					AutomatedCleaningMode: CleaningModeFull,
+					PreservedDisks: []RootDeviceHints{
+						{SerialNumber: "S3EVNX0K"},
+						{DeviceName: "sdb"},
+					},
+				},
+			},
+			oldBMH:    nil,
+			wantedErr: "device name of root device hint must be a /dev/ path, not \"sdb\"",
+		},
 		{
 			name: "invalidImageURL",
 			newBMH: &BareMetalHost{
@@ -767,6 +783,58 @@ func TestValidateUpdate(t *testing.T) {
 				TypeMeta: tm, ObjectMeta: om, Spec: BareMetalHostSpec{BootMACAddress: "test-mac"}},
 			wantedErr: "bootMACAddress can not be changed once it is set",
 		},
+		{
+			name: "updateBackendBMHProvisioned",
+			newBMH: &BareMetalHost{
+				TypeMeta: tm, ObjectMeta: om, Spec: BareMetalHostSpec{ProvisioningBackend: "redfish"},
+				Status: BareMetalHostStatus{Provisioning: ProvisionStatus{State: StateProvisioned}}},
+			oldBMH: &BareMetalHost{
+				TypeMeta: tm, ObjectMeta: om, Spec: BareMetalHostSpec{}},
+			wantedErr: "provisioning backend can not be changed if the BMH is not in the Registering state, or if the BMH is not detached",
+		},
+		{
+			name: "updateBackendAnnotationBMHProvisioned",
+			newBMH: &BareMetalHost{
+				TypeMeta: tm,
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test",
+					Namespace:   "test-namespace",
+					Annotations: map[string]string{ProvisioningBackendAnnotation: "redfish"},
+				},
+				Status: BareMetalHostStatus{Provisioning: ProvisionStatus{State: StateProvisioned}}},
+			oldBMH: &BareMetalHost{
+				TypeMeta: tm, ObjectMeta: om, Spec: BareMetalHostSpec{}},
+			wantedErr: "provisioning backend can not be changed if the BMH is not in the Registering state, or if the BMH is not detached",
+		},
+		{
+			name: "updateBackendToDefaultBMHProvisioned",
+			newBMH: &BareMetalHost{
+				TypeMeta: tm, ObjectMeta: om, Spec: BareMetalHostSpec{ProvisioningBackend: DefaultProvisioningBackend},
+				Status: BareMetalHostStatus{Provisioning: ProvisionStatus{State: StateProvisioned}}},
+			oldBMH: &BareMetalHost{
+				TypeMeta: tm, ObjectMeta: om, Spec: BareMetalHostSpec{}},
+			wantedErr: "",
+		},
+		{
+			name: "updateBackendBMHRegistering",
+			newBMH: &BareMetalHost{
+				TypeMeta: tm, ObjectMeta: om, Spec: BareMetalHostSpec{ProvisioningBackend: "redfish"},
+				Status: BareMetalHostStatus{Provisioning: ProvisionStatus{State: StateRegistering}}},
+			oldBMH: &BareMetalHost{
+				TypeMeta: tm, ObjectMeta: om, Spec: BareMetalHostSpec{}},
+			wantedErr: "",
+		},
+		{
+			name: "updateBackendBMHDetached",
+			newBMH: &BareMetalHost{
+				TypeMeta: tm, ObjectMeta: om, Spec: BareMetalHostSpec{ProvisioningBackend: "redfish"},
+				Status: BareMetalHostStatus{
+					OperationalStatus: OperationalStatusDetached,
+					Provisioning:      ProvisionStatus{State: StateProvisioned}}},
+			oldBMH: &BareMetalHost{
+				TypeMeta: tm, ObjectMeta: om, Spec: BareMetalHostSpec{}},
+			wantedErr: "",
+		},
 	}
 
 	for _, tt := range tests {
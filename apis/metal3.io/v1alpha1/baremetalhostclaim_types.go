@@ -0,0 +1,213 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// BareMetalHostClaimFinalizer is the name of the finalizer added to
+	// claims to release the bound host before the claim is removed.
+	BareMetalHostClaimFinalizer string = "baremetalhostclaim.metal3.io"
+)
+
+// ClaimPhase describes the binding of a claim to a host.
+type ClaimPhase string
+
+const (
+	// ClaimPending means that no host satisfying the claim is
+	// available yet.
+	ClaimPending ClaimPhase = "Pending"
+
+	// ClaimBound means that a host has been bound to the claim.
+	ClaimBound ClaimPhase = "Bound"
+
+	// ClaimLost means that the bound host was deleted or taken over by
+	// another consumer.
+	ClaimLost ClaimPhase = "Lost"
+)
+
+// ClaimConditionType is the type of a condition of a claim.
+type ClaimConditionType string
+
+const (
+	// ClaimConditionHostTainted indicates that the bound host carries
+	// a NoExecute taint the claim does not tolerate.
+	ClaimConditionHostTainted ClaimConditionType = "HostTainted"
+)
+
+// DiskRequirements are the requirements on at least one disk of a host.
+type DiskRequirements struct {
+	// The minimum size of the disk in Gigabytes.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinSizeGigabytes int `json:"minSizeGigabytes,omitempty"`
+
+	// The type of the disk.
+	// +kubebuilder:validation:Enum=HDD;SSD;NVME
+	// +optional
+	Type DiskType `json:"type,omitempty"`
+}
+
+// HardwareRequirements are matched against the hardware details
+// discovered on a host.
+type HardwareRequirements struct {
+	// The minimum number of CPUs.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinCPUCount int `json:"minCPUCount,omitempty"`
+
+	// The minimum amount of memory in Mebibytes.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinRAMMebibytes int `json:"minRAMMebibytes,omitempty"`
+
+	// Requirements on at least one disk of the host.
+	// +optional
+	Disk *DiskRequirements `json:"disk,omitempty"`
+
+	// The minimum speed in Gbps of at least one NIC of the host.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinNICSpeedGbps int `json:"minNICSpeedGbps,omitempty"`
+}
+
+// Matches returns whether the hardware details satisfy the requirements.
+// Hosts without hardware details only match empty requirements.
+func (req *HardwareRequirements) Matches(details *HardwareDetails) bool {
+	if *req == (HardwareRequirements{}) {
+		return true
+	}
+	if details == nil {
+		return false
+	}
+
+	if details.CPU.Count < req.MinCPUCount || details.RAMMebibytes < req.MinRAMMebibytes {
+		return false
+	}
+
+	if req.Disk != nil {
+		found := false
+		for _, disk := range details.Storage {
+			if disk.SizeBytes < Capacity(req.Disk.MinSizeGigabytes)*GigaByte {
+				continue
+			}
+			if req.Disk.Type != "" && disk.Type != req.Disk.Type {
+				continue
+			}
+			found = true
+			break
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if req.MinNICSpeedGbps > 0 {
+		found := false
+		for _, nic := range details.NIC {
+			if nic.SpeedGbps >= req.MinNICSpeedGbps {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// BareMetalHostClaimSpec defines the host requested by a claim.
+type BareMetalHostClaimSpec struct {
+	// A label selector the claimed host must match. An empty selector
+	// matches all hosts in the namespace.
+	// +optional
+	HostSelector *metav1.LabelSelector `json:"hostSelector,omitempty"`
+
+	// Hardware requirements the claimed host must satisfy.
+	// +optional
+	Hardware HardwareRequirements `json:"hardware,omitempty"`
+
+	// Tolerations of the taints of the hosts. Hosts with a NoSchedule
+	// or NoExecute taint that is not tolerated are not bound, and hosts
+	// with a PreferNoSchedule taint that is not tolerated are only
+	// bound when no other host matches.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// BareMetalHostClaimStatus defines the observed binding of a claim.
+type BareMetalHostClaimStatus struct {
+	// The phase of the binding.
+	// +optional
+	Phase ClaimPhase `json:"phase,omitempty"`
+
+	// The name of the host bound to the claim, in the namespace of the
+	// claim.
+	// +optional
+	HostName string `json:"hostName,omitempty"`
+
+	// Details of why the claim is not bound, if any.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The time at which the claim was bound.
+	// +optional
+	BoundTime *metav1.Time `json:"boundTime,omitempty"`
+
+	// Conditions describing the bound host.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// BareMetalHostClaim requests the binding of one available host
+// matching a label selector and hardware requirements.
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName=bmhc
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Binding phase of the claim"
+// +kubebuilder:printcolumn:name="Host",type="string",JSONPath=".status.hostName",description="Host bound to the claim"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of BareMetalHostClaim"
+// +kubebuilder:object:root=true
+type BareMetalHostClaim struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BareMetalHostClaimSpec   `json:"spec,omitempty"`
+	Status BareMetalHostClaimStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BareMetalHostClaimList contains a list of BareMetalHostClaims.
+type BareMetalHostClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BareMetalHostClaim `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BareMetalHostClaim{}, &BareMetalHostClaimList{})
+}
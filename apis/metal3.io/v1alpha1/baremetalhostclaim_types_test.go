@@ -0,0 +1,96 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHardwareRequirementsMatches(t *testing.T) {
+	details := &HardwareDetails{
+		CPU:          CPU{Count: 32},
+		RAMMebibytes: 131072,
+		Storage: []Storage{
+			{Name: "/dev/sda", Type: HDD, SizeBytes: 4 * TeraByte},
+			{Name: "/dev/nvme0n1", Type: NVME, SizeBytes: 960 * GigaByte},
+		},
+		NIC: []NIC{
+			{Name: "eth0", SpeedGbps: 1},
+			{Name: "eth1", SpeedGbps: 25},
+		},
+	}
+
+	for _, tc := range []struct {
+		Scenario     string
+		Requirements HardwareRequirements
+		Details      *HardwareDetails
+		Expected     bool
+	}{
+		{
+			Scenario: "no requirements",
+			Details:  details,
+			Expected: true,
+		},
+		{
+			Scenario: "no requirements without details",
+			Details:  nil,
+			Expected: true,
+		},
+		{
+			Scenario:     "requirements without details",
+			Requirements: HardwareRequirements{MinCPUCount: 1},
+			Expected:     false,
+		},
+		{
+			Scenario: "all satisfied",
+			Requirements: HardwareRequirements{
+				MinCPUCount:     32,
+				MinRAMMebibytes: 65536,
+				Disk:            &DiskRequirements{MinSizeGigabytes: 900, Type: NVME},
+				MinNICSpeedGbps: 25,
+			},
+			Details:  details,
+			Expected: true,
+		},
+		{
+			Scenario:     "too few CPUs",
+			Requirements: HardwareRequirements{MinCPUCount: 64},
+			Details:      details,
+			Expected:     false,
+		},
+		{
+			Scenario:     "too little RAM",
+			Requirements: HardwareRequirements{MinRAMMebibytes: 262144},
+			Details:      details,
+			Expected:     false,
+		},
+		{
+			Scenario:     "large disk of any type",
+			Requirements: HardwareRequirements{Disk: &DiskRequirements{MinSizeGigabytes: 2000}},
+			Details:      details,
+			Expected:     true,
+		},
+		{
+			Scenario:     "no disk both large and fast",
+			Requirements: HardwareRequirements{Disk: &DiskRequirements{MinSizeGigabytes: 2000, Type: NVME}},
+			Details:      details,
+			Expected:     false,
+		},
+		{
+			Scenario:     "no disk of type",
+			Requirements: HardwareRequirements{Disk: &DiskRequirements{Type: SSD}},
+			Details:      details,
+			Expected:     false,
+		},
+		{
+			Scenario:     "NICs too slow",
+			Requirements: HardwareRequirements{MinNICSpeedGbps: 100},
+			Details:      details,
+			Expected:     false,
+		},
+	} {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, tc.Requirements.Matches(tc.Details))
+		})
+	}
+}
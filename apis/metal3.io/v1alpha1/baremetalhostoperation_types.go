@@ -0,0 +1,230 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// BareMetalHostOperationFinalizer is the name of the finalizer added
+	// to running operations to release the hosts in progress before the
+	// operation is removed.
+	BareMetalHostOperationFinalizer string = "baremetalhostoperation.metal3.io"
+)
+
+// OperationPhase describes the progress of an operation.
+type OperationPhase string
+
+const (
+	// OperationRunning means that the action is being run on the hosts.
+	OperationRunning OperationPhase = "Running"
+
+	// OperationSucceeded means that the action was run on all hosts,
+	// and failed on no more hosts than the failure threshold.
+	OperationSucceeded OperationPhase = "Succeeded"
+
+	// OperationFailed means that the action failed on more hosts than
+	// the failure threshold, or that the operation is invalid.
+	OperationFailed OperationPhase = "Failed"
+)
+
+// HostOperationPhase describes the progress of an operation on one host.
+type HostOperationPhase string
+
+const (
+	// HostOperationPending means that the action was not started on the
+	// host yet.
+	HostOperationPending HostOperationPhase = "Pending"
+
+	// HostOperationInProgress means that the action is running on the
+	// host.
+	HostOperationInProgress HostOperationPhase = "InProgress"
+
+	// HostOperationSucceeded means that the action finished on the host.
+	HostOperationSucceeded HostOperationPhase = "Succeeded"
+
+	// HostOperationFailed means that the action failed on the host.
+	HostOperationFailed HostOperationPhase = "Failed"
+
+	// HostOperationSkipped means that the action does not apply to the
+	// host, e.g. because it is not provisioned.
+	HostOperationSkipped HostOperationPhase = "Skipped"
+)
+
+// RebootOperation reboots the hosts using the reboot annotation.
+type RebootOperation struct {
+	// The mode of the reboot.
+	// +kubebuilder:validation:Enum=hard;soft
+	// +kubebuilder:default=soft
+	// +optional
+	Mode RebootMode `json:"mode,omitempty"`
+}
+
+// FirmwareUpdateOperation updates the firmware components of provisioned
+// hosts through their HostFirmwareComponents and servicing.
+type FirmwareUpdateOperation struct {
+	// The firmware updates to apply.
+	// +kubebuilder:validation:MinItems=1
+	Updates []FirmwareUpdate `json:"updates"`
+}
+
+// ReprovisionOperation provisions the hosts with a new image.
+type ReprovisionOperation struct {
+	// The image to provision the hosts with.
+	Image Image `json:"image"`
+}
+
+// BareMetalHostOperationAction is the action run on the hosts. Exactly
+// one of its fields must be set.
+type BareMetalHostOperationAction struct {
+	// +optional
+	Reboot *RebootOperation `json:"reboot,omitempty"`
+
+	// +optional
+	FirmwareUpdate *FirmwareUpdateOperation `json:"firmwareUpdate,omitempty"`
+
+	// +optional
+	Reprovision *ReprovisionOperation `json:"reprovision,omitempty"`
+}
+
+// BareMetalHostOperationSpec defines the action to run and how it is
+// rolled out over the hosts.
+type BareMetalHostOperationSpec struct {
+	// A label selector for the hosts to run the action on, evaluated
+	// when the operation starts.
+	HostSelector metav1.LabelSelector `json:"hostSelector"`
+
+	// The action to run.
+	// +kubebuilder:validation:XValidation:rule="[has(self.reboot), has(self.firmwareUpdate), has(self.reprovision)].filter(x, x).size() == 1",message="exactly one action must be set"
+	Action BareMetalHostOperationAction `json:"action"`
+
+	// The maximum number of hosts the action runs on at the same time,
+	// as a number or a percentage of the hosts rounded down. Defaults
+	// to 1, and is at least 1.
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// The number of hosts in a batch. When set, the action is only
+	// started on the hosts of a batch once it has finished on all hosts
+	// of the previous batch.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BatchSize int `json:"batchSize,omitempty"`
+
+	// The number of hosts the action may fail on. No new hosts are
+	// started once it is exceeded.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+
+	// The time after which the action is considered failed on a host.
+	// Defaults to 2 hours.
+	// +optional
+	HostTimeout *metav1.Duration `json:"hostTimeout,omitempty"`
+}
+
+// HostOperationProgress is the progress of the operation on one host.
+type HostOperationProgress struct {
+	// The name of the host, in the namespace of the operation.
+	HostName string `json:"hostName"`
+
+	// The progress of the action on the host.
+	Phase HostOperationPhase `json:"phase"`
+
+	// The step of the action running on the host, if it has several.
+	// +optional
+	Step string `json:"step,omitempty"`
+
+	// Details of why the action failed or was skipped.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The time at which the action was started on the host.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// The time at which the action finished on the host.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// BareMetalHostOperationStatus reports the progress of the operation.
+type BareMetalHostOperationStatus struct {
+	// The progress of the operation.
+	// +optional
+	Phase OperationPhase `json:"phase,omitempty"`
+
+	// Details of why the operation failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The progress of the action on each host, in the order the hosts
+	// are processed.
+	// +optional
+	Hosts []HostOperationProgress `json:"hosts,omitempty"`
+
+	// The number of hosts the action succeeded on.
+	// +optional
+	Succeeded int `json:"succeeded,omitempty"`
+
+	// The number of hosts the action failed on.
+	// +optional
+	Failed int `json:"failed,omitempty"`
+
+	// The time at which the operation started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// The time at which the operation finished.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// BareMetalHostOperation runs an action, such as a reboot, a firmware
+// update or a reprovisioning, across the hosts matching a selector, a
+// limited number of hosts at a time.
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName=bmho
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Progress of the operation"
+// +kubebuilder:printcolumn:name="Succeeded",type="integer",JSONPath=".status.succeeded",description="Number of hosts the action succeeded on"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failed",description="Number of hosts the action failed on"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of BareMetalHostOperation"
+// +kubebuilder:object:root=true
+type BareMetalHostOperation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BareMetalHostOperationSpec   `json:"spec,omitempty"`
+	Status BareMetalHostOperationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BareMetalHostOperationList contains a list of BareMetalHostOperations.
+type BareMetalHostOperationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BareMetalHostOperation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BareMetalHostOperation{}, &BareMetalHostOperationList{})
+}
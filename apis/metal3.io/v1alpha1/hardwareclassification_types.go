@@ -0,0 +1,313 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// HardwareClassificationFinalizer is the name of the finalizer added
+	// to classifications to remove their labels from the hosts before
+	// the classification is removed.
+	HardwareClassificationFinalizer string = "hardwareclassification.metal3.io"
+)
+
+// IntRange is an inclusive range of integers. A bound that is not set
+// does not limit the range.
+type IntRange struct {
+	// The minimum value.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Min *int `json:"min,omitempty"`
+
+	// The maximum value.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Max *int `json:"max,omitempty"`
+}
+
+// Contains returns whether the value is in the range.
+func (r *IntRange) Contains(value int) bool {
+	if r == nil {
+		return true
+	}
+	if r.Min != nil && value < *r.Min {
+		return false
+	}
+	if r.Max != nil && value > *r.Max {
+		return false
+	}
+	return true
+}
+
+func (r *IntRange) String() string {
+	switch {
+	case r.Min != nil && r.Max != nil:
+		return fmt.Sprintf("between %d and %d", *r.Min, *r.Max)
+	case r.Min != nil:
+		return fmt.Sprintf("at least %d", *r.Min)
+	case r.Max != nil:
+		return fmt.Sprintf("at most %d", *r.Max)
+	default:
+		return "any"
+	}
+}
+
+// CPURule is a rule over the CPUs of a host.
+type CPURule struct {
+	// The range of the number of CPUs.
+	// +optional
+	Count *IntRange `json:"count,omitempty"`
+
+	// A substring of the model of the CPUs.
+	// +optional
+	Model string `json:"model,omitempty"`
+}
+
+// DiskRule is a rule over the disks of a host. The disks matching the
+// type and size are counted.
+type DiskRule struct {
+	// The type of the disks.
+	// +kubebuilder:validation:Enum=HDD;SSD;NVME
+	// +optional
+	Type DiskType `json:"type,omitempty"`
+
+	// The range of the size of the disks in Gigabytes.
+	// +optional
+	SizeGigabytes *IntRange `json:"sizeGigabytes,omitempty"`
+
+	// The range of the number of matching disks. At least one disk must
+	// match when not set.
+	// +optional
+	Count *IntRange `json:"count,omitempty"`
+}
+
+// NICRule is a rule over the NICs of a host. The NICs matching the speed
+// are counted.
+type NICRule struct {
+	// The minimum speed of the NICs in Gbps.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinSpeedGbps int `json:"minSpeedGbps,omitempty"`
+
+	// The range of the number of matching NICs. At least one NIC must
+	// match when not set.
+	// +optional
+	Count *IntRange `json:"count,omitempty"`
+}
+
+// VendorRule is a rule over the vendor of a host. Each field is a
+// substring of the matching value of the hardware details.
+type VendorRule struct {
+	// The manufacturer of the system.
+	// +optional
+	Manufacturer string `json:"manufacturer,omitempty"`
+
+	// The product name of the system.
+	// +optional
+	ProductName string `json:"productName,omitempty"`
+}
+
+// HardwareClassificationRules are the rules a host must satisfy to be
+// classified. Rules that are not set are satisfied by all hosts.
+type HardwareClassificationRules struct {
+	// +optional
+	CPU *CPURule `json:"cpu,omitempty"`
+
+	// The range of the amount of memory in Mebibytes.
+	// +optional
+	RAMMebibytes *IntRange `json:"ramMebibytes,omitempty"`
+
+	// +optional
+	Disk *DiskRule `json:"disk,omitempty"`
+
+	// +optional
+	NIC *NICRule `json:"nic,omitempty"`
+
+	// +optional
+	Vendor *VendorRule `json:"vendor,omitempty"`
+}
+
+// Match checks the hardware details against the rules. It returns a
+// description of the first rule that is not satisfied, or an empty
+// string if the details match.
+func (rules *HardwareClassificationRules) Match(details *HardwareDetails) string {
+	if details == nil {
+		return "no hardware details"
+	}
+
+	if cpu := rules.CPU; cpu != nil {
+		if !cpu.Count.Contains(details.CPU.Count) {
+			return fmt.Sprintf("CPU count %d is not %s", details.CPU.Count, cpu.Count)
+		}
+		if cpu.Model != "" && !strings.Contains(details.CPU.Model, cpu.Model) {
+			return fmt.Sprintf("CPU model %q does not contain %q", details.CPU.Model, cpu.Model)
+		}
+	}
+
+	if !rules.RAMMebibytes.Contains(details.RAMMebibytes) {
+		return fmt.Sprintf("RAM %d MiB is not %s", details.RAMMebibytes, rules.RAMMebibytes)
+	}
+
+	if disk := rules.Disk; disk != nil {
+		count := 0
+		for _, storage := range details.Storage {
+			if disk.Type != "" && storage.Type != disk.Type {
+				continue
+			}
+			if !disk.SizeGigabytes.Contains(int(storage.SizeBytes / GigaByte)) {
+				continue
+			}
+			count++
+		}
+		if disk.Count == nil && count == 0 {
+			return "no matching disk"
+		}
+		if !disk.Count.Contains(count) {
+			return fmt.Sprintf("matching disk count %d is not %s", count, disk.Count)
+		}
+	}
+
+	if nic := rules.NIC; nic != nil {
+		count := 0
+		seen := map[string]bool{}
+		for _, n := range details.NIC {
+			// Dual-stack NICs are reported once per address
+			if seen[n.Name] {
+				continue
+			}
+			seen[n.Name] = true
+			if n.SpeedGbps >= nic.MinSpeedGbps {
+				count++
+			}
+		}
+		if nic.Count == nil && count == 0 {
+			return "no matching NIC"
+		}
+		if !nic.Count.Contains(count) {
+			return fmt.Sprintf("matching NIC count %d is not %s", count, nic.Count)
+		}
+	}
+
+	if vendor := rules.Vendor; vendor != nil {
+		if !strings.Contains(details.SystemVendor.Manufacturer, vendor.Manufacturer) {
+			return fmt.Sprintf("manufacturer %q does not contain %q", details.SystemVendor.Manufacturer, vendor.Manufacturer)
+		}
+		if !strings.Contains(details.SystemVendor.ProductName, vendor.ProductName) {
+			return fmt.Sprintf("product name %q does not contain %q", details.SystemVendor.ProductName, vendor.ProductName)
+		}
+	}
+
+	return ""
+}
+
+// HardwareClassificationSpec defines the rules of a classification and
+// the labels applied to the hosts satisfying them.
+type HardwareClassificationSpec struct {
+	// A label selector for the hosts to classify. An empty selector
+	// selects all hosts in the namespace.
+	// +optional
+	HostSelector *metav1.LabelSelector `json:"hostSelector,omitempty"`
+
+	// The rules the hardware details of a host must satisfy.
+	// +optional
+	Rules HardwareClassificationRules `json:"rules,omitempty"`
+
+	// The labels applied to the hosts satisfying the rules, and removed
+	// from the other selected hosts.
+	// +kubebuilder:validation:MinProperties=1
+	Labels map[string]string `json:"labels"`
+}
+
+// HostClassificationResult is the result of the classification of one
+// host.
+type HostClassificationResult struct {
+	// The name of the host, in the namespace of the classification.
+	HostName string `json:"hostName"`
+
+	// Whether the host satisfies the rules.
+	Matched bool `json:"matched"`
+
+	// The first rule the host does not satisfy.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The labels that are not applied to the matching host because
+	// another classification matching it sets them to a different value.
+	// +optional
+	Conflict string `json:"conflict,omitempty"`
+}
+
+// HardwareClassificationStatus reports the classification of the
+// inspected hosts.
+type HardwareClassificationStatus struct {
+	// The results for the selected hosts that have hardware details,
+	// sorted by host name.
+	// +optional
+	Results []HostClassificationResult `json:"results,omitempty"`
+
+	// The number of hosts satisfying the rules.
+	// +optional
+	MatchedHosts int `json:"matchedHosts,omitempty"`
+
+	// The labels last applied to the hosts, which are removed from them
+	// when they change.
+	// +optional
+	AppliedLabels map[string]string `json:"appliedLabels,omitempty"`
+
+	// The generation of the classification the results are for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Why the labels cannot be applied to the hosts, when they are not
+	// valid Kubernetes labels.
+	// +optional
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+// HardwareClassification labels the inspected hosts of its namespace
+// whose hardware details satisfy its rules.
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName=hwc
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Matched",type="integer",JSONPath=".status.matchedHosts",description="Number of hosts satisfying the rules"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of HardwareClassification"
+// +kubebuilder:object:root=true
+type HardwareClassification struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HardwareClassificationSpec   `json:"spec,omitempty"`
+	Status HardwareClassificationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HardwareClassificationList contains a list of HardwareClassifications.
+type HardwareClassificationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HardwareClassification `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HardwareClassification{}, &HardwareClassificationList{})
+}
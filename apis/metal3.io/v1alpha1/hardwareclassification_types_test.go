@@ -0,0 +1,144 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntRangeContains(t *testing.T) {
+	one, three := 1, 3
+
+	for _, tc := range []struct {
+		Scenario string
+		Range    *IntRange
+		Value    int
+		Expected bool
+	}{
+		{Scenario: "nil", Range: nil, Value: 0, Expected: true},
+		{Scenario: "unbounded", Range: &IntRange{}, Value: 42, Expected: true},
+		{Scenario: "below min", Range: &IntRange{Min: &one}, Value: 0, Expected: false},
+		{Scenario: "min", Range: &IntRange{Min: &one}, Value: 1, Expected: true},
+		{Scenario: "max", Range: &IntRange{Min: &one, Max: &three}, Value: 3, Expected: true},
+		{Scenario: "above max", Range: &IntRange{Max: &three}, Value: 4, Expected: false},
+	} {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, tc.Range.Contains(tc.Value))
+		})
+	}
+}
+
+func TestHardwareClassificationRulesMatch(t *testing.T) {
+	one, two, thirtyTwo, fiveHundred := 1, 2, 32, 500
+	details := &HardwareDetails{
+		SystemVendor: HardwareSystemVendor{
+			Manufacturer: "Dell Inc.",
+			ProductName:  "PowerEdge R750 (SKU=090E)",
+		},
+		CPU:          CPU{Count: 64, Model: "Intel(R) Xeon(R) Gold 6338 CPU @ 2.00GHz"},
+		RAMMebibytes: 262144,
+		Storage: []Storage{
+			{Name: "/dev/sda", Type: SSD, SizeBytes: 480 * GigaByte},
+			{Name: "/dev/nvme0n1", Type: NVME, SizeBytes: 1600 * GigaByte},
+			{Name: "/dev/nvme1n1", Type: NVME, SizeBytes: 1600 * GigaByte},
+		},
+		NIC: []NIC{
+			{Name: "eno1", SpeedGbps: 1},
+			{Name: "ens1f0", SpeedGbps: 25, IP: "192.0.2.1"},
+			{Name: "ens1f0", SpeedGbps: 25, IP: "2001:db8::1"},
+			{Name: "ens1f1", SpeedGbps: 25},
+		},
+	}
+
+	for _, tc := range []struct {
+		Scenario string
+		Rules    HardwareClassificationRules
+		Details  *HardwareDetails
+		Expected string
+	}{
+		{
+			Scenario: "no rules",
+			Details:  details,
+		},
+		{
+			Scenario: "no details",
+			Expected: "no hardware details",
+		},
+		{
+			Scenario: "cpu",
+			Rules:    HardwareClassificationRules{CPU: &CPURule{Count: &IntRange{Min: &thirtyTwo}, Model: "Xeon"}},
+			Details:  details,
+		},
+		{
+			Scenario: "too few cpus",
+			Rules:    HardwareClassificationRules{CPU: &CPURule{Count: &IntRange{Max: &thirtyTwo}}},
+			Details:  details,
+			Expected: "CPU count 64 is not at most 32",
+		},
+		{
+			Scenario: "cpu model",
+			Rules:    HardwareClassificationRules{CPU: &CPURule{Model: "EPYC"}},
+			Details:  details,
+			Expected: `CPU model "Intel(R) Xeon(R) Gold 6338 CPU @ 2.00GHz" does not contain "EPYC"`,
+		},
+		{
+			Scenario: "ram",
+			Rules:    HardwareClassificationRules{RAMMebibytes: &IntRange{Max: &fiveHundred}},
+			Details:  details,
+			Expected: "RAM 262144 MiB is not at most 500",
+		},
+		{
+			Scenario: "nvme disks",
+			Rules: HardwareClassificationRules{Disk: &DiskRule{
+				Type:          NVME,
+				SizeGigabytes: &IntRange{Min: &fiveHundred},
+				Count:         &IntRange{Min: &two, Max: &two},
+			}},
+			Details: details,
+		},
+		{
+			Scenario: "no matching disk",
+			Rules:    HardwareClassificationRules{Disk: &DiskRule{Type: HDD}},
+			Details:  details,
+			Expected: "no matching disk",
+		},
+		{
+			Scenario: "too many disks",
+			Rules:    HardwareClassificationRules{Disk: &DiskRule{Count: &IntRange{Max: &two}}},
+			Details:  details,
+			Expected: "matching disk count 3 is not at most 2",
+		},
+		{
+			Scenario: "fast nics counted once",
+			Rules:    HardwareClassificationRules{NIC: &NICRule{MinSpeedGbps: 25, Count: &IntRange{Min: &two, Max: &two}}},
+			Details:  details,
+		},
+		{
+			Scenario: "no fast nic",
+			Rules:    HardwareClassificationRules{NIC: &NICRule{MinSpeedGbps: 100}},
+			Details:  details,
+			Expected: "no matching NIC",
+		},
+		{
+			Scenario: "too few nics",
+			Rules:    HardwareClassificationRules{NIC: &NICRule{Count: &IntRange{Min: &one, Max: &one}}},
+			Details:  details,
+			Expected: "matching NIC count 3 is not between 1 and 1",
+		},
+		{
+			Scenario: "vendor",
+			Rules:    HardwareClassificationRules{Vendor: &VendorRule{Manufacturer: "Dell", ProductName: "R750"}},
+			Details:  details,
+		},
+		{
+			Scenario: "other vendor",
+			Rules:    HardwareClassificationRules{Vendor: &VendorRule{Manufacturer: "HPE"}},
+			Details:  details,
+			Expected: `manufacturer "Dell Inc." does not contain "HPE"`,
+		},
+	} {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, tc.Rules.Match(tc.Details))
+		})
+	}
+}
@@ -0,0 +1,136 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HardwareProfileMatch holds the criteria a host must satisfy for a
+// profile to be selected automatically. Each criterion is a substring of
+// the matching value of the hardware details of the host. A profile
+// without criteria matches all hosts.
+type HardwareProfileMatch struct {
+	// The manufacturer of the system.
+	// +optional
+	Manufacturer string `json:"manufacturer,omitempty"`
+
+	// The product name of the system.
+	// +optional
+	ProductName string `json:"productName,omitempty"`
+
+	// Disk models that must each be found on at least one disk of the
+	// host.
+	// +optional
+	DiskModels []string `json:"diskModels,omitempty"`
+}
+
+// HardwareProfileSpec defines the settings for a class of hardware.
+type HardwareProfileSpec struct {
+	// Criteria for selecting the profile for hosts that do not name
+	// their hardware profile.
+	// +optional
+	Match HardwareProfileMatch `json:"match,omitempty"`
+
+	// Root device hints used when the host does not set its own.
+	// +optional
+	RootDeviceHints *RootDeviceHints `json:"rootDeviceHints,omitempty"`
+
+	// RAID configuration used when the host does not set its own.
+	// +optional
+	RAID *RAIDConfig `json:"raid,omitempty"`
+
+	// Firmware configuration used when the host does not set its own.
+	// +optional
+	Firmware *FirmwareConfig `json:"firmware,omitempty"`
+
+	// Idle power-off policy used when the host does not set its own.
+	// +optional
+	IdlePowerOff *IdlePowerOffPolicy `json:"idlePowerOff,omitempty"`
+
+	// Remediation policy used when the host does not set its own.
+	// +optional
+	Remediation *RemediationPolicy `json:"remediation,omitempty"`
+}
+
+// MatchScore returns how specifically the criteria of the profile match
+// the hardware details, as the number of criteria satisfied, or -1 if
+// the details do not match.
+func (spec *HardwareProfileSpec) MatchScore(details *HardwareDetails) int {
+	if details == nil {
+		return -1
+	}
+
+	score := 0
+	match := spec.Match
+	if match.Manufacturer != "" {
+		if !strings.Contains(details.SystemVendor.Manufacturer, match.Manufacturer) {
+			return -1
+		}
+		score++
+	}
+	if match.ProductName != "" {
+		if !strings.Contains(details.SystemVendor.ProductName, match.ProductName) {
+			return -1
+		}
+		score++
+	}
+	for _, model := range match.DiskModels {
+		found := false
+		for _, disk := range details.Storage {
+			if strings.Contains(disk.Model, model) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return -1
+		}
+		score++
+	}
+	return score
+}
+
+// HardwareProfile holds the settings for a class of hardware, and the
+// criteria for selecting it for hosts automatically.
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:scope=Cluster,shortName=hwp
+// +kubebuilder:printcolumn:name="Manufacturer",type="string",JSONPath=".spec.match.manufacturer",description="Manufacturer matched by the profile"
+// +kubebuilder:printcolumn:name="Product",type="string",JSONPath=".spec.match.productName",description="Product name matched by the profile"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of HardwareProfile"
+// +kubebuilder:object:root=true
+type HardwareProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HardwareProfileSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HardwareProfileList contains a list of HardwareProfiles.
+type HardwareProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HardwareProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HardwareProfile{}, &HardwareProfileList{})
+}
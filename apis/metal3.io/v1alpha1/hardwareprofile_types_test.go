@@ -0,0 +1,70 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHardwareProfileMatchScore(t *testing.T) {
+	details := &HardwareDetails{
+		SystemVendor: HardwareSystemVendor{
+			Manufacturer: "Dell Inc.",
+			ProductName:  "PowerEdge R750 (SKU=090E)",
+		},
+		Storage: []Storage{
+			{Name: "/dev/sda", Model: "PERC H755 Front"},
+			{Name: "/dev/nvme0n1", Model: "Dell Ent NVMe P5600"},
+		},
+	}
+
+	for _, tc := range []struct {
+		Scenario string
+		Match    HardwareProfileMatch
+		Details  *HardwareDetails
+		Expected int
+	}{
+		{
+			Scenario: "no criteria",
+			Details:  details,
+			Expected: 0,
+		},
+		{
+			Scenario: "no details",
+			Expected: -1,
+		},
+		{
+			Scenario: "manufacturer",
+			Match:    HardwareProfileMatch{Manufacturer: "Dell"},
+			Details:  details,
+			Expected: 1,
+		},
+		{
+			Scenario: "all criteria",
+			Match: HardwareProfileMatch{
+				Manufacturer: "Dell",
+				ProductName:  "PowerEdge R750",
+				DiskModels:   []string{"PERC H755", "NVMe"},
+			},
+			Details:  details,
+			Expected: 4,
+		},
+		{
+			Scenario: "other product",
+			Match:    HardwareProfileMatch{Manufacturer: "Dell", ProductName: "PowerEdge R640"},
+			Details:  details,
+			Expected: -1,
+		},
+		{
+			Scenario: "missing disk model",
+			Match:    HardwareProfileMatch{DiskModels: []string{"PERC H755", "PERC H330"}},
+			Details:  details,
+			Expected: -1,
+		},
+	} {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			spec := HardwareProfileSpec{Match: tc.Match}
+			assert.Equal(t, tc.Expected, spec.MatchScore(tc.Details))
+		})
+	}
+}
@@ -0,0 +1,121 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SwitchPortConnection is a NIC of a host connected to a switch port.
+type SwitchPortConnection struct {
+	// The name of the host, in the namespace of the topology.
+	HostName string `json:"hostName"`
+
+	// The name of the NIC of the host.
+	NIC string `json:"nic"`
+
+	// The MAC address of the NIC.
+	// +optional
+	MAC string `json:"mac,omitempty"`
+}
+
+// SwitchPort is a port of a switch with the hosts connected to it.
+type SwitchPort struct {
+	// The ID of the port.
+	PortID string `json:"portID"`
+
+	// The description of the port.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// The NICs connected to the port. More than one connection usually
+	// means that the hosts are connected through an unmanaged switch.
+	// +optional
+	Connections []SwitchPortConnection `json:"connections,omitempty"`
+}
+
+// NetworkSwitch is a switch discovered from the LLDP data of the NICs
+// of the hosts.
+type NetworkSwitch struct {
+	// The chassis ID of the switch.
+	SwitchID string `json:"switchID"`
+
+	// The system name of the switch.
+	// +optional
+	SystemName string `json:"systemName,omitempty"`
+
+	// The management addresses of the switch.
+	// +optional
+	ManagementAddresses []string `json:"managementAddresses,omitempty"`
+
+	// The ports of the switch hosts are connected to.
+	// +optional
+	Ports []SwitchPort `json:"ports,omitempty"`
+}
+
+// NetworkTopologySpec selects the hosts included in a topology.
+type NetworkTopologySpec struct {
+	// A label selector for the hosts to include. An empty selector
+	// includes all hosts in the namespace.
+	// +optional
+	HostSelector *metav1.LabelSelector `json:"hostSelector,omitempty"`
+}
+
+// NetworkTopologyStatus is the physical network topology of the hosts.
+type NetworkTopologyStatus struct {
+	// The switches the hosts are connected to, sorted by ID.
+	// +optional
+	Switches []NetworkSwitch `json:"switches,omitempty"`
+
+	// The number of hosts included in the topology.
+	// +optional
+	Hosts int `json:"hosts,omitempty"`
+
+	// The number of included hosts that have NICs without LLDP data.
+	// +optional
+	HostsWithoutLLDP int `json:"hostsWithoutLLDP,omitempty"`
+}
+
+// NetworkTopology summarizes the switch ports the hosts of a namespace
+// are connected to, as discovered by inspection.
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName=ntopo
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Hosts",type="integer",JSONPath=".status.hosts",description="Number of hosts in the topology"
+// +kubebuilder:printcolumn:name="Without LLDP",type="integer",JSONPath=".status.hostsWithoutLLDP",description="Number of hosts with NICs without LLDP data"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of NetworkTopology"
+// +kubebuilder:object:root=true
+type NetworkTopology struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NetworkTopologySpec   `json:"spec,omitempty"`
+	Status NetworkTopologyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NetworkTopologyList contains a list of NetworkTopologies.
+type NetworkTopologyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NetworkTopology `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NetworkTopology{}, &NetworkTopologyList{})
+}
@@ -0,0 +1,146 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PowerAction is the power change requested by a PowerOperation.
+type PowerAction string
+
+const (
+	// PowerActionOn powers the host on and keeps it on.
+	PowerActionOn PowerAction = "PowerOn"
+
+	// PowerActionOff powers the host off and keeps it off.
+	PowerActionOff PowerAction = "PowerOff"
+
+	// PowerActionReboot powers the host off and back on.
+	PowerActionReboot PowerAction = "Reboot"
+)
+
+// PowerOperationPhase describes the progress of a power operation.
+type PowerOperationPhase string
+
+const (
+	// PowerOperationPending means that the operation was acknowledged by
+	// the host but has not started yet.
+	PowerOperationPending PowerOperationPhase = "Pending"
+
+	// PowerOperationRunning means that the power of the host is being
+	// changed.
+	PowerOperationRunning PowerOperationPhase = "Running"
+
+	// PowerOperationSucceeded means that the host reached the requested
+	// power state.
+	PowerOperationSucceeded PowerOperationPhase = "Succeeded"
+
+	// PowerOperationFailed means that the power of the host could not be
+	// changed, or not before the deadline.
+	PowerOperationFailed PowerOperationPhase = "Failed"
+)
+
+// PowerOperationSpec defines the power change requested for a host.
+type PowerOperationSpec struct {
+	// The name of the host, in the namespace of the operation.
+	// +kubebuilder:validation:MinLength=1
+	HostName string `json:"hostName"`
+
+	// The power change to make.
+	// +kubebuilder:validation:Enum=PowerOn;PowerOff;Reboot
+	Action PowerAction `json:"action"`
+
+	// The mode used to power the host off.
+	// +kubebuilder:validation:Enum=hard;soft
+	// +kubebuilder:default=soft
+	// +optional
+	Mode RebootMode `json:"mode,omitempty"`
+
+	// Reboot the host even if it is not provisioned, e.g. when it is
+	// available. Reboots of hosts that are not provisioned wait until the
+	// host is provisioned otherwise.
+	// +optional
+	Force bool `json:"force,omitempty"`
+
+	// The time by which the operation must have completed. The operation
+	// fails if it has not completed by then, whether it has started or
+	// not.
+	// +optional
+	Deadline *metav1.Time `json:"deadline,omitempty"`
+}
+
+// PowerOperationStatus reports the progress and the result of the
+// operation.
+type PowerOperationStatus struct {
+	// The progress of the operation, and its result once finished.
+	// +optional
+	Phase PowerOperationPhase `json:"phase,omitempty"`
+
+	// Whether the host has been powered off during a reboot.
+	// +optional
+	PoweredOff bool `json:"poweredOff,omitempty"`
+
+	// The time at which the operation started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// The time at which the operation finished.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Details of why the operation failed.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// Finished returns whether the operation succeeded or failed.
+func (status *PowerOperationStatus) Finished() bool {
+	return status.Phase == PowerOperationSucceeded || status.Phase == PowerOperationFailed
+}
+
+// PowerOperation requests a change of the power state of a
+// BareMetalHost, and records its outcome. The operations of a host are
+// run one at a time, in the order they are created.
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName=powerop
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Host",type="string",JSONPath=".spec.hostName",description="Host the operation applies to"
+// +kubebuilder:printcolumn:name="Action",type="string",JSONPath=".spec.action",description="Power change requested"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Progress of the operation"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of PowerOperation"
+// +kubebuilder:object:root=true
+type PowerOperation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PowerOperationSpec   `json:"spec,omitempty"`
+	Status PowerOperationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PowerOperationList contains a list of PowerOperations.
+type PowerOperationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PowerOperation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PowerOperation{}, &PowerOperationList{})
+}
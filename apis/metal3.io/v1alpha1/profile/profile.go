@@ -23,6 +23,18 @@ type Profile struct {
 	// RootDeviceHints holds the suggestions for placing the storage
 	// for the root filesystem.
 	RootDeviceHints metal3api.RootDeviceHints
+
+	// RAID holds the default RAID configuration, if any.
+	RAID *metal3api.RAIDConfig
+
+	// Firmware holds the default firmware configuration, if any.
+	Firmware *metal3api.FirmwareConfig
+
+	// IdlePowerOff holds the default idle power-off policy, if any.
+	IdlePowerOff *metal3api.IdlePowerOffPolicy
+
+	// Remediation holds the default remediation policy, if any.
+	Remediation *metal3api.RemediationPolicy
 }
 
 var profiles = make(map[string]Profile)
@@ -68,7 +80,23 @@ func init() {
 	}
 }
 
-// GetProfile returns the named profile.
+// FromHardwareProfile returns the profile defined by a HardwareProfile
+// resource.
+func FromHardwareProfile(hwProfile *metal3api.HardwareProfile) Profile {
+	prof := Profile{
+		Name:         hwProfile.Name,
+		RAID:         hwProfile.Spec.RAID.DeepCopy(),
+		Firmware:     hwProfile.Spec.Firmware.DeepCopy(),
+		IdlePowerOff: hwProfile.Spec.IdlePowerOff.DeepCopy(),
+		Remediation:  hwProfile.Spec.Remediation.DeepCopy(),
+	}
+	if hwProfile.Spec.RootDeviceHints != nil {
+		prof.RootDeviceHints = *hwProfile.Spec.RootDeviceHints.DeepCopy()
+	}
+	return prof
+}
+
+// GetProfile returns the named built-in profile.
 func GetProfile(name string) (Profile, error) {
 	profile, ok := profiles[name]
 	if !ok {
@@ -0,0 +1,131 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"slices"
+
+	"github.com/metal3-io/baremetal-operator/pkg/hardwareutils/bmc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ProvisioningConcurrencyBucket limits the number of matching hosts that
+// may be inspected, provisioned or deprovisioned at the same time. A host
+// matches a bucket when it satisfies all of its criteria.
+type ProvisioningConcurrencyBucket struct {
+	// The name of the bucket, reported when it delays a host.
+	Name string `json:"name"`
+
+	// The maximum number of matching hosts that may be busy at the same
+	// time. The limit is best-effort, as hosts starting at the same time
+	// may exceed it briefly.
+	// +kubebuilder:validation:Minimum=1
+	Limit int `json:"limit"`
+
+	// The namespaces of the hosts counted in the bucket. Hosts in all
+	// namespaces are counted when empty.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// A label selector for the hosts counted in the bucket, for example
+	// to select the hosts of a network segment.
+	// +optional
+	HostSelector *metav1.LabelSelector `json:"hostSelector,omitempty"`
+
+	// The BMC drivers, such as redfish, idrac or ipmi, of the hosts
+	// counted in the bucket. Hosts using any driver are counted when
+	// empty.
+	// +optional
+	BMCDrivers []string `json:"bmcDrivers,omitempty"`
+
+	// Whether hosts provisioned using virtual media are counted in the
+	// bucket. They are not counted by default, like for the global
+	// provisioning limit, as they do not depend on DHCP.
+	// +optional
+	IncludeVirtualMedia bool `json:"includeVirtualMedia,omitempty"`
+}
+
+// Matches returns whether the host is counted in the bucket.
+func (bucket *ProvisioningConcurrencyBucket) Matches(host *BareMetalHost) (bool, error) {
+	if len(bucket.Namespaces) > 0 && !slices.Contains(bucket.Namespaces, host.Namespace) {
+		return false, nil
+	}
+
+	if bucket.HostSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(bucket.HostSelector)
+		if err != nil {
+			return false, err
+		}
+		if !selector.Matches(labels.Set(host.Labels)) {
+			return false, nil
+		}
+	}
+
+	if len(bucket.BMCDrivers) == 0 && bucket.IncludeVirtualMedia {
+		return true, nil
+	}
+
+	accessDetails, err := bmc.NewAccessDetails(host.Spec.BMC.Address, host.Spec.BMC.DisableCertificateVerification)
+	if err != nil {
+		return false, err
+	}
+	if len(bucket.BMCDrivers) > 0 && !slices.Contains(bucket.BMCDrivers, accessDetails.Driver()) {
+		return false, nil
+	}
+	if !bucket.IncludeVirtualMedia && accessDetails.SupportsISOPreprovisioningImage() {
+		return false, nil
+	}
+	return true, nil
+}
+
+// ProvisioningConcurrencyPolicySpec defines the buckets of hosts whose
+// concurrent provisioning is limited.
+type ProvisioningConcurrencyPolicySpec struct {
+	// The buckets of hosts. A host is delayed when any bucket it matches
+	// is full.
+	// +kubebuilder:validation:MinItems=1
+	Buckets []ProvisioningConcurrencyBucket `json:"buckets"`
+}
+
+// ProvisioningConcurrencyPolicy limits the number of hosts that may be
+// inspected, provisioned or deprovisioned at the same time, per bucket of
+// hosts, in addition to the global provisioning limit.
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:scope=Cluster,shortName=pcp
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of ProvisioningConcurrencyPolicy"
+// +kubebuilder:object:root=true
+type ProvisioningConcurrencyPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ProvisioningConcurrencyPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ProvisioningConcurrencyPolicyList contains a list of
+// ProvisioningConcurrencyPolicies.
+type ProvisioningConcurrencyPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProvisioningConcurrencyPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ProvisioningConcurrencyPolicy{}, &ProvisioningConcurrencyPolicyList{})
+}
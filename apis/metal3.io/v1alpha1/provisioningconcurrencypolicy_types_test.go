@@ -0,0 +1,99 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestProvisioningConcurrencyBucketMatches(t *testing.T) {
+	for _, tc := range []struct {
+		Scenario string
+		Bucket   ProvisioningConcurrencyBucket
+		Address  string
+		Expected bool
+	}{
+		{
+			Scenario: "no criteria",
+			Address:  "ipmi://192.168.122.1",
+			Expected: true,
+		},
+		{
+			Scenario: "namespace",
+			Bucket:   ProvisioningConcurrencyBucket{Namespaces: []string{"tenant-a", "tenant-b"}},
+			Address:  "ipmi://192.168.122.1",
+			Expected: true,
+		},
+		{
+			Scenario: "other namespace",
+			Bucket:   ProvisioningConcurrencyBucket{Namespaces: []string{"tenant-b"}},
+			Address:  "ipmi://192.168.122.1",
+			Expected: false,
+		},
+		{
+			Scenario: "label",
+			Bucket: ProvisioningConcurrencyBucket{
+				HostSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"segment": "rack-1"}},
+			},
+			Address:  "ipmi://192.168.122.1",
+			Expected: true,
+		},
+		{
+			Scenario: "other label",
+			Bucket: ProvisioningConcurrencyBucket{
+				HostSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"segment": "rack-2"}},
+			},
+			Address:  "ipmi://192.168.122.1",
+			Expected: false,
+		},
+		{
+			Scenario: "driver",
+			Bucket:   ProvisioningConcurrencyBucket{BMCDrivers: []string{"idrac", "ipmi"}},
+			Address:  "ipmi://192.168.122.1",
+			Expected: true,
+		},
+		{
+			Scenario: "other driver",
+			Bucket:   ProvisioningConcurrencyBucket{BMCDrivers: []string{"redfish"}},
+			Address:  "ipmi://192.168.122.1",
+			Expected: false,
+		},
+		{
+			Scenario: "virtual media",
+			Address:  "redfish-virtualmedia://192.168.122.1/redfish/v1/Systems/1",
+			Expected: false,
+		},
+		{
+			Scenario: "virtual media included",
+			Bucket:   ProvisioningConcurrencyBucket{BMCDrivers: []string{"redfish"}, IncludeVirtualMedia: true},
+			Address:  "redfish-virtualmedia://192.168.122.1/redfish/v1/Systems/1",
+			Expected: true,
+		},
+	} {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			host := &BareMetalHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "host",
+					Namespace: "tenant-a",
+					Labels:    map[string]string{"segment": "rack-1"},
+				},
+				Spec: BareMetalHostSpec{BMC: BMCDetails{Address: tc.Address}},
+			}
+
+			matches, err := tc.Bucket.Matches(host)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.Expected, matches)
+		})
+	}
+}
+
+func TestProvisioningConcurrencyBucketMatchesInvalidAddress(t *testing.T) {
+	bucket := ProvisioningConcurrencyBucket{BMCDrivers: []string{"redfish"}}
+	host := &BareMetalHost{Spec: BareMetalHostSpec{BMC: BMCDetails{Address: "unknown://192.168.122.1"}}}
+
+	_, err := bucket.Matches(host)
+	assert.Error(t, err)
+}
@@ -25,8 +25,24 @@ import (
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Accelerator) DeepCopyInto(out *Accelerator) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Accelerator.
+func (in *Accelerator) DeepCopy() *Accelerator {
+	if in == nil {
+		return nil
+	}
+	out := new(Accelerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AttachedImageReference) DeepCopyInto(out *AttachedImageReference) {
 	*out = *in
@@ -194,31 +210,26 @@ func (in *BareMetalHost) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BareMetalHostList) DeepCopyInto(out *BareMetalHostList) {
+func (in *BareMetalHostClaim) DeepCopyInto(out *BareMetalHostClaim) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]BareMetalHost, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BareMetalHostList.
-func (in *BareMetalHostList) DeepCopy() *BareMetalHostList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BareMetalHostClaim.
+func (in *BareMetalHostClaim) DeepCopy() *BareMetalHostClaim {
 	if in == nil {
 		return nil
 	}
-	out := new(BareMetalHostList)
+	out := new(BareMetalHostClaim)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *BareMetalHostList) DeepCopyObject() runtime.Object {
+func (in *BareMetalHostClaim) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -226,177 +237,144 @@ func (in *BareMetalHostList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BareMetalHostSpec) DeepCopyInto(out *BareMetalHostSpec) {
+func (in *BareMetalHostClaimList) DeepCopyInto(out *BareMetalHostClaimList) {
 	*out = *in
-	if in.Taints != nil {
-		in, out := &in.Taints, &out.Taints
-		*out = make([]v1.Taint, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BareMetalHostClaim, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.BMC = in.BMC
-	if in.RAID != nil {
-		in, out := &in.RAID, &out.RAID
-		*out = new(RAIDConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Firmware != nil {
-		in, out := &in.Firmware, &out.Firmware
-		*out = new(FirmwareConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.RootDeviceHints != nil {
-		in, out := &in.RootDeviceHints, &out.RootDeviceHints
-		*out = new(RootDeviceHints)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ConsumerRef != nil {
-		in, out := &in.ConsumerRef, &out.ConsumerRef
-		*out = new(v1.ObjectReference)
-		**out = **in
-	}
-	if in.Image != nil {
-		in, out := &in.Image, &out.Image
-		*out = new(Image)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.UserData != nil {
-		in, out := &in.UserData, &out.UserData
-		*out = new(v1.SecretReference)
-		**out = **in
-	}
-	if in.NetworkData != nil {
-		in, out := &in.NetworkData, &out.NetworkData
-		*out = new(v1.SecretReference)
-		**out = **in
-	}
-	if in.MetaData != nil {
-		in, out := &in.MetaData, &out.MetaData
-		*out = new(v1.SecretReference)
-		**out = **in
-	}
-	if in.CustomDeploy != nil {
-		in, out := &in.CustomDeploy, &out.CustomDeploy
-		*out = new(CustomDeploy)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BareMetalHostSpec.
-func (in *BareMetalHostSpec) DeepCopy() *BareMetalHostSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BareMetalHostClaimList.
+func (in *BareMetalHostClaimList) DeepCopy() *BareMetalHostClaimList {
 	if in == nil {
 		return nil
 	}
-	out := new(BareMetalHostSpec)
+	out := new(BareMetalHostClaimList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BareMetalHostClaimList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BareMetalHostStatus) DeepCopyInto(out *BareMetalHostStatus) {
+func (in *BareMetalHostClaimSpec) DeepCopyInto(out *BareMetalHostClaimSpec) {
 	*out = *in
-	if in.LastUpdated != nil {
-		in, out := &in.LastUpdated, &out.LastUpdated
-		*out = (*in).DeepCopy()
-	}
-	if in.HardwareDetails != nil {
-		in, out := &in.HardwareDetails, &out.HardwareDetails
-		*out = new(HardwareDetails)
+	if in.HostSelector != nil {
+		in, out := &in.HostSelector, &out.HostSelector
+		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
-	in.Provisioning.DeepCopyInto(&out.Provisioning)
-	in.GoodCredentials.DeepCopyInto(&out.GoodCredentials)
-	in.TriedCredentials.DeepCopyInto(&out.TriedCredentials)
-	in.OperationHistory.DeepCopyInto(&out.OperationHistory)
+	in.Hardware.DeepCopyInto(&out.Hardware)
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BareMetalHostStatus.
-func (in *BareMetalHostStatus) DeepCopy() *BareMetalHostStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BareMetalHostClaimSpec.
+func (in *BareMetalHostClaimSpec) DeepCopy() *BareMetalHostClaimSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(BareMetalHostStatus)
+	out := new(BareMetalHostClaimSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CPU) DeepCopyInto(out *CPU) {
+func (in *BareMetalHostClaimStatus) DeepCopyInto(out *BareMetalHostClaimStatus) {
 	*out = *in
-	if in.Flags != nil {
-		in, out := &in.Flags, &out.Flags
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.BoundTime != nil {
+		in, out := &in.BoundTime, &out.BoundTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPU.
-func (in *CPU) DeepCopy() *CPU {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BareMetalHostClaimStatus.
+func (in *BareMetalHostClaimStatus) DeepCopy() *BareMetalHostClaimStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(CPU)
+	out := new(BareMetalHostClaimStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CredentialsStatus) DeepCopyInto(out *CredentialsStatus) {
+func (in *BareMetalHostList) DeepCopyInto(out *BareMetalHostList) {
 	*out = *in
-	if in.Reference != nil {
-		in, out := &in.Reference, &out.Reference
-		*out = new(v1.SecretReference)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BareMetalHost, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialsStatus.
-func (in *CredentialsStatus) DeepCopy() *CredentialsStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BareMetalHostList.
+func (in *BareMetalHostList) DeepCopy() *BareMetalHostList {
 	if in == nil {
 		return nil
 	}
-	out := new(CredentialsStatus)
+	out := new(BareMetalHostList)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CustomDeploy) DeepCopyInto(out *CustomDeploy) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomDeploy.
-func (in *CustomDeploy) DeepCopy() *CustomDeploy {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BareMetalHostList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(CustomDeploy)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataImage) DeepCopyInto(out *DataImage) {
+func (in *BareMetalHostOperation) DeepCopyInto(out *BareMetalHostOperation) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImage.
-func (in *DataImage) DeepCopy() *DataImage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BareMetalHostOperation.
+func (in *BareMetalHostOperation) DeepCopy() *BareMetalHostOperation {
 	if in == nil {
 		return nil
 	}
-	out := new(DataImage)
+	out := new(BareMetalHostOperation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DataImage) DeepCopyObject() runtime.Object {
+func (in *BareMetalHostOperation) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -404,46 +382,61 @@ func (in *DataImage) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataImageError) DeepCopyInto(out *DataImageError) {
+func (in *BareMetalHostOperationAction) DeepCopyInto(out *BareMetalHostOperationAction) {
 	*out = *in
+	if in.Reboot != nil {
+		in, out := &in.Reboot, &out.Reboot
+		*out = new(RebootOperation)
+		**out = **in
+	}
+	if in.FirmwareUpdate != nil {
+		in, out := &in.FirmwareUpdate, &out.FirmwareUpdate
+		*out = new(FirmwareUpdateOperation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reprovision != nil {
+		in, out := &in.Reprovision, &out.Reprovision
+		*out = new(ReprovisionOperation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImageError.
-func (in *DataImageError) DeepCopy() *DataImageError {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BareMetalHostOperationAction.
+func (in *BareMetalHostOperationAction) DeepCopy() *BareMetalHostOperationAction {
 	if in == nil {
 		return nil
 	}
-	out := new(DataImageError)
+	out := new(BareMetalHostOperationAction)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataImageList) DeepCopyInto(out *DataImageList) {
+func (in *BareMetalHostOperationList) DeepCopyInto(out *BareMetalHostOperationList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DataImage, len(*in))
+		*out = make([]BareMetalHostOperation, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImageList.
-func (in *DataImageList) DeepCopy() *DataImageList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BareMetalHostOperationList.
+func (in *BareMetalHostOperationList) DeepCopy() *BareMetalHostOperationList {
 	if in == nil {
 		return nil
 	}
-	out := new(DataImageList)
+	out := new(BareMetalHostOperationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DataImageList) DeepCopyObject() runtime.Object {
+func (in *BareMetalHostOperationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -451,159 +444,1248 @@ func (in *DataImageList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataImageSpec) DeepCopyInto(out *DataImageSpec) {
+func (in *BareMetalHostOperationSpec) DeepCopyInto(out *BareMetalHostOperationSpec) {
 	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImageSpec.
-func (in *DataImageSpec) DeepCopy() *DataImageSpec {
-	if in == nil {
-		return nil
+	in.HostSelector.DeepCopyInto(&out.HostSelector)
+	in.Action.DeepCopyInto(&out.Action)
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
 	}
-	out := new(DataImageSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataImageStatus) DeepCopyInto(out *DataImageStatus) {
-	*out = *in
-	if in.LastReconciled != nil {
-		in, out := &in.LastReconciled, &out.LastReconciled
-		*out = (*in).DeepCopy()
+	if in.HostTimeout != nil {
+		in, out := &in.HostTimeout, &out.HostTimeout
+		*out = new(metav1.Duration)
+		**out = **in
 	}
-	out.AttachedImage = in.AttachedImage
-	out.Error = in.Error
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImageStatus.
-func (in *DataImageStatus) DeepCopy() *DataImageStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BareMetalHostOperationSpec.
+func (in *BareMetalHostOperationSpec) DeepCopy() *BareMetalHostOperationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DataImageStatus)
+	out := new(BareMetalHostOperationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in DesiredSettingsMap) DeepCopyInto(out *DesiredSettingsMap) {
-	{
-		in := &in
-		*out = make(DesiredSettingsMap, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+func (in *BareMetalHostOperationStatus) DeepCopyInto(out *BareMetalHostOperationStatus) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]HostOperationProgress, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DesiredSettingsMap.
-func (in DesiredSettingsMap) DeepCopy() DesiredSettingsMap {
-	if in == nil {
-		return nil
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
 	}
-	out := new(DesiredSettingsMap)
-	in.DeepCopyInto(out)
-	return *out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DetachedAnnotationArguments) DeepCopyInto(out *DetachedAnnotationArguments) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DetachedAnnotationArguments.
-func (in *DetachedAnnotationArguments) DeepCopy() *DetachedAnnotationArguments {
-	if in == nil {
-		return nil
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
 	}
-	out := new(DetachedAnnotationArguments)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Firmware) DeepCopyInto(out *Firmware) {
-	*out = *in
-	out.BIOS = in.BIOS
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Firmware.
-func (in *Firmware) DeepCopy() *Firmware {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BareMetalHostOperationStatus.
+func (in *BareMetalHostOperationStatus) DeepCopy() *BareMetalHostOperationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Firmware)
+	out := new(BareMetalHostOperationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FirmwareComponentStatus) DeepCopyInto(out *FirmwareComponentStatus) {
+func (in *BareMetalHostSpec) DeepCopyInto(out *BareMetalHostSpec) {
+	*out = *in
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]v1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.BMC = in.BMC
+	if in.RAID != nil {
+		in, out := &in.RAID, &out.RAID
+		*out = new(RAIDConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Firmware != nil {
+		in, out := &in.Firmware, &out.Firmware
+		*out = new(FirmwareConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RootDeviceHints != nil {
+		in, out := &in.RootDeviceHints, &out.RootDeviceHints
+		*out = new(RootDeviceHints)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConsumerRef != nil {
+		in, out := &in.ConsumerRef, &out.ConsumerRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(Image)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UserData != nil {
+		in, out := &in.UserData, &out.UserData
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	if in.NetworkData != nil {
+		in, out := &in.NetworkData, &out.NetworkData
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	if in.MetaData != nil {
+		in, out := &in.MetaData, &out.MetaData
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	if in.PreservedDisks != nil {
+		in, out := &in.PreservedDisks, &out.PreservedDisks
+		*out = make([]RootDeviceHints, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CustomDeploy != nil {
+		in, out := &in.CustomDeploy, &out.CustomDeploy
+		*out = new(CustomDeploy)
+		**out = **in
+	}
+	if in.Rescue != nil {
+		in, out := &in.Rescue, &out.Rescue
+		*out = new(RescueConfig)
+		**out = **in
+	}
+	if in.BootDevice != nil {
+		in, out := &in.BootDevice, &out.BootDevice
+		*out = new(BootDeviceConfig)
+		**out = **in
+	}
+	if in.IdlePowerOff != nil {
+		in, out := &in.IdlePowerOff, &out.IdlePowerOff
+		*out = new(IdlePowerOffPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(RemediationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BareMetalHostSpec.
+func (in *BareMetalHostSpec) DeepCopy() *BareMetalHostSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BareMetalHostSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BareMetalHostStatus) DeepCopyInto(out *BareMetalHostStatus) {
+	*out = *in
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.HardwareDetails != nil {
+		in, out := &in.HardwareDetails, &out.HardwareDetails
+		*out = new(HardwareDetails)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Provisioning.DeepCopyInto(&out.Provisioning)
+	in.GoodCredentials.DeepCopyInto(&out.GoodCredentials)
+	in.TriedCredentials.DeepCopyInto(&out.TriedCredentials)
+	in.OperationHistory.DeepCopyInto(&out.OperationHistory)
+	if in.StateHistory != nil {
+		in, out := &in.StateHistory, &out.StateHistory
+		*out = make([]StateTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DiskErasure != nil {
+		in, out := &in.DiskErasure, &out.DiskErasure
+		*out = new(DiskErasure)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BootDevice != nil {
+		in, out := &in.BootDevice, &out.BootDevice
+		*out = new(BootDeviceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(RemediationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProvisioningQueue != nil {
+		in, out := &in.ProvisioningQueue, &out.ProvisioningQueue
+		*out = new(ProvisioningQueueStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IdleSince != nil {
+		in, out := &in.IdleSince, &out.IdleSince
+		*out = (*in).DeepCopy()
+	}
+	if in.IdlePoweredOffSince != nil {
+		in, out := &in.IdlePoweredOffSince, &out.IdlePoweredOffSince
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BareMetalHostStatus.
+func (in *BareMetalHostStatus) DeepCopy() *BareMetalHostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BareMetalHostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootDeviceConfig) DeepCopyInto(out *BootDeviceConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootDeviceConfig.
+func (in *BootDeviceConfig) DeepCopy() *BootDeviceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BootDeviceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootDeviceStatus) DeepCopyInto(out *BootDeviceStatus) {
+	*out = *in
+	if in.Requested != nil {
+		in, out := &in.Requested, &out.Requested
+		*out = new(BootDeviceConfig)
+		**out = **in
+	}
+	if in.LastApplied != nil {
+		in, out := &in.LastApplied, &out.LastApplied
+		*out = new(BootDeviceConfig)
+		**out = **in
+	}
+	if in.LastAppliedTime != nil {
+		in, out := &in.LastAppliedTime, &out.LastAppliedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootDeviceStatus.
+func (in *BootDeviceStatus) DeepCopy() *BootDeviceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BootDeviceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPU) DeepCopyInto(out *CPU) {
+	*out = *in
+	if in.Flags != nil {
+		in, out := &in.Flags, &out.Flags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPU.
+func (in *CPU) DeepCopy() *CPU {
+	if in == nil {
+		return nil
+	}
+	out := new(CPU)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPURule) DeepCopyInto(out *CPURule) {
+	*out = *in
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(IntRange)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPURule.
+func (in *CPURule) DeepCopy() *CPURule {
+	if in == nil {
+		return nil
+	}
+	out := new(CPURule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialsStatus) DeepCopyInto(out *CredentialsStatus) {
+	*out = *in
+	if in.Reference != nil {
+		in, out := &in.Reference, &out.Reference
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialsStatus.
+func (in *CredentialsStatus) DeepCopy() *CredentialsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomDeploy) DeepCopyInto(out *CustomDeploy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomDeploy.
+func (in *CustomDeploy) DeepCopy() *CustomDeploy {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomDeploy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataImage) DeepCopyInto(out *DataImage) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImage.
+func (in *DataImage) DeepCopy() *DataImage {
+	if in == nil {
+		return nil
+	}
+	out := new(DataImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataImage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataImageError) DeepCopyInto(out *DataImageError) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImageError.
+func (in *DataImageError) DeepCopy() *DataImageError {
+	if in == nil {
+		return nil
+	}
+	out := new(DataImageError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataImageList) DeepCopyInto(out *DataImageList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DataImage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImageList.
+func (in *DataImageList) DeepCopy() *DataImageList {
+	if in == nil {
+		return nil
+	}
+	out := new(DataImageList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataImageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataImageSpec) DeepCopyInto(out *DataImageSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImageSpec.
+func (in *DataImageSpec) DeepCopy() *DataImageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DataImageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataImageStatus) DeepCopyInto(out *DataImageStatus) {
+	*out = *in
+	if in.LastReconciled != nil {
+		in, out := &in.LastReconciled, &out.LastReconciled
+		*out = (*in).DeepCopy()
+	}
+	out.AttachedImage = in.AttachedImage
+	out.Error = in.Error
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImageStatus.
+func (in *DataImageStatus) DeepCopy() *DataImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in DesiredSettingsMap) DeepCopyInto(out *DesiredSettingsMap) {
+	{
+		in := &in
+		*out = make(DesiredSettingsMap, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DesiredSettingsMap.
+func (in DesiredSettingsMap) DeepCopy() DesiredSettingsMap {
+	if in == nil {
+		return nil
+	}
+	out := new(DesiredSettingsMap)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DetachedAnnotationArguments) DeepCopyInto(out *DetachedAnnotationArguments) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DetachedAnnotationArguments.
+func (in *DetachedAnnotationArguments) DeepCopy() *DetachedAnnotationArguments {
+	if in == nil {
+		return nil
+	}
+	out := new(DetachedAnnotationArguments)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskErasure) DeepCopyInto(out *DiskErasure) {
+	*out = *in
+	in.Started.DeepCopyInto(&out.Started)
+	if in.Finished != nil {
+		in, out := &in.Finished, &out.Finished
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreservedDisks != nil {
+		in, out := &in.PreservedDisks, &out.PreservedDisks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskErasure.
+func (in *DiskErasure) DeepCopy() *DiskErasure {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskErasure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskRequirements) DeepCopyInto(out *DiskRequirements) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskRequirements.
+func (in *DiskRequirements) DeepCopy() *DiskRequirements {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskRequirements)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskRule) DeepCopyInto(out *DiskRule) {
+	*out = *in
+	if in.SizeGigabytes != nil {
+		in, out := &in.SizeGigabytes, &out.SizeGigabytes
+		*out = new(IntRange)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(IntRange)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskRule.
+func (in *DiskRule) DeepCopy() *DiskRule {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Firmware) DeepCopyInto(out *Firmware) {
+	*out = *in
+	out.BIOS = in.BIOS
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Firmware.
+func (in *Firmware) DeepCopy() *Firmware {
+	if in == nil {
+		return nil
+	}
+	out := new(Firmware)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareComponentStatus) DeepCopyInto(out *FirmwareComponentStatus) {
 	*out = *in
 	in.UpdatedAt.DeepCopyInto(&out.UpdatedAt)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareComponentStatus.
-func (in *FirmwareComponentStatus) DeepCopy() *FirmwareComponentStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareComponentStatus.
+func (in *FirmwareComponentStatus) DeepCopy() *FirmwareComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareConfig) DeepCopyInto(out *FirmwareConfig) {
+	*out = *in
+	if in.VirtualizationEnabled != nil {
+		in, out := &in.VirtualizationEnabled, &out.VirtualizationEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SimultaneousMultithreadingEnabled != nil {
+		in, out := &in.SimultaneousMultithreadingEnabled, &out.SimultaneousMultithreadingEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SriovEnabled != nil {
+		in, out := &in.SriovEnabled, &out.SriovEnabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareConfig.
+func (in *FirmwareConfig) DeepCopy() *FirmwareConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareSchema) DeepCopyInto(out *FirmwareSchema) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareSchema.
+func (in *FirmwareSchema) DeepCopy() *FirmwareSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FirmwareSchema) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareSchemaList) DeepCopyInto(out *FirmwareSchemaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FirmwareSchema, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareSchemaList.
+func (in *FirmwareSchemaList) DeepCopy() *FirmwareSchemaList {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareSchemaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FirmwareSchemaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareSchemaSpec) DeepCopyInto(out *FirmwareSchemaSpec) {
+	*out = *in
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		*out = make(map[string]SettingSchema, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareSchemaSpec.
+func (in *FirmwareSchemaSpec) DeepCopy() *FirmwareSchemaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareSchemaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareUpdate) DeepCopyInto(out *FirmwareUpdate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareUpdate.
+func (in *FirmwareUpdate) DeepCopy() *FirmwareUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareUpdateOperation) DeepCopyInto(out *FirmwareUpdateOperation) {
+	*out = *in
+	if in.Updates != nil {
+		in, out := &in.Updates, &out.Updates
+		*out = make([]FirmwareUpdate, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareUpdateOperation.
+func (in *FirmwareUpdateOperation) DeepCopy() *FirmwareUpdateOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareUpdateOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareClassification) DeepCopyInto(out *HardwareClassification) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareClassification.
+func (in *HardwareClassification) DeepCopy() *HardwareClassification {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareClassification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HardwareClassification) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareClassificationList) DeepCopyInto(out *HardwareClassificationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HardwareClassification, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareClassificationList.
+func (in *HardwareClassificationList) DeepCopy() *HardwareClassificationList {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareClassificationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HardwareClassificationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareClassificationRules) DeepCopyInto(out *HardwareClassificationRules) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		*out = new(CPURule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RAMMebibytes != nil {
+		in, out := &in.RAMMebibytes, &out.RAMMebibytes
+		*out = new(IntRange)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Disk != nil {
+		in, out := &in.Disk, &out.Disk
+		*out = new(DiskRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NIC != nil {
+		in, out := &in.NIC, &out.NIC
+		*out = new(NICRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Vendor != nil {
+		in, out := &in.Vendor, &out.Vendor
+		*out = new(VendorRule)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareClassificationRules.
+func (in *HardwareClassificationRules) DeepCopy() *HardwareClassificationRules {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareClassificationRules)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareClassificationSpec) DeepCopyInto(out *HardwareClassificationSpec) {
+	*out = *in
+	if in.HostSelector != nil {
+		in, out := &in.HostSelector, &out.HostSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Rules.DeepCopyInto(&out.Rules)
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareClassificationSpec.
+func (in *HardwareClassificationSpec) DeepCopy() *HardwareClassificationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareClassificationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareClassificationStatus) DeepCopyInto(out *HardwareClassificationStatus) {
+	*out = *in
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make([]HostClassificationResult, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppliedLabels != nil {
+		in, out := &in.AppliedLabels, &out.AppliedLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareClassificationStatus.
+func (in *HardwareClassificationStatus) DeepCopy() *HardwareClassificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareClassificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareData) DeepCopyInto(out *HardwareData) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareData.
+func (in *HardwareData) DeepCopy() *HardwareData {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareData)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HardwareData) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareDataList) DeepCopyInto(out *HardwareDataList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HardwareData, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareDataList.
+func (in *HardwareDataList) DeepCopy() *HardwareDataList {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareDataList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HardwareDataList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareDataSpec) DeepCopyInto(out *HardwareDataSpec) {
+	*out = *in
+	if in.HardwareDetails != nil {
+		in, out := &in.HardwareDetails, &out.HardwareDetails
+		*out = new(HardwareDetails)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareDataSpec.
+func (in *HardwareDataSpec) DeepCopy() *HardwareDataSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareDataSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareDetails) DeepCopyInto(out *HardwareDetails) {
+	*out = *in
+	out.SystemVendor = in.SystemVendor
+	out.Firmware = in.Firmware
+	if in.MemoryModules != nil {
+		in, out := &in.MemoryModules, &out.MemoryModules
+		*out = make([]MemoryModule, len(*in))
+		copy(*out, *in)
+	}
+	if in.NIC != nil {
+		in, out := &in.NIC, &out.NIC
+		*out = make([]NIC, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = make([]Storage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.CPU.DeepCopyInto(&out.CPU)
+	if in.PCIDevices != nil {
+		in, out := &in.PCIDevices, &out.PCIDevices
+		*out = make([]PCIDevice, len(*in))
+		copy(*out, *in)
+	}
+	if in.Accelerators != nil {
+		in, out := &in.Accelerators, &out.Accelerators
+		*out = make([]Accelerator, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareDetails.
+func (in *HardwareDetails) DeepCopy() *HardwareDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareProfile) DeepCopyInto(out *HardwareProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareProfile.
+func (in *HardwareProfile) DeepCopy() *HardwareProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HardwareProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareProfileList) DeepCopyInto(out *HardwareProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HardwareProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareProfileList.
+func (in *HardwareProfileList) DeepCopy() *HardwareProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HardwareProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareProfileMatch) DeepCopyInto(out *HardwareProfileMatch) {
+	*out = *in
+	if in.DiskModels != nil {
+		in, out := &in.DiskModels, &out.DiskModels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareProfileMatch.
+func (in *HardwareProfileMatch) DeepCopy() *HardwareProfileMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareProfileMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareProfileSpec) DeepCopyInto(out *HardwareProfileSpec) {
+	*out = *in
+	in.Match.DeepCopyInto(&out.Match)
+	if in.RootDeviceHints != nil {
+		in, out := &in.RootDeviceHints, &out.RootDeviceHints
+		*out = new(RootDeviceHints)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RAID != nil {
+		in, out := &in.RAID, &out.RAID
+		*out = new(RAIDConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Firmware != nil {
+		in, out := &in.Firmware, &out.Firmware
+		*out = new(FirmwareConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IdlePowerOff != nil {
+		in, out := &in.IdlePowerOff, &out.IdlePowerOff
+		*out = new(IdlePowerOffPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(RemediationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareProfileSpec.
+func (in *HardwareProfileSpec) DeepCopy() *HardwareProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareRAIDVolume) DeepCopyInto(out *HardwareRAIDVolume) {
+	*out = *in
+	if in.SizeGibibytes != nil {
+		in, out := &in.SizeGibibytes, &out.SizeGibibytes
+		*out = new(int)
+		**out = **in
+	}
+	if in.Rotational != nil {
+		in, out := &in.Rotational, &out.Rotational
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NumberOfPhysicalDisks != nil {
+		in, out := &in.NumberOfPhysicalDisks, &out.NumberOfPhysicalDisks
+		*out = new(int)
+		**out = **in
+	}
+	if in.PhysicalDisks != nil {
+		in, out := &in.PhysicalDisks, &out.PhysicalDisks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareRAIDVolume.
+func (in *HardwareRAIDVolume) DeepCopy() *HardwareRAIDVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareRAIDVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareRequirements) DeepCopyInto(out *HardwareRequirements) {
+	*out = *in
+	if in.Disk != nil {
+		in, out := &in.Disk, &out.Disk
+		*out = new(DiskRequirements)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareRequirements.
+func (in *HardwareRequirements) DeepCopy() *HardwareRequirements {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareRequirements)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareSystemVendor) DeepCopyInto(out *HardwareSystemVendor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareSystemVendor.
+func (in *HardwareSystemVendor) DeepCopy() *HardwareSystemVendor {
 	if in == nil {
 		return nil
 	}
-	out := new(FirmwareComponentStatus)
+	out := new(HardwareSystemVendor)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FirmwareConfig) DeepCopyInto(out *FirmwareConfig) {
+func (in *HostClassificationResult) DeepCopyInto(out *HostClassificationResult) {
 	*out = *in
-	if in.VirtualizationEnabled != nil {
-		in, out := &in.VirtualizationEnabled, &out.VirtualizationEnabled
-		*out = new(bool)
-		**out = **in
-	}
-	if in.SimultaneousMultithreadingEnabled != nil {
-		in, out := &in.SimultaneousMultithreadingEnabled, &out.SimultaneousMultithreadingEnabled
-		*out = new(bool)
-		**out = **in
-	}
-	if in.SriovEnabled != nil {
-		in, out := &in.SriovEnabled, &out.SriovEnabled
-		*out = new(bool)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareConfig.
-func (in *FirmwareConfig) DeepCopy() *FirmwareConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostClassificationResult.
+func (in *HostClassificationResult) DeepCopy() *HostClassificationResult {
 	if in == nil {
 		return nil
 	}
-	out := new(FirmwareConfig)
+	out := new(HostClassificationResult)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FirmwareSchema) DeepCopyInto(out *FirmwareSchema) {
+func (in *HostFirmwareComponents) DeepCopyInto(out *HostFirmwareComponents) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareSchema.
-func (in *FirmwareSchema) DeepCopy() *FirmwareSchema {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareComponents.
+func (in *HostFirmwareComponents) DeepCopy() *HostFirmwareComponents {
 	if in == nil {
 		return nil
 	}
-	out := new(FirmwareSchema)
+	out := new(HostFirmwareComponents)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FirmwareSchema) DeepCopyObject() runtime.Object {
+func (in *HostFirmwareComponents) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -611,31 +1693,31 @@ func (in *FirmwareSchema) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FirmwareSchemaList) DeepCopyInto(out *FirmwareSchemaList) {
+func (in *HostFirmwareComponentsList) DeepCopyInto(out *HostFirmwareComponentsList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]FirmwareSchema, len(*in))
+		*out = make([]HostFirmwareComponents, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareSchemaList.
-func (in *FirmwareSchemaList) DeepCopy() *FirmwareSchemaList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareComponentsList.
+func (in *HostFirmwareComponentsList) DeepCopy() *HostFirmwareComponentsList {
 	if in == nil {
 		return nil
 	}
-	out := new(FirmwareSchemaList)
+	out := new(HostFirmwareComponentsList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FirmwareSchemaList) DeepCopyObject() runtime.Object {
+func (in *HostFirmwareComponentsList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -643,62 +1725,84 @@ func (in *FirmwareSchemaList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FirmwareSchemaSpec) DeepCopyInto(out *FirmwareSchemaSpec) {
+func (in *HostFirmwareComponentsSpec) DeepCopyInto(out *HostFirmwareComponentsSpec) {
 	*out = *in
-	if in.Schema != nil {
-		in, out := &in.Schema, &out.Schema
-		*out = make(map[string]SettingSchema, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
+	if in.Updates != nil {
+		in, out := &in.Updates, &out.Updates
+		*out = make([]FirmwareUpdate, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareSchemaSpec.
-func (in *FirmwareSchemaSpec) DeepCopy() *FirmwareSchemaSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareComponentsSpec.
+func (in *HostFirmwareComponentsSpec) DeepCopy() *HostFirmwareComponentsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(FirmwareSchemaSpec)
+	out := new(HostFirmwareComponentsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FirmwareUpdate) DeepCopyInto(out *FirmwareUpdate) {
+func (in *HostFirmwareComponentsStatus) DeepCopyInto(out *HostFirmwareComponentsStatus) {
 	*out = *in
+	if in.Updates != nil {
+		in, out := &in.Updates, &out.Updates
+		*out = make([]FirmwareUpdate, len(*in))
+		copy(*out, *in)
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]FirmwareComponentStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareUpdate.
-func (in *FirmwareUpdate) DeepCopy() *FirmwareUpdate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareComponentsStatus.
+func (in *HostFirmwareComponentsStatus) DeepCopy() *HostFirmwareComponentsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(FirmwareUpdate)
+	out := new(HostFirmwareComponentsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HardwareData) DeepCopyInto(out *HardwareData) {
+func (in *HostFirmwareSettings) DeepCopyInto(out *HostFirmwareSettings) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareData.
-func (in *HardwareData) DeepCopy() *HardwareData {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareSettings.
+func (in *HostFirmwareSettings) DeepCopy() *HostFirmwareSettings {
 	if in == nil {
 		return nil
 	}
-	out := new(HardwareData)
+	out := new(HostFirmwareSettings)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HardwareData) DeepCopyObject() runtime.Object {
+func (in *HostFirmwareSettings) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -706,141 +1810,297 @@ func (in *HardwareData) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HardwareDataList) DeepCopyInto(out *HardwareDataList) {
+func (in *HostFirmwareSettingsList) DeepCopyInto(out *HostFirmwareSettingsList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]HardwareData, len(*in))
+		*out = make([]HostFirmwareSettings, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareSettingsList.
+func (in *HostFirmwareSettingsList) DeepCopy() *HostFirmwareSettingsList {
+	if in == nil {
+		return nil
+	}
+	out := new(HostFirmwareSettingsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HostFirmwareSettingsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostFirmwareSettingsSpec) DeepCopyInto(out *HostFirmwareSettingsSpec) {
+	*out = *in
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(DesiredSettingsMap, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareSettingsSpec.
+func (in *HostFirmwareSettingsSpec) DeepCopy() *HostFirmwareSettingsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostFirmwareSettingsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostFirmwareSettingsStatus) DeepCopyInto(out *HostFirmwareSettingsStatus) {
+	*out = *in
+	if in.FirmwareSchema != nil {
+		in, out := &in.FirmwareSchema, &out.FirmwareSchema
+		*out = new(SchemaReference)
+		**out = **in
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(SettingsMap, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareSettingsStatus.
+func (in *HostFirmwareSettingsStatus) DeepCopy() *HostFirmwareSettingsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HostFirmwareSettingsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostOperationProgress) DeepCopyInto(out *HostOperationProgress) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostOperationProgress.
+func (in *HostOperationProgress) DeepCopy() *HostOperationProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(HostOperationProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdlePowerOffPolicy) DeepCopyInto(out *IdlePowerOffPolicy) {
+	*out = *in
+	out.After = in.After
+	if in.PowerOnWindows != nil {
+		in, out := &in.PowerOnWindows, &out.PowerOnWindows
+		*out = make([]PowerWindow, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareDataList.
-func (in *HardwareDataList) DeepCopy() *HardwareDataList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdlePowerOffPolicy.
+func (in *IdlePowerOffPolicy) DeepCopy() *IdlePowerOffPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(IdlePowerOffPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Image) DeepCopyInto(out *Image) {
+	*out = *in
+	if in.DiskFormat != nil {
+		in, out := &in.DiskFormat, &out.DiskFormat
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Image.
+func (in *Image) DeepCopy() *Image {
+	if in == nil {
+		return nil
+	}
+	out := new(Image)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntRange) DeepCopyInto(out *IntRange) {
+	*out = *in
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		*out = new(int)
+		**out = **in
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntRange.
+func (in *IntRange) DeepCopy() *IntRange {
 	if in == nil {
 		return nil
 	}
-	out := new(HardwareDataList)
+	out := new(IntRange)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HardwareDataList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LLDP) DeepCopyInto(out *LLDP) {
+	*out = *in
+	if in.SwitchManagementAddresses != nil {
+		in, out := &in.SwitchManagementAddresses, &out.SwitchManagementAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LLDP.
+func (in *LLDP) DeepCopy() *LLDP {
+	if in == nil {
+		return nil
+	}
+	out := new(LLDP)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HardwareDataSpec) DeepCopyInto(out *HardwareDataSpec) {
+func (in *MemoryModule) DeepCopyInto(out *MemoryModule) {
 	*out = *in
-	if in.HardwareDetails != nil {
-		in, out := &in.HardwareDetails, &out.HardwareDetails
-		*out = new(HardwareDetails)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareDataSpec.
-func (in *HardwareDataSpec) DeepCopy() *HardwareDataSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryModule.
+func (in *MemoryModule) DeepCopy() *MemoryModule {
 	if in == nil {
 		return nil
 	}
-	out := new(HardwareDataSpec)
+	out := new(MemoryModule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HardwareDetails) DeepCopyInto(out *HardwareDetails) {
+func (in *NIC) DeepCopyInto(out *NIC) {
 	*out = *in
-	out.SystemVendor = in.SystemVendor
-	out.Firmware = in.Firmware
-	if in.NIC != nil {
-		in, out := &in.NIC, &out.NIC
-		*out = make([]NIC, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.VLANs != nil {
+		in, out := &in.VLANs, &out.VLANs
+		*out = make([]VLAN, len(*in))
+		copy(*out, *in)
 	}
-	if in.Storage != nil {
-		in, out := &in.Storage, &out.Storage
-		*out = make([]Storage, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.LLDP != nil {
+		in, out := &in.LLDP, &out.LLDP
+		*out = new(LLDP)
+		(*in).DeepCopyInto(*out)
 	}
-	in.CPU.DeepCopyInto(&out.CPU)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareDetails.
-func (in *HardwareDetails) DeepCopy() *HardwareDetails {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NIC.
+func (in *NIC) DeepCopy() *NIC {
 	if in == nil {
 		return nil
 	}
-	out := new(HardwareDetails)
+	out := new(NIC)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HardwareRAIDVolume) DeepCopyInto(out *HardwareRAIDVolume) {
+func (in *NICRule) DeepCopyInto(out *NICRule) {
 	*out = *in
-	if in.SizeGibibytes != nil {
-		in, out := &in.SizeGibibytes, &out.SizeGibibytes
-		*out = new(int)
-		**out = **in
-	}
-	if in.Rotational != nil {
-		in, out := &in.Rotational, &out.Rotational
-		*out = new(bool)
-		**out = **in
-	}
-	if in.NumberOfPhysicalDisks != nil {
-		in, out := &in.NumberOfPhysicalDisks, &out.NumberOfPhysicalDisks
-		*out = new(int)
-		**out = **in
-	}
-	if in.PhysicalDisks != nil {
-		in, out := &in.PhysicalDisks, &out.PhysicalDisks
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(IntRange)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareRAIDVolume.
-func (in *HardwareRAIDVolume) DeepCopy() *HardwareRAIDVolume {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NICRule.
+func (in *NICRule) DeepCopy() *NICRule {
 	if in == nil {
 		return nil
 	}
-	out := new(HardwareRAIDVolume)
+	out := new(NICRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HardwareSystemVendor) DeepCopyInto(out *HardwareSystemVendor) {
+func (in *NetworkSwitch) DeepCopyInto(out *NetworkSwitch) {
 	*out = *in
+	if in.ManagementAddresses != nil {
+		in, out := &in.ManagementAddresses, &out.ManagementAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]SwitchPort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareSystemVendor.
-func (in *HardwareSystemVendor) DeepCopy() *HardwareSystemVendor {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSwitch.
+func (in *NetworkSwitch) DeepCopy() *NetworkSwitch {
 	if in == nil {
 		return nil
 	}
-	out := new(HardwareSystemVendor)
+	out := new(NetworkSwitch)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostFirmwareComponents) DeepCopyInto(out *HostFirmwareComponents) {
+func (in *NetworkTopology) DeepCopyInto(out *NetworkTopology) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -848,18 +2108,18 @@ func (in *HostFirmwareComponents) DeepCopyInto(out *HostFirmwareComponents) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareComponents.
-func (in *HostFirmwareComponents) DeepCopy() *HostFirmwareComponents {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkTopology.
+func (in *NetworkTopology) DeepCopy() *NetworkTopology {
 	if in == nil {
 		return nil
 	}
-	out := new(HostFirmwareComponents)
+	out := new(NetworkTopology)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HostFirmwareComponents) DeepCopyObject() runtime.Object {
+func (in *NetworkTopology) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -867,31 +2127,31 @@ func (in *HostFirmwareComponents) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostFirmwareComponentsList) DeepCopyInto(out *HostFirmwareComponentsList) {
+func (in *NetworkTopologyList) DeepCopyInto(out *NetworkTopologyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]HostFirmwareComponents, len(*in))
+		*out = make([]NetworkTopology, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareComponentsList.
-func (in *HostFirmwareComponentsList) DeepCopy() *HostFirmwareComponentsList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkTopologyList.
+func (in *NetworkTopologyList) DeepCopy() *NetworkTopologyList {
 	if in == nil {
 		return nil
 	}
-	out := new(HostFirmwareComponentsList)
+	out := new(NetworkTopologyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HostFirmwareComponentsList) DeepCopyObject() runtime.Object {
+func (in *NetworkTopologyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -899,254 +2159,215 @@ func (in *HostFirmwareComponentsList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostFirmwareComponentsSpec) DeepCopyInto(out *HostFirmwareComponentsSpec) {
+func (in *NetworkTopologySpec) DeepCopyInto(out *NetworkTopologySpec) {
 	*out = *in
-	if in.Updates != nil {
-		in, out := &in.Updates, &out.Updates
-		*out = make([]FirmwareUpdate, len(*in))
-		copy(*out, *in)
+	if in.HostSelector != nil {
+		in, out := &in.HostSelector, &out.HostSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareComponentsSpec.
-func (in *HostFirmwareComponentsSpec) DeepCopy() *HostFirmwareComponentsSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkTopologySpec.
+func (in *NetworkTopologySpec) DeepCopy() *NetworkTopologySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HostFirmwareComponentsSpec)
+	out := new(NetworkTopologySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostFirmwareComponentsStatus) DeepCopyInto(out *HostFirmwareComponentsStatus) {
+func (in *NetworkTopologyStatus) DeepCopyInto(out *NetworkTopologyStatus) {
 	*out = *in
-	if in.Updates != nil {
-		in, out := &in.Updates, &out.Updates
-		*out = make([]FirmwareUpdate, len(*in))
-		copy(*out, *in)
-	}
-	if in.Components != nil {
-		in, out := &in.Components, &out.Components
-		*out = make([]FirmwareComponentStatus, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.LastUpdated != nil {
-		in, out := &in.LastUpdated, &out.LastUpdated
-		*out = (*in).DeepCopy()
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+	if in.Switches != nil {
+		in, out := &in.Switches, &out.Switches
+		*out = make([]NetworkSwitch, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareComponentsStatus.
-func (in *HostFirmwareComponentsStatus) DeepCopy() *HostFirmwareComponentsStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkTopologyStatus.
+func (in *NetworkTopologyStatus) DeepCopy() *NetworkTopologyStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HostFirmwareComponentsStatus)
+	out := new(NetworkTopologyStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostFirmwareSettings) DeepCopyInto(out *HostFirmwareSettings) {
+func (in *OperationHistory) DeepCopyInto(out *OperationHistory) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	in.Register.DeepCopyInto(&out.Register)
+	in.Inspect.DeepCopyInto(&out.Inspect)
+	in.Provision.DeepCopyInto(&out.Provision)
+	in.Deprovision.DeepCopyInto(&out.Deprovision)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareSettings.
-func (in *HostFirmwareSettings) DeepCopy() *HostFirmwareSettings {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationHistory.
+func (in *OperationHistory) DeepCopy() *OperationHistory {
 	if in == nil {
 		return nil
 	}
-	out := new(HostFirmwareSettings)
+	out := new(OperationHistory)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HostFirmwareSettings) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostFirmwareSettingsList) DeepCopyInto(out *HostFirmwareSettingsList) {
+func (in *OperationMetric) DeepCopyInto(out *OperationMetric) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]HostFirmwareSettings, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareSettingsList.
-func (in *HostFirmwareSettingsList) DeepCopy() *HostFirmwareSettingsList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationMetric.
+func (in *OperationMetric) DeepCopy() *OperationMetric {
 	if in == nil {
 		return nil
 	}
-	out := new(HostFirmwareSettingsList)
+	out := new(OperationMetric)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HostFirmwareSettingsList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostFirmwareSettingsSpec) DeepCopyInto(out *HostFirmwareSettingsSpec) {
+func (in *PCIDevice) DeepCopyInto(out *PCIDevice) {
 	*out = *in
-	if in.Settings != nil {
-		in, out := &in.Settings, &out.Settings
-		*out = make(DesiredSettingsMap, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareSettingsSpec.
-func (in *HostFirmwareSettingsSpec) DeepCopy() *HostFirmwareSettingsSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PCIDevice.
+func (in *PCIDevice) DeepCopy() *PCIDevice {
 	if in == nil {
 		return nil
 	}
-	out := new(HostFirmwareSettingsSpec)
+	out := new(PCIDevice)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostFirmwareSettingsStatus) DeepCopyInto(out *HostFirmwareSettingsStatus) {
+func (in *PowerOperation) DeepCopyInto(out *PowerOperation) {
 	*out = *in
-	if in.FirmwareSchema != nil {
-		in, out := &in.FirmwareSchema, &out.FirmwareSchema
-		*out = new(SchemaReference)
-		**out = **in
-	}
-	if in.Settings != nil {
-		in, out := &in.Settings, &out.Settings
-		*out = make(SettingsMap, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.LastUpdated != nil {
-		in, out := &in.LastUpdated, &out.LastUpdated
-		*out = (*in).DeepCopy()
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostFirmwareSettingsStatus.
-func (in *HostFirmwareSettingsStatus) DeepCopy() *HostFirmwareSettingsStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerOperation.
+func (in *PowerOperation) DeepCopy() *PowerOperation {
 	if in == nil {
 		return nil
 	}
-	out := new(HostFirmwareSettingsStatus)
+	out := new(PowerOperation)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PowerOperation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Image) DeepCopyInto(out *Image) {
+func (in *PowerOperationList) DeepCopyInto(out *PowerOperationList) {
 	*out = *in
-	if in.DiskFormat != nil {
-		in, out := &in.DiskFormat, &out.DiskFormat
-		*out = new(string)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PowerOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Image.
-func (in *Image) DeepCopy() *Image {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerOperationList.
+func (in *PowerOperationList) DeepCopy() *PowerOperationList {
 	if in == nil {
 		return nil
 	}
-	out := new(Image)
-	in.DeepCopyInto(out)
-	return out
+	out := new(PowerOperationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PowerOperationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NIC) DeepCopyInto(out *NIC) {
+func (in *PowerOperationSpec) DeepCopyInto(out *PowerOperationSpec) {
 	*out = *in
-	if in.VLANs != nil {
-		in, out := &in.VLANs, &out.VLANs
-		*out = make([]VLAN, len(*in))
-		copy(*out, *in)
+	if in.Deadline != nil {
+		in, out := &in.Deadline, &out.Deadline
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NIC.
-func (in *NIC) DeepCopy() *NIC {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerOperationSpec.
+func (in *PowerOperationSpec) DeepCopy() *PowerOperationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NIC)
+	out := new(PowerOperationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperationHistory) DeepCopyInto(out *OperationHistory) {
+func (in *PowerOperationStatus) DeepCopyInto(out *PowerOperationStatus) {
 	*out = *in
-	in.Register.DeepCopyInto(&out.Register)
-	in.Inspect.DeepCopyInto(&out.Inspect)
-	in.Provision.DeepCopyInto(&out.Provision)
-	in.Deprovision.DeepCopyInto(&out.Deprovision)
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationHistory.
-func (in *OperationHistory) DeepCopy() *OperationHistory {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerOperationStatus.
+func (in *PowerOperationStatus) DeepCopy() *PowerOperationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(OperationHistory)
+	out := new(PowerOperationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperationMetric) DeepCopyInto(out *OperationMetric) {
+func (in *PowerWindow) DeepCopyInto(out *PowerWindow) {
 	*out = *in
-	in.Start.DeepCopyInto(&out.Start)
-	in.End.DeepCopyInto(&out.End)
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]Weekday, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationMetric.
-func (in *OperationMetric) DeepCopy() *OperationMetric {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerWindow.
+func (in *PowerWindow) DeepCopy() *PowerWindow {
 	if in == nil {
 		return nil
 	}
-	out := new(OperationMetric)
+	out := new(PowerWindow)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1289,6 +2510,132 @@ func (in *ProvisionStatus) DeepCopy() *ProvisionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningConcurrencyBucket) DeepCopyInto(out *ProvisioningConcurrencyBucket) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostSelector != nil {
+		in, out := &in.HostSelector, &out.HostSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BMCDrivers != nil {
+		in, out := &in.BMCDrivers, &out.BMCDrivers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningConcurrencyBucket.
+func (in *ProvisioningConcurrencyBucket) DeepCopy() *ProvisioningConcurrencyBucket {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisioningConcurrencyBucket)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningConcurrencyPolicy) DeepCopyInto(out *ProvisioningConcurrencyPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningConcurrencyPolicy.
+func (in *ProvisioningConcurrencyPolicy) DeepCopy() *ProvisioningConcurrencyPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisioningConcurrencyPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProvisioningConcurrencyPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningConcurrencyPolicyList) DeepCopyInto(out *ProvisioningConcurrencyPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProvisioningConcurrencyPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningConcurrencyPolicyList.
+func (in *ProvisioningConcurrencyPolicyList) DeepCopy() *ProvisioningConcurrencyPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisioningConcurrencyPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProvisioningConcurrencyPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningConcurrencyPolicySpec) DeepCopyInto(out *ProvisioningConcurrencyPolicySpec) {
+	*out = *in
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]ProvisioningConcurrencyBucket, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningConcurrencyPolicySpec.
+func (in *ProvisioningConcurrencyPolicySpec) DeepCopy() *ProvisioningConcurrencyPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisioningConcurrencyPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningQueueStatus) DeepCopyInto(out *ProvisioningQueueStatus) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningQueueStatus.
+func (in *ProvisioningQueueStatus) DeepCopy() *ProvisioningQueueStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisioningQueueStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RAIDConfig) DeepCopyInto(out *RAIDConfig) {
 	*out = *in
@@ -1333,6 +2680,125 @@ func (in *RebootAnnotationArguments) DeepCopy() *RebootAnnotationArguments {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RebootOperation) DeepCopyInto(out *RebootOperation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RebootOperation.
+func (in *RebootOperation) DeepCopy() *RebootOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(RebootOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationAttempt) DeepCopyInto(out *RemediationAttempt) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationAttempt.
+func (in *RemediationAttempt) DeepCopy() *RemediationAttempt {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationAttempt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationPolicy) DeepCopyInto(out *RemediationPolicy) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]RemediationRule, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationPolicy.
+func (in *RemediationPolicy) DeepCopy() *RemediationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationRule) DeepCopyInto(out *RemediationRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationRule.
+func (in *RemediationRule) DeepCopy() *RemediationRule {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationStatus) DeepCopyInto(out *RemediationStatus) {
+	*out = *in
+	if in.Attempts != nil {
+		in, out := &in.Attempts, &out.Attempts
+		*out = make([]RemediationAttempt, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationStatus.
+func (in *RemediationStatus) DeepCopy() *RemediationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReprovisionOperation) DeepCopyInto(out *ReprovisionOperation) {
+	*out = *in
+	in.Image.DeepCopyInto(&out.Image)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReprovisionOperation.
+func (in *ReprovisionOperation) DeepCopy() *ReprovisionOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(ReprovisionOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RescueConfig) DeepCopyInto(out *RescueConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RescueConfig.
+func (in *RescueConfig) DeepCopy() *RescueConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RescueConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RootDeviceHints) DeepCopyInto(out *RootDeviceHints) {
 	*out = *in
@@ -1496,6 +2962,22 @@ func (in *SoftwareRAIDVolume) DeepCopy() *SoftwareRAIDVolume {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StateTransition) DeepCopyInto(out *StateTransition) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateTransition.
+func (in *StateTransition) DeepCopy() *StateTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(StateTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Storage) DeepCopyInto(out *Storage) {
 	*out = *in
@@ -1516,6 +2998,41 @@ func (in *Storage) DeepCopy() *Storage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchPort) DeepCopyInto(out *SwitchPort) {
+	*out = *in
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = make([]SwitchPortConnection, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchPort.
+func (in *SwitchPort) DeepCopy() *SwitchPort {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchPortConnection) DeepCopyInto(out *SwitchPortConnection) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchPortConnection.
+func (in *SwitchPortConnection) DeepCopy() *SwitchPortConnection {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchPortConnection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VLAN) DeepCopyInto(out *VLAN) {
 	*out = *in
@@ -1530,3 +3047,18 @@ func (in *VLAN) DeepCopy() *VLAN {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VendorRule) DeepCopyInto(out *VendorRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VendorRule.
+func (in *VendorRule) DeepCopy() *VendorRule {
+	if in == nil {
+		return nil
+	}
+	out := new(VendorRule)
+	in.DeepCopyInto(out)
+	return out
+}
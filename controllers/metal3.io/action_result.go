@@ -44,6 +44,21 @@ func (r actionContinue) Dirty() bool {
 	return false
 }
 
+// actionWait is a result indicating that the host is in a steady state
+// and that the resource need not be requeued, as any change will trigger
+// a new reconcile.
+type actionWait struct {
+	dirty bool
+}
+
+func (r actionWait) Result() (result reconcile.Result, err error) {
+	return
+}
+
+func (r actionWait) Dirty() bool {
+	return r.dirty
+}
+
 // actionUpdate is a result indicating that the current action is still
 // in progress, and that the resource should remain in the same provisioning
 // state but write new Status data.
@@ -44,7 +44,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
@@ -53,8 +56,16 @@ const (
 	preprovImageRetryDelay        = time.Minute * 5
 	provisionerNotReadyRetryDelay = time.Second * 30
 	subResourceNotReadyRetryDelay = time.Second * 60
-	clarifySoftPoweroffFailure    = "Continuing with hard poweroff after soft poweroff fails. More details: "
-	hardwareDataFinalizer         = metal3api.BareMetalHostFinalizer + "/hardwareData"
+	// Deadlines for single calls to the provisioner. A call only submits
+	// or polls an operation in the backend, so these bound how long an
+	// unresponsive backend can block a reconcile worker.
+	provisionerCallTimeout     = time.Minute
+	provisionerRegisterTimeout = time.Minute * 3
+	provisionerInspectTimeout  = time.Minute * 2
+	provisionerCapacityTimeout = time.Minute * 2
+
+	clarifySoftPoweroffFailure = "Continuing with hard poweroff after soft poweroff fails. More details: "
+	hardwareDataFinalizer      = metal3api.BareMetalHostFinalizer + "/hardwareData"
 )
 
 // BareMetalHostReconciler reconciles a BareMetalHost object.
@@ -63,6 +74,10 @@ type BareMetalHostReconciler struct {
 	Log                logr.Logger
 	ProvisionerFactory provisioner.Factory
 	APIReader          client.Reader
+
+	// powerPoller monitors the power state of the hosts whose backend
+	// supports it, or is nil if none does.
+	powerPoller *powerStatePoller
 }
 
 // Instead of passing a zillion arguments to the action of a phase,
@@ -75,6 +90,7 @@ type reconcileInfo struct {
 	bmcCredsSecret    *corev1.Secret
 	events            []corev1.Event
 	postSaveCallbacks []func()
+	profiles          *hardwareProfiles
 }
 
 // match the provisioner.EventPublisher interface.
@@ -82,12 +98,23 @@ func (info *reconcileInfo) publishEvent(reason, message string) {
 	info.events = append(info.events, info.host.NewEvent(reason, message))
 }
 
+// provisionerContext returns the context for calls to the provisioner
+// from the current action. It is cancelled once the timeout expires or
+// the reconcile is abandoned.
+func (info *reconcileInfo) provisionerContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(info.ctx, timeout)
+}
+
 // +kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts/finalizers,verbs=update
 // +kubebuilder:rbac:groups=metal3.io,resources=preprovisioningimages,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=metal3.io,resources=hardwaredata,verbs=get;list;watch;create;delete;patch;update
 // +kubebuilder:rbac:groups=metal3.io,resources=hardware/finalizers,verbs=update
+// +kubebuilder:rbac:groups=metal3.io,resources=hardwareprofiles,verbs=get;list;watch
+// +kubebuilder:rbac:groups=metal3.io,resources=poweroperations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=metal3.io,resources=poweroperations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=metal3.io,resources=provisioningconcurrencypolicies,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
 
@@ -122,6 +149,7 @@ func (r *BareMetalHostReconciler) Reconcile(ctx context.Context, request ctrl.Re
 			// reconcile request.  Owned objects are automatically
 			// garbage collected. For additional cleanup logic use
 			// finalizers.  Return and don't requeue
+			forgetIdlePowerOff(request)
 			return ctrl.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -204,14 +232,17 @@ func (r *BareMetalHostReconciler) Reconcile(ctx context.Context, request ctrl.Re
 		host:           host,
 		request:        request,
 		bmcCredsSecret: bmcCredsSecret,
+		profiles:       &hardwareProfiles{ctx: ctx, client: r.Client},
 	}
 
 	prov, err := r.ProvisionerFactory.NewProvisioner(ctx, provisioner.BuildHostData(*host, *bmcCreds), info.publishEvent)
 	if err != nil {
-		return ctrl.Result{}, errors.Wrap(err, "failed to create provisioner")
+		return r.provisionerErrorResult(ctx, err, request, host)
 	}
 
-	ready, err := prov.TryInit()
+	tryInitCtx, cancel := context.WithTimeout(ctx, provisionerCallTimeout)
+	ready, err := prov.TryInit(tryInitCtx)
+	cancel()
 	if err != nil {
 		return ctrl.Result{}, errors.Wrap(err, "failed to check services availability")
 	}
@@ -221,6 +252,15 @@ func (r *BareMetalHostReconciler) Reconcile(ctx context.Context, request ctrl.Re
 		return ctrl.Result{Requeue: true, RequeueAfter: provisionerNotReadyRetryDelay}, nil
 	}
 
+	if reporter, ok := prov.(provisioner.EndpointReporter); ok && reporter.Endpoint() != host.Status.Provisioning.Endpoint {
+		info.log.Info("assigned to provisioner endpoint", "endpoint", reporter.Endpoint(),
+			"previousEndpoint", host.Status.Provisioning.Endpoint)
+		host.Status.Provisioning.Endpoint = reporter.Endpoint()
+		if err = r.saveHostStatus(ctx, host); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to save provisioner endpoint")
+		}
+	}
+
 	stateMachine := newHostStateMachine(host, r, prov, haveCreds)
 	actResult := stateMachine.ReconcileState(info)
 	result, err = actResult.Result()
@@ -230,11 +270,19 @@ func (r *BareMetalHostReconciler) Reconcile(ctx context.Context, request ctrl.Re
 		return result, err
 	}
 
+	// The conditions are derived from the rest of the status, so they
+	// only change along with it or with the generation of the host.
+	wasTainted := hostTainted(host)
+	conditionsChanged := updateHostConditions(host)
+	if hostTainted(host) && !wasTainted {
+		reportTaint(info)
+	}
+
 	// Only save status when we're told to, otherwise we
 	// introduce an infinite loop reconciling the same object over and
 	// over when there is an unrecoverable error (tracked through the
 	// error state of the host).
-	if actResult.Dirty() {
+	if actResult.Dirty() || conditionsChanged {
 		// Save Host
 		info.log.Info("saving host status",
 			"operational status", host.OperationalStatus(),
@@ -318,6 +366,8 @@ func recordActionFailure(info *reconcileInfo, errorType metal3api.ErrorType, err
 		metal3api.InspectionError:              "InspectionError",
 		metal3api.ProvisioningError:            "ProvisioningError",
 		metal3api.PowerManagementError:         "PowerManagementError",
+		metal3api.ServicingError:               "ServicingError",
+		metal3api.RescueError:                  "RescueError",
 	}[errorType]
 
 	counter := actionFailureCounters.WithLabelValues(eventType)
@@ -328,7 +378,7 @@ func recordActionFailure(info *reconcileInfo, errorType metal3api.ErrorType, err
 	return actionFailed{dirty: true, ErrorType: errorType, errorCount: info.host.Status.ErrorCount}
 }
 
-func recordActionDelayed(info *reconcileInfo, state metal3api.ProvisioningState) actionResult {
+func recordActionDelayed(info *reconcileInfo, state metal3api.ProvisioningState, bucket string) actionResult {
 	var counter prometheus.Counter
 
 	if state == metal3api.StateDeprovisioning {
@@ -337,7 +387,13 @@ func recordActionDelayed(info *reconcileInfo, state metal3api.ProvisioningState)
 		counter = delayedProvisioningHostCounters.With(hostMetricLabels(info.request))
 	}
 
-	info.postSaveCallbacks = append(info.postSaveCallbacks, counter.Inc)
+	info.postSaveCallbacks = append(info.postSaveCallbacks, counter.Inc,
+		delayedHostBucketCounters.WithLabelValues(bucket).Inc)
+
+	info.log.Info("host delayed, no capacity left", "bucket", bucket)
+	if info.host.Status.OperationalStatus != metal3api.OperationalStatusDelayed {
+		info.publishEvent("Delayed", fmt.Sprintf("Waiting for capacity in bucket %s", bucket))
+	}
 
 	info.host.SetOperationalStatus(metal3api.OperationalStatusDelayed)
 	return actionDelayed{}
@@ -380,6 +436,30 @@ func (r *BareMetalHostReconciler) credentialsErrorResult(ctx context.Context, er
 	}
 }
 
+// provisionerErrorResult records on the host why no provisioner can be
+// created for it, when that needs a change of the host or of the
+// operator configuration to be fixed.
+func (r *BareMetalHostReconciler) provisionerErrorResult(ctx context.Context, err error, request ctrl.Request, host *metal3api.BareMetalHost) (ctrl.Result, error) {
+	var unknownBackend provisioner.UnknownBackendError
+	var unknownEndpoint provisioner.UnknownEndpointError
+	if !errors.As(err, &unknownBackend) && !errors.As(err, &unknownEndpoint) {
+		return ctrl.Result{}, errors.Wrap(err, "failed to create provisioner")
+	}
+
+	// The host is reconciled again once it is changed or the operator
+	// restarted with a new configuration, so the error is only recorded
+	// once.
+	if host.Status.ErrorType == metal3api.RegistrationError && host.Status.ErrorMessage == err.Error() {
+		return ctrl.Result{}, nil
+	}
+	saveErr := r.setErrorCondition(ctx, request, host, metal3api.RegistrationError, err.Error())
+	if saveErr != nil {
+		return ctrl.Result{Requeue: true}, saveErr
+	}
+	r.publishEvent(ctx, request, host.NewEvent("ProvisionerError", err.Error()))
+	return ctrl.Result{}, nil
+}
+
 // hasRebootAnnotation checks for existence of reboot annotations and returns true if at least one exist.
 func hasRebootAnnotation(info *reconcileInfo, expectForce bool) (hasReboot bool, rebootMode metal3api.RebootMode) {
 	rebootMode = metal3api.RebootModeSoft
@@ -482,8 +562,11 @@ func setErrorMessage(host *metal3api.BareMetalHost, errType metal3api.ErrorType,
 }
 
 func (r *BareMetalHostReconciler) actionPowerOffBeforeDeleting(prov provisioner.Provisioner, info *reconcileInfo) actionResult {
+	ctx, cancel := info.provisionerContext(provisionerCallTimeout)
+	defer cancel()
+
 	info.log.Info("host ready to be powered off")
-	provResult, err := prov.PowerOff(
+	provResult, err := prov.PowerOff(ctx,
 		metal3api.RebootModeHard,
 		info.host.Status.ErrorType == metal3api.PowerManagementError)
 
@@ -508,6 +591,9 @@ func (r *BareMetalHostReconciler) actionPowerOffBeforeDeleting(prov provisioner.
 
 // Manage deletion of the host.
 func (r *BareMetalHostReconciler) actionDeleting(prov provisioner.Provisioner, info *reconcileInfo) actionResult {
+	ctx, cancel := info.provisionerContext(provisionerCallTimeout)
+	defer cancel()
+
 	info.log.Info(
 		"marked to be deleted",
 		"timestamp", info.host.DeletionTimestamp,
@@ -519,7 +605,7 @@ func (r *BareMetalHostReconciler) actionDeleting(prov provisioner.Provisioner, i
 		return deleteComplete{}
 	}
 
-	provResult, err := prov.Delete()
+	provResult, err := prov.Delete(ctx)
 	if err != nil {
 		return actionError{errors.Wrap(err, "failed to delete")}
 	}
@@ -586,7 +672,10 @@ func hasCustomDeploy(host *metal3api.BareMetalHost) bool {
 
 // detachHost() detaches the host from the Provisioner.
 func (r *BareMetalHostReconciler) detachHost(prov provisioner.Provisioner, info *reconcileInfo) actionResult {
-	provResult, err := prov.Detach()
+	ctx, cancel := info.provisionerContext(provisionerCallTimeout)
+	defer cancel()
+
+	provResult, err := prov.Detach(ctx)
 	if err != nil {
 		return actionError{errors.Wrap(err, "failed to detach")}
 	}
@@ -771,6 +860,9 @@ func (r *BareMetalHostReconciler) getPreprovImage(info *reconcileInfo, formats [
 
 // Test the credentials by connecting to the management controller.
 func (r *BareMetalHostReconciler) registerHost(prov provisioner.Provisioner, info *reconcileInfo) actionResult {
+	ctx, cancel := info.provisionerContext(provisionerRegisterTimeout)
+	defer cancel()
+
 	info.log.V(1).Info("registering and validating access to management controller",
 		"credentials", info.host.Status.TriedCredentials)
 	dirty := false
@@ -783,7 +875,7 @@ func (r *BareMetalHostReconciler) registerHost(prov provisioner.Provisioner, inf
 		dirty = true
 	}
 
-	preprovImgFormats, err := prov.PreprovisioningImageFormats()
+	preprovImgFormats, err := prov.PreprovisioningImageFormats(ctx)
 	if err != nil {
 		return actionError{err}
 	}
@@ -817,7 +909,7 @@ func (r *BareMetalHostReconciler) registerHost(prov provisioner.Provisioner, inf
 		return recordActionFailure(info, metal3api.RegistrationError, "failed to read preprovisioningNetworkData")
 	}
 
-	provResult, provID, err := prov.ValidateManagementAccess(
+	provResult, provID, err := prov.ValidateManagementAccess(ctx,
 		provisioner.ManagementAccessData{
 			BootMode:                   info.host.Status.Provisioning.BootMode,
 			AutomatedCleaningMode:      info.host.Spec.AutomatedCleaningMode,
@@ -925,7 +1017,7 @@ func updateRootDeviceHints(host *metal3api.BareMetalHost, info *reconcileInfo) (
 	// precedence. Otherwise use the values from the hardware profile.
 	hintSource := host.Spec.RootDeviceHints
 	if hintSource == nil {
-		hwProf, err := profile.GetProfile(host.HardwareProfile())
+		hwProf, err := info.profiles.get(host.HardwareProfile())
 		if err != nil {
 			return false, errors.Wrap(err, "failed to update root device hints")
 		}
@@ -941,6 +1033,9 @@ func updateRootDeviceHints(host *metal3api.BareMetalHost, info *reconcileInfo) (
 
 // Ensure we have the information about the hardware on the host.
 func (r *BareMetalHostReconciler) actionInspecting(prov provisioner.Provisioner, info *reconcileInfo) actionResult {
+	ctx, cancel := info.provisionerContext(provisionerInspectTimeout)
+	defer cancel()
+
 	info.log.Info("inspecting hardware")
 
 	if inspectionDisabled(info.host) {
@@ -954,7 +1049,7 @@ func (r *BareMetalHostReconciler) actionInspecting(prov provisioner.Provisioner,
 	refresh := hasInspectAnnotation(info.host)
 	forceReboot, _ := hasRebootAnnotation(info, true)
 
-	provResult, started, details, err := prov.InspectHardware(
+	provResult, started, details, err := prov.InspectHardware(ctx,
 		provisioner.InspectData{
 			BootMode: info.host.Status.Provisioning.BootMode,
 		},
@@ -1078,9 +1173,18 @@ func getHardwareProfileName(host *metal3api.BareMetalHost) string {
 
 func (r *BareMetalHostReconciler) matchProfile(info *reconcileInfo) (dirty bool, err error) {
 	hardwareProfile := getHardwareProfileName(info.host)
+	if needsProfileMatch(info.host) {
+		matched, err := info.profiles.match(info.host.Status.HardwareDetails)
+		if err != nil {
+			return false, err
+		}
+		if matched != "" {
+			hardwareProfile = matched
+		}
+	}
 	info.log.V(1).Info("using hardware profile", "profile", hardwareProfile)
 
-	_, err = profile.GetProfile(hardwareProfile)
+	_, err = info.profiles.get(hardwareProfile)
 	if err != nil {
 		info.log.Info("invalid hardware profile", "profile", hardwareProfile)
 		return
@@ -1102,6 +1206,9 @@ func (r *BareMetalHostReconciler) matchProfile(info *reconcileInfo) (dirty bool,
 }
 
 func (r *BareMetalHostReconciler) actionPreparing(prov provisioner.Provisioner, info *reconcileInfo) actionResult {
+	ctx, cancel := info.provisionerContext(provisionerCallTimeout)
+	defer cancel()
+
 	info.log.Info("preparing")
 
 	bmhDirty, newStatus, err := getHostProvisioningSettings(info.host, info)
@@ -1149,7 +1256,7 @@ func (r *BareMetalHostReconciler) actionPreparing(prov provisioner.Provisioner,
 		prepareData.TargetFirmwareComponents = hfc.Spec.Updates
 	}
 
-	provResult, started, err := prov.Prepare(prepareData, bmhDirty || hfsDirty || hfcDirty,
+	provResult, started, err := prov.Prepare(ctx, prepareData, bmhDirty || hfsDirty || hfcDirty,
 		info.host.Status.ErrorType == metal3api.PreparationError)
 
 	if err != nil {
@@ -1202,6 +1309,9 @@ func (r *BareMetalHostReconciler) actionPreparing(prov provisioner.Provisioner,
 
 // Start/continue provisioning if we need to.
 func (r *BareMetalHostReconciler) actionProvisioning(prov provisioner.Provisioner, info *reconcileInfo) actionResult {
+	ctx, cancel := info.provisionerContext(provisionerCallTimeout)
+	defer cancel()
+
 	hostConf := &hostConfigData{
 		host:          info.host,
 		log:           info.log.WithName("host_config_data"),
@@ -1209,7 +1319,7 @@ func (r *BareMetalHostReconciler) actionProvisioning(prov provisioner.Provisione
 	}
 	info.log.Info("provisioning")
 
-	hwProf, err := profile.GetProfile(info.host.HardwareProfile())
+	hwProf, err := info.profiles.get(info.host.HardwareProfile())
 	if err != nil {
 		return actionError{errors.Wrap(err,
 			fmt.Sprintf("could not start provisioning with bad hardware profile %s",
@@ -1223,7 +1333,7 @@ func (r *BareMetalHostReconciler) actionProvisioning(prov provisioner.Provisione
 		image = *info.host.Spec.Image.DeepCopy()
 	}
 
-	provResult, err := prov.Provision(provisioner.ProvisionData{
+	provResult, err := prov.Provision(ctx, provisioner.ProvisionData{
 		Image:           image,
 		CustomDeploy:    info.host.Spec.CustomDeploy.DeepCopy(),
 		HostConfig:      hostConf,
@@ -1288,10 +1398,13 @@ func clearHostProvisioningSettings(host *metal3api.BareMetalHost) {
 }
 
 func (r *BareMetalHostReconciler) actionDeprovisioning(prov provisioner.Provisioner, info *reconcileInfo) actionResult {
+	ctx, cancel := info.provisionerContext(provisionerCallTimeout)
+	defer cancel()
+
 	if info.host.Status.Provisioning.Image.URL != "" {
 		// Adopt the host in case it has been re-registered during the
 		// deprovisioning process before it completed
-		provResult, err := prov.Adopt(
+		provResult, err := prov.Adopt(ctx,
 			provisioner.AdoptData{State: info.host.Status.Provisioning.State},
 			info.host.Status.ErrorType == metal3api.ProvisionedRegistrationError)
 		if err != nil {
@@ -1311,15 +1424,28 @@ func (r *BareMetalHostReconciler) actionDeprovisioning(prov provisioner.Provisio
 
 	info.log.Info("deprovisioning")
 
-	provResult, err := prov.Deprovision(info.host.Status.ErrorType == metal3api.ProvisioningError)
+	deprovisionData := provisioner.DeprovisionData{
+		EraseDisks:     info.host.Spec.AutomatedCleaningMode == metal3api.CleaningModeFull,
+		PreservedDisks: info.host.Spec.PreservedDisks,
+		ErasureStarted: diskErasureStarted(info.host),
+	}
+	provResult, erasureStarted, err := prov.Deprovision(ctx, deprovisionData, info.host.Status.ErrorType == metal3api.ProvisioningError)
 	if err != nil {
 		return actionError{errors.Wrap(err, "failed to deprovision")}
 	}
 
 	if provResult.ErrorMessage != "" {
+		finishDiskErasure(info.host, provResult.ErrorMessage)
 		return recordActionFailure(info, metal3api.ProvisioningError, provResult.ErrorMessage)
 	}
 
+	if erasureStarted {
+		info.log.Info("started erasing disks")
+		info.host.Status.DiskErasure = &metal3api.DiskErasure{Started: metav1.Now()}
+		clearError(info.host)
+		return actionUpdate{actionContinue{provResult.RequeueAfter}}
+	}
+
 	if provResult.Dirty {
 		result := actionContinue{provResult.RequeueAfter}
 		if clearError(info.host) {
@@ -1328,6 +1454,11 @@ func (r *BareMetalHostReconciler) actionDeprovisioning(prov provisioner.Provisio
 		return result
 	}
 
+	if finishDiskErasure(info.host, "") {
+		info.log.Info("finished erasing disks")
+		info.publishEvent("DiskErasureComplete", "Disks erased")
+	}
+
 	if clearRebootAnnotations(info.host) {
 		if err = r.Update(info.ctx, info.host); err != nil {
 			return actionError{errors.Wrap(err, "failed to remove reboot annotations from host")}
@@ -1346,10 +1477,13 @@ func (r *BareMetalHostReconciler) actionDeprovisioning(prov provisioner.Provisio
 
 // Check the current power status against the desired power status.
 func (r *BareMetalHostReconciler) manageHostPower(prov provisioner.Provisioner, info *reconcileInfo) actionResult {
+	ctx, cancel := info.provisionerContext(provisionerCallTimeout)
+	defer cancel()
+
 	var provResult provisioner.Result
 
 	// Check the current status and save it before trying to update it.
-	hwState, err := prov.UpdateHardwareState()
+	hwState, err := prov.UpdateHardwareState(ctx)
 	if err != nil {
 		return actionError{errors.Wrap(err, "failed to update the host power status")}
 	}
@@ -1385,11 +1519,62 @@ func (r *BareMetalHostReconciler) manageHostPower(prov provisioner.Provisioner,
 		desiredPowerOnState = false
 	}
 
+	powerOp, powerOpDeadline, actResult := r.handlePowerOperation(info, isProvisioned)
+	if actResult != nil {
+		return actResult
+	}
+	if powerOp != nil {
+		if powerOp.Spec.Action == metal3api.PowerActionReboot && !powerOp.Status.PoweredOff {
+			desiredPowerOnState = false
+		}
+		if powerOp.Spec.Mode == metal3api.RebootModeHard {
+			desiredRebootMode = metal3api.RebootModeHard
+		}
+	}
+
+	// Available hosts may be powered off while idle. They are powered on
+	// again by provisioning.
+	var idlePolicy *metal3api.IdlePowerOffPolicy
+	if provState == metal3api.StateAvailable {
+		idlePolicy, err = idlePowerOffPolicy(info)
+		if err != nil {
+			return actionError{err}
+		}
+	}
+	now := metav1.Now()
+	idle := idlePolicy != nil && powerOp == nil && !desiredReboot && info.host.Spec.Online &&
+		info.host.Status.IdleSince != nil && idlePolicy.PowersOff(info.host.Status.IdleSince.Time, now.Time)
+	if idle {
+		desiredPowerOnState = false
+	}
+	if updateIdlePowerStatus(info, idlePolicy, idle, now) {
+		return actionUpdate{}
+	}
+
 	// Power state needs to be monitored regularly, so if we leave
 	// this function without an error we always want to requeue after
-	// a delay.
-	steadyStateResult := actionContinue{time.Second * 60}
+	// a delay, unless the power state poller does it for us. Hosts with
+	// an idle power-off policy are also checked regularly so that they
+	// are powered off once idle for long enough.
+	var steadyStateResult actionResult = actionContinue{time.Second * 60}
+	var steadyStateUpdate actionResult = actionUpdate{actionContinue{time.Second * 60}}
+	if r.powerPoller.polls(info.host) && idlePolicy == nil {
+		steadyStateResult = actionWait{}
+		steadyStateUpdate = actionWait{dirty: true}
+	}
+	// Power operations must be failed once past their deadline, even if
+	// they cannot start and nothing else changes.
+	if powerOpDeadline != nil {
+		requeueAfter := time.Until(powerOpDeadline.Time)
+		if steadyState, ok := steadyStateResult.(actionContinue); !ok || steadyState.delay > requeueAfter {
+			steadyStateResult = actionContinue{requeueAfter}
+			steadyStateUpdate = actionUpdate{actionContinue{requeueAfter}}
+		}
+	}
 	if info.host.Status.PoweredOn == desiredPowerOnState {
+		if idle {
+			recordIdlePowerOff(info, now)
+		}
 		return steadyStateResult
 	}
 
@@ -1409,12 +1594,12 @@ func (r *BareMetalHostReconciler) manageHostPower(prov provisioner.Provisioner,
 			}
 		}
 
-		provResult, err = prov.PowerOn(info.host.Status.ErrorType == metal3api.PowerManagementError)
+		provResult, err = prov.PowerOn(ctx, info.host.Status.ErrorType == metal3api.PowerManagementError)
 	} else {
 		if info.host.Status.ErrorCount > 0 {
 			desiredRebootMode = metal3api.RebootModeHard
 		}
-		provResult, err = prov.PowerOff(desiredRebootMode, info.host.Status.ErrorType == metal3api.PowerManagementError)
+		provResult, err = prov.PowerOff(ctx, desiredRebootMode, info.host.Status.ErrorType == metal3api.PowerManagementError)
 	}
 	if err != nil {
 		return actionError{errors.Wrap(err, "failed to manage power state of host")}
@@ -1425,6 +1610,11 @@ func (r *BareMetalHostReconciler) manageHostPower(prov provisioner.Provisioner,
 			info.host.Status.ErrorType != metal3api.PowerManagementError {
 			provResult.ErrorMessage = clarifySoftPoweroffFailure + provResult.ErrorMessage
 		}
+		if powerOp != nil {
+			if err := r.finishPowerOperation(info, powerOp, metal3api.PowerOperationFailed, provResult.ErrorMessage); err != nil {
+				return actionError{err}
+			}
+		}
 		return recordActionFailure(info, metal3api.PowerManagementError, provResult.ErrorMessage)
 	}
 
@@ -1448,13 +1638,16 @@ func (r *BareMetalHostReconciler) manageHostPower(prov provisioner.Provisioner,
 	// The provisioner did not have to do anything to change the power
 	// state and there were no errors, so reflect the new state in the
 	// host status field.
-	info.host.Status.PoweredOn = info.host.Spec.Online
+	info.host.Status.PoweredOn = desiredPowerOnState
 	info.host.Status.ErrorCount = 0
-	return actionUpdate{steadyStateResult}
+	return steadyStateUpdate
 }
 
 // DataImage handler for attaching/detaching image.
 func (r *BareMetalHostReconciler) handleDataImageActions(prov provisioner.Provisioner, info *reconcileInfo) actionResult {
+	ctx, cancel := info.provisionerContext(provisionerCallTimeout)
+	defer cancel()
+
 	dataImage := &metal3api.DataImage{}
 	if err := r.Get(info.ctx, info.request.NamespacedName, dataImage); err != nil {
 		// DataImage does not exist or it may have been deleted
@@ -1493,7 +1686,7 @@ func (r *BareMetalHostReconciler) handleDataImageActions(prov provisioner.Provis
 	// this reconcile is called ( after the delay specified in the previous
 	// action)
 	// TODO(hroyrh) : update this once vmedia.get api is available
-	isNodeBusy, nodeError := prov.IsDataImageReady()
+	isNodeBusy, nodeError := prov.IsDataImageReady(ctx)
 	if isNodeBusy {
 		info.log.Info("Node is busy, requeuing")
 
@@ -1612,7 +1805,10 @@ func ownerReferenceExists(owner metav1.Object, resource metav1.Object) bool {
 
 // Attach the DataImage to the BareMetalHost.
 func (r *BareMetalHostReconciler) attachDataImage(prov provisioner.Provisioner, info *reconcileInfo, dataImage *metal3api.DataImage) error {
-	if err := prov.AttachDataImage(dataImage.Spec.URL); err != nil {
+	ctx, cancel := info.provisionerContext(provisionerCallTimeout)
+	defer cancel()
+
+	if err := prov.AttachDataImage(ctx, dataImage.Spec.URL); err != nil {
 		info.log.Info("Error while attaching DataImage", "URL", dataImage.Spec.URL, "Error", err.Error())
 
 		dataImage.Status.Error.Count++
@@ -1638,7 +1834,10 @@ func (r *BareMetalHostReconciler) attachDataImage(prov provisioner.Provisioner,
 
 // Detach the DataImage from the BareMetalHost.
 func (r *BareMetalHostReconciler) detachDataImage(prov provisioner.Provisioner, info *reconcileInfo, dataImage *metal3api.DataImage) error {
-	if err := prov.DetachDataImage(); err != nil {
+	ctx, cancel := info.provisionerContext(provisionerCallTimeout)
+	defer cancel()
+
+	if err := prov.DetachDataImage(ctx); err != nil {
 		info.log.Info("Error while detaching DataImage", "DataImage", dataImage.Name, "Error", err.Error())
 
 		dataImage.Status.Error.Count++
@@ -1667,7 +1866,10 @@ func (r *BareMetalHostReconciler) detachDataImage(prov provisioner.Provisioner,
 // action. We use the Adopt() API to make sure that the provisioner is aware of
 // the provisioning details. Then we monitor its power status.
 func (r *BareMetalHostReconciler) actionManageSteadyState(prov provisioner.Provisioner, info *reconcileInfo) actionResult {
-	provResult, err := prov.Adopt(
+	ctx, cancel := info.provisionerContext(provisionerCallTimeout)
+	defer cancel()
+
+	provResult, err := prov.Adopt(ctx,
 		provisioner.AdoptData{State: info.host.Status.Provisioning.State},
 		info.host.Status.ErrorType == metal3api.ProvisionedRegistrationError)
 	if err != nil {
@@ -1684,6 +1886,10 @@ func (r *BareMetalHostReconciler) actionManageSteadyState(prov provisioner.Provi
 		return result
 	}
 
+	if actResult := r.actionSetBootDevice(prov, info); actResult != nil {
+		return actResult
+	}
+
 	return r.manageHostPower(prov, info)
 }
 
@@ -1696,6 +1902,9 @@ func (r *BareMetalHostReconciler) actionManageAvailable(prov provisioner.Provisi
 		clearError(info.host)
 		return actionComplete{}
 	}
+	if actResult := r.actionSetBootDevice(prov, info); actResult != nil {
+		return actResult
+	}
 	return r.manageHostPower(prov, info)
 }
 
@@ -1719,8 +1928,21 @@ func saveHostProvisioningSettings(host *metal3api.BareMetalHost, info *reconcile
 		return dirty, err
 	}
 
+	// Settings the host does not configure itself default to those of
+	// its hardware profile.
+	var hwProf profile.Profile
+	if host.HardwareProfile() != "" {
+		hwProf, err = info.profiles.get(host.HardwareProfile())
+		if err != nil {
+			return dirty, err
+		}
+	}
+
 	// Copy RAID settings
 	specRAID := host.Spec.RAID
+	if specRAID == nil {
+		specRAID = hwProf.RAID
+	}
 	// If RAID configure is nil or empty, means that we need to keep the current hardware RAID configuration
 	// or clear current software RAID configuration
 	if specRAID == nil || reflect.DeepEqual(specRAID, &metal3api.RAIDConfig{}) {
@@ -1745,8 +1967,12 @@ func saveHostProvisioningSettings(host *metal3api.BareMetalHost, info *reconcile
 	}
 
 	// Copy BIOS settings
-	if !reflect.DeepEqual(host.Status.Provisioning.Firmware, host.Spec.Firmware) {
-		host.Status.Provisioning.Firmware = host.Spec.Firmware
+	specFirmware := host.Spec.Firmware
+	if specFirmware == nil {
+		specFirmware = hwProf.Firmware
+	}
+	if !reflect.DeepEqual(host.Status.Provisioning.Firmware, specFirmware) {
+		host.Status.Provisioning.Firmware = specFirmware
 		info.log.Info("Firmware settings have changed")
 		dirty = true
 	}
@@ -1755,6 +1981,9 @@ func saveHostProvisioningSettings(host *metal3api.BareMetalHost, info *reconcile
 }
 
 func (r *BareMetalHostReconciler) saveHostFirmwareComponents(prov provisioner.Provisioner, info *reconcileInfo, hfc *metal3api.HostFirmwareComponents) (dirty bool, err error) {
+	ctx, cancel := info.provisionerContext(provisionerCallTimeout)
+	defer cancel()
+
 	dirty = false
 	if reflect.DeepEqual(hfc.Status.Updates, hfc.Spec.Updates) {
 		info.log.Info("not saving hostFirmwareComponents information since is not necessary")
@@ -1769,7 +1998,7 @@ func (r *BareMetalHostReconciler) saveHostFirmwareComponents(prov provisioner.Pr
 	}
 
 	// Retrieve new information about the firmware components stored in ironic
-	components, err := prov.GetFirmwareComponents()
+	components, err := prov.GetFirmwareComponents(ctx)
 	if err != nil {
 		info.log.Error(err, "failed to get new information for firmware components in ironic")
 		return dirty, err
@@ -2102,6 +2331,21 @@ func (r *BareMetalHostReconciler) SetupWithManager(mgr ctrl.Manager, preprovImgE
 		controller.Owns(&metal3api.PreprovisioningImage{})
 	}
 
+	controller.Watches(&metal3api.HardwareProfile{}, handler.EnqueueRequestsFromMapFunc(r.hostsForHardwareProfile))
+	controller.Watches(&metal3api.PowerOperation{}, handler.EnqueueRequestsFromMapFunc(r.hostForPowerOperation))
+
+	if err := metrics.Registry.Register(newHostStateCollector(mgr.GetClient(), r.Log.WithName("metrics"))); err != nil {
+		return err
+	}
+
+	if poller, ok := r.ProvisionerFactory.(provisioner.PowerStatePoller); ok {
+		r.powerPoller = newPowerStatePoller(mgr.GetClient(), poller, r.Log.WithName("power-poller"))
+		if err := mgr.Add(r.powerPoller); err != nil {
+			return err
+		}
+		controller.WatchesRawSource(&source.Channel{Source: r.powerPoller.events}, &handler.EnqueueRequestForObject{})
+	}
+
 	return controller.Complete(r)
 }
 
@@ -10,6 +10,7 @@ import (
 
 	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
 	"github.com/metal3-io/baremetal-operator/pkg/hardwareutils/bmc"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
 	"github.com/metal3-io/baremetal-operator/pkg/provisioner/fixture"
 	"github.com/metal3-io/baremetal-operator/pkg/secretutils"
 	"github.com/metal3-io/baremetal-operator/pkg/utils"
@@ -483,6 +484,54 @@ func TestPause(t *testing.T) {
 	)
 }
 
+// TestUnknownProvisioningBackend ensures that a host requesting a
+// backend which is not configured records that as an error.
+func TestUnknownProvisioningBackend(t *testing.T) {
+	host := newDefaultHost(t)
+	host.Spec.ProvisioningBackend = "missing"
+	r := newTestReconciler(host)
+	r.ProvisionerFactory = provisioner.NewRegistry("fixture").Register("fixture", &fixture.Fixture{})
+
+	tryReconcile(t, r, host,
+		func(host *metal3api.BareMetalHost, result reconcile.Result) bool {
+			return host.Status.ErrorType == metal3api.RegistrationError
+		},
+	)
+	assert.Equal(t, `unknown provisioning backend "missing"`, host.Status.ErrorMessage)
+	assert.Equal(t, 1, host.Status.ErrorCount)
+
+	// The error is not recorded again while the host is unchanged
+	_, err := r.Reconcile(context.Background(), newRequest(host))
+	assert.NoError(t, err)
+	assert.NoError(t, r.Get(context.Background(), newRequest(host).NamespacedName, host))
+	assert.Equal(t, 1, host.Status.ErrorCount)
+}
+
+type failingProvisionerFactory struct {
+	err error
+}
+
+func (f failingProvisionerFactory) NewProvisioner(context.Context, provisioner.HostData, provisioner.EventPublisher) (provisioner.Provisioner, error) {
+	return nil, f.err
+}
+
+// TestUnknownProvisionerEndpoint ensures that a host assigned to a
+// backend instance which is not configured records that as an error.
+func TestUnknownProvisionerEndpoint(t *testing.T) {
+	host := newDefaultHost(t)
+	r := newTestReconciler(host)
+	r.ProvisionerFactory = failingProvisionerFactory{
+		err: provisioner.UnknownEndpointError{Endpoint: "south", Reason: "endpoint south is not configured"},
+	}
+
+	tryReconcile(t, r, host,
+		func(host *metal3api.BareMetalHost, result reconcile.Result) bool {
+			return host.Status.ErrorType == metal3api.RegistrationError
+		},
+	)
+	assert.Equal(t, "endpoint south is not configured", host.Status.ErrorMessage)
+}
+
 // TestInspectDisabled ensures that Inspection is skipped when disabled.
 func TestInspectDisabled(t *testing.T) {
 	host := newDefaultHost(t)
@@ -594,6 +643,7 @@ func TestInspectionDisabledAnnotation(t *testing.T) {
 
 func makeReconcileInfo(host *metal3api.BareMetalHost) *reconcileInfo {
 	return &reconcileInfo{
+		ctx:  context.TODO(),
 		log:  logf.Log.WithName("controllers").WithName("BareMetalHost").WithName("baremetal_controller"),
 		host: host,
 	}
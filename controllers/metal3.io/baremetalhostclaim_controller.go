@@ -0,0 +1,352 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/utils"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const hostClaimKind = "BareMetalHostClaim"
+
+// BareMetalHostClaimReconciler binds BareMetalHostClaims to available
+// hosts.
+type BareMetalHostClaimReconciler struct {
+	client.Client
+	Log logr.Logger
+}
+
+// +kubebuilder:rbac:groups=metal3.io,resources=baremetalhostclaims,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=metal3.io,resources=baremetalhostclaims/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=metal3.io,resources=baremetalhostclaims/finalizers,verbs=update
+// +kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
+
+// Reconcile binds a pending claim to one available host matching it, and
+// releases the host when the claim is deleted.
+func (r *BareMetalHostClaimReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("baremetalhostclaim", request.NamespacedName)
+
+	claim := &metal3api.BareMetalHostClaim{}
+	if err := r.Get(ctx, request.NamespacedName, claim); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, errors.Wrap(err, "could not load claim")
+	}
+
+	if !claim.DeletionTimestamp.IsZero() {
+		if !utils.StringInList(claim.Finalizers, metal3api.BareMetalHostClaimFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.releaseHost(ctx, claim); err != nil {
+			return ctrl.Result{}, err
+		}
+		reqLogger.Info("released host", "host", claim.Status.HostName)
+		claim.Finalizers = utils.FilterStringFromList(claim.Finalizers, metal3api.BareMetalHostClaimFinalizer)
+		if err := r.Update(ctx, claim); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to remove finalizer")
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !utils.StringInList(claim.Finalizers, metal3api.BareMetalHostClaimFinalizer) {
+		claim.Finalizers = append(claim.Finalizers, metal3api.BareMetalHostClaimFinalizer)
+		if err := r.Update(ctx, claim); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to add finalizer")
+		}
+	}
+
+	switch claim.Status.Phase {
+	case metal3api.ClaimBound:
+		return ctrl.Result{}, r.checkBinding(ctx, reqLogger, claim)
+	case metal3api.ClaimLost:
+		return ctrl.Result{}, nil
+	default:
+		return ctrl.Result{}, r.bindHost(ctx, reqLogger, claim)
+	}
+}
+
+// bindHost looks for a host for a pending claim. The host is bound by
+// setting its consumerRef, which fails on conflict if another claim or
+// consumer updated the host in the meantime.
+func (r *BareMetalHostClaimReconciler) bindHost(ctx context.Context, log logr.Logger, claim *metal3api.BareMetalHostClaim) error {
+	hosts, err := r.candidateHosts(ctx, claim)
+	if err != nil {
+		return err
+	}
+
+	for _, host := range hosts {
+		if !claimsHost(claim, host) {
+			host.Spec.ConsumerRef = claimReference(claim)
+			if err := r.Update(ctx, host); err != nil {
+				if k8serrors.IsConflict(err) {
+					log.Info("host changed while binding, trying the next one", "host", host.Name)
+					continue
+				}
+				return errors.Wrap(err, "failed to bind host")
+			}
+		}
+
+		log.Info("bound host", "host", host.Name)
+		now := metav1.Now()
+		claim.Status = metal3api.BareMetalHostClaimStatus{
+			Phase:     metal3api.ClaimBound,
+			HostName:  host.Name,
+			BoundTime: &now,
+		}
+		return r.saveStatus(ctx, claim)
+	}
+
+	message := "no available host matches the claim"
+	if claim.Status.Phase == metal3api.ClaimPending && claim.Status.Message == message {
+		return nil
+	}
+	claim.Status = metal3api.BareMetalHostClaimStatus{
+		Phase:   metal3api.ClaimPending,
+		Message: message,
+	}
+	return r.saveStatus(ctx, claim)
+}
+
+// candidateHosts returns the hosts that can be bound to the claim, in
+// the order in which binding is attempted. A host already bound to the
+// claim, e.g. when the status of the claim failed to be saved, comes
+// first.
+func (r *BareMetalHostClaimReconciler) candidateHosts(ctx context.Context, claim *metal3api.BareMetalHostClaim) ([]*metal3api.BareMetalHost, error) {
+	selector := labels.Everything()
+	if claim.Spec.HostSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(claim.Spec.HostSelector)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid host selector")
+		}
+	}
+
+	hostList := &metal3api.BareMetalHostList{}
+	err := r.List(ctx, hostList, client.InNamespace(claim.Namespace),
+		client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list hosts")
+	}
+
+	candidates := []*metal3api.BareMetalHost{}
+	for i := range hostList.Items {
+		host := &hostList.Items[i]
+		if claimsHost(claim, host) {
+			return []*metal3api.BareMetalHost{host}, nil
+		}
+		if host.Spec.ConsumerRef != nil || !host.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if host.Status.Provisioning.State != metal3api.StateAvailable || hasDetachedAnnotation(host) {
+			continue
+		}
+		if !claim.Spec.Hardware.Matches(host.Status.HardwareDetails) {
+			continue
+		}
+		if len(host.UntoleratedTaints(claim.Spec.Tolerations, corev1.TaintEffectNoSchedule, corev1.TaintEffectNoExecute)) > 0 {
+			continue
+		}
+		candidates = append(candidates, host)
+	}
+
+	// Hosts with a PreferNoSchedule taint are only bound when no other
+	// host matches.
+	preferred := func(host *metal3api.BareMetalHost) bool {
+		return len(host.UntoleratedTaints(claim.Spec.Tolerations, corev1.TaintEffectPreferNoSchedule)) == 0
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if preferred(candidates[i]) != preferred(candidates[j]) {
+			return preferred(candidates[i])
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+	return candidates, nil
+}
+
+// checkBinding marks the claim as lost when its host was deleted or
+// bound to another consumer, and reports the NoExecute taints of the
+// host the claim does not tolerate.
+func (r *BareMetalHostClaimReconciler) checkBinding(ctx context.Context, log logr.Logger, claim *metal3api.BareMetalHostClaim) error {
+	host := &metal3api.BareMetalHost{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: claim.Namespace, Name: claim.Status.HostName}, host)
+	switch {
+	case k8serrors.IsNotFound(err):
+		claim.Status.Message = "the bound host was deleted"
+	case err != nil:
+		return errors.Wrap(err, "could not load bound host")
+	case !claimsHost(claim, host):
+		claim.Status.Message = "the bound host was claimed by another consumer"
+	default:
+		return r.checkTaints(ctx, log, claim, host)
+	}
+
+	log.Info("lost host", "host", claim.Status.HostName, "reason", claim.Status.Message)
+	claim.Status.Phase = metal3api.ClaimLost
+	return r.saveStatus(ctx, claim)
+}
+
+func (r *BareMetalHostClaimReconciler) checkTaints(ctx context.Context, log logr.Logger, claim *metal3api.BareMetalHostClaim, host *metal3api.BareMetalHost) error {
+	condition := metav1.Condition{
+		Type:               string(metal3api.ClaimConditionHostTainted),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: claim.Generation,
+		Reason:             reasonNotTainted,
+	}
+	taints := host.UntoleratedTaints(claim.Spec.Tolerations, corev1.TaintEffectNoExecute)
+	if len(taints) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reasonNoExecuteTaint
+		condition.Message = fmt.Sprintf("host %s has taints %s", host.Name, taintsString(taints))
+	}
+
+	wasTainted := meta.IsStatusConditionTrue(claim.Status.Conditions, condition.Type)
+	if !meta.SetStatusCondition(&claim.Status.Conditions, condition) {
+		return nil
+	}
+	if err := r.saveStatus(ctx, claim); err != nil {
+		return err
+	}
+
+	if condition.Status == metav1.ConditionTrue && !wasTainted {
+		log.Info("bound host tainted", "host", host.Name, "taints", condition.Message)
+		r.publishEvent(ctx, log, claim, string(metal3api.ClaimConditionHostTainted), condition.Message)
+	}
+	return nil
+}
+
+func (r *BareMetalHostClaimReconciler) publishEvent(ctx context.Context, log logr.Logger, claim *metal3api.BareMetalHostClaim, reason, message string) {
+	t := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: reason + "-",
+			Namespace:    claim.Namespace,
+		},
+		InvolvedObject: *claimReference(claim),
+		Reason:         reason,
+		Message:        message,
+		Source: corev1.EventSource{
+			Component: "metal3-baremetalhostclaim-controller",
+		},
+		FirstTimestamp:      t,
+		LastTimestamp:       t,
+		Count:               1,
+		Type:                corev1.EventTypeWarning,
+		ReportingController: "metal3.io/baremetalhostclaim-controller",
+	}
+	if err := r.Create(ctx, event); err != nil {
+		log.Info("failed to record event, ignoring", "reason", reason, "message", message, "error", err)
+	}
+}
+
+// releaseHost clears the consumerRef of the host bound to the claim.
+func (r *BareMetalHostClaimReconciler) releaseHost(ctx context.Context, claim *metal3api.BareMetalHostClaim) error {
+	if claim.Status.HostName == "" {
+		return nil
+	}
+
+	host := &metal3api.BareMetalHost{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: claim.Namespace, Name: claim.Status.HostName}, host)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "could not load bound host")
+	}
+	if !claimsHost(claim, host) {
+		return nil
+	}
+
+	host.Spec.ConsumerRef = nil
+	return errors.Wrap(r.Update(ctx, host), "failed to release host")
+}
+
+func (r *BareMetalHostClaimReconciler) saveStatus(ctx context.Context, claim *metal3api.BareMetalHostClaim) error {
+	return errors.Wrap(r.Status().Update(ctx, claim), "failed to update claim status")
+}
+
+func claimReference(claim *metal3api.BareMetalHostClaim) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion: metal3api.GroupVersion.String(),
+		Kind:       hostClaimKind,
+		Namespace:  claim.Namespace,
+		Name:       claim.Name,
+		UID:        claim.UID,
+	}
+}
+
+// claimsHost returns whether the host is bound to the claim.
+func claimsHost(claim *metal3api.BareMetalHostClaim, host *metal3api.BareMetalHost) bool {
+	ref := host.Spec.ConsumerRef
+	return ref != nil && ref.Kind == hostClaimKind &&
+		ref.APIVersion == metal3api.GroupVersion.String() &&
+		ref.Name == claim.Name && ref.UID == claim.UID
+}
+
+// claimsForHost returns the claims to reconcile when a host changes: the
+// pending claims of its namespace, which it may now satisfy, and the
+// claims bound to it.
+func (r *BareMetalHostClaimReconciler) claimsForHost(ctx context.Context, obj client.Object) []reconcile.Request {
+	claims := &metal3api.BareMetalHostClaimList{}
+	if err := r.List(ctx, claims, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "failed to list claims", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, claim := range claims.Items {
+		switch claim.Status.Phase {
+		case metal3api.ClaimLost:
+			continue
+		case metal3api.ClaimBound:
+			if claim.Status.HostName != obj.GetName() {
+				continue
+			}
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: claim.Namespace, Name: claim.Name},
+		})
+	}
+	return requests
+}
+
+// SetupWithManager registers the reconciler to be run by the manager.
+func (r *BareMetalHostClaimReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconcile int) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&metal3api.BareMetalHostClaim{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconcile}).
+		Watches(&metal3api.BareMetalHost{}, handler.EnqueueRequestsFromMapFunc(r.claimsForHost)).
+		Complete(r)
+}
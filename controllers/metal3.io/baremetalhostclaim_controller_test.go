@@ -0,0 +1,232 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newClaimTestReconciler(initObjs ...runtime.Object) *BareMetalHostClaimReconciler {
+	clientBuilder := fakeclient.NewClientBuilder().WithRuntimeObjects(initObjs...)
+	for _, v := range initObjs {
+		clientBuilder = clientBuilder.WithStatusSubresource(v.(client.Object))
+	}
+
+	return &BareMetalHostClaimReconciler{
+		Client: clientBuilder.Build(),
+		Log:    ctrl.Log.WithName("controllers").WithName("BareMetalHostClaim"),
+	}
+}
+
+func newClaim(name string, spec metal3api.BareMetalHostClaimSpec) *metal3api.BareMetalHostClaim {
+	return &metal3api.BareMetalHostClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       types.UID(name + "-uid"),
+		},
+		Spec: spec,
+	}
+}
+
+func newClaimableHost(name string, state metal3api.ProvisioningState, cpus int, labels map[string]string) *metal3api.BareMetalHost {
+	host := newHost(name, &metal3api.BareMetalHostSpec{})
+	host.Labels = labels
+	host.Status.Provisioning.State = state
+	host.Status.HardwareDetails = &metal3api.HardwareDetails{CPU: metal3api.CPU{Count: cpus}}
+	return host
+}
+
+func reconcileClaim(t *testing.T, r *BareMetalHostClaimReconciler, claim *metal3api.BareMetalHostClaim) *metal3api.BareMetalHostClaim {
+	t.Helper()
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: claim.Namespace, Name: claim.Name}}
+	if _, err := r.Reconcile(context.TODO(), request); err != nil {
+		t.Fatal(err)
+	}
+
+	updated := &metal3api.BareMetalHostClaim{}
+	if err := r.Get(context.TODO(), request.NamespacedName, updated); err != nil {
+		t.Fatal(err)
+	}
+	return updated
+}
+
+func getClaimTestHost(t *testing.T, r *BareMetalHostClaimReconciler, name string) *metal3api.BareMetalHost {
+	t.Helper()
+	host := &metal3api.BareMetalHost{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, host); err != nil {
+		t.Fatal(err)
+	}
+	return host
+}
+
+func TestBareMetalHostClaimBinding(t *testing.T) {
+	rack1 := map[string]string{"rack": "r1"}
+	hosts := []runtime.Object{
+		newClaimableHost("a-small", metal3api.StateAvailable, 8, rack1),
+		newClaimableHost("b-provisioned", metal3api.StateProvisioned, 32, rack1),
+		newClaimableHost("c-other-rack", metal3api.StateAvailable, 32, map[string]string{"rack": "r2"}),
+		newClaimableHost("d-match", metal3api.StateAvailable, 32, rack1),
+		newClaimableHost("e-match", metal3api.StateAvailable, 64, rack1),
+	}
+	spec := metal3api.BareMetalHostClaimSpec{
+		HostSelector: &metav1.LabelSelector{MatchLabels: rack1},
+		Hardware:     metal3api.HardwareRequirements{MinCPUCount: 16},
+	}
+	first := newClaim("first", spec)
+	second := newClaim("second", spec)
+	third := newClaim("third", spec)
+
+	r := newClaimTestReconciler(append(hosts, first, second, third)...)
+
+	first = reconcileClaim(t, r, first)
+	assert.Equal(t, metal3api.ClaimBound, first.Status.Phase)
+	assert.Equal(t, "d-match", first.Status.HostName)
+	assert.NotNil(t, first.Status.BoundTime)
+	assert.Contains(t, first.Finalizers, metal3api.BareMetalHostClaimFinalizer)
+
+	second = reconcileClaim(t, r, second)
+	assert.Equal(t, metal3api.ClaimBound, second.Status.Phase)
+	assert.Equal(t, "e-match", second.Status.HostName)
+
+	third = reconcileClaim(t, r, third)
+	assert.Equal(t, metal3api.ClaimPending, third.Status.Phase)
+	assert.Empty(t, third.Status.HostName)
+
+	host := getClaimTestHost(t, r, "d-match")
+	assert.Equal(t, &corev1.ObjectReference{
+		APIVersion: metal3api.GroupVersion.String(),
+		Kind:       "BareMetalHostClaim",
+		Namespace:  namespace,
+		Name:       "first",
+		UID:        first.UID,
+	}, host.Spec.ConsumerRef)
+
+	// Deleting a claim releases its host for the pending claim.
+	if err := r.Delete(context.TODO(), first); err != nil {
+		t.Fatal(err)
+	}
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "first"}}
+	if _, err := r.Reconcile(context.TODO(), request); err != nil {
+		t.Fatal(err)
+	}
+	host = getClaimTestHost(t, r, "d-match")
+	assert.Nil(t, host.Spec.ConsumerRef)
+
+	third = reconcileClaim(t, r, third)
+	assert.Equal(t, metal3api.ClaimBound, third.Status.Phase)
+	assert.Equal(t, "d-match", third.Status.HostName)
+}
+
+func TestBareMetalHostClaimRecoversBinding(t *testing.T) {
+	claim := newClaim("claim", metal3api.BareMetalHostClaimSpec{})
+	// The host was bound, but the status of the claim was not saved.
+	host := newClaimableHost("host", metal3api.StateAvailable, 8, nil)
+	host.Spec.ConsumerRef = claimReference(claim)
+	other := newClaimableHost("another", metal3api.StateAvailable, 8, nil)
+
+	r := newClaimTestReconciler(host, other, claim)
+
+	claim = reconcileClaim(t, r, claim)
+	assert.Equal(t, metal3api.ClaimBound, claim.Status.Phase)
+	assert.Equal(t, "host", claim.Status.HostName)
+	assert.Nil(t, getClaimTestHost(t, r, "another").Spec.ConsumerRef)
+}
+
+func TestBareMetalHostClaimLost(t *testing.T) {
+	claim := newClaim("claim", metal3api.BareMetalHostClaimSpec{})
+	host := newClaimableHost("host", metal3api.StateAvailable, 8, nil)
+
+	r := newClaimTestReconciler(host, claim)
+
+	claim = reconcileClaim(t, r, claim)
+	assert.Equal(t, metal3api.ClaimBound, claim.Status.Phase)
+
+	host = getClaimTestHost(t, r, "host")
+	host.Spec.ConsumerRef = &corev1.ObjectReference{Kind: "Machine", Name: "other"}
+	if err := r.Update(context.TODO(), host); err != nil {
+		t.Fatal(err)
+	}
+
+	claim = reconcileClaim(t, r, claim)
+	assert.Equal(t, metal3api.ClaimLost, claim.Status.Phase)
+	assert.Equal(t, "host", claim.Status.HostName)
+	assert.NotEmpty(t, claim.Status.Message)
+}
+
+func TestBareMetalHostClaimTaints(t *testing.T) {
+	fenced := newClaimableHost("a-fenced", metal3api.StateAvailable, 8, nil)
+	fenced.Spec.Taints = []corev1.Taint{{Key: "suspect", Effect: corev1.TaintEffectNoSchedule}}
+	avoided := newClaimableHost("b-avoided", metal3api.StateAvailable, 8, nil)
+	avoided.Spec.Taints = []corev1.Taint{{Key: "old", Effect: corev1.TaintEffectPreferNoSchedule}}
+	clean := newClaimableHost("c-clean", metal3api.StateAvailable, 8, nil)
+
+	tolerant := newClaim("tolerant", metal3api.BareMetalHostClaimSpec{
+		Tolerations: []corev1.Toleration{{Key: "suspect", Operator: corev1.TolerationOpExists}},
+	})
+	second := newClaim("second", metal3api.BareMetalHostClaimSpec{})
+	third := newClaim("third", metal3api.BareMetalHostClaimSpec{})
+
+	r := newClaimTestReconciler(fenced, avoided, clean, tolerant, second, third)
+
+	tolerant = reconcileClaim(t, r, tolerant)
+	assert.Equal(t, "a-fenced", tolerant.Status.HostName)
+
+	second = reconcileClaim(t, r, second)
+	assert.Equal(t, "c-clean", second.Status.HostName)
+
+	third = reconcileClaim(t, r, third)
+	assert.Equal(t, "b-avoided", third.Status.HostName)
+}
+
+func TestBareMetalHostClaimHostTainted(t *testing.T) {
+	claim := newClaim("claim", metal3api.BareMetalHostClaimSpec{
+		Tolerations: []corev1.Toleration{{Key: "tolerated", Operator: corev1.TolerationOpExists}},
+	})
+	host := newClaimableHost("host", metal3api.StateAvailable, 8, nil)
+
+	r := newClaimTestReconciler(host, claim)
+
+	claim = reconcileClaim(t, r, claim)
+	assert.Equal(t, metal3api.ClaimBound, claim.Status.Phase)
+
+	setTaints := func(taints ...corev1.Taint) {
+		host := getClaimTestHost(t, r, "host")
+		host.Spec.Taints = taints
+		if err := r.Update(context.TODO(), host); err != nil {
+			t.Fatal(err)
+		}
+	}
+	events := func() []corev1.Event {
+		list := &corev1.EventList{}
+		if err := r.List(context.TODO(), list); err != nil {
+			t.Fatal(err)
+		}
+		return list.Items
+	}
+
+	setTaints(corev1.Taint{Key: "tolerated", Effect: corev1.TaintEffectNoExecute})
+	claim = reconcileClaim(t, r, claim)
+	assert.False(t, meta.IsStatusConditionTrue(claim.Status.Conditions, string(metal3api.ClaimConditionHostTainted)))
+	assert.Empty(t, events())
+
+	setTaints(corev1.Taint{Key: "suspect", Effect: corev1.TaintEffectNoExecute})
+	claim = reconcileClaim(t, r, claim)
+	assert.True(t, meta.IsStatusConditionTrue(claim.Status.Conditions, string(metal3api.ClaimConditionHostTainted)))
+	claim = reconcileClaim(t, r, claim)
+	if len(events()) != 1 {
+		t.Fatalf("expected a single event, got %d", len(events()))
+	}
+	assert.Equal(t, "claim", events()[0].InvolvedObject.Name)
+	assert.Equal(t, metal3api.ClaimBound, claim.Status.Phase, "tainted hosts stay bound")
+}
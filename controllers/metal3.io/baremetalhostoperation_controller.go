@@ -0,0 +1,599 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/utils"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// Hosts in progress are also checked regularly, to detect timeouts.
+	hostOperationRequeueDelay = 30 * time.Second
+
+	rebootStepPoweringOff = "PoweringOff"
+	rebootStepPoweringOn  = "PoweringOn"
+
+	firmwareUpdateStepServicing = "Servicing"
+
+	// The time after which the action is considered failed on a host
+	// when the operation does not set one.
+	defaultHostOperationTimeout = 2 * time.Hour
+)
+
+// BareMetalHostOperationReconciler runs the actions of
+// BareMetalHostOperations across their hosts.
+type BareMetalHostOperationReconciler struct {
+	client.Client
+	Log logr.Logger
+}
+
+// +kubebuilder:rbac:groups=metal3.io,resources=baremetalhostoperations,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=metal3.io,resources=baremetalhostoperations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=metal3.io,resources=baremetalhostoperations/finalizers,verbs=update
+// +kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=metal3.io,resources=hostfirmwarecomponents,verbs=get;list;watch;update
+
+// Reconcile selects the hosts of a new operation, and then follows the
+// progress of the action on the hosts in progress and starts it on the
+// next ones.
+func (r *BareMetalHostOperationReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("baremetalhostoperation", request.NamespacedName)
+
+	op := &metal3api.BareMetalHostOperation{}
+	if err := r.Get(ctx, request.NamespacedName, op); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, errors.Wrap(err, "could not load operation")
+	}
+
+	if !op.DeletionTimestamp.IsZero() {
+		if !utils.StringInList(op.Finalizers, metal3api.BareMetalHostOperationFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		for _, progress := range op.Status.Hosts {
+			if progress.Phase != metal3api.HostOperationInProgress {
+				continue
+			}
+			if err := r.releaseHost(ctx, op, progress.HostName); err != nil {
+				return ctrl.Result{}, err
+			}
+			reqLogger.Info("released host", "host", progress.HostName)
+		}
+		return ctrl.Result{}, r.removeFinalizer(ctx, op)
+	}
+
+	switch op.Status.Phase {
+	case metal3api.OperationSucceeded, metal3api.OperationFailed:
+		return ctrl.Result{}, nil
+	case "":
+		if err := r.startOperation(ctx, reqLogger, op); err != nil {
+			return ctrl.Result{}, err
+		}
+		if op.Status.Phase != metal3api.OperationRunning {
+			return ctrl.Result{}, nil
+		}
+	}
+
+	return r.runOperation(ctx, reqLogger, op)
+}
+
+// startOperation selects the hosts of the operation.
+func (r *BareMetalHostOperationReconciler) startOperation(ctx context.Context, log logr.Logger, op *metal3api.BareMetalHostOperation) error {
+	now := metav1.Now()
+
+	action := op.Spec.Action
+	actions := 0
+	for _, set := range []bool{action.Reboot != nil, action.FirmwareUpdate != nil, action.Reprovision != nil} {
+		if set {
+			actions++
+		}
+	}
+	if actions != 1 {
+		op.Status = metal3api.BareMetalHostOperationStatus{
+			Phase:          metal3api.OperationFailed,
+			Message:        "exactly one action must be set",
+			StartTime:      &now,
+			CompletionTime: &now,
+		}
+		return r.saveStatus(ctx, op)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&op.Spec.HostSelector)
+	if err != nil {
+		op.Status = metal3api.BareMetalHostOperationStatus{
+			Phase:          metal3api.OperationFailed,
+			Message:        fmt.Sprintf("invalid host selector: %s", err),
+			StartTime:      &now,
+			CompletionTime: &now,
+		}
+		return r.saveStatus(ctx, op)
+	}
+
+	hostList := &metal3api.BareMetalHostList{}
+	err = r.List(ctx, hostList, client.InNamespace(op.Namespace),
+		client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return errors.Wrap(err, "failed to list hosts")
+	}
+	sort.Slice(hostList.Items, func(i, j int) bool {
+		return hostList.Items[i].Name < hostList.Items[j].Name
+	})
+
+	if !utils.StringInList(op.Finalizers, metal3api.BareMetalHostOperationFinalizer) {
+		op.Finalizers = append(op.Finalizers, metal3api.BareMetalHostOperationFinalizer)
+		if err := r.Update(ctx, op); err != nil {
+			return errors.Wrap(err, "failed to add finalizer")
+		}
+	}
+
+	log.Info("starting operation", "hosts", len(hostList.Items))
+	op.Status = metal3api.BareMetalHostOperationStatus{
+		Phase:     metal3api.OperationRunning,
+		StartTime: &now,
+	}
+	for _, host := range hostList.Items {
+		op.Status.Hosts = append(op.Status.Hosts, metal3api.HostOperationProgress{
+			HostName: host.Name,
+			Phase:    metal3api.HostOperationPending,
+		})
+	}
+	return r.saveStatus(ctx, op)
+}
+
+// runOperation follows the progress of the hosts in progress, starts the
+// action on the next hosts and finishes the operation when no host is
+// left.
+func (r *BareMetalHostOperationReconciler) runOperation(ctx context.Context, log logr.Logger, op *metal3api.BareMetalHostOperation) (ctrl.Result, error) {
+	original := op.Status.DeepCopy()
+	hosts := op.Status.Hosts
+
+	for i := range hosts {
+		progress := &hosts[i]
+		if progress.Phase != metal3api.HostOperationInProgress {
+			continue
+		}
+		if err := r.checkHost(ctx, op, progress); err != nil {
+			return ctrl.Result{}, err
+		}
+		if progress.Phase == metal3api.HostOperationInProgress &&
+			time.Since(progress.StartTime.Time) > hostOperationTimeout(op) {
+			if err := r.releaseHost(ctx, op, progress.HostName); err != nil {
+				return ctrl.Result{}, err
+			}
+			finishHost(progress, metal3api.HostOperationFailed, "timed out")
+		}
+		if progress.Phase != metal3api.HostOperationInProgress {
+			log.Info("finished host", "host", progress.HostName, "phase", progress.Phase, "message", progress.Message)
+		}
+	}
+
+	maxUnavailable := 1
+	if op.Spec.MaxUnavailable != nil {
+		value, err := intstr.GetScaledValueFromIntOrPercent(op.Spec.MaxUnavailable, len(hosts), false)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "invalid maxUnavailable")
+		}
+		maxUnavailable = max(value, 1)
+	}
+
+	for i := range hosts {
+		progress := &hosts[i]
+		inProgress, failed := countHostOperations(hosts)
+		if failed > op.Spec.FailureThreshold || inProgress >= maxUnavailable {
+			break
+		}
+		if progress.Phase != metal3api.HostOperationPending {
+			continue
+		}
+		if !previousBatchFinished(hosts, op.Spec.BatchSize, i) {
+			break
+		}
+		if err := r.startHost(ctx, op, progress); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info("started host", "host", progress.HostName, "phase", progress.Phase, "message", progress.Message)
+	}
+
+	inProgress, failed := countHostOperations(hosts)
+	op.Status.Succeeded = 0
+	op.Status.Failed = failed
+	pending := false
+	for _, progress := range hosts {
+		switch progress.Phase {
+		case metal3api.HostOperationSucceeded:
+			op.Status.Succeeded++
+		case metal3api.HostOperationPending:
+			pending = true
+		}
+	}
+
+	finished := inProgress == 0 && (!pending || failed > op.Spec.FailureThreshold)
+	if finished {
+		now := metav1.Now()
+		op.Status.CompletionTime = &now
+		op.Status.Phase = metal3api.OperationSucceeded
+		if failed > op.Spec.FailureThreshold {
+			op.Status.Phase = metal3api.OperationFailed
+			op.Status.Message = fmt.Sprintf("the action failed on %d hosts, more than the failure threshold of %d",
+				failed, op.Spec.FailureThreshold)
+		}
+		log.Info("operation finished", "phase", op.Status.Phase, "succeeded", op.Status.Succeeded, "failed", failed)
+	}
+
+	if !reflect.DeepEqual(original, &op.Status) {
+		if err := r.saveStatus(ctx, op); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if finished {
+		return ctrl.Result{}, r.removeFinalizer(ctx, op)
+	}
+	return ctrl.Result{RequeueAfter: hostOperationRequeueDelay}, nil
+}
+
+func hostOperationTimeout(op *metal3api.BareMetalHostOperation) time.Duration {
+	if op.Spec.HostTimeout == nil {
+		return defaultHostOperationTimeout
+	}
+	return op.Spec.HostTimeout.Duration
+}
+
+func countHostOperations(hosts []metal3api.HostOperationProgress) (inProgress int, failed int) {
+	for _, progress := range hosts {
+		switch progress.Phase {
+		case metal3api.HostOperationInProgress:
+			inProgress++
+		case metal3api.HostOperationFailed:
+			failed++
+		}
+	}
+	return
+}
+
+// previousBatchFinished returns whether the action has finished on all
+// hosts of the batches before the one of the host at the given index.
+func previousBatchFinished(hosts []metal3api.HostOperationProgress, batchSize int, index int) bool {
+	if batchSize <= 0 {
+		return true
+	}
+	for _, progress := range hosts[:(index/batchSize)*batchSize] {
+		if progress.Phase == metal3api.HostOperationPending || progress.Phase == metal3api.HostOperationInProgress {
+			return false
+		}
+	}
+	return true
+}
+
+func finishHost(progress *metal3api.HostOperationProgress, phase metal3api.HostOperationPhase, message string) {
+	now := metav1.Now()
+	progress.Phase = phase
+	progress.Step = ""
+	progress.Message = message
+	progress.CompletionTime = &now
+}
+
+func rebootOperationAnnotation(op *metal3api.BareMetalHostOperation) string {
+	return metal3api.RebootAnnotationPrefix + "/" + op.Name
+}
+
+func hostErrorMessage(host *metal3api.BareMetalHost) string {
+	return fmt.Sprintf("the host already has a %s: %s", host.Status.ErrorType, host.Status.ErrorMessage)
+}
+
+// startHost starts the action on a host, or skips the host when the
+// action does not apply to it.
+func (r *BareMetalHostOperationReconciler) startHost(ctx context.Context, op *metal3api.BareMetalHostOperation, progress *metal3api.HostOperationProgress) error {
+	host := &metal3api.BareMetalHost{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: op.Namespace, Name: progress.HostName}, host)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			finishHost(progress, metal3api.HostOperationSkipped, "the host was deleted")
+			return nil
+		}
+		return errors.Wrap(err, "could not load host")
+	}
+
+	state := host.Status.Provisioning.State
+	action := op.Spec.Action
+	switch {
+	case action.Reboot != nil:
+		if (state != metal3api.StateProvisioned && state != metal3api.StateExternallyProvisioned) || !host.Spec.Online {
+			finishHost(progress, metal3api.HostOperationSkipped, "the host is not provisioned and online")
+			return nil
+		}
+		// A power management error fails the reboot, so a host already
+		// in error is skipped rather than failed on the first check.
+		if host.Status.ErrorType != "" {
+			finishHost(progress, metal3api.HostOperationSkipped, hostErrorMessage(host))
+			return nil
+		}
+		mode := action.Reboot.Mode
+		if mode == "" {
+			mode = metal3api.RebootModeSoft
+		}
+		value, err := json.Marshal(metal3api.RebootAnnotationArguments{Mode: mode})
+		if err != nil {
+			return errors.Wrap(err, "failed to encode reboot annotation")
+		}
+		if host.Annotations == nil {
+			host.Annotations = map[string]string{}
+		}
+		host.Annotations[rebootOperationAnnotation(op)] = string(value)
+		if err := r.Update(ctx, host); err != nil {
+			return errors.Wrap(err, "failed to add reboot annotation")
+		}
+		progress.Step = rebootStepPoweringOff
+
+	case action.FirmwareUpdate != nil:
+		if state != metal3api.StateProvisioned {
+			finishHost(progress, metal3api.HostOperationSkipped, "the host is not provisioned")
+			return nil
+		}
+		hfc := &metal3api.HostFirmwareComponents{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: op.Namespace, Name: progress.HostName}, hfc)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				finishHost(progress, metal3api.HostOperationSkipped, "the host has no HostFirmwareComponents")
+				return nil
+			}
+			return errors.Wrap(err, "could not load host firmware components")
+		}
+		// The updates are recorded in the status when servicing starts
+		if reflect.DeepEqual(hfc.Status.Updates, action.FirmwareUpdate.Updates) &&
+			host.Status.ErrorType != metal3api.ServicingError {
+			finishHost(progress, metal3api.HostOperationSucceeded, "the firmware updates are already applied")
+			return nil
+		}
+		hfc.Spec.Updates = action.FirmwareUpdate.Updates
+		if err := r.Update(ctx, hfc); err != nil {
+			return errors.Wrap(err, "failed to update host firmware components")
+		}
+		if host.Annotations == nil {
+			host.Annotations = map[string]string{}
+		}
+		host.Annotations[metal3api.ServicingAnnotation] = ""
+		if err := r.Update(ctx, host); err != nil {
+			return errors.Wrap(err, "failed to add servicing annotation")
+		}
+
+	case action.Reprovision != nil:
+		if state != metal3api.StateProvisioned {
+			finishHost(progress, metal3api.HostOperationSkipped, "the host is not provisioned")
+			return nil
+		}
+		if host.Status.Provisioning.Image.URL == action.Reprovision.Image.URL {
+			finishHost(progress, metal3api.HostOperationSucceeded, "the host is already provisioned with the image")
+			return nil
+		}
+		// Any error fails the reprovisioning, so a host already in error
+		// is skipped rather than failed on the first check.
+		if host.Status.ErrorType != "" {
+			finishHost(progress, metal3api.HostOperationSkipped, hostErrorMessage(host))
+			return nil
+		}
+		host.Spec.Image = action.Reprovision.Image.DeepCopy()
+		if err := r.Update(ctx, host); err != nil {
+			return errors.Wrap(err, "failed to update host image")
+		}
+	}
+
+	now := metav1.Now()
+	progress.Phase = metal3api.HostOperationInProgress
+	progress.StartTime = &now
+	return nil
+}
+
+// checkHost follows the progress of the action on a host.
+func (r *BareMetalHostOperationReconciler) checkHost(ctx context.Context, op *metal3api.BareMetalHostOperation, progress *metal3api.HostOperationProgress) error {
+	host := &metal3api.BareMetalHost{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: op.Namespace, Name: progress.HostName}, host)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			finishHost(progress, metal3api.HostOperationFailed, "the host was deleted")
+			return nil
+		}
+		return errors.Wrap(err, "could not load host")
+	}
+
+	action := op.Spec.Action
+	switch {
+	case action.Reboot != nil:
+		if host.Status.ErrorType == metal3api.PowerManagementError {
+			if err := r.releaseHost(ctx, op, host.Name); err != nil {
+				return err
+			}
+			finishHost(progress, metal3api.HostOperationFailed, host.Status.ErrorMessage)
+			return nil
+		}
+		switch progress.Step {
+		case rebootStepPoweringOff:
+			if host.Status.PoweredOn {
+				return nil
+			}
+			delete(host.Annotations, rebootOperationAnnotation(op))
+			if err := r.Update(ctx, host); err != nil {
+				return errors.Wrap(err, "failed to remove reboot annotation")
+			}
+			progress.Step = rebootStepPoweringOn
+		case rebootStepPoweringOn:
+			if host.Status.PoweredOn {
+				finishHost(progress, metal3api.HostOperationSucceeded, "")
+			}
+		}
+
+	case action.FirmwareUpdate != nil:
+		if host.Status.ErrorType == metal3api.ServicingError {
+			if err := r.releaseHost(ctx, op, host.Name); err != nil {
+				return err
+			}
+			finishHost(progress, metal3api.HostOperationFailed, host.Status.ErrorMessage)
+			return nil
+		}
+		if host.OperationalStatus() == metal3api.OperationalStatusServicing {
+			progress.Step = firmwareUpdateStepServicing
+			return nil
+		}
+		hfc := &metal3api.HostFirmwareComponents{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: op.Namespace, Name: progress.HostName}, hfc)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				if err := r.releaseHost(ctx, op, host.Name); err != nil {
+					return err
+				}
+				finishHost(progress, metal3api.HostOperationFailed, "the host firmware components were deleted")
+				return nil
+			}
+			return errors.Wrap(err, "could not load host firmware components")
+		}
+		// An invalid update is never serviced
+		valid := meta.FindStatusCondition(hfc.Status.Conditions, string(metal3api.HostFirmwareComponentsValid))
+		if valid != nil && valid.Status == metav1.ConditionFalse && valid.ObservedGeneration == hfc.Generation {
+			if err := r.releaseHost(ctx, op, host.Name); err != nil {
+				return err
+			}
+			finishHost(progress, metal3api.HostOperationFailed, valid.Message)
+			return nil
+		}
+		// The updates are recorded in the status when servicing starts,
+		// and no change is detected any more once they are applied. A
+		// servicing that started and finished between two checks is
+		// covered too.
+		if !reflect.DeepEqual(hfc.Status.Updates, action.FirmwareUpdate.Updates) {
+			return nil
+		}
+		changeDetected := meta.FindStatusCondition(hfc.Status.Conditions, string(metal3api.HostFirmwareComponentsChangeDetected))
+		if changeDetected == nil || changeDetected.Status != metav1.ConditionFalse ||
+			changeDetected.ObservedGeneration != hfc.Generation {
+			return nil
+		}
+		if err := r.releaseHost(ctx, op, host.Name); err != nil {
+			return err
+		}
+		finishHost(progress, metal3api.HostOperationSucceeded, "")
+
+	case action.Reprovision != nil:
+		if host.Status.Provisioning.State == metal3api.StateProvisioned &&
+			host.Status.Provisioning.Image.URL == action.Reprovision.Image.URL {
+			finishHost(progress, metal3api.HostOperationSucceeded, "")
+			return nil
+		}
+		// Any error, such as failing to power off the host or to clean
+		// it while deprovisioning, fails the host rather than keeping it
+		// in progress while the host retries.
+		if host.Status.ErrorType != "" {
+			finishHost(progress, metal3api.HostOperationFailed, host.Status.ErrorMessage)
+		}
+	}
+	return nil
+}
+
+// releaseHost removes the annotations the operation added to a host, so
+// that a host being rebooted is powered on again and no new servicing is
+// started.
+func (r *BareMetalHostOperationReconciler) releaseHost(ctx context.Context, op *metal3api.BareMetalHostOperation, hostName string) error {
+	var annotation string
+	switch {
+	case op.Spec.Action.Reboot != nil:
+		annotation = rebootOperationAnnotation(op)
+	case op.Spec.Action.FirmwareUpdate != nil:
+		annotation = metal3api.ServicingAnnotation
+	default:
+		return nil
+	}
+
+	host := &metal3api.BareMetalHost{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: op.Namespace, Name: hostName}, host)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "could not load host")
+	}
+	if _, ok := host.Annotations[annotation]; !ok {
+		return nil
+	}
+	delete(host.Annotations, annotation)
+	return errors.Wrap(r.Update(ctx, host), "failed to release host")
+}
+
+func (r *BareMetalHostOperationReconciler) removeFinalizer(ctx context.Context, op *metal3api.BareMetalHostOperation) error {
+	if !utils.StringInList(op.Finalizers, metal3api.BareMetalHostOperationFinalizer) {
+		return nil
+	}
+	op.Finalizers = utils.FilterStringFromList(op.Finalizers, metal3api.BareMetalHostOperationFinalizer)
+	return errors.Wrap(r.Update(ctx, op), "failed to remove finalizer")
+}
+
+func (r *BareMetalHostOperationReconciler) saveStatus(ctx context.Context, op *metal3api.BareMetalHostOperation) error {
+	return errors.Wrap(r.Status().Update(ctx, op), "failed to update operation status")
+}
+
+// operationsForHost returns the running operations a host that changed
+// is part of.
+func (r *BareMetalHostOperationReconciler) operationsForHost(ctx context.Context, obj client.Object) []reconcile.Request {
+	ops := &metal3api.BareMetalHostOperationList{}
+	if err := r.List(ctx, ops, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "failed to list operations", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, op := range ops.Items {
+		if op.Status.Phase != metal3api.OperationRunning {
+			continue
+		}
+		for _, progress := range op.Status.Hosts {
+			if progress.HostName == obj.GetName() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: op.Namespace, Name: op.Name},
+				})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// SetupWithManager registers the reconciler to be run by the manager.
+func (r *BareMetalHostOperationReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconcile int) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&metal3api.BareMetalHostOperation{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconcile}).
+		Watches(&metal3api.BareMetalHost{}, handler.EnqueueRequestsFromMapFunc(r.operationsForHost)).
+		Complete(r)
+}
@@ -0,0 +1,436 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newOperationTestReconciler(initObjs ...runtime.Object) *BareMetalHostOperationReconciler {
+	clientBuilder := fakeclient.NewClientBuilder().WithRuntimeObjects(initObjs...)
+	for _, v := range initObjs {
+		clientBuilder = clientBuilder.WithStatusSubresource(v.(client.Object))
+	}
+
+	return &BareMetalHostOperationReconciler{
+		Client: clientBuilder.Build(),
+		Log:    ctrl.Log.WithName("controllers").WithName("BareMetalHostOperation"),
+	}
+}
+
+func newOperation(action metal3api.BareMetalHostOperationAction) *metal3api.BareMetalHostOperation {
+	return &metal3api.BareMetalHostOperation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rack1",
+			Namespace: namespace,
+		},
+		Spec: metal3api.BareMetalHostOperationSpec{
+			HostSelector: metav1.LabelSelector{MatchLabels: map[string]string{"rack": "r1"}},
+			Action:       action,
+		},
+	}
+}
+
+func newOperationHost(name string, state metal3api.ProvisioningState) *metal3api.BareMetalHost {
+	host := newHost(name, &metal3api.BareMetalHostSpec{Online: true})
+	host.Labels = map[string]string{"rack": "r1"}
+	host.Status.Provisioning.State = state
+	host.Status.Provisioning.Image.URL = "http://example.com/old.qcow2"
+	host.Status.PoweredOn = true
+	return host
+}
+
+func reconcileOperation(t *testing.T, r *BareMetalHostOperationReconciler) *metal3api.BareMetalHostOperation {
+	t.Helper()
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "rack1"}}
+	if _, err := r.Reconcile(context.TODO(), request); err != nil {
+		t.Fatal(err)
+	}
+
+	op := &metal3api.BareMetalHostOperation{}
+	if err := r.Get(context.TODO(), request.NamespacedName, op); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			t.Fatal(err)
+		}
+		return nil
+	}
+	return op
+}
+
+func getOperationHost(t *testing.T, r *BareMetalHostOperationReconciler, name string) *metal3api.BareMetalHost {
+	t.Helper()
+	host := &metal3api.BareMetalHost{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, host); err != nil {
+		t.Fatal(err)
+	}
+	return host
+}
+
+func updateOperationHostStatus(t *testing.T, r *BareMetalHostOperationReconciler, name string, update func(*metal3api.BareMetalHost)) {
+	t.Helper()
+	host := getOperationHost(t, r, name)
+	update(host)
+	if err := r.Status().Update(context.TODO(), host); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func hostPhases(op *metal3api.BareMetalHostOperation) map[string]metal3api.HostOperationPhase {
+	phases := map[string]metal3api.HostOperationPhase{}
+	for _, progress := range op.Status.Hosts {
+		phases[progress.HostName] = progress.Phase
+	}
+	return phases
+}
+
+func TestBareMetalHostOperationReboot(t *testing.T) {
+	op := newOperation(metal3api.BareMetalHostOperationAction{Reboot: &metal3api.RebootOperation{Mode: metal3api.RebootModeHard}})
+	maxUnavailable := intstr.FromInt(2)
+	op.Spec.MaxUnavailable = &maxUnavailable
+	other := newOperationHost("other-rack", metal3api.StateProvisioned)
+	other.Labels = nil
+	r := newOperationTestReconciler(op,
+		newOperationHost("host-0", metal3api.StateProvisioned),
+		newOperationHost("host-1", metal3api.StateExternallyProvisioned),
+		newOperationHost("host-2", metal3api.StateProvisioned),
+		newOperationHost("host-3", metal3api.StateAvailable),
+		other,
+	)
+	annotation := metal3api.RebootAnnotationPrefix + "/rack1"
+
+	op = reconcileOperation(t, r)
+	assert.Equal(t, metal3api.OperationRunning, op.Status.Phase)
+	assert.Contains(t, op.Finalizers, metal3api.BareMetalHostOperationFinalizer)
+	assert.Equal(t, map[string]metal3api.HostOperationPhase{
+		"host-0": metal3api.HostOperationInProgress,
+		"host-1": metal3api.HostOperationInProgress,
+		"host-2": metal3api.HostOperationPending,
+		"host-3": metal3api.HostOperationPending,
+	}, hostPhases(op))
+	assert.Equal(t, `{"mode":"hard","force":false}`, getOperationHost(t, r, "host-0").Annotations[annotation])
+	assert.Contains(t, getOperationHost(t, r, "host-1").Annotations, annotation)
+	assert.NotContains(t, getOperationHost(t, r, "host-2").Annotations, annotation)
+
+	// The host is powered on again once powered off
+	updateOperationHostStatus(t, r, "host-0", func(host *metal3api.BareMetalHost) { host.Status.PoweredOn = false })
+	op = reconcileOperation(t, r)
+	assert.Equal(t, rebootStepPoweringOn, op.Status.Hosts[0].Step)
+	assert.NotContains(t, getOperationHost(t, r, "host-0").Annotations, annotation)
+
+	updateOperationHostStatus(t, r, "host-0", func(host *metal3api.BareMetalHost) { host.Status.PoweredOn = true })
+	op = reconcileOperation(t, r)
+	assert.Equal(t, map[string]metal3api.HostOperationPhase{
+		"host-0": metal3api.HostOperationSucceeded,
+		"host-1": metal3api.HostOperationInProgress,
+		"host-2": metal3api.HostOperationInProgress,
+		"host-3": metal3api.HostOperationPending,
+	}, hostPhases(op))
+
+	for _, name := range []string{"host-1", "host-2"} {
+		updateOperationHostStatus(t, r, name, func(host *metal3api.BareMetalHost) { host.Status.PoweredOn = false })
+		reconcileOperation(t, r)
+		updateOperationHostStatus(t, r, name, func(host *metal3api.BareMetalHost) { host.Status.PoweredOn = true })
+	}
+	op = reconcileOperation(t, r)
+	assert.Equal(t, metal3api.OperationSucceeded, op.Status.Phase)
+	assert.Equal(t, 3, op.Status.Succeeded)
+	assert.Equal(t, 0, op.Status.Failed)
+	assert.Equal(t, metal3api.HostOperationSkipped, op.Status.Hosts[3].Phase)
+	assert.NotNil(t, op.Status.CompletionTime)
+	assert.NotContains(t, op.Finalizers, metal3api.BareMetalHostOperationFinalizer)
+}
+
+func TestBareMetalHostOperationFailureThreshold(t *testing.T) {
+	image := metal3api.Image{URL: "http://example.com/new.qcow2"}
+	op := newOperation(metal3api.BareMetalHostOperationAction{Reprovision: &metal3api.ReprovisionOperation{Image: image}})
+	r := newOperationTestReconciler(op,
+		newOperationHost("host-0", metal3api.StateProvisioned),
+		newOperationHost("host-1", metal3api.StateProvisioned),
+	)
+
+	op = reconcileOperation(t, r)
+	assert.Equal(t, &image, getOperationHost(t, r, "host-0").Spec.Image)
+	assert.Nil(t, getOperationHost(t, r, "host-1").Spec.Image)
+
+	updateOperationHostStatus(t, r, "host-0", func(host *metal3api.BareMetalHost) {
+		host.Status.Provisioning.State = metal3api.StateProvisioning
+		host.Status.ErrorType = metal3api.ProvisioningError
+		host.Status.ErrorMessage = "deploy failed"
+	})
+	op = reconcileOperation(t, r)
+	assert.Equal(t, metal3api.OperationFailed, op.Status.Phase)
+	assert.Equal(t, 1, op.Status.Failed)
+	assert.Equal(t, "deploy failed", op.Status.Hosts[0].Message)
+	assert.Equal(t, metal3api.HostOperationPending, op.Status.Hosts[1].Phase)
+	assert.Nil(t, getOperationHost(t, r, "host-1").Spec.Image)
+}
+
+func TestBareMetalHostOperationReprovision(t *testing.T) {
+	image := metal3api.Image{URL: "http://example.com/new.qcow2"}
+	op := newOperation(metal3api.BareMetalHostOperationAction{Reprovision: &metal3api.ReprovisionOperation{Image: image}})
+	current := newOperationHost("host-1", metal3api.StateProvisioned)
+	current.Status.Provisioning.Image = image
+	r := newOperationTestReconciler(op, newOperationHost("host-0", metal3api.StateProvisioned), current)
+
+	reconcileOperation(t, r)
+	updateOperationHostStatus(t, r, "host-0", func(host *metal3api.BareMetalHost) {
+		host.Status.Provisioning.Image = image
+	})
+	op = reconcileOperation(t, r)
+	assert.Equal(t, metal3api.OperationSucceeded, op.Status.Phase)
+	assert.Equal(t, 2, op.Status.Succeeded)
+	assert.Equal(t, "the host is already provisioned with the image", op.Status.Hosts[1].Message)
+}
+
+func TestBareMetalHostOperationReprovisionError(t *testing.T) {
+	image := metal3api.Image{URL: "http://example.com/new.qcow2"}
+	op := newOperation(metal3api.BareMetalHostOperationAction{Reprovision: &metal3api.ReprovisionOperation{Image: image}})
+	r := newOperationTestReconciler(op, newOperationHost("host-0", metal3api.StateProvisioned))
+
+	reconcileOperation(t, r)
+	updateOperationHostStatus(t, r, "host-0", func(host *metal3api.BareMetalHost) {
+		host.Status.Provisioning.State = metal3api.StateDeprovisioning
+		host.Status.ErrorType = metal3api.PowerManagementError
+		host.Status.ErrorMessage = "BMC unreachable"
+	})
+	op = reconcileOperation(t, r)
+	assert.Equal(t, metal3api.OperationFailed, op.Status.Phase)
+	assert.Equal(t, metal3api.HostOperationFailed, op.Status.Hosts[0].Phase)
+	assert.Equal(t, "BMC unreachable", op.Status.Hosts[0].Message)
+}
+
+func TestBareMetalHostOperationExistingError(t *testing.T) {
+	image := metal3api.Image{URL: "http://example.com/new.qcow2"}
+	actions := map[string]metal3api.BareMetalHostOperationAction{
+		"reboot":      {Reboot: &metal3api.RebootOperation{}},
+		"reprovision": {Reprovision: &metal3api.ReprovisionOperation{Image: image}},
+	}
+	for name, action := range actions {
+		t.Run(name, func(t *testing.T) {
+			failing := newOperationHost("host-0", metal3api.StateProvisioned)
+			failing.Status.ErrorType = metal3api.PowerManagementError
+			failing.Status.ErrorMessage = "BMC unreachable"
+			r := newOperationTestReconciler(newOperation(action), failing)
+
+			op := reconcileOperation(t, r)
+			assert.Equal(t, metal3api.OperationSucceeded, op.Status.Phase)
+			assert.Equal(t, 0, op.Status.Failed)
+			assert.Equal(t, metal3api.HostOperationSkipped, op.Status.Hosts[0].Phase)
+			assert.Equal(t, "the host already has a power management error: BMC unreachable", op.Status.Hosts[0].Message)
+			host := getOperationHost(t, r, "host-0")
+			assert.Empty(t, host.Annotations)
+			assert.Nil(t, host.Spec.Image)
+		})
+	}
+}
+
+func TestBareMetalHostOperationFirmwareUpdate(t *testing.T) {
+	updates := []metal3api.FirmwareUpdate{{Component: "bmc", URL: "http://example.com/bmc.bin"}}
+	op := newOperation(metal3api.BareMetalHostOperationAction{FirmwareUpdate: &metal3api.FirmwareUpdateOperation{Updates: updates}})
+	hfc := &metal3api.HostFirmwareComponents{
+		ObjectMeta: metav1.ObjectMeta{Name: "host-0", Namespace: namespace},
+	}
+	r := newOperationTestReconciler(op, hfc, newOperationHost("host-0", metal3api.StateProvisioned))
+
+	reconcileOperation(t, r)
+	assert.Contains(t, getOperationHost(t, r, "host-0").Annotations, metal3api.ServicingAnnotation)
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: "host-0"}, hfc); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, updates, hfc.Spec.Updates)
+
+	// Servicing started, but not yet recorded on the host
+	hfc.Status.Updates = updates
+	if err := r.Status().Update(context.TODO(), hfc); err != nil {
+		t.Fatal(err)
+	}
+	op = reconcileOperation(t, r)
+	assert.Equal(t, metal3api.HostOperationInProgress, op.Status.Hosts[0].Phase)
+	assert.Empty(t, op.Status.Hosts[0].Step)
+
+	// Servicing in progress
+	updateOperationHostStatus(t, r, "host-0", func(host *metal3api.BareMetalHost) {
+		host.SetOperationalStatus(metal3api.OperationalStatusServicing)
+	})
+	op = reconcileOperation(t, r)
+	assert.Equal(t, metal3api.HostOperationInProgress, op.Status.Hosts[0].Phase)
+	assert.Equal(t, firmwareUpdateStepServicing, op.Status.Hosts[0].Step)
+
+	updateOperationHostStatus(t, r, "host-0", func(host *metal3api.BareMetalHost) {
+		host.SetOperationalStatus(metal3api.OperationalStatusOK)
+	})
+	setFirmwareComponentsCondition(t, r, metal3api.HostFirmwareComponentsChangeDetected, metav1.ConditionFalse, "")
+	op = reconcileOperation(t, r)
+	assert.Equal(t, metal3api.OperationSucceeded, op.Status.Phase)
+	assert.NotContains(t, getOperationHost(t, r, "host-0").Annotations, metal3api.ServicingAnnotation)
+}
+
+func setFirmwareComponentsCondition(t *testing.T, r *BareMetalHostOperationReconciler, cond metal3api.UpdatesConditionType, status metav1.ConditionStatus, message string) {
+	t.Helper()
+	hfc := &metal3api.HostFirmwareComponents{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: "host-0"}, hfc); err != nil {
+		t.Fatal(err)
+	}
+	meta.SetStatusCondition(&hfc.Status.Conditions, metav1.Condition{
+		Type:               string(cond),
+		Status:             status,
+		ObservedGeneration: hfc.Generation,
+		Reason:             "test",
+		Message:            message,
+	})
+	if err := r.Status().Update(context.TODO(), hfc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBareMetalHostOperationFirmwareUpdateUnobserved(t *testing.T) {
+	updates := []metal3api.FirmwareUpdate{{Component: "bmc", URL: "http://example.com/bmc.bin"}}
+	op := newOperation(metal3api.BareMetalHostOperationAction{FirmwareUpdate: &metal3api.FirmwareUpdateOperation{Updates: updates}})
+	hfc := &metal3api.HostFirmwareComponents{
+		ObjectMeta: metav1.ObjectMeta{Name: "host-0", Namespace: namespace},
+	}
+	r := newOperationTestReconciler(op, hfc, newOperationHost("host-0", metal3api.StateProvisioned))
+
+	op = reconcileOperation(t, r)
+	assert.Equal(t, metal3api.HostOperationInProgress, op.Status.Hosts[0].Phase)
+
+	// Servicing started and finished between two reconciles
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: "host-0"}, hfc); err != nil {
+		t.Fatal(err)
+	}
+	hfc.Status.Updates = updates
+	if err := r.Status().Update(context.TODO(), hfc); err != nil {
+		t.Fatal(err)
+	}
+	setFirmwareComponentsCondition(t, r, metal3api.HostFirmwareComponentsChangeDetected, metav1.ConditionFalse, "")
+
+	op = reconcileOperation(t, r)
+	assert.Equal(t, metal3api.OperationSucceeded, op.Status.Phase)
+	assert.Equal(t, metal3api.HostOperationSucceeded, op.Status.Hosts[0].Phase)
+	assert.NotContains(t, getOperationHost(t, r, "host-0").Annotations, metal3api.ServicingAnnotation)
+}
+
+func TestBareMetalHostOperationFirmwareUpdateInvalid(t *testing.T) {
+	updates := []metal3api.FirmwareUpdate{{Component: "nic", URL: "http://example.com/nic.bin"}}
+	op := newOperation(metal3api.BareMetalHostOperationAction{FirmwareUpdate: &metal3api.FirmwareUpdateOperation{Updates: updates}})
+	hfc := &metal3api.HostFirmwareComponents{
+		ObjectMeta: metav1.ObjectMeta{Name: "host-0", Namespace: namespace},
+	}
+	r := newOperationTestReconciler(op, hfc, newOperationHost("host-0", metal3api.StateProvisioned))
+
+	op = reconcileOperation(t, r)
+	assert.Equal(t, metal3api.HostOperationInProgress, op.Status.Hosts[0].Phase)
+
+	setFirmwareComponentsCondition(t, r, metal3api.HostFirmwareComponentsValid, metav1.ConditionFalse, "Invalid Firmware Components: nic")
+	op = reconcileOperation(t, r)
+	assert.Equal(t, metal3api.OperationFailed, op.Status.Phase)
+	assert.Equal(t, metal3api.HostOperationFailed, op.Status.Hosts[0].Phase)
+	assert.Equal(t, "Invalid Firmware Components: nic", op.Status.Hosts[0].Message)
+	assert.NotContains(t, getOperationHost(t, r, "host-0").Annotations, metal3api.ServicingAnnotation)
+}
+
+func TestBareMetalHostOperationFirmwareUpdateApplied(t *testing.T) {
+	updates := []metal3api.FirmwareUpdate{{Component: "bmc", URL: "http://example.com/bmc.bin"}}
+	op := newOperation(metal3api.BareMetalHostOperationAction{FirmwareUpdate: &metal3api.FirmwareUpdateOperation{Updates: updates}})
+	hfc := &metal3api.HostFirmwareComponents{
+		ObjectMeta: metav1.ObjectMeta{Name: "host-0", Namespace: namespace},
+		Status:     metal3api.HostFirmwareComponentsStatus{Updates: updates},
+	}
+	r := newOperationTestReconciler(op, hfc, newOperationHost("host-0", metal3api.StateProvisioned))
+
+	op = reconcileOperation(t, r)
+	assert.Equal(t, metal3api.OperationSucceeded, op.Status.Phase)
+	assert.Equal(t, "the firmware updates are already applied", op.Status.Hosts[0].Message)
+	assert.NotContains(t, getOperationHost(t, r, "host-0").Annotations, metal3api.ServicingAnnotation)
+}
+
+func TestBareMetalHostOperationTimeout(t *testing.T) {
+	op := newOperation(metal3api.BareMetalHostOperationAction{Reboot: &metal3api.RebootOperation{}})
+	op.Spec.HostTimeout = &metav1.Duration{Duration: time.Minute}
+	op.Spec.FailureThreshold = 1
+	r := newOperationTestReconciler(op, newOperationHost("host-0", metal3api.StateProvisioned))
+
+	op = reconcileOperation(t, r)
+	op.Status.Hosts[0].StartTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+	if err := r.Status().Update(context.TODO(), op); err != nil {
+		t.Fatal(err)
+	}
+
+	op = reconcileOperation(t, r)
+	assert.Equal(t, metal3api.OperationSucceeded, op.Status.Phase)
+	assert.Equal(t, 1, op.Status.Failed)
+	assert.Equal(t, "timed out", op.Status.Hosts[0].Message)
+	assert.NotContains(t, getOperationHost(t, r, "host-0").Annotations, metal3api.RebootAnnotationPrefix+"/rack1")
+}
+
+func TestBareMetalHostOperationDefaultTimeout(t *testing.T) {
+	op := newOperation(metal3api.BareMetalHostOperationAction{Reboot: &metal3api.RebootOperation{}})
+	op.Spec.FailureThreshold = 1
+	r := newOperationTestReconciler(op, newOperationHost("host-0", metal3api.StateProvisioned))
+
+	op = reconcileOperation(t, r)
+	op.Status.Hosts[0].StartTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+	if err := r.Status().Update(context.TODO(), op); err != nil {
+		t.Fatal(err)
+	}
+	op = reconcileOperation(t, r)
+	assert.Equal(t, metal3api.HostOperationInProgress, op.Status.Hosts[0].Phase)
+
+	op.Status.Hosts[0].StartTime = &metav1.Time{Time: time.Now().Add(-defaultHostOperationTimeout - time.Minute)}
+	if err := r.Status().Update(context.TODO(), op); err != nil {
+		t.Fatal(err)
+	}
+	op = reconcileOperation(t, r)
+	assert.Equal(t, metal3api.HostOperationFailed, op.Status.Hosts[0].Phase)
+	assert.Equal(t, "timed out", op.Status.Hosts[0].Message)
+}
+
+func TestBareMetalHostOperationDeleted(t *testing.T) {
+	op := newOperation(metal3api.BareMetalHostOperationAction{Reboot: &metal3api.RebootOperation{}})
+	r := newOperationTestReconciler(op, newOperationHost("host-0", metal3api.StateProvisioned))
+
+	op = reconcileOperation(t, r)
+	assert.Contains(t, getOperationHost(t, r, "host-0").Annotations, metal3api.RebootAnnotationPrefix+"/rack1")
+
+	if err := r.Delete(context.TODO(), op); err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, reconcileOperation(t, r))
+	assert.NotContains(t, getOperationHost(t, r, "host-0").Annotations, metal3api.RebootAnnotationPrefix+"/rack1")
+}
+
+func TestBareMetalHostOperationInvalidAction(t *testing.T) {
+	r := newOperationTestReconciler(newOperation(metal3api.BareMetalHostOperationAction{}))
+
+	op := reconcileOperation(t, r)
+	assert.Equal(t, metal3api.OperationFailed, op.Status.Phase)
+	assert.Equal(t, "exactly one action must be set", op.Status.Message)
+}
+
+func TestPreviousBatchFinished(t *testing.T) {
+	hosts := []metal3api.HostOperationProgress{
+		{Phase: metal3api.HostOperationSucceeded},
+		{Phase: metal3api.HostOperationInProgress},
+		{Phase: metal3api.HostOperationPending},
+		{Phase: metal3api.HostOperationPending},
+	}
+
+	assert.True(t, previousBatchFinished(hosts, 0, 2))
+	assert.True(t, previousBatchFinished(hosts, 3, 2))
+	assert.False(t, previousBatchFinished(hosts, 2, 2))
+	assert.False(t, previousBatchFinished(hosts, 2, 3))
+
+	hosts[1].Phase = metal3api.HostOperationFailed
+	assert.True(t, previousBatchFinished(hosts, 2, 3))
+}
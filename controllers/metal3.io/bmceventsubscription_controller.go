@@ -182,7 +182,10 @@ func (r *BMCEventSubscriptionReconciler) createSubscription(ctx context.Context,
 		return err
 	}
 
-	if _, err := prov.AddBMCEventSubscriptionForNode(subscription, headers); err != nil {
+	provCtx, cancel := context.WithTimeout(ctx, provisionerCallTimeout)
+	defer cancel()
+
+	if _, err := prov.AddBMCEventSubscriptionForNode(provCtx, subscription, headers); err != nil {
 		return errors.Wrap(err, "failed to create subscription")
 	}
 
@@ -194,7 +197,10 @@ func (r *BMCEventSubscriptionReconciler) deleteSubscription(ctx context.Context,
 	reqLogger.Info("deleting subscription")
 
 	if subscriptionHasFinalizer(subscription) {
-		if _, err := prov.RemoveBMCEventSubscriptionForNode(*subscription); err != nil {
+		provCtx, cancel := context.WithTimeout(ctx, provisionerCallTimeout)
+		defer cancel()
+
+		if _, err := prov.RemoveBMCEventSubscriptionForNode(provCtx, *subscription); err != nil {
 			return errors.Wrap(err, "failed to remove a subscription")
 		}
 
@@ -219,7 +225,10 @@ func (r *BMCEventSubscriptionReconciler) getProvisioner(ctx context.Context, req
 		return prov, ready, errors.Wrap(err, "failed to create provisioner")
 	}
 
-	ready, err = prov.TryInit()
+	provCtx, cancel := context.WithTimeout(ctx, provisionerCallTimeout)
+	defer cancel()
+
+	ready, err = prov.TryInit(provCtx)
 	if err != nil {
 		return prov, ready, errors.Wrap(err, "failed to check services availability")
 	}
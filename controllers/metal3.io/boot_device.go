@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"fmt"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// actionSetBootDevice applies the boot device request of the host. Each
+// request is only handled once, a failed request is retried when it is
+// changed or removed and added again. It returns nil when there is
+// nothing to do.
+func (r *BareMetalHostReconciler) actionSetBootDevice(prov provisioner.Provisioner, info *reconcileInfo) actionResult {
+	request := info.host.Spec.BootDevice
+	status := info.host.Status.BootDevice
+
+	if request == nil {
+		if status == nil || status.Requested == nil {
+			return nil
+		}
+		// Forget the last request so that it can be made again
+		status.Requested = nil
+		status.Result = ""
+		status.ErrorMessage = ""
+		return actionUpdate{}
+	}
+
+	if status != nil && status.Requested != nil && *status.Requested == *request {
+		return nil
+	}
+
+	ctx, cancel := info.provisionerContext(provisionerCallTimeout)
+	defer cancel()
+
+	provResult, err := prov.SetBootDevice(ctx, provisioner.BootDeviceData{
+		Device:     request.Device,
+		Persistent: request.Persistent,
+	})
+	if err != nil {
+		return actionError{errors.Wrap(err, "failed to set boot device")}
+	}
+	if provResult.Dirty {
+		return actionContinue{provResult.RequeueAfter}
+	}
+
+	if status == nil {
+		status = &metal3api.BootDeviceStatus{}
+		info.host.Status.BootDevice = status
+	}
+	status.Requested = request.DeepCopy()
+
+	if provResult.ErrorMessage != "" {
+		info.log.Info("failed to set boot device", "device", request.Device, "error", provResult.ErrorMessage)
+		status.Result = metal3api.BootDeviceFailed
+		status.ErrorMessage = provResult.ErrorMessage
+		info.publishEvent("BootDeviceFailed", provResult.ErrorMessage)
+		return actionUpdate{}
+	}
+
+	info.log.Info("boot device set", "device", request.Device, "persistent", request.Persistent)
+	now := metav1.Now()
+	status.Result = metal3api.BootDeviceApplied
+	status.ErrorMessage = ""
+	status.LastApplied = request.DeepCopy()
+	status.LastAppliedTime = &now
+	info.publishEvent("BootDeviceApplied", fmt.Sprintf("Boot device set to %s", request.Device))
+	return actionUpdate{}
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetBootDevice(t *testing.T) {
+	pxe := &metal3api.BootDeviceConfig{Device: metal3api.BootDevicePXE}
+	disk := &metal3api.BootDeviceConfig{Device: metal3api.BootDeviceDisk, Persistent: true}
+
+	testCases := []struct {
+		Scenario            string
+		Request             *metal3api.BootDeviceConfig
+		Status              *metal3api.BootDeviceStatus
+		ProvResult          provisioner.Result
+		ExpectedUpdate      bool
+		ExpectedResult      metal3api.BootDeviceResult
+		ExpectedLastApplied *metal3api.BootDeviceConfig
+	}{
+		{
+			Scenario: "no request",
+		},
+		{
+			Scenario:            "new request",
+			Request:             pxe,
+			ExpectedUpdate:      true,
+			ExpectedResult:      metal3api.BootDeviceApplied,
+			ExpectedLastApplied: pxe,
+		},
+		{
+			Scenario: "request already applied",
+			Request:  pxe,
+			Status: &metal3api.BootDeviceStatus{
+				Requested:   pxe,
+				Result:      metal3api.BootDeviceApplied,
+				LastApplied: pxe,
+			},
+			ExpectedResult:      metal3api.BootDeviceApplied,
+			ExpectedLastApplied: pxe,
+		},
+		{
+			Scenario: "request changed",
+			Request:  disk,
+			Status: &metal3api.BootDeviceStatus{
+				Requested:   pxe,
+				Result:      metal3api.BootDeviceApplied,
+				LastApplied: pxe,
+			},
+			ExpectedUpdate:      true,
+			ExpectedResult:      metal3api.BootDeviceApplied,
+			ExpectedLastApplied: disk,
+		},
+		{
+			Scenario: "request failed",
+			Request:  disk,
+			Status: &metal3api.BootDeviceStatus{
+				Requested:   pxe,
+				Result:      metal3api.BootDeviceApplied,
+				LastApplied: pxe,
+			},
+			ProvResult:          provisioner.Result{ErrorMessage: "unsupported"},
+			ExpectedUpdate:      true,
+			ExpectedResult:      metal3api.BootDeviceFailed,
+			ExpectedLastApplied: pxe,
+		},
+		{
+			Scenario: "request removed",
+			Status: &metal3api.BootDeviceStatus{
+				Requested:   pxe,
+				Result:      metal3api.BootDeviceApplied,
+				LastApplied: pxe,
+			},
+			ExpectedUpdate:      true,
+			ExpectedLastApplied: pxe,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			host := host(metal3api.StateProvisioned).SetStatusImageURL("not-empty").build()
+			host.Spec.BootDevice = tc.Request
+			host.Status.BootDevice = tc.Status
+			prov := newMockProvisioner()
+			prov.nextResults["SetBootDevice"] = tc.ProvResult
+			r := newTestReconciler(host)
+			info := makeDefaultReconcileInfo(host)
+
+			actResult := r.actionSetBootDevice(prov, info)
+
+			if tc.ExpectedUpdate {
+				assert.IsType(t, actionUpdate{}, actResult)
+			} else {
+				assert.Nil(t, actResult)
+			}
+			if host.Status.BootDevice == nil {
+				assert.Empty(t, tc.ExpectedResult)
+				return
+			}
+			assert.Equal(t, tc.ExpectedResult, host.Status.BootDevice.Result)
+			assert.Equal(t, tc.ExpectedLastApplied, host.Status.BootDevice.LastApplied)
+			if tc.Request != nil {
+				assert.Equal(t, tc.Request, host.Status.BootDevice.Requested)
+			} else {
+				assert.Nil(t, host.Status.BootDevice.Requested)
+			}
+		})
+	}
+}
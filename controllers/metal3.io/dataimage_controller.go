@@ -152,7 +152,10 @@ func (r *DataImageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, fmt.Errorf("failed to create provisioner, %w", err)
 	}
 
-	ready, err := prov.TryInit()
+	provCtx, cancel := context.WithTimeout(ctx, provisionerCallTimeout)
+	defer cancel()
+
+	ready, err := prov.TryInit(provCtx)
 	if err != nil || !ready {
 		var msg string
 		if err == nil {
@@ -165,7 +168,7 @@ func (r *DataImageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}
 
 	// Check if any attach/detach action is pending or failed to attach
-	_, nodeError := prov.IsDataImageReady()
+	_, nodeError := prov.IsDataImageReady(provCtx)
 
 	// Is the current dataImage status valid
 	dirty := false
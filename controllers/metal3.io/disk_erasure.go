@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"slices"
+	"strings"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// diskMatchesHints checks a disk against root device hints, with the
+// same semantics as used by ironic to find the root device.
+func diskMatchesHints(disk *metal3api.Storage, hints *metal3api.RootDeviceHints) bool {
+	if hints.DeviceName != "" && hints.DeviceName != disk.Name &&
+		!slices.Contains(disk.AlternateNames, hints.DeviceName) {
+		return false
+	}
+	if hints.HCTL != "" && hints.HCTL != disk.HCTL {
+		return false
+	}
+	if hints.Model != "" && !strings.Contains(disk.Model, hints.Model) {
+		return false
+	}
+	if hints.Vendor != "" && !strings.Contains(disk.Vendor, hints.Vendor) {
+		return false
+	}
+	if hints.SerialNumber != "" && hints.SerialNumber != disk.SerialNumber {
+		return false
+	}
+	if hints.MinSizeGigabytes != 0 && disk.SizeBytes < metal3api.Capacity(hints.MinSizeGigabytes)*metal3api.GibiByte {
+		return false
+	}
+	if hints.WWN != "" && hints.WWN != disk.WWN {
+		return false
+	}
+	if hints.WWNWithExtension != "" && hints.WWNWithExtension != disk.WWNWithExtension {
+		return false
+	}
+	if hints.WWNVendorExtension != "" && hints.WWNVendorExtension != disk.WWNVendorExtension {
+		return false
+	}
+	if hints.Rotational != nil && *hints.Rotational != disk.Rotational {
+		return false
+	}
+	return true
+}
+
+func diskPreserved(host *metal3api.BareMetalHost, disk *metal3api.Storage) bool {
+	for i := range host.Spec.PreservedDisks {
+		if diskMatchesHints(disk, &host.Spec.PreservedDisks[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// diskErasureStarted returns whether the disks of the host are being
+// erased, or have been erased successfully, as part of the current
+// deprovisioning.
+func diskErasureStarted(host *metal3api.BareMetalHost) bool {
+	erasure := host.Status.DiskErasure
+	if erasure == nil || erasure.Failed() {
+		return false
+	}
+	deprovisionStart := host.Status.OperationHistory.Deprovision.Start
+	return !erasure.Started.Before(&deprovisionStart)
+}
+
+// finishDiskErasure records the results of the disk erasure running as
+// part of the current deprovisioning, if any. It returns true if the
+// status of the host has changed.
+func finishDiskErasure(host *metal3api.BareMetalHost, errorMessage string) bool {
+	if !diskErasureStarted(host) || host.Status.DiskErasure.Finished != nil {
+		return false
+	}
+
+	erasure := host.Status.DiskErasure
+	now := metav1.Now()
+	erasure.Finished = &now
+	erasure.ErrorMessage = errorMessage
+	erasure.Duration = &metav1.Duration{Duration: now.Sub(erasure.Started.Time)}
+	erasure.Result = metal3api.DiskErased
+	if errorMessage != "" {
+		erasure.Result = metal3api.DiskErasureFailed
+	}
+	erasure.Disks = nil
+	erasure.PreservedDisks = nil
+
+	if host.Status.HardwareDetails == nil {
+		return true
+	}
+
+	for i := range host.Status.HardwareDetails.Storage {
+		disk := &host.Status.HardwareDetails.Storage[i]
+		if diskPreserved(host, disk) {
+			erasure.PreservedDisks = append(erasure.PreservedDisks, disk.Name)
+		} else {
+			erasure.Disks = append(erasure.Disks, disk.Name)
+		}
+	}
+	return true
+}
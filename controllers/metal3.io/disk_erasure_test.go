@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDiskMatchesHints(t *testing.T) {
+	rotational := true
+	disk := metal3api.Storage{
+		Name:           "/dev/sda",
+		AlternateNames: []string{"/dev/disk/by-path/pci-0000:00:07.0-scsi-0:0:0:0"},
+		Rotational:     true,
+		SizeBytes:      100 * metal3api.GibiByte,
+		Vendor:         "ATA",
+		Model:          "Samsung SSD 860",
+		SerialNumber:   "S3EVNX0K",
+		HCTL:           "0:0:0:0",
+	}
+
+	testCases := []struct {
+		Scenario string
+		Hints    metal3api.RootDeviceHints
+		Expected bool
+	}{
+		{
+			Scenario: "device name",
+			Hints:    metal3api.RootDeviceHints{DeviceName: "/dev/sda"},
+			Expected: true,
+		},
+		{
+			Scenario: "device path",
+			Hints:    metal3api.RootDeviceHints{DeviceName: "/dev/disk/by-path/pci-0000:00:07.0-scsi-0:0:0:0"},
+			Expected: true,
+		},
+		{
+			Scenario: "other device name",
+			Hints:    metal3api.RootDeviceHints{DeviceName: "/dev/sdb"},
+			Expected: false,
+		},
+		{
+			Scenario: "model substring",
+			Hints:    metal3api.RootDeviceHints{Model: "Samsung"},
+			Expected: true,
+		},
+		{
+			Scenario: "serial and size",
+			Hints:    metal3api.RootDeviceHints{SerialNumber: "S3EVNX0K", MinSizeGigabytes: 100},
+			Expected: true,
+		},
+		{
+			Scenario: "too small",
+			Hints:    metal3api.RootDeviceHints{MinSizeGigabytes: 200},
+			Expected: false,
+		},
+		{
+			Scenario: "rotational",
+			Hints:    metal3api.RootDeviceHints{HCTL: "0:0:0:0", Rotational: &rotational},
+			Expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, diskMatchesHints(&disk, &tc.Hints))
+		})
+	}
+}
+
+func TestDiskErasure(t *testing.T) {
+	started := metav1.NewTime(time.Now().Add(-time.Minute))
+
+	testCases := []struct {
+		Scenario          string
+		Erasure           *metal3api.DiskErasure
+		DeprovisionResult provisioner.Result
+		ExpectedState     metal3api.ProvisioningState
+		ExpectedFinished  bool
+		ExpectedResult    metal3api.DiskErasureResult
+		ExpectedErrorType metal3api.ErrorType
+	}{
+		{
+			Scenario:          "start erasure",
+			DeprovisionResult: provisioner.Result{Dirty: true},
+			ExpectedState:     metal3api.StateDeprovisioning,
+		},
+		{
+			Scenario:          "erasure running",
+			Erasure:           &metal3api.DiskErasure{Started: started},
+			DeprovisionResult: provisioner.Result{Dirty: true},
+			ExpectedState:     metal3api.StateDeprovisioning,
+		},
+		{
+			Scenario:         "erasure finished",
+			Erasure:          &metal3api.DiskErasure{Started: started},
+			ExpectedState:    metal3api.StateAvailable,
+			ExpectedFinished: true,
+			ExpectedResult:   metal3api.DiskErased,
+		},
+		{
+			Scenario:          "erasure failed",
+			Erasure:           &metal3api.DiskErasure{Started: started},
+			DeprovisionResult: provisioner.Result{ErrorMessage: "Cleaning failed"},
+			ExpectedState:     metal3api.StateDeprovisioning,
+			ExpectedFinished:  true,
+			ExpectedResult:    metal3api.DiskErasureFailed,
+			ExpectedErrorType: metal3api.ProvisioningError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			host := host(metal3api.StateDeprovisioning).build()
+			host.Spec.AutomatedCleaningMode = metal3api.CleaningModeFull
+			host.Spec.PreservedDisks = []metal3api.RootDeviceHints{{SerialNumber: "S3EVNX0K"}}
+			host.Status.HardwareDetails = &metal3api.HardwareDetails{
+				Storage: []metal3api.Storage{
+					{Name: "/dev/sda", SerialNumber: "S3EVNX0K"},
+					{Name: "/dev/sdb", SerialNumber: "S3EVNX0L"},
+				},
+			}
+			host.Status.DiskErasure = tc.Erasure
+
+			prov := newMockProvisioner()
+			prov.nextResults["Deprovision"] = tc.DeprovisionResult
+			r := newTestReconciler(host)
+			hsm := newHostStateMachine(host, r, prov, true)
+			info := makeDefaultReconcileInfo(host)
+			info.request = newRequest(host)
+
+			hsm.ReconcileState(info)
+
+			assert.Equal(t, tc.ExpectedState, host.Status.Provisioning.State)
+			assert.Equal(t, tc.ExpectedErrorType, host.Status.ErrorType)
+			if assert.NotNil(t, host.Status.DiskErasure) {
+				erasure := host.Status.DiskErasure
+				assert.Equal(t, tc.ExpectedFinished, erasure.Finished != nil)
+				assert.Equal(t, tc.ExpectedResult, erasure.Result)
+				if tc.ExpectedFinished {
+					assert.NotNil(t, erasure.Duration)
+					assert.Equal(t, []string{"/dev/sdb"}, erasure.Disks)
+					assert.Equal(t, []string{"/dev/sda"}, erasure.PreservedDisks)
+				} else {
+					assert.Empty(t, erasure.Disks)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1/profile"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// hardwareProfiles looks up the hardware profiles of hosts in the
+// HardwareProfile resources, falling back to the built-in profiles.
+type hardwareProfiles struct {
+	ctx    context.Context
+	client client.Reader
+}
+
+// get returns the named profile. A HardwareProfile resource takes
+// precedence over a built-in profile of the same name.
+func (p *hardwareProfiles) get(name string) (profile.Profile, error) {
+	if p != nil {
+		hwProfile := &metal3api.HardwareProfile{}
+		err := p.client.Get(p.ctx, types.NamespacedName{Name: name}, hwProfile)
+		if err == nil {
+			return profile.FromHardwareProfile(hwProfile), nil
+		}
+		if !k8serrors.IsNotFound(err) {
+			return profile.Profile{}, errors.Wrapf(err, "failed to get hardware profile %q", name)
+		}
+	}
+	return profile.GetProfile(name)
+}
+
+// match returns the name of the HardwareProfile matching the hardware
+// details most specifically, or an empty string if none matches. Ties
+// are broken by name.
+func (p *hardwareProfiles) match(details *metal3api.HardwareDetails) (string, error) {
+	if p == nil || details == nil {
+		return "", nil
+	}
+
+	hwProfiles := &metal3api.HardwareProfileList{}
+	if err := p.client.List(p.ctx, hwProfiles); err != nil {
+		return "", errors.Wrap(err, "failed to list hardware profiles")
+	}
+
+	best, bestScore := "", -1
+	for i := range hwProfiles.Items {
+		hwProfile := &hwProfiles.Items[i]
+		score := hwProfile.Spec.MatchScore(details)
+		if score > bestScore || (score == bestScore && score >= 0 && hwProfile.Name < best) {
+			best, bestScore = hwProfile.Name, score
+		}
+	}
+	return best, nil
+}
+
+// needsProfileMatch returns whether a hardware profile should be
+// selected for the host from its hardware details. Hosts that name their
+// profile, or that already have a profile other than the default one,
+// keep it.
+func needsProfileMatch(host *metal3api.BareMetalHost) bool {
+	if host.Spec.HardwareProfile != "" || host.Status.HardwareDetails == nil {
+		return false
+	}
+	switch host.Status.HardwareProfile {
+	case "", profile.DefaultProfileName:
+		return true
+	default:
+		return false
+	}
+}
+
+// hostsForHardwareProfile returns the hosts to reconcile when a
+// HardwareProfile changes: those that may now match it, and those using
+// it.
+func (r *BareMetalHostReconciler) hostsForHardwareProfile(ctx context.Context, obj client.Object) []reconcile.Request {
+	hosts := &metal3api.BareMetalHostList{}
+	if err := r.List(ctx, hosts); err != nil {
+		r.Log.Error(err, "failed to list hosts")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for i := range hosts.Items {
+		host := &hosts.Items[i]
+		if needsProfileMatch(host) || host.Status.HardwareProfile == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: host.Namespace, Name: host.Name},
+			})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1/profile"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newHardwareProfile(name string, spec metal3api.HardwareProfileSpec) *metal3api.HardwareProfile {
+	return &metal3api.HardwareProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       spec,
+	}
+}
+
+func newProfiledHost(manufacturer, productName string) *metal3api.BareMetalHost {
+	host := newHost("myhost", &metal3api.BareMetalHostSpec{})
+	host.Status.HardwareDetails = &metal3api.HardwareDetails{
+		SystemVendor: metal3api.HardwareSystemVendor{
+			Manufacturer: manufacturer,
+			ProductName:  productName,
+		},
+	}
+	return host
+}
+
+func TestHardwareProfilesMatch(t *testing.T) {
+	r := newTestReconciler(
+		newHardwareProfile("b-vendor", metal3api.HardwareProfileSpec{
+			Match: metal3api.HardwareProfileMatch{Manufacturer: "Dell"},
+		}),
+		newHardwareProfile("a-vendor", metal3api.HardwareProfileSpec{
+			Match: metal3api.HardwareProfileMatch{Manufacturer: "Dell"},
+		}),
+		newHardwareProfile("product", metal3api.HardwareProfileSpec{
+			Match: metal3api.HardwareProfileMatch{Manufacturer: "Dell", ProductName: "R750"},
+		}),
+		newHardwareProfile("other", metal3api.HardwareProfileSpec{
+			Match: metal3api.HardwareProfileMatch{Manufacturer: "HPE"},
+		}),
+	)
+	profiles := &hardwareProfiles{ctx: context.TODO(), client: r.Client}
+
+	testCases := []struct {
+		Scenario string
+		Host     *metal3api.BareMetalHost
+		Expected string
+	}{
+		{
+			Scenario: "most specific",
+			Host:     newProfiledHost("Dell Inc.", "PowerEdge R750"),
+			Expected: "product",
+		},
+		{
+			Scenario: "tie broken by name",
+			Host:     newProfiledHost("Dell Inc.", "PowerEdge R640"),
+			Expected: "a-vendor",
+		},
+		{
+			Scenario: "no match",
+			Host:     newProfiledHost("Supermicro", "SYS-1029"),
+			Expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			name, err := profiles.match(tc.Host.Status.HardwareDetails)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.Expected, name)
+		})
+	}
+}
+
+func TestHardwareProfilesGet(t *testing.T) {
+	r := newTestReconciler(
+		newHardwareProfile("libvirt", metal3api.HardwareProfileSpec{
+			RootDeviceHints: &metal3api.RootDeviceHints{DeviceName: "/dev/vdb"},
+		}),
+	)
+	profiles := &hardwareProfiles{ctx: context.TODO(), client: r.Client}
+
+	prof, err := profiles.get("libvirt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "/dev/vdb", prof.RootDeviceHints.DeviceName, "resources take precedence over built-in profiles")
+
+	prof, err = profiles.get("dell")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "0:0:0:0", prof.RootDeviceHints.HCTL)
+
+	_, err = profiles.get("missing")
+	assert.Error(t, err)
+}
+
+func TestMatchProfileFromHardwareDetails(t *testing.T) {
+	virtualization := true
+	hwProfile := newHardwareProfile("r750", metal3api.HardwareProfileSpec{
+		Match:           metal3api.HardwareProfileMatch{ProductName: "R750"},
+		RootDeviceHints: &metal3api.RootDeviceHints{HCTL: "0:2:0:0"},
+		RAID: &metal3api.RAIDConfig{
+			HardwareRAIDVolumes: []metal3api.HardwareRAIDVolume{{Level: "1"}},
+		},
+		Firmware: &metal3api.FirmwareConfig{VirtualizationEnabled: &virtualization},
+	})
+
+	testCases := []struct {
+		Scenario string
+		Setup    func(host *metal3api.BareMetalHost)
+		Expected string
+	}{
+		{
+			Scenario: "default profile",
+			Setup: func(host *metal3api.BareMetalHost) {
+				host.Status.HardwareProfile = profile.DefaultProfileName
+			},
+			Expected: "r750",
+		},
+		{
+			Scenario: "profile named by the host",
+			Setup: func(host *metal3api.BareMetalHost) {
+				host.Spec.HardwareProfile = "dell"
+			},
+			Expected: "dell",
+		},
+		{
+			Scenario: "profile already selected",
+			Setup: func(host *metal3api.BareMetalHost) {
+				host.Status.HardwareProfile = "openstack"
+			},
+			Expected: "openstack",
+		},
+		{
+			Scenario: "not inspected",
+			Setup: func(host *metal3api.BareMetalHost) {
+				host.Status.HardwareDetails = nil
+			},
+			Expected: profile.DefaultProfileName,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			host := newProfiledHost("Dell Inc.", "PowerEdge R750")
+			tc.Setup(host)
+			r := newTestReconciler(hwProfile, host)
+			info := makeReconcileInfo(host)
+			info.profiles = &hardwareProfiles{ctx: context.TODO(), client: r.Client}
+
+			_, err := r.matchProfile(info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.Expected, host.Status.HardwareProfile)
+		})
+	}
+
+	t.Run("provisioning settings", func(t *testing.T) {
+		host := newProfiledHost("Dell Inc.", "PowerEdge R750")
+		r := newTestReconciler(hwProfile, host)
+		info := makeReconcileInfo(host)
+		info.profiles = &hardwareProfiles{ctx: context.TODO(), client: r.Client}
+
+		if _, err := r.matchProfile(info); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := saveHostProvisioningSettings(host, info); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "0:2:0:0", host.Status.Provisioning.RootDeviceHints.HCTL)
+		assert.Equal(t, hwProfile.Spec.RAID, host.Status.Provisioning.RAID)
+		assert.Equal(t, hwProfile.Spec.Firmware, host.Status.Provisioning.Firmware)
+
+		// Settings of the host take precedence.
+		host.Spec.Firmware = &metal3api.FirmwareConfig{}
+		if _, err := saveHostProvisioningSettings(host, info); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, host.Spec.Firmware, host.Status.Provisioning.Firmware)
+	})
+}
@@ -0,0 +1,348 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/utils"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// HardwareClassificationReconciler labels the hosts matching
+// HardwareClassifications.
+type HardwareClassificationReconciler struct {
+	client.Client
+	Log logr.Logger
+}
+
+// +kubebuilder:rbac:groups=metal3.io,resources=hardwareclassifications,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=metal3.io,resources=hardwareclassifications/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=metal3.io,resources=hardwareclassifications/finalizers,verbs=update
+// +kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts,verbs=get;list;watch;update
+
+// Reconcile evaluates the rules of the classification for the hosts of
+// its namespace, and applies or removes its labels accordingly.
+func (r *HardwareClassificationReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("hardwareclassification", request.NamespacedName)
+
+	classification := &metal3api.HardwareClassification{}
+	if err := r.Get(ctx, request.NamespacedName, classification); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, errors.Wrap(err, "could not load classification")
+	}
+
+	hostList := &metal3api.BareMetalHostList{}
+	if err := r.List(ctx, hostList, client.InNamespace(classification.Namespace)); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to list hosts")
+	}
+
+	if !classification.DeletionTimestamp.IsZero() {
+		if !utils.StringInList(classification.Finalizers, metal3api.HardwareClassificationFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		for i := range hostList.Items {
+			if err := r.labelHost(ctx, reqLogger, classification, &hostList.Items[i], false, nil, nil); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		classification.Finalizers = utils.FilterStringFromList(classification.Finalizers, metal3api.HardwareClassificationFinalizer)
+		if err := r.Update(ctx, classification); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to remove finalizer")
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !utils.StringInList(classification.Finalizers, metal3api.HardwareClassificationFinalizer) {
+		classification.Finalizers = append(classification.Finalizers, metal3api.HardwareClassificationFinalizer)
+		if err := r.Update(ctx, classification); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to add finalizer")
+		}
+	}
+
+	selector, err := classificationSelector(classification)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "invalid host selector")
+	}
+
+	others, err := r.otherClassifications(ctx, classification)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	status := metal3api.HardwareClassificationStatus{
+		AppliedLabels:      classification.Spec.Labels,
+		ObservedGeneration: classification.Generation,
+		ErrorMessage:       invalidLabels(classification.Spec.Labels),
+	}
+	if status.ErrorMessage != "" {
+		// The hosts keep the labels applied before until the labels are
+		// fixed, so that they can still be removed then.
+		status.AppliedLabels = classification.Status.AppliedLabels
+	}
+	for i := range hostList.Items {
+		host := &hostList.Items[i]
+		matched := false
+		shared, conflicting, conflict := sharedLabels(classification, others, host)
+		// Hosts are only classified once inspected, and keep their labels
+		// while being deleted.
+		if selector.Matches(labels.Set(host.Labels)) && host.Status.HardwareDetails != nil {
+			message := classification.Spec.Rules.Match(host.Status.HardwareDetails)
+			matched = message == ""
+			result := metal3api.HostClassificationResult{
+				HostName: host.Name,
+				Matched:  matched,
+				Message:  message,
+			}
+			if matched {
+				status.MatchedHosts++
+				result.Conflict = conflict
+			}
+			status.Results = append(status.Results, result)
+		}
+		if !host.DeletionTimestamp.IsZero() || status.ErrorMessage != "" {
+			continue
+		}
+		if err := r.labelHost(ctx, reqLogger, classification, host, matched, shared, conflicting); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	sort.Slice(status.Results, func(i, j int) bool {
+		return status.Results[i].HostName < status.Results[j].HostName
+	})
+
+	if equality.Semantic.DeepEqual(status, classification.Status) {
+		return ctrl.Result{}, nil
+	}
+	classification.Status = status
+	if err := r.Status().Update(ctx, classification); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to update classification status")
+	}
+	return ctrl.Result{}, nil
+}
+
+// classificationSelector returns the selector of the hosts to classify.
+func classificationSelector(classification *metal3api.HardwareClassification) (labels.Selector, error) {
+	if classification.Spec.HostSelector == nil {
+		return labels.Everything(), nil
+	}
+	return metav1.LabelSelectorAsSelector(classification.Spec.HostSelector)
+}
+
+// classifier is another classification of the namespace, which may
+// label the same hosts.
+type classifier struct {
+	classification *metal3api.HardwareClassification
+	selector       labels.Selector
+}
+
+// otherClassifications returns the other classifications of the
+// namespace applying their labels, sorted by name.
+func (r *HardwareClassificationReconciler) otherClassifications(ctx context.Context, classification *metal3api.HardwareClassification) ([]classifier, error) {
+	classifications := &metal3api.HardwareClassificationList{}
+	if err := r.List(ctx, classifications, client.InNamespace(classification.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list classifications")
+	}
+
+	others := []classifier{}
+	for i := range classifications.Items {
+		other := &classifications.Items[i]
+		if other.Name == classification.Name || !other.DeletionTimestamp.IsZero() ||
+			invalidLabels(other.Spec.Labels) != "" {
+			continue
+		}
+		selector, err := classificationSelector(other)
+		if err != nil {
+			continue
+		}
+		others = append(others, classifier{classification: other, selector: selector})
+	}
+	sort.Slice(others, func(i, j int) bool {
+		return others[i].classification.Name < others[j].classification.Name
+	})
+	return others, nil
+}
+
+// sharedLabels returns the labels of the classification that other
+// classifications matching the host set too, those they set to a
+// different value, and a description of the latter.
+func sharedLabels(classification *metal3api.HardwareClassification, others []classifier, host *metal3api.BareMetalHost) (shared, conflicting map[string]bool, conflict string) {
+	keys := []string{}
+	for key := range classification.Spec.Labels {
+		keys = append(keys, key)
+	}
+	for key := range classification.Status.AppliedLabels {
+		if _, ok := classification.Spec.Labels[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	shared = map[string]bool{}
+	conflicting = map[string]bool{}
+	conflicts := []string{}
+	for _, other := range others {
+		if !other.selector.Matches(labels.Set(host.Labels)) ||
+			other.classification.Spec.Rules.Match(host.Status.HardwareDetails) != "" {
+			continue
+		}
+		for _, key := range keys {
+			value, ok := other.classification.Spec.Labels[key]
+			if !ok {
+				continue
+			}
+			shared[key] = true
+			if own, set := classification.Spec.Labels[key]; set && value != own {
+				conflicting[key] = true
+				conflicts = append(conflicts, fmt.Sprintf("label %s is set to %q by classification %s",
+					key, value, other.classification.Name))
+			}
+		}
+	}
+	return shared, conflicting, strings.Join(conflicts, "; ")
+}
+
+// invalidLabels describes why the labels are not valid Kubernetes
+// labels, or returns an empty string when they are.
+func invalidLabels(labelSet map[string]string) string {
+	keys := make([]string, 0, len(labelSet))
+	for key := range labelSet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	problems := []string{}
+	for _, key := range keys {
+		for _, msg := range validation.IsQualifiedName(key) {
+			problems = append(problems, fmt.Sprintf("invalid label key %q: %s", key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(labelSet[key]) {
+			problems = append(problems, fmt.Sprintf("invalid value %q of label %s: %s", labelSet[key], key, msg))
+		}
+	}
+	return strings.Join(problems, "; ")
+}
+
+// labelHost applies the labels of the classification to a matching
+// host, and removes the labels previously applied from any other host.
+// Labels are only removed when they still have the value set by the
+// classification and no other classification matching the host sets
+// them, and labels set to another value by such a classification are
+// not overwritten.
+func (r *HardwareClassificationReconciler) labelHost(ctx context.Context, log logr.Logger, classification *metal3api.HardwareClassification, host *metal3api.BareMetalHost, matched bool, shared, conflicting map[string]bool) error {
+	changed := false
+	remove := func(key, value string) {
+		if current, ok := host.Labels[key]; ok && current == value && !shared[key] {
+			delete(host.Labels, key)
+			changed = true
+		}
+	}
+
+	for key, value := range classification.Status.AppliedLabels {
+		if _, stillApplied := classification.Spec.Labels[key]; matched && stillApplied {
+			continue
+		}
+		remove(key, value)
+	}
+	for key, value := range classification.Spec.Labels {
+		if !matched {
+			remove(key, value)
+			continue
+		}
+		if conflicting[key] {
+			continue
+		}
+		if current, ok := host.Labels[key]; ok && current == value {
+			continue
+		}
+		if host.Labels == nil {
+			host.Labels = map[string]string{}
+		}
+		host.Labels[key] = value
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	log.Info("updating host labels", "host", host.Name, "matched", matched)
+	return errors.Wrap(r.Update(ctx, host), "failed to update host labels")
+}
+
+// classificationsInNamespace returns the classifications of the
+// namespace of a host or a classification that changed, as the labels of
+// a classification may conflict with those of the others.
+func (r *HardwareClassificationReconciler) classificationsInNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	classifications := &metal3api.HardwareClassificationList{}
+	if err := r.List(ctx, classifications, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "failed to list classifications", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, classification := range classifications.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: classification.Namespace, Name: classification.Name},
+		})
+	}
+	return requests
+}
+
+// hostClassificationChanged filters the updates of hosts that can change
+// their classification: those of their labels and hardware details.
+func hostClassificationChanged(e event.UpdateEvent) bool {
+	oldHost, oldOK := e.ObjectOld.(*metal3api.BareMetalHost)
+	newHost, newOK := e.ObjectNew.(*metal3api.BareMetalHost)
+	if !oldOK || !newOK {
+		return true
+	}
+	return !equality.Semantic.DeepEqual(oldHost.Labels, newHost.Labels) ||
+		!equality.Semantic.DeepEqual(oldHost.Status.HardwareDetails, newHost.Status.HardwareDetails)
+}
+
+// SetupWithManager registers the reconciler to be run by the manager.
+func (r *HardwareClassificationReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconcile int) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&metal3api.HardwareClassification{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconcile}).
+		Watches(&metal3api.BareMetalHost{}, handler.EnqueueRequestsFromMapFunc(r.classificationsInNamespace),
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: hostClassificationChanged})).
+		Watches(&metal3api.HardwareClassification{}, handler.EnqueueRequestsFromMapFunc(r.classificationsInNamespace),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func newClassificationTestReconciler(initObjs ...runtime.Object) *HardwareClassificationReconciler {
+	clientBuilder := fakeclient.NewClientBuilder().WithRuntimeObjects(initObjs...)
+	for _, v := range initObjs {
+		clientBuilder = clientBuilder.WithStatusSubresource(v.(client.Object))
+	}
+
+	return &HardwareClassificationReconciler{
+		Client: clientBuilder.Build(),
+		Log:    ctrl.Log.WithName("controllers").WithName("HardwareClassification"),
+	}
+}
+
+func newClassification(labels map[string]string) *metal3api.HardwareClassification {
+	minCPUs := 32
+	return &metal3api.HardwareClassification{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "large",
+			Namespace: namespace,
+		},
+		Spec: metal3api.HardwareClassificationSpec{
+			Rules: metal3api.HardwareClassificationRules{
+				CPU: &metal3api.CPURule{Count: &metal3api.IntRange{Min: &minCPUs}},
+			},
+			Labels: labels,
+		},
+	}
+}
+
+func newClassifiedHost(name string, cpus int, labels map[string]string) *metal3api.BareMetalHost {
+	host := newHost(name, &metal3api.BareMetalHostSpec{})
+	host.Labels = labels
+	if cpus > 0 {
+		host.Status.HardwareDetails = &metal3api.HardwareDetails{CPU: metal3api.CPU{Count: cpus}}
+	}
+	return host
+}
+
+func reconcileClassification(t *testing.T, r *HardwareClassificationReconciler, classification *metal3api.HardwareClassification) *metal3api.HardwareClassification {
+	t.Helper()
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: classification.Namespace, Name: classification.Name}}
+	if _, err := r.Reconcile(context.TODO(), request); err != nil {
+		t.Fatal(err)
+	}
+
+	updated := &metal3api.HardwareClassification{}
+	if err := r.Get(context.TODO(), request.NamespacedName, updated); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			t.Fatal(err)
+		}
+		return nil
+	}
+	return updated
+}
+
+func getClassifiedHostLabels(t *testing.T, r *HardwareClassificationReconciler, name string) map[string]string {
+	t.Helper()
+	host := &metal3api.BareMetalHost{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, host); err != nil {
+		t.Fatal(err)
+	}
+	return host.Labels
+}
+
+func TestHardwareClassificationLabels(t *testing.T) {
+	classLabel := map[string]string{"class": "large"}
+	classification := newClassification(classLabel)
+	r := newClassificationTestReconciler(
+		classification,
+		newClassifiedHost("big", 64, map[string]string{"rack": "r1"}),
+		newClassifiedHost("small", 8, map[string]string{"class": "large"}),
+		newClassifiedHost("uninspected", 0, nil),
+		newClassifiedHost("hand-labelled", 8, map[string]string{"class": "small"}),
+	)
+
+	updated := reconcileClassification(t, r, classification)
+
+	assert.Contains(t, updated.Finalizers, metal3api.HardwareClassificationFinalizer)
+	assert.Equal(t, 1, updated.Status.MatchedHosts)
+	assert.Equal(t, classLabel, updated.Status.AppliedLabels)
+	assert.Equal(t, []metal3api.HostClassificationResult{
+		{HostName: "big", Matched: true},
+		{HostName: "hand-labelled", Matched: false, Message: "CPU count 8 is not at least 32"},
+		{HostName: "small", Matched: false, Message: "CPU count 8 is not at least 32"},
+	}, updated.Status.Results)
+
+	assert.Equal(t, map[string]string{"rack": "r1", "class": "large"}, getClassifiedHostLabels(t, r, "big"))
+	assert.Empty(t, getClassifiedHostLabels(t, r, "small"))
+	assert.Empty(t, getClassifiedHostLabels(t, r, "uninspected"))
+	assert.Equal(t, map[string]string{"class": "small"}, getClassifiedHostLabels(t, r, "hand-labelled"))
+
+	// Changing the labels removes the ones applied before
+	updated.Spec.Labels = map[string]string{"size": "large"}
+	if err := r.Update(context.TODO(), updated); err != nil {
+		t.Fatal(err)
+	}
+	updated = reconcileClassification(t, r, updated)
+	assert.Equal(t, map[string]string{"rack": "r1", "size": "large"}, getClassifiedHostLabels(t, r, "big"))
+	assert.Equal(t, map[string]string{"size": "large"}, updated.Status.AppliedLabels)
+
+	// Deleting the classification removes its labels
+	if err := r.Delete(context.TODO(), updated); err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, reconcileClassification(t, r, updated))
+	assert.Equal(t, map[string]string{"rack": "r1"}, getClassifiedHostLabels(t, r, "big"))
+}
+
+func TestHardwareClassificationInvalidLabels(t *testing.T) {
+	classification := newClassification(map[string]string{"class": "large", "bad key": "large"})
+	classification.Status.AppliedLabels = map[string]string{"class": "large"}
+	r := newClassificationTestReconciler(
+		classification,
+		newClassifiedHost("big", 64, map[string]string{"class": "large"}),
+		newClassifiedHost("other", 64, nil),
+	)
+
+	updated := reconcileClassification(t, r, classification)
+
+	assert.Contains(t, updated.Status.ErrorMessage, `invalid label key "bad key"`)
+	assert.Equal(t, map[string]string{"class": "large"}, updated.Status.AppliedLabels)
+	assert.Equal(t, 2, updated.Status.MatchedHosts)
+	assert.Equal(t, map[string]string{"class": "large"}, getClassifiedHostLabels(t, r, "big"))
+	assert.Empty(t, getClassifiedHostLabels(t, r, "other"))
+}
+
+func TestHardwareClassificationConflict(t *testing.T) {
+	classification := newClassification(map[string]string{"class": "large", "rack": "r1"})
+	fast := newClassification(map[string]string{"class": "fast", "rack": "r1"})
+	fast.Name = "fast"
+	fast.Spec.Rules = metal3api.HardwareClassificationRules{
+		NIC: &metal3api.NICRule{MinSpeedGbps: 100},
+	}
+	fastHost := newClassifiedHost("big-fast", 64, nil)
+	fastHost.Status.HardwareDetails.NIC = []metal3api.NIC{{Name: "eth0", SpeedGbps: 100}}
+	r := newClassificationTestReconciler(
+		classification,
+		fast,
+		fastHost,
+		newClassifiedHost("big", 64, nil),
+	)
+
+	updated := reconcileClassification(t, r, classification)
+
+	assert.Equal(t, []metal3api.HostClassificationResult{
+		{HostName: "big", Matched: true},
+		{HostName: "big-fast", Matched: true, Conflict: `label class is set to "fast" by classification fast`},
+	}, updated.Status.Results)
+	assert.Equal(t, map[string]string{"class": "large", "rack": "r1"}, getClassifiedHostLabels(t, r, "big"))
+	assert.Equal(t, map[string]string{"rack": "r1"}, getClassifiedHostLabels(t, r, "big-fast"))
+
+	// The other classification does not overwrite the label either
+	updated = reconcileClassification(t, r, fast)
+	assert.Equal(t, []metal3api.HostClassificationResult{
+		{HostName: "big", Matched: false, Message: "no matching NIC"},
+		{HostName: "big-fast", Matched: true, Conflict: `label class is set to "large" by classification large`},
+	}, updated.Status.Results)
+	assert.Equal(t, map[string]string{"rack": "r1"}, getClassifiedHostLabels(t, r, "big-fast"))
+	// Labels shared with a classification matching the host are kept
+	assert.Equal(t, map[string]string{"class": "large", "rack": "r1"}, getClassifiedHostLabels(t, r, "big"))
+}
+
+func TestHostClassificationChanged(t *testing.T) {
+	oldHost := newClassifiedHost("host", 8, nil)
+
+	updatedHost := oldHost.DeepCopy()
+	updatedHost.Status.PoweredOn = true
+	assert.False(t, hostClassificationChanged(event.UpdateEvent{ObjectOld: oldHost, ObjectNew: updatedHost}))
+
+	updatedHost = oldHost.DeepCopy()
+	updatedHost.Status.HardwareDetails.CPU.Count = 64
+	assert.True(t, hostClassificationChanged(event.UpdateEvent{ObjectOld: oldHost, ObjectNew: updatedHost}))
+
+	updatedHost = oldHost.DeepCopy()
+	updatedHost.Labels = map[string]string{"rack": "r1"}
+	assert.True(t, hostClassificationChanged(event.UpdateEvent{ObjectOld: oldHost, ObjectNew: updatedHost}))
+}
@@ -0,0 +1,276 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reasons of the BareMetalHost conditions.
+const (
+	reasonSucceeded             = "Succeeded"
+	reasonInProgress            = "InProgress"
+	reasonFailed                = "Failed"
+	reasonNotStarted            = "NotStarted"
+	reasonUnmanaged             = "Unmanaged"
+	reasonDisabled              = "Disabled"
+	reasonExternallyProvisioned = "ExternallyProvisioned"
+	reasonPoweredOn             = "PoweredOn"
+	reasonPoweredOff            = "PoweredOff"
+	reasonPowerManagementError  = "PowerManagementError"
+	reasonDetached              = "Detached"
+	reasonDelayed               = "Delayed"
+	reasonServicing             = "Servicing"
+	reasonError                 = "Error"
+	reasonNoExecuteTaint        = "NoExecuteTaint"
+	reasonNotTainted            = "NotTainted"
+)
+
+// hostCondition is the computed value of a single condition.
+type hostCondition struct {
+	status  metav1.ConditionStatus
+	reason  string
+	message string
+}
+
+func conditionTrue(reason, message string) hostCondition {
+	return hostCondition{metav1.ConditionTrue, reason, message}
+}
+
+func conditionFalse(reason, message string) hostCondition {
+	return hostCondition{metav1.ConditionFalse, reason, message}
+}
+
+func conditionUnknown(reason, message string) hostCondition {
+	return hostCondition{metav1.ConditionUnknown, reason, message}
+}
+
+// updateHostConditions derives the conditions of the host from the rest
+// of its status, and returns whether they changed.
+func updateHostConditions(host *metal3api.BareMetalHost) bool {
+	conditions := []struct {
+		conditionType metal3api.HostConditionType
+		condition     hostCondition
+	}{
+		{metal3api.ConditionRegistered, registeredCondition(host)},
+		{metal3api.ConditionBMCAccessible, bmcAccessibleCondition(host)},
+		{metal3api.ConditionInspected, inspectedCondition(host)},
+		{metal3api.ConditionPrepared, preparedCondition(host)},
+		{metal3api.ConditionProvisioned, provisionedCondition(host)},
+		{metal3api.ConditionPoweredOn, poweredOnCondition(host)},
+		{metal3api.ConditionReady, readyCondition(host)},
+		{metal3api.ConditionTainted, taintedCondition(host)},
+	}
+
+	original := make([]metav1.Condition, len(host.Status.Conditions))
+	copy(original, host.Status.Conditions)
+
+	for _, c := range conditions {
+		meta.SetStatusCondition(&host.Status.Conditions, metav1.Condition{
+			Type:               string(c.conditionType),
+			Status:             c.condition.status,
+			ObservedGeneration: host.Generation,
+			Reason:             c.condition.reason,
+			Message:            c.condition.message,
+		})
+	}
+
+	return !equality.Semantic.DeepEqual(original, host.Status.Conditions)
+}
+
+func registeredCondition(host *metal3api.BareMetalHost) hostCondition {
+	switch host.Status.ErrorType {
+	case metal3api.RegistrationError, metal3api.ProvisionedRegistrationError:
+		return conditionFalse(reasonFailed, host.Status.ErrorMessage)
+	}
+
+	switch host.Status.Provisioning.State {
+	case metal3api.StateNone:
+		return conditionFalse(reasonNotStarted, "")
+	case metal3api.StateUnmanaged:
+		return conditionFalse(reasonUnmanaged, "Host has no BMC details")
+	case metal3api.StateRegistering:
+		return conditionFalse(reasonInProgress, "")
+	}
+
+	if host.Status.Provisioning.ID == "" {
+		return conditionFalse(reasonNotStarted, "")
+	}
+	return conditionTrue(reasonSucceeded, "")
+}
+
+func bmcAccessibleCondition(host *metal3api.BareMetalHost) hostCondition {
+	switch host.Status.ErrorType {
+	case metal3api.RegistrationError, metal3api.ProvisionedRegistrationError:
+		return conditionFalse(reasonFailed, host.Status.ErrorMessage)
+	case metal3api.PowerManagementError:
+		return conditionFalse(reasonPowerManagementError, host.Status.ErrorMessage)
+	}
+
+	if host.Status.GoodCredentials.Reference == nil {
+		return conditionUnknown(reasonNotStarted, "")
+	}
+	return conditionTrue(reasonSucceeded, "")
+}
+
+func inspectedCondition(host *metal3api.BareMetalHost) hostCondition {
+	if host.Status.ErrorType == metal3api.InspectionError {
+		return conditionFalse(reasonFailed, host.Status.ErrorMessage)
+	}
+	if host.Status.Provisioning.State == metal3api.StateInspecting {
+		return conditionFalse(reasonInProgress, "")
+	}
+	if host.Status.HardwareDetails != nil {
+		return conditionTrue(reasonSucceeded, "")
+	}
+	if inspectionDisabled(host) {
+		return conditionFalse(reasonDisabled, "")
+	}
+	return conditionFalse(reasonNotStarted, "")
+}
+
+func preparedCondition(host *metal3api.BareMetalHost) hostCondition {
+	if host.Status.ErrorType == metal3api.PreparationError {
+		return conditionFalse(reasonFailed, host.Status.ErrorMessage)
+	}
+
+	switch host.Status.Provisioning.State {
+	case metal3api.StatePreparing:
+		return conditionFalse(reasonInProgress, "")
+	case metal3api.StateProvisioning, metal3api.StateProvisioned,
+		metal3api.StateRescuing, metal3api.StateRescued:
+		return conditionTrue(reasonSucceeded, "")
+	default:
+		return conditionFalse(reasonNotStarted, "")
+	}
+}
+
+func provisionedCondition(host *metal3api.BareMetalHost) hostCondition {
+	if host.Status.ErrorType == metal3api.ProvisioningError {
+		return conditionFalse(reasonFailed, host.Status.ErrorMessage)
+	}
+
+	switch host.Status.Provisioning.State {
+	case metal3api.StateProvisioning:
+		return conditionFalse(reasonInProgress, "")
+	case metal3api.StateProvisioned, metal3api.StateRescuing, metal3api.StateRescued:
+		return conditionTrue(reasonSucceeded, provisionedMessage(host))
+	case metal3api.StateExternallyProvisioned:
+		return conditionTrue(reasonExternallyProvisioned, "")
+	default:
+		return conditionFalse(reasonNotStarted, "")
+	}
+}
+
+func provisionedMessage(host *metal3api.BareMetalHost) string {
+	if host.Status.Provisioning.Image.URL != "" {
+		return fmt.Sprintf("Image %s provisioned", host.Status.Provisioning.Image.URL)
+	}
+	if host.Status.Provisioning.CustomDeploy != nil {
+		return fmt.Sprintf("Custom deploy %s provisioned", host.Status.Provisioning.CustomDeploy.Method)
+	}
+	return ""
+}
+
+func poweredOnCondition(host *metal3api.BareMetalHost) hostCondition {
+	status := metav1.ConditionFalse
+	reason := reasonPoweredOff
+	if host.Status.PoweredOn {
+		status = metav1.ConditionTrue
+		reason = reasonPoweredOn
+	}
+
+	if host.Status.ErrorType == metal3api.PowerManagementError {
+		return hostCondition{status, reasonPowerManagementError, host.Status.ErrorMessage}
+	}
+	return hostCondition{status, reason, ""}
+}
+
+func readyCondition(host *metal3api.BareMetalHost) hostCondition {
+	switch host.Status.OperationalStatus {
+	case metal3api.OperationalStatusError:
+		return conditionFalse(reasonError, host.Status.ErrorMessage)
+	case metal3api.OperationalStatusDetached:
+		return conditionFalse(reasonDetached, "")
+	case metal3api.OperationalStatusDelayed:
+		if queue := host.Status.ProvisioningQueue; queue != nil {
+			return conditionFalse(reasonDelayed, fmt.Sprintf("Waiting for capacity in bucket %s at position %d", queue.Bucket, queue.Position))
+		}
+		return conditionFalse(reasonDelayed, "Waiting for the provisioner to have capacity")
+	case metal3api.OperationalStatusServicing:
+		return conditionFalse(reasonServicing, "Changes are being applied to the host")
+	}
+
+	state := host.Status.Provisioning.State
+	switch state {
+	case metal3api.StateAvailable, metal3api.StateProvisioned, metal3api.StateExternallyProvisioned:
+		if host.DeletionTimestamp.IsZero() {
+			return conditionTrue(reasonSucceeded, fmt.Sprintf("Host is %s", state))
+		}
+	}
+	if state == metal3api.StateNone {
+		return conditionFalse(reasonInProgress, "")
+	}
+	return conditionFalse(reasonInProgress, fmt.Sprintf("Host is %s", state))
+}
+
+func taintedCondition(host *metal3api.BareMetalHost) hostCondition {
+	switch host.Status.Provisioning.State {
+	case metal3api.StateProvisioned, metal3api.StateExternallyProvisioned:
+		if taints := host.UntoleratedTaints(nil, corev1.TaintEffectNoExecute); len(taints) > 0 {
+			return conditionTrue(reasonNoExecuteTaint, fmt.Sprintf("Host has taints %s", taintsString(taints)))
+		}
+	}
+	return conditionFalse(reasonNotTainted, "")
+}
+
+func taintsString(taints []corev1.Taint) string {
+	descriptions := make([]string, len(taints))
+	for i := range taints {
+		descriptions[i] = taints[i].ToString()
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+func hostTainted(host *metal3api.BareMetalHost) bool {
+	return meta.IsStatusConditionTrue(host.Status.Conditions, string(metal3api.ConditionTainted))
+}
+
+// reportTaint publishes events on the host and on its consumer when a
+// NoExecute taint starts applying to the provisioned host.
+func reportTaint(info *reconcileInfo) {
+	host := info.host
+	cond := meta.FindStatusCondition(host.Status.Conditions, string(metal3api.ConditionTainted))
+	if cond == nil {
+		return
+	}
+
+	event := host.NewEvent(reasonNoExecuteTaint, cond.Message)
+	event.Type = corev1.EventTypeWarning
+	info.events = append(info.events, event)
+
+	// Claims report the taints they do not tolerate themselves.
+	ref := host.Spec.ConsumerRef
+	if ref == nil || (ref.Kind == hostClaimKind && ref.APIVersion == metal3api.GroupVersion.String()) {
+		return
+	}
+	consumerEvent := host.NewEvent(reasonNoExecuteTaint, cond.Message)
+	consumerEvent.Type = corev1.EventTypeWarning
+	consumerEvent.InvolvedObject = *host.Spec.ConsumerRef
+	if consumerEvent.InvolvedObject.Namespace != "" {
+		consumerEvent.Namespace = consumerEvent.InvolvedObject.Namespace
+	}
+	consumerEvent.Related = &corev1.ObjectReference{
+		Kind:       "BareMetalHost",
+		Namespace:  host.Namespace,
+		Name:       host.Name,
+		UID:        host.UID,
+		APIVersion: metal3api.GroupVersion.String(),
+	}
+	info.events = append(info.events, consumerEvent)
+}
@@ -0,0 +1,254 @@
+package controllers
+
+import (
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUpdateHostConditions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		setup    func(host *metal3api.BareMetalHost)
+		expected map[metal3api.HostConditionType]metav1.ConditionStatus
+		reasons  map[metal3api.HostConditionType]string
+	}{
+		{
+			name: "new host",
+			setup: func(host *metal3api.BareMetalHost) {
+				host.Status.Provisioning.State = metal3api.StateNone
+			},
+			expected: map[metal3api.HostConditionType]metav1.ConditionStatus{
+				metal3api.ConditionRegistered:    metav1.ConditionFalse,
+				metal3api.ConditionBMCAccessible: metav1.ConditionUnknown,
+				metal3api.ConditionInspected:     metav1.ConditionFalse,
+				metal3api.ConditionPrepared:      metav1.ConditionFalse,
+				metal3api.ConditionProvisioned:   metav1.ConditionFalse,
+				metal3api.ConditionPoweredOn:     metav1.ConditionFalse,
+				metal3api.ConditionReady:         metav1.ConditionFalse,
+			},
+			reasons: map[metal3api.HostConditionType]string{
+				metal3api.ConditionRegistered: reasonNotStarted,
+				metal3api.ConditionReady:      reasonInProgress,
+			},
+		},
+		{
+			name: "registration error",
+			setup: func(host *metal3api.BareMetalHost) {
+				host.Status.Provisioning.State = metal3api.StateRegistering
+				host.Status.ErrorType = metal3api.RegistrationError
+				host.Status.ErrorMessage = "bad credentials"
+				host.Status.OperationalStatus = metal3api.OperationalStatusError
+			},
+			expected: map[metal3api.HostConditionType]metav1.ConditionStatus{
+				metal3api.ConditionRegistered:    metav1.ConditionFalse,
+				metal3api.ConditionBMCAccessible: metav1.ConditionFalse,
+				metal3api.ConditionReady:         metav1.ConditionFalse,
+			},
+			reasons: map[metal3api.HostConditionType]string{
+				metal3api.ConditionRegistered:    reasonFailed,
+				metal3api.ConditionBMCAccessible: reasonFailed,
+				metal3api.ConditionReady:         reasonError,
+			},
+		},
+		{
+			name: "inspecting",
+			setup: func(host *metal3api.BareMetalHost) {
+				host.Status.Provisioning.State = metal3api.StateInspecting
+				host.Status.Provisioning.ID = "id"
+				host.Status.GoodCredentials.Reference = &corev1.SecretReference{Name: "creds"}
+			},
+			expected: map[metal3api.HostConditionType]metav1.ConditionStatus{
+				metal3api.ConditionRegistered:    metav1.ConditionTrue,
+				metal3api.ConditionBMCAccessible: metav1.ConditionTrue,
+				metal3api.ConditionInspected:     metav1.ConditionFalse,
+			},
+			reasons: map[metal3api.HostConditionType]string{
+				metal3api.ConditionInspected: reasonInProgress,
+			},
+		},
+		{
+			name: "inspection disabled",
+			setup: func(host *metal3api.BareMetalHost) {
+				host.Status.Provisioning.State = metal3api.StateAvailable
+				host.Status.Provisioning.ID = "id"
+				host.Annotations = map[string]string{metal3api.InspectAnnotationPrefix: metal3api.InspectAnnotationValueDisabled}
+			},
+			expected: map[metal3api.HostConditionType]metav1.ConditionStatus{
+				metal3api.ConditionInspected: metav1.ConditionFalse,
+				metal3api.ConditionReady:     metav1.ConditionTrue,
+			},
+			reasons: map[metal3api.HostConditionType]string{
+				metal3api.ConditionInspected: reasonDisabled,
+			},
+		},
+		{
+			name: "provisioned",
+			setup: func(host *metal3api.BareMetalHost) {
+				host.Status.Provisioning.State = metal3api.StateProvisioned
+				host.Status.Provisioning.ID = "id"
+				host.Status.Provisioning.Image.URL = "http://example.com/image"
+				host.Status.HardwareDetails = &metal3api.HardwareDetails{}
+				host.Status.PoweredOn = true
+			},
+			expected: map[metal3api.HostConditionType]metav1.ConditionStatus{
+				metal3api.ConditionInspected:   metav1.ConditionTrue,
+				metal3api.ConditionPrepared:    metav1.ConditionTrue,
+				metal3api.ConditionProvisioned: metav1.ConditionTrue,
+				metal3api.ConditionPoweredOn:   metav1.ConditionTrue,
+				metal3api.ConditionReady:       metav1.ConditionTrue,
+			},
+			reasons: map[metal3api.HostConditionType]string{
+				metal3api.ConditionPoweredOn: reasonPoweredOn,
+			},
+		},
+		{
+			name: "provisioning failed",
+			setup: func(host *metal3api.BareMetalHost) {
+				host.Status.Provisioning.State = metal3api.StateProvisioning
+				host.Status.Provisioning.ID = "id"
+				host.Status.ErrorType = metal3api.ProvisioningError
+				host.Status.OperationalStatus = metal3api.OperationalStatusError
+			},
+			expected: map[metal3api.HostConditionType]metav1.ConditionStatus{
+				metal3api.ConditionPrepared:    metav1.ConditionTrue,
+				metal3api.ConditionProvisioned: metav1.ConditionFalse,
+				metal3api.ConditionReady:       metav1.ConditionFalse,
+			},
+			reasons: map[metal3api.HostConditionType]string{
+				metal3api.ConditionProvisioned: reasonFailed,
+			},
+		},
+		{
+			name: "power management error",
+			setup: func(host *metal3api.BareMetalHost) {
+				host.Status.Provisioning.State = metal3api.StateExternallyProvisioned
+				host.Status.Provisioning.ID = "id"
+				host.Status.ErrorType = metal3api.PowerManagementError
+				host.Status.OperationalStatus = metal3api.OperationalStatusError
+			},
+			expected: map[metal3api.HostConditionType]metav1.ConditionStatus{
+				metal3api.ConditionBMCAccessible: metav1.ConditionFalse,
+				metal3api.ConditionProvisioned:   metav1.ConditionTrue,
+				metal3api.ConditionPoweredOn:     metav1.ConditionFalse,
+				metal3api.ConditionReady:         metav1.ConditionFalse,
+			},
+			reasons: map[metal3api.HostConditionType]string{
+				metal3api.ConditionBMCAccessible: reasonPowerManagementError,
+				metal3api.ConditionProvisioned:   reasonExternallyProvisioned,
+				metal3api.ConditionPoweredOn:     reasonPowerManagementError,
+			},
+		},
+		{
+			name: "detached",
+			setup: func(host *metal3api.BareMetalHost) {
+				host.Status.Provisioning.State = metal3api.StateProvisioned
+				host.Status.Provisioning.ID = "id"
+				host.Status.OperationalStatus = metal3api.OperationalStatusDetached
+			},
+			expected: map[metal3api.HostConditionType]metav1.ConditionStatus{
+				metal3api.ConditionProvisioned: metav1.ConditionTrue,
+				metal3api.ConditionReady:       metav1.ConditionFalse,
+			},
+			reasons: map[metal3api.HostConditionType]string{
+				metal3api.ConditionReady: reasonDetached,
+			},
+		},
+		{
+			name: "tainted",
+			setup: func(host *metal3api.BareMetalHost) {
+				host.Status.Provisioning.State = metal3api.StateProvisioned
+				host.Status.Provisioning.ID = "id"
+				host.Spec.Taints = []corev1.Taint{
+					{Key: "suspect", Effect: corev1.TaintEffectNoExecute},
+					{Key: "other", Effect: corev1.TaintEffectNoSchedule},
+				}
+			},
+			expected: map[metal3api.HostConditionType]metav1.ConditionStatus{
+				metal3api.ConditionTainted: metav1.ConditionTrue,
+			},
+			reasons: map[metal3api.HostConditionType]string{
+				metal3api.ConditionTainted: reasonNoExecuteTaint,
+			},
+		},
+		{
+			name: "tainted before provisioning",
+			setup: func(host *metal3api.BareMetalHost) {
+				host.Status.Provisioning.State = metal3api.StateAvailable
+				host.Spec.Taints = []corev1.Taint{
+					{Key: "suspect", Effect: corev1.TaintEffectNoExecute},
+				}
+			},
+			expected: map[metal3api.HostConditionType]metav1.ConditionStatus{
+				metal3api.ConditionTainted: metav1.ConditionFalse,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			host := newHost("myhost", &metal3api.BareMetalHostSpec{})
+			host.Generation = 3
+			tc.setup(host)
+
+			assert.True(t, updateHostConditions(host))
+			assert.Len(t, host.Status.Conditions, 8)
+
+			for condType, status := range tc.expected {
+				cond := meta.FindStatusCondition(host.Status.Conditions, string(condType))
+				if cond == nil {
+					t.Fatalf("condition %s not set", condType)
+				}
+				assert.Equal(t, status, cond.Status, "condition %s", condType)
+				assert.Equal(t, int64(3), cond.ObservedGeneration)
+				if reason, ok := tc.reasons[condType]; ok {
+					assert.Equal(t, reason, cond.Reason, "condition %s", condType)
+				}
+			}
+
+			assert.False(t, updateHostConditions(host), "conditions change only along with the status")
+
+			host.Generation = 4
+			assert.True(t, updateHostConditions(host), "conditions follow the generation of the host")
+		})
+	}
+}
+
+func TestReportTaint(t *testing.T) {
+	host := newHost("myhost", &metal3api.BareMetalHostSpec{
+		ConsumerRef: &corev1.ObjectReference{Kind: "Machine", Namespace: "machines", Name: "machine"},
+		Taints:      []corev1.Taint{{Key: "suspect", Value: "dimm", Effect: corev1.TaintEffectNoExecute}},
+	})
+	host.Status.Provisioning.State = metal3api.StateProvisioned
+	updateHostConditions(host)
+	info := makeReconcileInfo(host)
+
+	reportTaint(info)
+
+	if len(info.events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(info.events))
+	}
+	assert.Equal(t, "BareMetalHost", info.events[0].InvolvedObject.Kind)
+	assert.Equal(t, *host.Spec.ConsumerRef, info.events[1].InvolvedObject)
+	assert.Equal(t, "machines", info.events[1].Namespace)
+	assert.Equal(t, "myhost", info.events[1].Related.Name)
+	assert.Contains(t, info.events[1].Message, "suspect=dimm:NoExecute")
+}
+
+func TestReadyConditionDelayed(t *testing.T) {
+	host := &metal3api.BareMetalHost{}
+	host.Status.Provisioning.State = metal3api.StateAvailable
+	host.Status.OperationalStatus = metal3api.OperationalStatusDelayed
+
+	assert.Equal(t, "Waiting for the provisioner to have capacity", readyCondition(host).message)
+
+	host.Status.ProvisioningQueue = &metal3api.ProvisioningQueueStatus{Position: 3, Bucket: "policy/tenant-a"}
+
+	condition := readyCondition(host)
+	assert.Equal(t, reasonDelayed, condition.reason)
+	assert.Equal(t, "Waiting for capacity in bucket policy/tenant-a at position 3", condition.message)
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	labelProvisioningState = "provisioning_state"
+	labelOperationalStatus = "operational_status"
+	labelManufacturer      = "manufacturer"
+	labelProductName       = "product_name"
+	labelComponent         = "component"
+	labelVersion           = "version"
+
+	// hostStateCollectTimeout is the maximum time spent listing hosts
+	// when metrics are scraped.
+	hostStateCollectTimeout = 10 * time.Second
+)
+
+var (
+	hostInfoDesc = prometheus.NewDesc("metal3_host_info",
+		"Information about the current state of the host, always 1",
+		[]string{labelHostNamespace, labelHostName, labelProvisioningState, labelOperationalStatus,
+			labelErrorType, labelManufacturer, labelProductName}, nil)
+	hostPoweredOnDesc = prometheus.NewDesc("metal3_host_powered_on",
+		"Whether the host is powered on",
+		[]string{labelHostNamespace, labelHostName}, nil)
+	hostErrorCountDesc = prometheus.NewDesc("metal3_host_error_count",
+		"The number of errors the host has encountered since its last successful operation",
+		[]string{labelHostNamespace, labelHostName}, nil)
+	hostFirmwareInfoDesc = prometheus.NewDesc("metal3_host_firmware_info",
+		"The current version of a firmware component of the host, always 1",
+		[]string{labelHostNamespace, labelHostName, labelComponent, labelVersion}, nil)
+)
+
+// hostStateCollector reports the current state of each host as gauges,
+// read from the cache when the metrics are scraped so that no series is
+// left behind for deleted hosts.
+type hostStateCollector struct {
+	client client.Reader
+	log    logr.Logger
+}
+
+func newHostStateCollector(c client.Reader, log logr.Logger) *hostStateCollector {
+	return &hostStateCollector{client: c, log: log}
+}
+
+// Describe implements prometheus.Collector.
+func (c *hostStateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- hostInfoDesc
+	ch <- hostPoweredOnDesc
+	ch <- hostErrorCountDesc
+	ch <- hostFirmwareInfoDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *hostStateCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), hostStateCollectTimeout)
+	defer cancel()
+
+	hosts := &metal3api.BareMetalHostList{}
+	if err := c.client.List(ctx, hosts); err != nil {
+		c.log.Error(err, "failed to list hosts")
+		return
+	}
+
+	components := map[types.NamespacedName][]metal3api.FirmwareComponentStatus{}
+	hfcs := &metal3api.HostFirmwareComponentsList{}
+	if err := c.client.List(ctx, hfcs); err != nil {
+		c.log.Error(err, "failed to list host firmware components")
+	}
+	for i := range hfcs.Items {
+		hfc := &hfcs.Items[i]
+		components[types.NamespacedName{Namespace: hfc.Namespace, Name: hfc.Name}] = hfc.Status.Components
+	}
+
+	for i := range hosts.Items {
+		host := &hosts.Items[i]
+		var manufacturer, productName string
+		if details := host.Status.HardwareDetails; details != nil {
+			manufacturer = details.SystemVendor.Manufacturer
+			productName = details.SystemVendor.ProductName
+		}
+
+		ch <- prometheus.MustNewConstMetric(hostInfoDesc, prometheus.GaugeValue, 1,
+			host.Namespace, host.Name, string(host.Status.Provisioning.State), string(host.Status.OperationalStatus),
+			string(host.Status.ErrorType), manufacturer, productName)
+
+		poweredOn := 0.0
+		if host.Status.PoweredOn {
+			poweredOn = 1
+		}
+		ch <- prometheus.MustNewConstMetric(hostPoweredOnDesc, prometheus.GaugeValue, poweredOn,
+			host.Namespace, host.Name)
+		ch <- prometheus.MustNewConstMetric(hostErrorCountDesc, prometheus.GaugeValue, float64(host.Status.ErrorCount),
+			host.Namespace, host.Name)
+
+		for _, component := range components[types.NamespacedName{Namespace: host.Namespace, Name: host.Name}] {
+			version := component.CurrentVersion
+			if version == "" {
+				version = component.InitialVersion
+			}
+			ch <- prometheus.MustNewConstMetric(hostFirmwareInfoDesc, prometheus.GaugeValue, 1,
+				host.Namespace, host.Name, component.Component, version)
+		}
+	}
+}
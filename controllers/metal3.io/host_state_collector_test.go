@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	promutil "github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestHostStateCollector(t *testing.T) {
+	host := newDefaultHost(t)
+	host.Name = "host-0"
+	host.Status.Provisioning.State = metal3api.StateProvisioned
+	host.Status.OperationalStatus = metal3api.OperationalStatusError
+	host.Status.ErrorType = metal3api.PowerManagementError
+	host.Status.ErrorCount = 3
+	host.Status.PoweredOn = true
+	host.Status.HardwareDetails = &metal3api.HardwareDetails{
+		SystemVendor: metal3api.HardwareSystemVendor{
+			Manufacturer: "Dell Inc.",
+			ProductName:  "PowerEdge R640",
+		},
+	}
+
+	other := newDefaultNamedHost(t, "host-1")
+	other.Status.Provisioning.State = metal3api.StateAvailable
+	other.Status.OperationalStatus = metal3api.OperationalStatusOK
+
+	hfc := &metal3api.HostFirmwareComponents{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      host.Name,
+			Namespace: host.Namespace,
+		},
+		Status: metal3api.HostFirmwareComponentsStatus{
+			Components: []metal3api.FirmwareComponentStatus{
+				{Component: "bios", InitialVersion: "1.0.0", CurrentVersion: "1.2.0"},
+				{Component: "bmc", InitialVersion: "4.40.10"},
+			},
+		},
+	}
+
+	r := newTestReconciler(host, other, hfc)
+	collector := newHostStateCollector(r.Client, ctrl.Log)
+
+	expected := `
+# HELP metal3_host_error_count The number of errors the host has encountered since its last successful operation
+# TYPE metal3_host_error_count gauge
+metal3_host_error_count{host="host-0",namespace="test-namespace"} 3
+metal3_host_error_count{host="host-1",namespace="test-namespace"} 0
+# HELP metal3_host_firmware_info The current version of a firmware component of the host, always 1
+# TYPE metal3_host_firmware_info gauge
+metal3_host_firmware_info{component="bios",host="host-0",namespace="test-namespace",version="1.2.0"} 1
+metal3_host_firmware_info{component="bmc",host="host-0",namespace="test-namespace",version="4.40.10"} 1
+# HELP metal3_host_info Information about the current state of the host, always 1
+# TYPE metal3_host_info gauge
+metal3_host_info{error_type="power management error",host="host-0",manufacturer="Dell Inc.",namespace="test-namespace",operational_status="error",product_name="PowerEdge R640",provisioning_state="provisioned"} 1
+metal3_host_info{error_type="",host="host-1",manufacturer="",namespace="test-namespace",operational_status="OK",product_name="",provisioning_state="available"} 1
+# HELP metal3_host_powered_on Whether the host is powered on
+# TYPE metal3_host_powered_on gauge
+metal3_host_powered_on{host="host-0",namespace="test-namespace"} 1
+metal3_host_powered_on{host="host-1",namespace="test-namespace"} 0
+`
+	if err := promutil.CollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
@@ -51,6 +51,8 @@ func (hsm *hostStateMachine) handlers() map[metal3api.ProvisioningState]stateHan
 		metal3api.StateReady:                   hsm.handleAvailable,
 		metal3api.StateProvisioning:            hsm.handleProvisioning,
 		metal3api.StateProvisioned:             hsm.handleProvisioned,
+		metal3api.StateRescuing:                hsm.handleRescuing,
+		metal3api.StateRescued:                 hsm.handleRescued,
 		metal3api.StateDeprovisioning:          hsm.handleDeprovisioning,
 		metal3api.StatePoweringOffBeforeDelete: hsm.handlePoweringOffBeforeDelete,
 		metal3api.StateDeleting:                hsm.handleDeleting,
@@ -81,14 +83,70 @@ func recordStateEnd(info *reconcileInfo, host *metal3api.BareMetalHost, state me
 	return
 }
 
+// stateTransitionTrigger describes what caused the host to move from the
+// initial state to the next one.
+func (hsm *hostStateMachine) stateTransitionTrigger(initialState metal3api.ProvisioningState) metal3api.StateTransitionTrigger {
+	host := hsm.Host
+	switch {
+	case !host.DeletionTimestamp.IsZero():
+		return metal3api.TriggerDeletion
+	case hsm.NextState == metal3api.StateDeprovisioning && host.Status.ErrorType != "":
+		return metal3api.TriggerError
+	case hsm.NextState == metal3api.StateDeprovisioning:
+		return metal3api.TriggerProvisioningCancelled
+	case hsm.NextState == metal3api.StateExternallyProvisioned, initialState == metal3api.StateExternallyProvisioned:
+		return metal3api.TriggerExternallyProvisioned
+	case hsm.NextState == metal3api.StateInspecting && hasInspectAnnotation(host):
+		return metal3api.TriggerInspectionRequested
+	case initialState == metal3api.StateAvailable && hsm.NextState == metal3api.StatePreparing:
+		return metal3api.TriggerConfigurationChanged
+	case initialState == metal3api.StateAvailable && hsm.NextState == metal3api.StateProvisioning:
+		return metal3api.TriggerProvisioningRequested
+	case hsm.NextState == metal3api.StateRescuing:
+		return metal3api.TriggerRescueRequested
+	default:
+		return metal3api.TriggerCompleted
+	}
+}
+
 func (hsm *hostStateMachine) ensureCapacity(info *reconcileInfo, state metal3api.ProvisioningState) actionResult {
-	hasCapacity, err := hsm.Provisioner.HasCapacity()
-	if err != nil {
-		return actionError{errors.Wrap(err, "failed to determine current provisioner capacity")}
+	now := metav1.Now()
+	var queue []queuedHost
+	var bucket string
+
+	if hsm.Reconciler != nil {
+		capacity, err := loadProvisioningCapacity(info.ctx, hsm.Reconciler, hsm.Host)
+		if err != nil {
+			return actionError{errors.Wrap(err, "failed to determine provisioning concurrency")}
+		}
+		if bucket, err = capacity.fullBucket(); err != nil {
+			return actionError{errors.Wrap(err, "failed to determine provisioning concurrency")}
+		}
+		queue = capacity.queue(now)
+		if bucket == "" {
+			// Let the hosts that have waited longer go first
+			bucket = capacity.waitingAhead(queue)
+		}
+	}
+
+	if bucket == "" {
+		ctx, cancel := info.provisionerContext(provisionerCapacityTimeout)
+		defer cancel()
+
+		hasCapacity, err := hsm.Provisioner.HasCapacity(ctx)
+		if err != nil {
+			return actionError{errors.Wrap(err, "failed to determine current provisioner capacity")}
+		}
+
+		if !hasCapacity {
+			bucket = provisionerBucket
+		}
 	}
 
-	if !hasCapacity {
-		return recordActionDelayed(info, state)
+	if bucket != "" {
+		result := recordActionDelayed(info, state, bucket)
+		updateProvisioningQueue(hsm.Host, queue, bucket, now)
+		return result
 	}
 
 	return nil
@@ -115,10 +173,20 @@ func (hsm *hostStateMachine) updateHostStateFrom(initialState metal3api.Provisio
 		now := metav1.Now()
 		recordStateEnd(info, hsm.Host, initialState, now)
 		recordStateBegin(hsm.Host, hsm.NextState, now)
+		recordIdleState(info, hsm.Host, hsm.NextState, now)
+		hsm.Host.RecordStateTransition(metal3api.StateTransition{
+			Time:         now,
+			OldState:     initialState,
+			NewState:     hsm.NextState,
+			Trigger:      hsm.stateTransitionTrigger(initialState),
+			ErrorType:    hsm.Host.Status.ErrorType,
+			ErrorMessage: hsm.Host.Status.ErrorMessage,
+		})
 		info.postSaveCallbacks = append(info.postSaveCallbacks, func() {
 			stateChanges.With(stateChangeMetricLabels(initialState, hsm.NextState)).Inc()
 		})
 		hsm.Host.Status.Provisioning.State = hsm.NextState
+		hsm.Host.Status.ProvisioningQueue = nil
 		// Here we assume that if we're being asked to change the
 		// state, the return value of ReconcileState (our caller) is
 		// set up to ensure the change in the host is written back to
@@ -152,6 +220,9 @@ func (hsm *hostStateMachine) checkDelayedHost(info *reconcileInfo) actionResult
 
 		// A slot is available, let's cleanup the status and retry
 		clearError(info.host)
+		if holdsProvisioningSlot(info.host) {
+			info.host.Status.ProvisioningQueue = nil
+		}
 		return actionUpdate{}
 	}
 
@@ -190,6 +261,10 @@ func (hsm *hostStateMachine) ReconcileState(info *reconcileInfo) (actionRes acti
 		return detachedResult
 	}
 
+	if remediationResult := hsm.checkRemediation(info); remediationResult != nil {
+		return remediationResult
+	}
+
 	if registerResult := hsm.ensureRegistered(info); registerResult != nil {
 		hostRegistrationRequired.Inc()
 		return registerResult
@@ -225,7 +300,8 @@ func (hsm *hostStateMachine) checkInitiateDelete(log logr.Logger) bool {
 	case metal3api.StateRegistering, metal3api.StateUnmanaged, metal3api.StateNone:
 		// Skip the power off before delete
 		hsm.NextState = metal3api.StateDeleting
-	case metal3api.StateProvisioning, metal3api.StateProvisioned:
+	case metal3api.StateProvisioning, metal3api.StateProvisioned,
+		metal3api.StateRescuing, metal3api.StateRescued:
 		if hsm.Host.OperationalStatus() == metal3api.OperationalStatusDetached {
 			if delayDeleteForDetachedHost(hsm.Host) {
 				log.Info("Delaying detached host deletion")
@@ -549,15 +625,65 @@ func (hsm *hostStateMachine) handleProvisioning(info *reconcileInfo) actionResul
 }
 
 func (hsm *hostStateMachine) handleProvisioned(info *reconcileInfo) actionResult {
-	if hsm.provisioningCancelled() {
+	// Servicing in progress has to finish before deprovisioning
+	servicing := hsm.Host.OperationalStatus() == metal3api.OperationalStatusServicing
+	if !servicing && hsm.provisioningCancelled() {
 		hsm.NextState = metal3api.StateDeprovisioning
 		return actionComplete{}
 	}
 
+	if actResult := hsm.Reconciler.actionServicing(hsm.Provisioner, info); actResult != nil {
+		return actResult
+	}
+
+	if hsm.Host.Spec.Rescue != nil {
+		hsm.NextState = metal3api.StateRescuing
+		return actionComplete{}
+	}
+
 	// ErrorCount is cleared when appropriate inside actionManageSteadyState
 	return hsm.Reconciler.actionManageSteadyState(hsm.Provisioner, info)
 }
 
+func (hsm *hostStateMachine) handleRescuing(info *reconcileInfo) actionResult {
+	if hsm.provisioningCancelled() {
+		hsm.NextState = metal3api.StateDeprovisioning
+		return actionComplete{}
+	}
+
+	if hsm.Host.Spec.Rescue == nil {
+		// Rescue was lifted before it finished, unrescue the host
+		hsm.NextState = metal3api.StateRescued
+		return actionComplete{}
+	}
+
+	actResult := hsm.Reconciler.actionRescuing(hsm.Provisioner, info)
+	if _, complete := actResult.(actionComplete); complete {
+		hsm.NextState = metal3api.StateRescued
+		hsm.Host.Status.ErrorCount = 0
+	}
+	return actResult
+}
+
+func (hsm *hostStateMachine) handleRescued(info *reconcileInfo) actionResult {
+	if hsm.provisioningCancelled() {
+		hsm.NextState = metal3api.StateDeprovisioning
+		return actionComplete{}
+	}
+
+	if hsm.Host.Spec.Rescue != nil {
+		// Wait for the rescue request to be lifted
+		return actionWait{}
+	}
+
+	actResult := hsm.Reconciler.actionUnrescuing(hsm.Provisioner, info)
+	if _, complete := actResult.(actionComplete); complete {
+		hsm.NextState = metal3api.StateProvisioned
+		hsm.Host.Status.ErrorCount = 0
+	}
+	return actResult
+}
+
 func (hsm *hostStateMachine) handleDeprovisioning(info *reconcileInfo) actionResult {
 	actResult := hsm.Reconciler.actionDeprovisioning(hsm.Provisioner, info)
 
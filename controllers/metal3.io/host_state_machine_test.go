@@ -165,6 +165,87 @@ func TestProvisioningCapacity(t *testing.T) {
 	}
 }
 
+func TestStateHistory(t *testing.T) {
+	testCases := []struct {
+		Scenario string
+		Host     *metal3api.BareMetalHost
+
+		ExpectedTransition metal3api.StateTransition
+	}{
+		{
+			Scenario: "inspection",
+			Host:     host(metal3api.StateRegistering).build(),
+
+			ExpectedTransition: metal3api.StateTransition{
+				OldState: metal3api.StateRegistering,
+				NewState: metal3api.StateInspecting,
+				Trigger:  metal3api.TriggerCompleted,
+			},
+		},
+		{
+			Scenario: "provisioning",
+			Host:     host(metal3api.StateAvailable).SaveHostProvisioningSettings().build(),
+
+			ExpectedTransition: metal3api.StateTransition{
+				OldState: metal3api.StateAvailable,
+				NewState: metal3api.StateProvisioning,
+				Trigger:  metal3api.TriggerProvisioningRequested,
+			},
+		},
+		{
+			Scenario: "provisioning failed",
+			Host: host(metal3api.StateProvisioning).
+				SetStatusError(metal3api.OperationalStatusError, metal3api.ProvisioningError, "deploy failed", 1).
+				build(),
+
+			ExpectedTransition: metal3api.StateTransition{
+				OldState:     metal3api.StateProvisioning,
+				NewState:     metal3api.StateDeprovisioning,
+				Trigger:      metal3api.TriggerError,
+				ErrorType:    metal3api.ProvisioningError,
+				ErrorMessage: "deploy failed",
+			},
+		},
+		{
+			Scenario: "provisioning cancelled",
+			Host:     host(metal3api.StateProvisioned).SetImageURL("").SetStatusImageURL("not-empty").build(),
+
+			ExpectedTransition: metal3api.StateTransition{
+				OldState: metal3api.StateProvisioned,
+				NewState: metal3api.StateDeprovisioning,
+				Trigger:  metal3api.TriggerProvisioningCancelled,
+			},
+		},
+		{
+			Scenario: "deletion",
+			Host:     host(metal3api.StateAvailable).setDeletion().withFinalizer().build(),
+
+			ExpectedTransition: metal3api.StateTransition{
+				OldState: metal3api.StateAvailable,
+				NewState: metal3api.StatePoweringOffBeforeDelete,
+				Trigger:  metal3api.TriggerDeletion,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			prov := newMockProvisioner()
+			prov.setHasCapacity(true)
+			hsm := newHostStateMachine(tc.Host, testNewReconciler(tc.Host), prov, true)
+
+			hsm.ReconcileState(makeDefaultReconcileInfo(tc.Host))
+
+			history := tc.Host.Status.StateHistory
+			if assert.Len(t, history, 1) {
+				assert.False(t, history[0].Time.IsZero())
+				history[0].Time = metav1.Time{}
+				assert.Equal(t, tc.ExpectedTransition, history[0])
+			}
+		})
+	}
+}
+
 func TestDeprovisioningCapacity(t *testing.T) {
 	testCases := []struct {
 		Scenario string
@@ -1271,6 +1352,7 @@ func (hb *hostBuilder) setDetached(val string) *hostBuilder {
 
 func makeDefaultReconcileInfo(host *metal3api.BareMetalHost) *reconcileInfo {
 	return &reconcileInfo{
+		ctx:     context.TODO(),
 		log:     logf.Log.WithName("controllers").WithName("BareMetalHost").WithName("host_state_machine"),
 		host:    host,
 		request: ctrl.Request{},
@@ -1311,7 +1393,7 @@ func (m *mockProvisioner) setHasCapacity(hasCapacity bool) {
 	m.hasCapacity = hasCapacity
 }
 
-func (m *mockProvisioner) HasCapacity() (result bool, err error) {
+func (m *mockProvisioner) HasCapacity(_ context.Context) (result bool, err error) {
 	return m.hasCapacity, nil
 }
 
@@ -1329,85 +1411,102 @@ func (m *mockProvisioner) calledNoError(methodName string) bool {
 	return m.callsNoError[methodName]
 }
 
-func (m *mockProvisioner) ValidateManagementAccess(_ provisioner.ManagementAccessData, _, _ bool) (result provisioner.Result, provID string, err error) {
+func (m *mockProvisioner) ValidateManagementAccess(_ context.Context, _ provisioner.ManagementAccessData, _, _ bool) (result provisioner.Result, provID string, err error) {
 	return m.getNextResultByMethod("ValidateManagementAccess"), "", err
 }
 
-func (m *mockProvisioner) PreprovisioningImageFormats() ([]metal3api.ImageFormat, error) {
+func (m *mockProvisioner) PreprovisioningImageFormats(_ context.Context) ([]metal3api.ImageFormat, error) {
 	return nil, nil
 }
 
-func (m *mockProvisioner) InspectHardware(_ provisioner.InspectData, _, _, _ bool) (result provisioner.Result, started bool, details *metal3api.HardwareDetails, err error) {
+func (m *mockProvisioner) InspectHardware(_ context.Context, _ provisioner.InspectData, _, _, _ bool) (result provisioner.Result, started bool, details *metal3api.HardwareDetails, err error) {
 	details = &metal3api.HardwareDetails{}
 	return m.getNextResultByMethod("InspectHardware"), true, details, err
 }
 
-func (m *mockProvisioner) UpdateHardwareState() (hwState provisioner.HardwareState, err error) {
+func (m *mockProvisioner) UpdateHardwareState(_ context.Context) (hwState provisioner.HardwareState, err error) {
 	return
 }
 
-func (m *mockProvisioner) Prepare(_ provisioner.PrepareData, _ bool, _ bool) (result provisioner.Result, started bool, err error) {
+func (m *mockProvisioner) Prepare(_ context.Context, _ provisioner.PrepareData, _ bool, _ bool) (result provisioner.Result, started bool, err error) {
 	return m.getNextResultByMethod("Prepare"), m.nextResults["Prepare"].Dirty, err
 }
 
-func (m *mockProvisioner) Adopt(_ provisioner.AdoptData, _ bool) (result provisioner.Result, err error) {
+func (m *mockProvisioner) Service(_ context.Context, _ provisioner.ServicingData, _ bool, _ bool) (result provisioner.Result, started bool, err error) {
+	return m.getNextResultByMethod("Service"), m.nextResults["Service"].Dirty, err
+}
+
+func (m *mockProvisioner) Rescue(_ context.Context, _ provisioner.RescueData, _ bool) (result provisioner.Result, err error) {
+	return m.getNextResultByMethod("Rescue"), err
+}
+
+func (m *mockProvisioner) Unrescue(_ context.Context, _ bool) (result provisioner.Result, err error) {
+	return m.getNextResultByMethod("Unrescue"), err
+}
+
+func (m *mockProvisioner) Adopt(_ context.Context, _ provisioner.AdoptData, _ bool) (result provisioner.Result, err error) {
 	return m.getNextResultByMethod("Adopt"), err
 }
 
-func (m *mockProvisioner) Provision(_ provisioner.ProvisionData, _ bool) (result provisioner.Result, err error) {
+func (m *mockProvisioner) Provision(_ context.Context, _ provisioner.ProvisionData, _ bool) (result provisioner.Result, err error) {
 	return m.getNextResultByMethod("Provision"), err
 }
 
-func (m *mockProvisioner) Deprovision(_ bool) (result provisioner.Result, err error) {
-	return m.getNextResultByMethod("Deprovision"), err
+func (m *mockProvisioner) Deprovision(_ context.Context, data provisioner.DeprovisionData, _ bool) (result provisioner.Result, erasureStarted bool, err error) {
+	result = m.getNextResultByMethod("Deprovision")
+	return result, data.EraseDisks && !data.ErasureStarted, err
+}
+
+func (m *mockProvisioner) SetBootDevice(_ context.Context, _ provisioner.BootDeviceData) (result provisioner.Result, err error) {
+	return m.getNextResultByMethod("SetBootDevice"), err
 }
 
-func (m *mockProvisioner) Delete() (result provisioner.Result, err error) {
+func (m *mockProvisioner) Delete(_ context.Context) (result provisioner.Result, err error) {
 	return m.getNextResultByMethod("Delete"), err
 }
 
-func (m *mockProvisioner) Detach() (result provisioner.Result, err error) {
+func (m *mockProvisioner) Detach(_ context.Context) (result provisioner.Result, err error) {
 	res := m.getNextResultByMethod("Detach")
 	return res, err
 }
 
-func (m *mockProvisioner) PowerOn(_ bool) (result provisioner.Result, err error) {
+func (m *mockProvisioner) PowerOn(_ context.Context, _ bool) (result provisioner.Result, err error) {
 	return m.getNextResultByMethod("PowerOn"), err
 }
 
-func (m *mockProvisioner) PowerOff(_ metal3api.RebootMode, _ bool) (result provisioner.Result, err error) {
+func (m *mockProvisioner) PowerOff(_ context.Context, _ metal3api.RebootMode, _ bool) (result provisioner.Result, err error) {
 	return m.getNextResultByMethod("PowerOff"), err
 }
 
-func (m *mockProvisioner) TryInit() (result bool, err error) {
+func (m *mockProvisioner) TryInit(_ context.Context) (result bool, err error) {
 	return
 }
 
-func (m *mockProvisioner) GetFirmwareSettings(_ bool) (settings metal3api.SettingsMap, schema map[string]metal3api.SettingSchema, err error) {
+func (m *mockProvisioner) GetFirmwareSettings(_ context.Context, _ bool) (settings metal3api.SettingsMap, schema map[string]metal3api.SettingSchema, err error) {
 	return
 }
 
-func (m *mockProvisioner) AddBMCEventSubscriptionForNode(_ *metal3api.BMCEventSubscription, _ provisioner.HTTPHeaders) (result provisioner.Result, err error) {
+func (m *mockProvisioner) AddBMCEventSubscriptionForNode(_ context.Context, _ *metal3api.BMCEventSubscription, _ provisioner.HTTPHeaders) (result provisioner.Result, err error) {
 	return result, nil
 }
 
-func (m *mockProvisioner) RemoveBMCEventSubscriptionForNode(_ metal3api.BMCEventSubscription) (result provisioner.Result, err error) {
+func (m *mockProvisioner) RemoveBMCEventSubscriptionForNode(_ context.Context, _ metal3api.BMCEventSubscription) (result provisioner.Result, err error) {
 	return result, nil
 }
 
-func (p *mockProvisioner) GetFirmwareComponents() (components []metal3api.FirmwareComponentStatus, err error) {
+func (p *mockProvisioner) GetFirmwareComponents(_ context.Context) (components []metal3api.FirmwareComponentStatus, err error) {
 	return components, nil
 }
 
-func (m *mockProvisioner) IsDataImageReady() (isNodeBusy bool, nodeError error) {
+func (m *mockProvisioner) IsDataImageReady(_ context.Context) (isNodeBusy bool, nodeError error) {
 	return false, nil
 }
 
-func (p *mockProvisioner) AttachDataImage(url string) (err error) {
+func (p *mockProvisioner) AttachDataImage(_ context.Context, url string) (err error) {
 	return nil
 }
 
-func (p *mockProvisioner) DetachDataImage() (err error) {
+func (p *mockProvisioner) DetachDataImage(_ context.Context) (err error) {
 	return nil
 }
 
@@ -139,7 +139,10 @@ func (r *HostFirmwareComponentsReconciler) Reconcile(ctx context.Context, req ct
 		return ctrl.Result{}, fmt.Errorf("failed to create provisioner: %w", err)
 	}
 
-	ready, err := prov.TryInit()
+	provCtx, cancel := context.WithTimeout(ctx, provisionerCallTimeout)
+	defer cancel()
+
+	ready, err := prov.TryInit(provCtx)
 	if err != nil || !ready {
 		var msg string
 		if err == nil {
@@ -153,7 +156,7 @@ func (r *HostFirmwareComponentsReconciler) Reconcile(ctx context.Context, req ct
 
 	info.log.V(1).Info("retrieving firmware components and saving to resource", "Node", bmh.Status.Provisioning.ID)
 	// Check ironic for the components information if possible
-	components, err := prov.GetFirmwareComponents()
+	components, err := prov.GetFirmwareComponents(provCtx)
 
 	if err != nil {
 		if errors.Is(err, provisioner.ErrFirmwareUpdateUnsupported) {
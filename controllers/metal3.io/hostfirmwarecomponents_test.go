@@ -399,7 +399,7 @@ func TestStoreHostFirmwareComponents(t *testing.T) {
 				bmh: bmh,
 			}
 
-			components, err := prov.GetFirmwareComponents()
+			components, err := prov.GetFirmwareComponents(context.TODO())
 			assert.NoError(t, err)
 
 			err = r.updateHostFirmware(info, components)
@@ -148,7 +148,10 @@ func (r *HostFirmwareSettingsReconciler) Reconcile(ctx context.Context, req ctrl
 		return ctrl.Result{}, errors.Wrap(err, "failed to create provisioner")
 	}
 
-	ready, err := prov.TryInit()
+	provCtx, cancel := context.WithTimeout(ctx, provisionerCallTimeout)
+	defer cancel()
+
+	ready, err := prov.TryInit(provCtx)
 	if err != nil || !ready {
 		var msg string
 		if err == nil {
@@ -163,7 +166,7 @@ func (r *HostFirmwareSettingsReconciler) Reconcile(ctx context.Context, req ctrl
 	info.log.V(1).Info("retrieving firmware settings and saving to resource", "Node", bmh.Status.Provisioning.ID)
 
 	// Get the current settings and schema, retry if provisioner returns error
-	currentSettings, schema, err := prov.GetFirmwareSettings(true)
+	currentSettings, schema, err := prov.GetFirmwareSettings(provCtx, true)
 	if err != nil {
 		reqLogger.Info("provisioner returns error", "Error", err.Error(), "RequeueAfter", provisionerRetryDelay)
 		return ctrl.Result{Requeue: true, RequeueAfter: provisionerRetryDelay}, nil
@@ -507,7 +507,7 @@ func TestStoreHostFirmwareSettings(t *testing.T) {
 				assert.NoError(t, err)
 			}
 
-			currentSettings, schema, err := prov.GetFirmwareSettings(true)
+			currentSettings, schema, err := prov.GetFirmwareSettings(context.TODO(), true)
 			assert.Equal(t, nil, err)
 
 			err = r.updateHostFirmwareSettings(currentSettings, schema, info)
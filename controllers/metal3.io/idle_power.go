@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// idlePowerOffPolicy returns the idle power-off policy of the host, that
+// of its hardware profile unless it sets its own.
+func idlePowerOffPolicy(info *reconcileInfo) (*metal3api.IdlePowerOffPolicy, error) {
+	if info.host.Spec.IdlePowerOff != nil {
+		return info.host.Spec.IdlePowerOff, nil
+	}
+	if info.host.HardwareProfile() == "" {
+		return nil, nil
+	}
+	hwProf, err := info.profiles.get(info.host.HardwareProfile())
+	if err != nil {
+		return nil, err
+	}
+	return hwProf.IdlePowerOff, nil
+}
+
+// updateIdlePowerStatus records when the host was powered off because it
+// is idle, and accounts for the time it stayed powered off once it is not
+// kept powered off any more. It returns whether the status was changed.
+func updateIdlePowerStatus(info *reconcileInfo, policy *metal3api.IdlePowerOffPolicy, idle bool, now metav1.Time) (dirty bool) {
+	status := &info.host.Status
+	if policy != nil && status.IdleSince == nil {
+		// The host became available before idle times were recorded
+		status.IdleSince = &now
+		dirty = true
+	}
+
+	switch {
+	case idle && !status.PoweredOn && status.IdlePoweredOffSince == nil:
+		info.log.Info("host powered off while idle")
+		status.IdlePoweredOffSince = &now
+		dirty = true
+	case !idle && status.IdlePoweredOffSince != nil:
+		recordIdlePowerOffEnd(info, now)
+		dirty = true
+	}
+	return
+}
+
+// idlePowerOffCounted holds, for each host kept powered off while idle,
+// the time up to which this has been accounted for in the metric, so
+// that the status does not need to be updated to account for it.
+var idlePowerOffCounted sync.Map
+
+// countIdlePowerOff adds the time the host has been kept powered off
+// while idle, and not yet accounted for, to the metric.
+func countIdlePowerOff(request ctrl.Request, since time.Time, now time.Time) {
+	if counted, ok := idlePowerOffCounted.Load(request.NamespacedName); ok && counted.(time.Time).After(since) {
+		since = counted.(time.Time)
+	}
+	if now.After(since) {
+		idlePowerOffSeconds.With(hostMetricLabels(request)).Add(now.Sub(since).Seconds())
+	}
+	idlePowerOffCounted.Store(request.NamespacedName, now)
+}
+
+// forgetIdlePowerOff drops the accounting of the time a deleted host was
+// kept powered off while idle.
+func forgetIdlePowerOff(request ctrl.Request) {
+	idlePowerOffCounted.Delete(request.NamespacedName)
+}
+
+// recordIdlePowerOff accounts for the time the host has been kept powered
+// off while idle, so that the metric grows while the host is powered off.
+func recordIdlePowerOff(info *reconcileInfo, now metav1.Time) {
+	if since := info.host.Status.IdlePoweredOffSince; since != nil {
+		countIdlePowerOff(info.request, since.Time, now.Time)
+	}
+}
+
+// recordIdlePowerOffEnd accounts for the rest of the time the host was
+// kept powered off while idle.
+func recordIdlePowerOffEnd(info *reconcileInfo, now metav1.Time) {
+	since := info.host.Status.IdlePoweredOffSince
+	if since == nil {
+		return
+	}
+	info.host.Status.IdlePoweredOffSince = nil
+	info.log.Info("host no longer kept powered off while idle", "duration", now.Sub(since.Time))
+
+	request := info.request
+	info.postSaveCallbacks = append(info.postSaveCallbacks, func() {
+		countIdlePowerOff(request, since.Time, now.Time)
+		idlePowerOffCounted.Delete(request.NamespacedName)
+	})
+}
+
+// recordIdleState records the time at which the host becomes available,
+// and stops accounting for idle time once it is not available any more.
+func recordIdleState(info *reconcileInfo, host *metal3api.BareMetalHost, nextState metal3api.ProvisioningState, now metav1.Time) {
+	if nextState == metal3api.StateAvailable {
+		host.Status.IdleSince = &now
+		return
+	}
+	host.Status.IdleSince = nil
+	recordIdlePowerOffEnd(info, now)
+}
@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	promutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func newIdleHost(idleFor time.Duration) *metal3api.BareMetalHost {
+	host := newHost("host", &metal3api.BareMetalHostSpec{Online: true})
+	host.Status.Provisioning.State = metal3api.StateAvailable
+	host.Status.PoweredOn = true
+	host.Status.IdleSince = &metav1.Time{Time: time.Now().Add(-idleFor)}
+	return host
+}
+
+func manageIdleHostPower(t *testing.T, r *BareMetalHostReconciler, prov *mockProvisioner, host *metal3api.BareMetalHost) actionResult {
+	t.Helper()
+	info := makeReconcileInfo(host)
+	info.profiles = &hardwareProfiles{ctx: context.TODO(), client: r.Client}
+	result := r.manageHostPower(prov, info)
+	if _, err := result.Result(); err != nil {
+		t.Fatal(err)
+	}
+	for _, callback := range info.postSaveCallbacks {
+		callback()
+	}
+	return result
+}
+
+func TestIdlePowerOff(t *testing.T) {
+	host := newIdleHost(2 * time.Hour)
+	host.Spec.IdlePowerOff = &metal3api.IdlePowerOffPolicy{After: metav1.Duration{Duration: time.Hour}}
+	r := newTestReconciler(host)
+
+	prov := newPowerMockProvisioner()
+	manageIdleHostPower(t, r, prov, host)
+	assert.False(t, prov.callsNoError["PowerOff"])
+	assert.True(t, host.Spec.Online)
+
+	host.Status.PoweredOn = false
+	result := manageIdleHostPower(t, r, prov, host)
+	assert.IsType(t, actionUpdate{}, result)
+	assert.NotNil(t, host.Status.IdlePoweredOffSince)
+
+	// The time powered off is accounted for while the host stays idle
+	poweredOffSince := metav1.NewTime(host.Status.IdlePoweredOffSince.Add(-10 * time.Minute))
+	host.Status.IdlePoweredOffSince = &poweredOffSince
+	metric := idlePowerOffSeconds.With(hostMetricLabels(ctrl.Request{}))
+	before := promutil.ToFloat64(metric)
+	result = manageIdleHostPower(t, r, prov, host)
+	assert.Equal(t, actionContinue{time.Minute}, result, "idle hosts are checked regularly")
+	assert.InDelta(t, 600, promutil.ToFloat64(metric)-before, 5)
+	assert.Equal(t, &poweredOffSince, host.Status.IdlePoweredOffSince)
+
+	// Time already accounted for is not counted again
+	manageIdleHostPower(t, r, prov, host)
+	assert.InDelta(t, 600, promutil.ToFloat64(metric)-before, 5)
+
+	// A power-on window starts
+	host.Spec.IdlePowerOff.PowerOnWindows = []metal3api.PowerWindow{{Start: "00:00", End: "00:00"}}
+	result = manageIdleHostPower(t, r, prov, host)
+	assert.IsType(t, actionUpdate{}, result)
+	assert.Nil(t, host.Status.IdlePoweredOffSince)
+
+	prov = newPowerMockProvisioner()
+	prov.nextResults = nil
+	manageIdleHostPower(t, r, prov, host)
+	assert.True(t, prov.callsNoError["PowerOn"])
+	assert.True(t, host.Status.PoweredOn)
+}
+
+func TestIdlePowerOffNotIdle(t *testing.T) {
+	testCases := []struct {
+		Scenario string
+		Setup    func(host *metal3api.BareMetalHost)
+	}{
+		{
+			Scenario: "recently available",
+			Setup: func(host *metal3api.BareMetalHost) {
+				host.Status.IdleSince = &metav1.Time{Time: time.Now()}
+			},
+		},
+		{
+			Scenario: "no policy",
+			Setup: func(host *metal3api.BareMetalHost) {
+				host.Spec.IdlePowerOff = nil
+			},
+		},
+		{
+			Scenario: "provisioned",
+			Setup: func(host *metal3api.BareMetalHost) {
+				host.Status.Provisioning.State = metal3api.StateProvisioned
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			host := newIdleHost(2 * time.Hour)
+			host.Spec.IdlePowerOff = &metal3api.IdlePowerOffPolicy{After: metav1.Duration{Duration: time.Hour}}
+			tc.Setup(host)
+			r := newTestReconciler(host)
+
+			prov := newPowerMockProvisioner()
+			manageIdleHostPower(t, r, prov, host)
+			assert.NotContains(t, prov.callsNoError, "PowerOff")
+			assert.True(t, host.Status.PoweredOn)
+		})
+	}
+}
+
+func TestIdlePowerOffProfilePolicy(t *testing.T) {
+	hwProfile := &metal3api.HardwareProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+		Spec: metal3api.HardwareProfileSpec{
+			IdlePowerOff: &metal3api.IdlePowerOffPolicy{After: metav1.Duration{Duration: time.Hour}},
+		},
+	}
+	host := newIdleHost(2 * time.Hour)
+	host.Status.HardwareProfile = "pool"
+	r := newTestReconciler(hwProfile, host)
+
+	info := makeReconcileInfo(host)
+	info.profiles = &hardwareProfiles{ctx: context.TODO(), client: r.Client}
+	policy, err := idlePowerOffPolicy(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, hwProfile.Spec.IdlePowerOff, policy)
+
+	host.Spec.IdlePowerOff = &metal3api.IdlePowerOffPolicy{After: metav1.Duration{Duration: time.Minute}}
+	policy, err = idlePowerOffPolicy(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, host.Spec.IdlePowerOff, policy)
+}
+
+func TestRecordIdleState(t *testing.T) {
+	host := newIdleHost(2 * time.Hour)
+	host.Status.IdlePoweredOffSince = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+	info := makeReconcileInfo(host)
+	now := metav1.Now()
+
+	recordIdleState(info, host, metal3api.StateProvisioning, now)
+	assert.Nil(t, host.Status.IdleSince)
+	assert.Nil(t, host.Status.IdlePoweredOffSince)
+	assert.Len(t, info.postSaveCallbacks, 1)
+
+	recordIdleState(info, host, metal3api.StateAvailable, now)
+	assert.Equal(t, &now, host.Status.IdleSince)
+}
+
+func TestIdlePowerOffForgottenOnDelete(t *testing.T) {
+	host := newIdleHost(2 * time.Hour)
+	request := newRequest(host)
+	r := newTestReconciler()
+	countIdlePowerOff(request, time.Now().Add(-time.Minute), time.Now())
+
+	_, err := r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	_, counted := idlePowerOffCounted.Load(request.NamespacedName)
+	assert.False(t, counted)
+}
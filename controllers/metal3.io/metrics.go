@@ -15,6 +15,7 @@ const (
 	labelPrevState     = "prev_state"
 	labelNewState      = "new_state"
 	labelHostDataType  = "host_data_type"
+	labelBucket        = "bucket"
 )
 
 var reconcileCounters = prometheus.NewCounterVec(prometheus.CounterOpts{
@@ -71,6 +72,15 @@ var delayedDeprovisioningHostCounters = prometheus.NewCounterVec(prometheus.Coun
 	Name: "metal3_delayed__deprovisioning_total",
 	Help: "The number of times hosts have been delayed while deprovisioning due a busy provisioner",
 }, []string{labelHostNamespace, labelHostName})
+var delayedHostBucketCounters = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "metal3_delayed_bucket_total",
+	Help: "The number of times hosts have been delayed by each full provisioning concurrency bucket",
+}, []string{labelBucket})
+
+var idlePowerOffSeconds = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "metal3_idle_power_off_seconds_total",
+	Help: "Length of time available hosts have been kept powered off while idle",
+}, []string{labelHostNamespace, labelHostName})
 
 var slowOperationBuckets = []float64{30, 90, 180, 360, 720, 1440}
 
@@ -138,7 +148,9 @@ func init() {
 		actionFailureCounters,
 		powerChangeAttempts,
 		delayedProvisioningHostCounters,
-		delayedDeprovisioningHostCounters)
+		delayedDeprovisioningHostCounters,
+		delayedHostBucketCounters,
+		idlePowerOffSeconds)
 
 	for _, collector := range stateTime {
 		metrics.Registry.MustRegister(collector)
@@ -0,0 +1,198 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-logr/logr"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// NetworkTopologyReconciler summarizes the LLDP data of the hosts of a
+// namespace into NetworkTopologies.
+type NetworkTopologyReconciler struct {
+	client.Client
+	Log logr.Logger
+}
+
+// +kubebuilder:rbac:groups=metal3.io,resources=networktopologies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=metal3.io,resources=networktopologies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts,verbs=get;list;watch
+
+// Reconcile rebuilds the topology from the hardware details of the
+// selected hosts.
+func (r *NetworkTopologyReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("networktopology", request.NamespacedName)
+
+	topology := &metal3api.NetworkTopology{}
+	if err := r.Get(ctx, request.NamespacedName, topology); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, errors.Wrap(err, "could not load network topology")
+	}
+
+	selector := labels.Everything()
+	if topology.Spec.HostSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(topology.Spec.HostSelector)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "invalid host selector")
+		}
+	}
+
+	hostList := &metal3api.BareMetalHostList{}
+	err := r.List(ctx, hostList, client.InNamespace(topology.Namespace),
+		client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to list hosts")
+	}
+
+	status := buildNetworkTopology(hostList.Items)
+	if equality.Semantic.DeepEqual(status, topology.Status) {
+		return ctrl.Result{}, nil
+	}
+
+	reqLogger.Info("network topology changed", "switches", len(status.Switches), "hosts", status.Hosts)
+	topology.Status = status
+	if err := r.Status().Update(ctx, topology); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to update network topology status")
+	}
+	return ctrl.Result{}, nil
+}
+
+// buildNetworkTopology groups the NICs of the hosts by the switch and
+// port their LLDP data reports. The result does not depend on the order
+// of the hosts, so that it only changes when the cabling does.
+func buildNetworkTopology(hosts []metal3api.BareMetalHost) metal3api.NetworkTopologyStatus {
+	sorted := make([]*metal3api.BareMetalHost, len(hosts))
+	for i := range hosts {
+		sorted[i] = &hosts[i]
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	status := metal3api.NetworkTopologyStatus{Hosts: len(hosts)}
+	switches := map[string]*metal3api.NetworkSwitch{}
+	ports := map[string]map[string]*metal3api.SwitchPort{}
+
+	for _, host := range sorted {
+		if host.Status.HardwareDetails == nil {
+			continue
+		}
+
+		withoutLLDP := false
+		seen := map[string]bool{}
+		for _, nic := range host.Status.HardwareDetails.NIC {
+			// Dual-stack NICs are reported once per address
+			if seen[nic.Name] {
+				continue
+			}
+			seen[nic.Name] = true
+
+			if nic.LLDP == nil || nic.LLDP.SwitchID == "" || nic.LLDP.PortID == "" {
+				withoutLLDP = true
+				continue
+			}
+
+			sw, ok := switches[nic.LLDP.SwitchID]
+			if !ok {
+				sw = &metal3api.NetworkSwitch{SwitchID: nic.LLDP.SwitchID}
+				switches[sw.SwitchID] = sw
+				ports[sw.SwitchID] = map[string]*metal3api.SwitchPort{}
+			}
+			if sw.SystemName == "" {
+				sw.SystemName = nic.LLDP.SwitchSystemName
+			}
+			if len(sw.ManagementAddresses) == 0 {
+				sw.ManagementAddresses = nic.LLDP.SwitchManagementAddresses
+			}
+
+			port, ok := ports[sw.SwitchID][nic.LLDP.PortID]
+			if !ok {
+				port = &metal3api.SwitchPort{PortID: nic.LLDP.PortID}
+				ports[sw.SwitchID][port.PortID] = port
+			}
+			if port.Description == "" {
+				port.Description = nic.LLDP.PortDescription
+			}
+			port.Connections = append(port.Connections, metal3api.SwitchPortConnection{
+				HostName: host.Name,
+				NIC:      nic.Name,
+				MAC:      nic.MAC,
+			})
+		}
+		if withoutLLDP {
+			status.HostsWithoutLLDP++
+		}
+	}
+
+	for id, sw := range switches {
+		for _, port := range ports[id] {
+			sw.Ports = append(sw.Ports, *port)
+		}
+		sort.Slice(sw.Ports, func(i, j int) bool {
+			return sw.Ports[i].PortID < sw.Ports[j].PortID
+		})
+		status.Switches = append(status.Switches, *sw)
+	}
+	sort.Slice(status.Switches, func(i, j int) bool {
+		return status.Switches[i].SwitchID < status.Switches[j].SwitchID
+	})
+	return status
+}
+
+// topologiesForHost returns the topologies of the namespace of a host
+// that changed.
+func (r *NetworkTopologyReconciler) topologiesForHost(ctx context.Context, obj client.Object) []reconcile.Request {
+	topologies := &metal3api.NetworkTopologyList{}
+	if err := r.List(ctx, topologies, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "failed to list network topologies", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, topology := range topologies.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: topology.Namespace, Name: topology.Name},
+		})
+	}
+	return requests
+}
+
+// SetupWithManager registers the reconciler to be run by the manager.
+func (r *NetworkTopologyReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconcile int) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&metal3api.NetworkTopology{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconcile}).
+		Watches(&metal3api.BareMetalHost{}, handler.EnqueueRequestsFromMapFunc(r.topologiesForHost)).
+		Complete(r)
+}
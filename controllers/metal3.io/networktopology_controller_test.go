@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTopologyTestReconciler(initObjs ...runtime.Object) *NetworkTopologyReconciler {
+	clientBuilder := fakeclient.NewClientBuilder().WithRuntimeObjects(initObjs...)
+	for _, v := range initObjs {
+		clientBuilder = clientBuilder.WithStatusSubresource(v.(client.Object))
+	}
+
+	return &NetworkTopologyReconciler{
+		Client: clientBuilder.Build(),
+		Log:    ctrl.Log.WithName("controllers").WithName("NetworkTopology"),
+	}
+}
+
+func newCabledHost(name string, labels map[string]string, nics ...metal3api.NIC) *metal3api.BareMetalHost {
+	host := newHost(name, &metal3api.BareMetalHostSpec{})
+	host.Labels = labels
+	host.Status.HardwareDetails = &metal3api.HardwareDetails{NIC: nics}
+	return host
+}
+
+func cabledNIC(name, mac, switchID, portID string) metal3api.NIC {
+	return metal3api.NIC{
+		Name: name,
+		MAC:  mac,
+		LLDP: &metal3api.LLDP{
+			SwitchID:         switchID,
+			SwitchSystemName: "tor-" + switchID,
+			PortID:           portID,
+		},
+	}
+}
+
+func TestBuildNetworkTopology(t *testing.T) {
+	hosts := []metal3api.BareMetalHost{
+		*newCabledHost("host-b", nil,
+			cabledNIC("eth0", "00:00:00:00:00:b0", "sw1", "Ethernet2"),
+			metal3api.NIC{Name: "eth1", MAC: "00:00:00:00:00:b1"},
+		),
+		*newCabledHost("host-a", nil,
+			cabledNIC("eth0", "00:00:00:00:00:a0", "sw1", "Ethernet1"),
+			// Dual-stack NICs are reported twice
+			cabledNIC("eth0", "00:00:00:00:00:a0", "sw1", "Ethernet1"),
+			cabledNIC("eth1", "00:00:00:00:00:a1", "sw0", "Ethernet1"),
+		),
+		*newCabledHost("host-c", nil,
+			cabledNIC("eth0", "00:00:00:00:00:c0", "sw1", "Ethernet1"),
+		),
+		*newHost("host-new", &metal3api.BareMetalHostSpec{}),
+	}
+
+	status := buildNetworkTopology(hosts)
+
+	assert.Equal(t, 4, status.Hosts)
+	assert.Equal(t, 1, status.HostsWithoutLLDP)
+	assert.Equal(t, []metal3api.NetworkSwitch{
+		{
+			SwitchID:   "sw0",
+			SystemName: "tor-sw0",
+			Ports: []metal3api.SwitchPort{
+				{
+					PortID: "Ethernet1",
+					Connections: []metal3api.SwitchPortConnection{
+						{HostName: "host-a", NIC: "eth1", MAC: "00:00:00:00:00:a1"},
+					},
+				},
+			},
+		},
+		{
+			SwitchID:   "sw1",
+			SystemName: "tor-sw1",
+			Ports: []metal3api.SwitchPort{
+				{
+					PortID: "Ethernet1",
+					Connections: []metal3api.SwitchPortConnection{
+						{HostName: "host-a", NIC: "eth0", MAC: "00:00:00:00:00:a0"},
+						{HostName: "host-c", NIC: "eth0", MAC: "00:00:00:00:00:c0"},
+					},
+				},
+				{
+					PortID: "Ethernet2",
+					Connections: []metal3api.SwitchPortConnection{
+						{HostName: "host-b", NIC: "eth0", MAC: "00:00:00:00:00:b0"},
+					},
+				},
+			},
+		},
+	}, status.Switches)
+}
+
+func TestNetworkTopologyReconcile(t *testing.T) {
+	rack1 := map[string]string{"rack": "r1"}
+	topology := &metal3api.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rack1",
+			Namespace: namespace,
+		},
+		Spec: metal3api.NetworkTopologySpec{
+			HostSelector: &metav1.LabelSelector{MatchLabels: rack1},
+		},
+	}
+	r := newTopologyTestReconciler(
+		topology,
+		newCabledHost("host-a", rack1, cabledNIC("eth0", "00:00:00:00:00:a0", "sw1", "Ethernet1")),
+		newCabledHost("host-b", map[string]string{"rack": "r2"}, cabledNIC("eth0", "00:00:00:00:00:b0", "sw2", "Ethernet1")),
+	)
+
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: topology.Name}}
+	if _, err := r.Reconcile(context.TODO(), request); err != nil {
+		t.Fatal(err)
+	}
+
+	updated := &metal3api.NetworkTopology{}
+	if err := r.Get(context.TODO(), request.NamespacedName, updated); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, updated.Status.Hosts)
+	if assert.Len(t, updated.Status.Switches, 1) {
+		assert.Equal(t, "sw1", updated.Status.Switches[0].SwitchID)
+	}
+
+	assert.Equal(t, []ctrl.Request{request}, r.topologiesForHost(context.TODO(), newHost("host-c", &metal3api.BareMetalHostSpec{})))
+}
@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// activePowerOperation returns the power operation to run on the host:
+// the running one, or else the oldest one that has not finished. It also
+// returns the earliest deadline of the operations that have not finished.
+// Operations past their deadline are failed, and new operations are
+// acknowledged by marking them pending.
+func (r *BareMetalHostReconciler) activePowerOperation(info *reconcileInfo) (*metal3api.PowerOperation, *metav1.Time, error) {
+	powerOps := &metal3api.PowerOperationList{}
+	if err := r.List(info.ctx, powerOps, client.InNamespace(info.host.Namespace)); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to list power operations")
+	}
+
+	pending := []*metal3api.PowerOperation{}
+	for i := range powerOps.Items {
+		powerOp := &powerOps.Items[i]
+		if powerOp.Spec.HostName != info.host.Name || powerOp.Status.Finished() || !powerOp.DeletionTimestamp.IsZero() {
+			continue
+		}
+		pending = append(pending, powerOp)
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		if !pending[i].CreationTimestamp.Equal(&pending[j].CreationTimestamp) {
+			return pending[i].CreationTimestamp.Before(&pending[j].CreationTimestamp)
+		}
+		return pending[i].Name < pending[j].Name
+	})
+
+	var active *metal3api.PowerOperation
+	var deadline *metav1.Time
+	now := metav1.Now()
+	for _, powerOp := range pending {
+		if powerOp.Spec.Deadline != nil {
+			if powerOp.Spec.Deadline.Before(&now) {
+				if err := r.finishPowerOperation(info, powerOp, metal3api.PowerOperationFailed, "deadline exceeded"); err != nil {
+					return nil, nil, err
+				}
+				continue
+			}
+			if deadline == nil || powerOp.Spec.Deadline.Before(deadline) {
+				deadline = powerOp.Spec.Deadline
+			}
+		}
+
+		if powerOp.Status.Phase == "" {
+			powerOp.Status.Phase = metal3api.PowerOperationPending
+			if err := r.Status().Update(info.ctx, powerOp); err != nil {
+				return nil, nil, errors.Wrap(err, "failed to acknowledge power operation")
+			}
+		}
+
+		if active == nil || powerOp.Status.Phase == metal3api.PowerOperationRunning {
+			active = powerOp
+		}
+	}
+	return active, deadline, nil
+}
+
+// handlePowerOperation starts the power operation of the host, and
+// finishes it once the host reaches the requested power state. It
+// returns the operation while it is running, the earliest deadline of
+// the operations that have not finished, by which the host must be
+// reconciled again, and an action result when the host must be
+// reconciled again before its power is managed.
+func (r *BareMetalHostReconciler) handlePowerOperation(info *reconcileInfo, isProvisioned bool) (*metal3api.PowerOperation, *metav1.Time, actionResult) {
+	powerOp, deadline, err := r.activePowerOperation(info)
+	if err != nil {
+		return nil, nil, actionError{err}
+	}
+	if powerOp == nil {
+		return nil, nil, nil
+	}
+	result, action := r.runPowerOperation(info, powerOp, isProvisioned)
+	return result, deadline, action
+}
+
+// runPowerOperation advances the active power operation of the host.
+func (r *BareMetalHostReconciler) runPowerOperation(info *reconcileInfo, powerOp *metal3api.PowerOperation, isProvisioned bool) (*metal3api.PowerOperation, actionResult) {
+	host := info.host
+	if powerOp.Status.Phase != metal3api.PowerOperationRunning {
+		if powerOp.Spec.Action == metal3api.PowerActionReboot && !isProvisioned && !powerOp.Spec.Force {
+			return nil, nil
+		}
+
+		info.log.Info("starting power operation", "operation", powerOp.Name, "action", powerOp.Spec.Action)
+		now := metav1.Now()
+		powerOp.Status.Phase = metal3api.PowerOperationRunning
+		powerOp.Status.StartTime = &now
+		if err := r.Status().Update(info.ctx, powerOp); err != nil {
+			return nil, actionError{errors.Wrap(err, "failed to start power operation")}
+		}
+		info.publishEvent("PowerOperationStarted", fmt.Sprintf("Power operation %s started: %s", powerOp.Name, powerOp.Spec.Action))
+
+		online := powerOp.Spec.Action == metal3api.PowerActionOn
+		if powerOp.Spec.Action != metal3api.PowerActionReboot && host.Spec.Online != online {
+			host.Spec.Online = online
+			if err := r.Update(info.ctx, host); err != nil {
+				return nil, actionError{errors.Wrap(err, "failed to update the online field of host")}
+			}
+		}
+		return nil, actionContinue{}
+	}
+
+	switch powerOp.Spec.Action {
+	case metal3api.PowerActionReboot:
+		if !powerOp.Status.PoweredOff {
+			if host.Status.PoweredOn {
+				return powerOp, nil
+			}
+			powerOp.Status.PoweredOff = true
+			if err := r.Status().Update(info.ctx, powerOp); err != nil {
+				return nil, actionError{errors.Wrap(err, "failed to save power operation status")}
+			}
+		}
+		if host.Status.PoweredOn != host.Spec.Online {
+			return powerOp, nil
+		}
+	default:
+		online := powerOp.Spec.Action == metal3api.PowerActionOn
+		if host.Spec.Online != online {
+			if err := r.finishPowerOperation(info, powerOp, metal3api.PowerOperationFailed, "the online field of the host was changed"); err != nil {
+				return nil, actionError{err}
+			}
+			return nil, nil
+		}
+		if host.Status.PoweredOn != online {
+			return powerOp, nil
+		}
+	}
+
+	if err := r.finishPowerOperation(info, powerOp, metal3api.PowerOperationSucceeded, ""); err != nil {
+		return nil, actionError{err}
+	}
+	return nil, nil
+}
+
+// finishPowerOperation records the result of the power operation.
+func (r *BareMetalHostReconciler) finishPowerOperation(info *reconcileInfo, powerOp *metal3api.PowerOperation, phase metal3api.PowerOperationPhase, message string) error {
+	info.log.Info("power operation finished", "operation", powerOp.Name, "phase", phase, "error", message)
+	now := metav1.Now()
+	powerOp.Status.Phase = phase
+	powerOp.Status.CompletionTime = &now
+	powerOp.Status.Error = message
+	if err := r.Status().Update(info.ctx, powerOp); err != nil {
+		return errors.Wrap(err, "failed to save power operation status")
+	}
+
+	if phase == metal3api.PowerOperationFailed {
+		info.publishEvent("PowerOperationFailed", fmt.Sprintf("Power operation %s failed: %s", powerOp.Name, message))
+	} else {
+		info.publishEvent("PowerOperationSucceeded", fmt.Sprintf("Power operation %s succeeded", powerOp.Name))
+	}
+	return nil
+}
+
+// hostForPowerOperation returns the host to reconcile when a
+// PowerOperation changes.
+func (r *BareMetalHostReconciler) hostForPowerOperation(_ context.Context, obj client.Object) []reconcile.Request {
+	powerOp, ok := obj.(*metal3api.PowerOperation)
+	if !ok || powerOp.Status.Finished() {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{Namespace: powerOp.Namespace, Name: powerOp.Spec.HostName},
+	}}
+}
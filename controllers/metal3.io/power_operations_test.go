@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newPowerOperation(name string, action metal3api.PowerAction) *metal3api.PowerOperation {
+	return &metal3api.PowerOperation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: metal3api.PowerOperationSpec{
+			HostName: "host",
+			Action:   action,
+		},
+	}
+}
+
+func newPowerOperationHost(state metal3api.ProvisioningState) *metal3api.BareMetalHost {
+	host := newHost("host", &metal3api.BareMetalHostSpec{Online: true})
+	host.Status.Provisioning.State = state
+	host.Status.PoweredOn = true
+	return host
+}
+
+func newPowerMockProvisioner() *mockProvisioner {
+	return &mockProvisioner{
+		nextResults: map[string]provisioner.Result{
+			"PowerOn":  {Dirty: true},
+			"PowerOff": {Dirty: true},
+		},
+		callsNoError: map[string]bool{},
+	}
+}
+
+func getPowerOperation(t *testing.T, r *BareMetalHostReconciler, name string) *metal3api.PowerOperation {
+	t.Helper()
+	powerOp := &metal3api.PowerOperation{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, powerOp); err != nil {
+		t.Fatal(err)
+	}
+	return powerOp
+}
+
+func manageHostPower(t *testing.T, r *BareMetalHostReconciler, prov provisioner.Provisioner, host *metal3api.BareMetalHost) {
+	t.Helper()
+	if _, err := r.manageHostPower(prov, makeReconcileInfo(host)).Result(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPowerOperationReboot(t *testing.T) {
+	host := newPowerOperationHost(metal3api.StateProvisioned)
+	r := newTestReconciler(host, newPowerOperation("reboot", metal3api.PowerActionReboot))
+	prov := newPowerMockProvisioner()
+
+	manageHostPower(t, r, prov, host)
+	powerOp := getPowerOperation(t, r, "reboot")
+	assert.Equal(t, metal3api.PowerOperationRunning, powerOp.Status.Phase)
+	assert.NotNil(t, powerOp.Status.StartTime)
+
+	manageHostPower(t, r, prov, host)
+	assert.False(t, getPowerOperation(t, r, "reboot").Status.PoweredOff)
+
+	host.Status.PoweredOn = false
+	manageHostPower(t, r, prov, host)
+	powerOp = getPowerOperation(t, r, "reboot")
+	assert.True(t, powerOp.Status.PoweredOff)
+	assert.Equal(t, metal3api.PowerOperationRunning, powerOp.Status.Phase)
+
+	host.Status.PoweredOn = true
+	manageHostPower(t, r, prov, host)
+	powerOp = getPowerOperation(t, r, "reboot")
+	assert.Equal(t, metal3api.PowerOperationSucceeded, powerOp.Status.Phase)
+	assert.NotNil(t, powerOp.Status.CompletionTime)
+	assert.Empty(t, powerOp.Status.Error)
+}
+
+func TestPowerOperationPowerOff(t *testing.T) {
+	host := newPowerOperationHost(metal3api.StateAvailable)
+	r := newTestReconciler(host, newPowerOperation("off", metal3api.PowerActionOff))
+	prov := newPowerMockProvisioner()
+
+	manageHostPower(t, r, prov, host)
+	assert.False(t, host.Spec.Online)
+	assert.Equal(t, metal3api.PowerOperationRunning, getPowerOperation(t, r, "off").Status.Phase)
+
+	manageHostPower(t, r, prov, host)
+	assert.Equal(t, metal3api.PowerOperationRunning, getPowerOperation(t, r, "off").Status.Phase)
+
+	host.Status.PoweredOn = false
+	manageHostPower(t, r, prov, host)
+	assert.Equal(t, metal3api.PowerOperationSucceeded, getPowerOperation(t, r, "off").Status.Phase)
+}
+
+func TestPowerOperationFailed(t *testing.T) {
+	host := newPowerOperationHost(metal3api.StateProvisioned)
+	host.Spec.Online = false
+	host.Status.PoweredOn = false
+	r := newTestReconciler(host, newPowerOperation("on", metal3api.PowerActionOn))
+	prov := newPowerMockProvisioner()
+	prov.nextResults["PowerOn"] = provisioner.Result{ErrorMessage: "BMC unreachable"}
+
+	manageHostPower(t, r, prov, host)
+	assert.True(t, host.Spec.Online)
+
+	manageHostPower(t, r, prov, host)
+	powerOp := getPowerOperation(t, r, "on")
+	assert.Equal(t, metal3api.PowerOperationFailed, powerOp.Status.Phase)
+	assert.Equal(t, "BMC unreachable", powerOp.Status.Error)
+}
+
+func TestPowerOperationOrder(t *testing.T) {
+	host := newPowerOperationHost(metal3api.StateAvailable)
+	expired := newPowerOperation("expired", metal3api.PowerActionOff)
+	expired.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	expired.Spec.Deadline = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+	reboot := newPowerOperation("reboot", metal3api.PowerActionReboot)
+	reboot.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Minute))
+	later := newPowerOperation("later", metal3api.PowerActionOff)
+	later.CreationTimestamp = metav1.NewTime(time.Now())
+	other := newPowerOperation("other", metal3api.PowerActionOff)
+	other.Spec.HostName = "other"
+	r := newTestReconciler(host, expired, reboot, later, other)
+
+	manageHostPower(t, r, newPowerMockProvisioner(), host)
+
+	powerOp := getPowerOperation(t, r, "expired")
+	assert.Equal(t, metal3api.PowerOperationFailed, powerOp.Status.Phase)
+	assert.Equal(t, "deadline exceeded", powerOp.Status.Error)
+	// The reboot of a host that is not provisioned waits for a force
+	assert.Equal(t, metal3api.PowerOperationPending, getPowerOperation(t, r, "reboot").Status.Phase)
+	assert.Equal(t, metal3api.PowerOperationPending, getPowerOperation(t, r, "later").Status.Phase)
+	assert.Empty(t, getPowerOperation(t, r, "other").Status.Phase)
+	assert.True(t, host.Spec.Online)
+
+	reboot = getPowerOperation(t, r, "reboot")
+	reboot.Spec.Force = true
+	if err := r.Update(context.TODO(), reboot); err != nil {
+		t.Fatal(err)
+	}
+	manageHostPower(t, r, newPowerMockProvisioner(), host)
+	assert.Equal(t, metal3api.PowerOperationRunning, getPowerOperation(t, r, "reboot").Status.Phase)
+	assert.Equal(t, metal3api.PowerOperationPending, getPowerOperation(t, r, "later").Status.Phase)
+}
+
+func TestPowerOperationDeadlineRequeue(t *testing.T) {
+	host := newPowerOperationHost(metal3api.StateAvailable)
+	reboot := newPowerOperation("reboot", metal3api.PowerActionReboot)
+	reboot.Spec.Deadline = &metav1.Time{Time: time.Now().Add(5 * time.Minute)}
+	r := newTestReconciler(host, reboot)
+	r.powerPoller = newPowerStatePoller(r.Client, fakePowerStatePoller{}, r.Log)
+
+	// The reboot cannot start before it is forced, and the power state
+	// is polled, but the host is reconciled again by the deadline.
+	result, err := r.manageHostPower(newPowerMockProvisioner(), makeReconcileInfo(host)).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, metal3api.PowerOperationPending, getPowerOperation(t, r, "reboot").Status.Phase)
+	assert.Positive(t, result.RequeueAfter)
+	assert.LessOrEqual(t, result.RequeueAfter, 5*time.Minute)
+}
+
+func TestHostForPowerOperation(t *testing.T) {
+	r := newTestReconciler()
+	powerOp := newPowerOperation("reboot", metal3api.PowerActionReboot)
+
+	requests := r.hostForPowerOperation(context.TODO(), powerOp)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, types.NamespacedName{Namespace: namespace, Name: "host"}, requests[0].NamespacedName)
+
+	powerOp.Status.Phase = metal3api.PowerOperationSucceeded
+	assert.Empty(t, r.hostForPowerOperation(context.TODO(), powerOp))
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+const (
+	// powerStatePollInterval is how often the power state of all hosts
+	// is read from the provisioner.
+	powerStatePollInterval = time.Second * 60
+	// powerStatePollTimeout bounds a single read of all power states.
+	powerStatePollTimeout = time.Second * 30
+)
+
+// powerStatePoller periodically reads the power state of all hosts from
+// the provisioner at once, and triggers a reconcile of the hosts whose
+// recorded power state differs. Hosts it covers do not have to requeue
+// themselves to monitor their power state.
+type powerStatePoller struct {
+	client   client.Reader
+	poller   provisioner.PowerStatePoller
+	interval time.Duration
+	log      logr.Logger
+	events   chan event.GenericEvent
+}
+
+func newPowerStatePoller(c client.Reader, poller provisioner.PowerStatePoller, log logr.Logger) *powerStatePoller {
+	return &powerStatePoller{
+		client:   c,
+		poller:   poller,
+		interval: powerStatePollInterval,
+		log:      log,
+		events:   make(chan event.GenericEvent),
+	}
+}
+
+// Start polls the provisioner until the context is cancelled. It
+// implements manager.Runnable.
+func (p *powerStatePoller) Start(ctx context.Context) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			p.poll(ctx)
+		}
+	}
+}
+
+// polls returns whether the power state of the host is monitored by
+// the poller.
+func (p *powerStatePoller) polls(host *metal3api.BareMetalHost) bool {
+	return p != nil && p.poller.PollsPowerState(provisioner.BuildHostDataNoBMC(*host))
+}
+
+func (p *powerStatePoller) poll(ctx context.Context) {
+	pollCtx, cancel := context.WithTimeout(ctx, powerStatePollTimeout)
+	defer cancel()
+
+	states, pollErr := p.poller.PowerStates(pollCtx)
+	if pollErr != nil {
+		// The hosts missing from an incomplete result are left alone
+		// until the next poll.
+		p.log.Info("failed to read the power state of some hosts", "error", pollErr.Error())
+	}
+
+	hosts := &metal3api.BareMetalHostList{}
+	if err := p.client.List(ctx, hosts); err != nil {
+		p.log.Error(err, "failed to list hosts")
+		return
+	}
+
+	for i := range hosts.Items {
+		host := &hosts.Items[i]
+		if !p.polls(host) || !powerStateMonitored(host) {
+			continue
+		}
+
+		poweredOn, known := states[host.Status.Provisioning.ID]
+		if known && poweredOn == host.Status.PoweredOn {
+			continue
+		}
+		if !known && pollErr != nil {
+			continue
+		}
+
+		p.log.V(1).Info("power state changed", "host", host.Namespace+"/"+host.Name,
+			"known", known, "poweredOn", poweredOn)
+		select {
+		case p.events <- event.GenericEvent{Object: host}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// powerStateMonitored returns whether the host is in one of the states
+// in which the reconciler only watches its power state.
+func powerStateMonitored(host *metal3api.BareMetalHost) bool {
+	if host.Status.Provisioning.ID == "" || hasDetachedAnnotation(host) {
+		return false
+	}
+	if _, paused := host.Annotations[metal3api.PausedAnnotation]; paused {
+		return false
+	}
+
+	switch host.Status.Provisioning.State {
+	case metal3api.StateAvailable, metal3api.StateProvisioned, metal3api.StateExternallyProvisioned:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+type fakePowerStatePoller struct {
+	states map[string]bool
+	err    error
+}
+
+func (f fakePowerStatePoller) PowerStates(_ context.Context) (map[string]bool, error) {
+	return f.states, f.err
+}
+
+func (f fakePowerStatePoller) PollsPowerState(hostData provisioner.HostData) bool {
+	return hostData.Backend != "unpolled"
+}
+
+func newPolledHost(name, id string, state metal3api.ProvisioningState, poweredOn bool) *metal3api.BareMetalHost {
+	host := newHost(name, &metal3api.BareMetalHostSpec{Online: poweredOn})
+	host.Status.Provisioning.ID = id
+	host.Status.Provisioning.State = state
+	host.Status.PoweredOn = poweredOn
+	return host
+}
+
+func TestPowerStatePoller(t *testing.T) {
+	unchanged := newPolledHost("unchanged", "id-unchanged", metal3api.StateProvisioned, true)
+	changed := newPolledHost("changed", "id-changed", metal3api.StateProvisioned, true)
+	available := newPolledHost("available", "id-available", metal3api.StateAvailable, false)
+	unknown := newPolledHost("unknown", "id-unknown", metal3api.StateExternallyProvisioned, false)
+	busy := newPolledHost("busy", "id-busy", metal3api.StateProvisioning, false)
+	detached := newPolledHost("detached", "id-detached", metal3api.StateProvisioned, false)
+	detached.Annotations = map[string]string{metal3api.DetachedAnnotation: ""}
+	unpolled := newPolledHost("unpolled", "id-unpolled", metal3api.StateProvisioned, false)
+	unpolled.Spec.ProvisioningBackend = "unpolled"
+
+	states := map[string]bool{
+		"id-unchanged": true,
+		"id-changed":   false,
+		"id-available": true,
+		"id-busy":      true,
+		"id-detached":  true,
+		"id-unpolled":  true,
+	}
+
+	cases := []struct {
+		name     string
+		err      error
+		expected []string
+	}{
+		{
+			name:     "complete",
+			expected: []string{"changed", "available", "unknown"},
+		},
+		{
+			name:     "incomplete",
+			err:      errors.New("endpoint down"),
+			expected: []string{"changed", "available"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestReconciler(unchanged, changed, available, unknown, busy, detached, unpolled)
+			poller := newPowerStatePoller(r.Client, fakePowerStatePoller{states: states, err: tc.err}, r.Log)
+			poller.events = make(chan event.GenericEvent, 10)
+
+			poller.poll(context.TODO())
+			close(poller.events)
+
+			enqueued := []string{}
+			for e := range poller.events {
+				enqueued = append(enqueued, e.Object.GetName())
+			}
+			assert.ElementsMatch(t, tc.expected, enqueued)
+		})
+	}
+}
+
+func TestManageHostPowerPolled(t *testing.T) {
+	host := newPolledHost("host", "id", metal3api.StateProvisioned, true)
+	r := newTestReconciler(host)
+	info := makeReconcileInfo(host)
+
+	result, err := r.manageHostPower(&mockProvisioner{}, info).Result()
+	assert.NoError(t, err)
+	assert.True(t, result.Requeue, "hosts not covered by the poller monitor their own power state")
+
+	r.powerPoller = newPowerStatePoller(r.Client, fakePowerStatePoller{}, r.Log)
+	result, err = r.manageHostPower(&mockProvisioner{}, info).Result()
+	assert.NoError(t, err)
+	assert.False(t, result.Requeue)
+	assert.Zero(t, result.RequeueAfter)
+}
@@ -0,0 +1,271 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/hardwareutils/bmc"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// provisionerBucket is the name reported for the global capacity of the
+// provisioner when it delays a host.
+const provisionerBucket = "provisioner"
+
+// holdsProvisioningSlot returns whether the host is being inspected,
+// provisioned or deprovisioned, and so counts against the capacity of
+// the buckets it matches.
+func holdsProvisioningSlot(host *metal3api.BareMetalHost) bool {
+	if host.Status.OperationalStatus == metal3api.OperationalStatusDelayed {
+		return false
+	}
+	switch host.Status.Provisioning.State {
+	case metal3api.StateInspecting, metal3api.StateProvisioning,
+		metal3api.StateDeprovisioning:
+		return true
+	default:
+		return false
+	}
+}
+
+// isQueued returns whether the host is waiting for provisioning
+// capacity. Paused hosts and hosts being deleted are not reconciled, and
+// would keep their place at the head of the queue forever, so they are
+// left out of it.
+func isQueued(host *metal3api.BareMetalHost) bool {
+	if _, paused := host.Annotations[metal3api.PausedAnnotation]; paused {
+		return false
+	}
+	return host.Status.OperationalStatus == metal3api.OperationalStatusDelayed &&
+		host.Status.ProvisioningQueue != nil &&
+		host.DeletionTimestamp.IsZero()
+}
+
+// usesProvisionerCapacity returns whether the host counts against the
+// global capacity of the provisioner, which exempts hosts using virtual
+// media.
+func usesProvisionerCapacity(host *metal3api.BareMetalHost) bool {
+	accessDetails, err := bmc.NewAccessDetails(host.Spec.BMC.Address, host.Spec.BMC.DisableCertificateVerification)
+	if err != nil {
+		return true
+	}
+	return !accessDetails.SupportsISOPreprovisioningImage()
+}
+
+// queuedHost is an entry of the queue of hosts waiting for provisioning
+// capacity.
+type queuedHost struct {
+	host  *metal3api.BareMetalHost
+	since metav1.Time
+	round int
+}
+
+// provisioningCapacity holds the hosts and the
+// ProvisioningConcurrencyPolicies deciding whether a host may be
+// inspected, provisioned or deprovisioned.
+type provisioningCapacity struct {
+	host     *metal3api.BareMetalHost
+	hosts    []metal3api.BareMetalHost
+	policies []metal3api.ProvisioningConcurrencyPolicy
+}
+
+func loadProvisioningCapacity(ctx context.Context, c client.Reader, host *metal3api.BareMetalHost) (*provisioningCapacity, error) {
+	policies := &metal3api.ProvisioningConcurrencyPolicyList{}
+	if err := c.List(ctx, policies); err != nil {
+		return nil, errors.Wrap(err, "failed to list provisioning concurrency policies")
+	}
+
+	hosts := &metal3api.BareMetalHostList{}
+	if err := c.List(ctx, hosts); err != nil {
+		return nil, errors.Wrap(err, "failed to list hosts")
+	}
+
+	return &provisioningCapacity{
+		host:     host,
+		hosts:    hosts.Items,
+		policies: policies.Items,
+	}, nil
+}
+
+func (pc *provisioningCapacity) isSelf(other *metal3api.BareMetalHost) bool {
+	return other.Namespace == pc.host.Namespace && other.Name == pc.host.Name
+}
+
+// findBucket returns the bucket of the policies with the given name, as
+// reported when it delays a host.
+func (pc *provisioningCapacity) findBucket(name string) *metal3api.ProvisioningConcurrencyBucket {
+	for i := range pc.policies {
+		policy := &pc.policies[i]
+		for j := range policy.Spec.Buckets {
+			if policy.Name+"/"+policy.Spec.Buckets[j].Name == name {
+				return &policy.Spec.Buckets[j]
+			}
+		}
+	}
+	return nil
+}
+
+// matchesBucket returns whether the host itself matches the bucket. A
+// host whose BMC address cannot be parsed is not matched, as for the
+// other hosts, since it cannot be registered until its address is fixed.
+func (pc *provisioningCapacity) matchesBucket(bucket *metal3api.ProvisioningConcurrencyBucket) (bool, error) {
+	matches, err := bucket.Matches(pc.host)
+	if err != nil {
+		if _, bmcErr := bmc.NewAccessDetails(pc.host.Spec.BMC.Address, pc.host.Spec.BMC.DisableCertificateVerification); bmcErr != nil {
+			return false, nil
+		}
+		return false, err
+	}
+	return matches, nil
+}
+
+// fullBucket returns the name of a bucket that the host matches and that
+// has no capacity left, or an empty string if there is none. A host that
+// already holds a slot keeps it. The hosts are counted from the cache, so
+// hosts reconciled at the same time may exceed the limit briefly.
+func (pc *provisioningCapacity) fullBucket() (string, error) {
+	if holdsProvisioningSlot(pc.host) {
+		return "", nil
+	}
+
+	for i := range pc.policies {
+		policy := &pc.policies[i]
+		for j := range policy.Spec.Buckets {
+			bucket := &policy.Spec.Buckets[j]
+			name := policy.Name + "/" + bucket.Name
+
+			matches, err := pc.matchesBucket(bucket)
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to match host to bucket %s", name)
+			}
+			if !matches {
+				continue
+			}
+
+			busy := 0
+			for k := range pc.hosts {
+				other := &pc.hosts[k]
+				if pc.isSelf(other) || !holdsProvisioningSlot(other) {
+					continue
+				}
+				// Hosts whose BMC address cannot be parsed are not
+				// being provisioned, and are not counted.
+				if matches, err := bucket.Matches(other); err == nil && matches {
+					busy++
+				}
+			}
+			if busy >= bucket.Limit {
+				return name, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// queue returns the hosts waiting for provisioning capacity, and the
+// host itself, in the order they are given capacity: by priority, then
+// taking turns between namespaces, then by how long they have waited.
+func (pc *provisioningCapacity) queue(now metav1.Time) []queuedHost {
+	queue := []queuedHost{}
+	for i := range pc.hosts {
+		other := &pc.hosts[i]
+		if !pc.isSelf(other) && isQueued(other) {
+			queue = append(queue, queuedHost{host: other, since: other.Status.ProvisioningQueue.Since})
+		}
+	}
+	self := queuedHost{host: pc.host, since: now}
+	if pc.host.Status.ProvisioningQueue != nil {
+		self.since = pc.host.Status.ProvisioningQueue.Since
+	}
+	queue = append(queue, self)
+
+	sort.SliceStable(queue, func(i, j int) bool {
+		return queuedBefore(&queue[i], &queue[j])
+	})
+
+	type turn struct {
+		namespace string
+		priority  int
+	}
+	turns := map[turn]int{}
+	for i := range queue {
+		entry := &queue[i]
+		key := turn{entry.host.Namespace, entry.host.Spec.ProvisioningPriority}
+		entry.round = turns[key]
+		turns[key]++
+	}
+
+	sort.SliceStable(queue, func(i, j int) bool {
+		a, b := &queue[i], &queue[j]
+		if a.host.Spec.ProvisioningPriority != b.host.Spec.ProvisioningPriority {
+			return a.host.Spec.ProvisioningPriority > b.host.Spec.ProvisioningPriority
+		}
+		if a.round != b.round {
+			return a.round < b.round
+		}
+		return queuedBefore(a, b)
+	})
+	return queue
+}
+
+// queuedBefore orders hosts of the same priority and round by how long
+// they have waited.
+func queuedBefore(a, b *queuedHost) bool {
+	if a.host.Spec.ProvisioningPriority != b.host.Spec.ProvisioningPriority {
+		return a.host.Spec.ProvisioningPriority > b.host.Spec.ProvisioningPriority
+	}
+	if !a.since.Equal(&b.since) {
+		return a.since.Before(&b.since)
+	}
+	if a.host.Namespace != b.host.Namespace {
+		return a.host.Namespace < b.host.Namespace
+	}
+	return a.host.Name < b.host.Name
+}
+
+// waitingAhead returns the bucket a host ahead in the queue is waiting
+// for capacity in, if the host is limited by it too, or an empty string
+// if the host may take the capacity.
+func (pc *provisioningCapacity) waitingAhead(queue []queuedHost) string {
+	if holdsProvisioningSlot(pc.host) {
+		return ""
+	}
+
+	for _, entry := range queue {
+		if pc.isSelf(entry.host) {
+			break
+		}
+		bucket := entry.host.Status.ProvisioningQueue.Bucket
+		if bucket == provisionerBucket {
+			if usesProvisionerCapacity(pc.host) {
+				return bucket
+			}
+			continue
+		}
+		if policyBucket := pc.findBucket(bucket); policyBucket != nil {
+			if matches, err := pc.matchesBucket(policyBucket); err != nil || matches {
+				return bucket
+			}
+		}
+	}
+	return ""
+}
+
+// updateProvisioningQueue records the place of a delayed host in the
+// queue.
+func updateProvisioningQueue(host *metal3api.BareMetalHost, queue []queuedHost, bucket string, now metav1.Time) {
+	status := &metal3api.ProvisioningQueueStatus{Since: now, Bucket: bucket}
+	if host.Status.ProvisioningQueue != nil {
+		status.Since = host.Status.ProvisioningQueue.Since
+	}
+	for i, entry := range queue {
+		if entry.host == host {
+			status.Position = i + 1
+			break
+		}
+	}
+	host.Status.ProvisioningQueue = status
+}
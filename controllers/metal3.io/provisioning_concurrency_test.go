@@ -0,0 +1,358 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newConcurrencyHost(name, address string, state metal3api.ProvisioningState) *metal3api.BareMetalHost {
+	host := newHost(name, &metal3api.BareMetalHostSpec{
+		BMC: metal3api.BMCDetails{Address: address},
+	})
+	host.Labels = map[string]string{"segment": "rack-1"}
+	host.Status.Provisioning.State = state
+	return host
+}
+
+func newConcurrencyPolicy(buckets ...metal3api.ProvisioningConcurrencyBucket) *metal3api.ProvisioningConcurrencyPolicy {
+	return &metal3api.ProvisioningConcurrencyPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec:       metal3api.ProvisioningConcurrencyPolicySpec{Buckets: buckets},
+	}
+}
+
+func TestFullConcurrencyBucket(t *testing.T) {
+	rack := metal3api.ProvisioningConcurrencyBucket{
+		Name:         "rack-1",
+		Limit:        2,
+		HostSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"segment": "rack-1"}},
+	}
+	imageServer := metal3api.ProvisioningConcurrencyBucket{
+		Name:                "image-server",
+		Limit:               1,
+		BMCDrivers:          []string{"redfish"},
+		IncludeVirtualMedia: true,
+	}
+
+	delayed := newConcurrencyHost("delayed", "ipmi://192.168.122.3", metal3api.StateProvisioning)
+	delayed.Status.OperationalStatus = metal3api.OperationalStatusDelayed
+
+	for _, tc := range []struct {
+		Scenario string
+		Policy   *metal3api.ProvisioningConcurrencyPolicy
+		Host     *metal3api.BareMetalHost
+		Others   []*metal3api.BareMetalHost
+		Expected string
+	}{
+		{
+			Scenario: "no policy",
+			Host:     newConcurrencyHost("host", "ipmi://192.168.122.1", metal3api.StateAvailable),
+			Others: []*metal3api.BareMetalHost{
+				newConcurrencyHost("busy-1", "ipmi://192.168.122.2", metal3api.StateProvisioning),
+			},
+		},
+		{
+			Scenario: "bucket with capacity",
+			Policy:   newConcurrencyPolicy(rack),
+			Host:     newConcurrencyHost("host", "ipmi://192.168.122.1", metal3api.StateAvailable),
+			Others: []*metal3api.BareMetalHost{
+				newConcurrencyHost("busy-1", "ipmi://192.168.122.2", metal3api.StateProvisioning),
+				newConcurrencyHost("idle", "ipmi://192.168.122.3", metal3api.StateAvailable),
+				delayed,
+			},
+		},
+		{
+			Scenario: "bucket full",
+			Policy:   newConcurrencyPolicy(rack),
+			Host:     newConcurrencyHost("host", "ipmi://192.168.122.1", metal3api.StateAvailable),
+			Others: []*metal3api.BareMetalHost{
+				newConcurrencyHost("busy-1", "ipmi://192.168.122.2", metal3api.StateProvisioning),
+				newConcurrencyHost("busy-2", "ipmi://192.168.122.3", metal3api.StateDeprovisioning),
+			},
+			Expected: "policy/rack-1",
+		},
+		{
+			Scenario: "host holding a slot",
+			Policy:   newConcurrencyPolicy(rack),
+			Host:     newConcurrencyHost("host", "ipmi://192.168.122.1", metal3api.StateProvisioning),
+			Others: []*metal3api.BareMetalHost{
+				newConcurrencyHost("busy-1", "ipmi://192.168.122.2", metal3api.StateProvisioning),
+				newConcurrencyHost("busy-2", "ipmi://192.168.122.3", metal3api.StateDeprovisioning),
+			},
+		},
+		{
+			Scenario: "virtual media not counted",
+			Policy:   newConcurrencyPolicy(rack),
+			Host:     newConcurrencyHost("host", "ipmi://192.168.122.1", metal3api.StateAvailable),
+			Others: []*metal3api.BareMetalHost{
+				newConcurrencyHost("busy-1", "ipmi://192.168.122.2", metal3api.StateProvisioning),
+				newConcurrencyHost("busy-2", "redfish-virtualmedia://192.168.122.3/redfish/v1/Systems/1", metal3api.StateProvisioning),
+			},
+		},
+		{
+			Scenario: "virtual media included",
+			Policy:   newConcurrencyPolicy(rack, imageServer),
+			Host:     newConcurrencyHost("host", "redfish-virtualmedia://192.168.122.1/redfish/v1/Systems/1", metal3api.StateAvailable),
+			Others: []*metal3api.BareMetalHost{
+				newConcurrencyHost("busy-1", "redfish-virtualmedia://192.168.122.2/redfish/v1/Systems/1", metal3api.StateInspecting),
+			},
+			Expected: "policy/image-server",
+		},
+		{
+			Scenario: "unparseable BMC address",
+			Policy:   newConcurrencyPolicy(imageServer),
+			Host:     newConcurrencyHost("host", "unknown://192.168.122.1", metal3api.StateAvailable),
+			Others: []*metal3api.BareMetalHost{
+				newConcurrencyHost("busy-1", "redfish-virtualmedia://192.168.122.2/redfish/v1/Systems/1", metal3api.StateInspecting),
+			},
+		},
+		{
+			Scenario: "other driver",
+			Policy:   newConcurrencyPolicy(imageServer),
+			Host:     newConcurrencyHost("host", "idrac-virtualmedia://192.168.122.1/redfish/v1/Systems/1", metal3api.StateAvailable),
+			Others: []*metal3api.BareMetalHost{
+				newConcurrencyHost("busy-1", "redfish-virtualmedia://192.168.122.2/redfish/v1/Systems/1", metal3api.StateInspecting),
+			},
+		},
+	} {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			objs := []runtime.Object{tc.Host}
+			for _, other := range tc.Others {
+				objs = append(objs, other)
+			}
+			if tc.Policy != nil {
+				objs = append(objs, tc.Policy)
+			}
+			r := newTestReconciler(objs...)
+
+			capacity, err := loadProvisioningCapacity(context.TODO(), r.Client, tc.Host)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bucket, err := capacity.fullBucket()
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.Expected, bucket)
+		})
+	}
+}
+
+func TestConcurrencyBucketDelaysHost(t *testing.T) {
+	host := newConcurrencyHost("host", "ipmi://192.168.122.1", metal3api.StateInspecting)
+	busy := newConcurrencyHost("busy", "ipmi://192.168.122.2", metal3api.StateProvisioning)
+	policy := newConcurrencyPolicy(metal3api.ProvisioningConcurrencyBucket{
+		Name:       "tenant",
+		Limit:      1,
+		Namespaces: []string{host.Namespace},
+	})
+	host.Status.OperationalStatus = metal3api.OperationalStatusDelayed
+
+	r := newTestReconciler(host, busy, policy)
+	prov := newMockProvisioner()
+	prov.setHasCapacity(true)
+	hsm := newHostStateMachine(host, r, prov, true)
+	info := makeReconcileInfo(host)
+
+	result := hsm.checkDelayedHost(info)
+
+	assert.IsType(t, actionDelayed{}, result)
+	assert.EqualValues(t, metal3api.OperationalStatusDelayed, host.Status.OperationalStatus)
+	assert.Empty(t, info.events, "no new event for a host already delayed")
+
+	busy.Status.Provisioning.State = metal3api.StateProvisioned
+	if err := r.Status().Update(context.TODO(), busy); err != nil {
+		t.Fatal(err)
+	}
+
+	result = hsm.checkDelayedHost(info)
+
+	assert.Equal(t, actionUpdate{}, result)
+	assert.EqualValues(t, metal3api.OperationalStatusOK, host.Status.OperationalStatus)
+}
+
+func TestRecordActionDelayedReportsBucket(t *testing.T) {
+	host := newConcurrencyHost("host", "ipmi://192.168.122.1", metal3api.StateAvailable)
+	info := makeReconcileInfo(host)
+
+	recordActionDelayed(info, metal3api.StateProvisioning, "policy/tenant")
+
+	assert.EqualValues(t, metal3api.OperationalStatusDelayed, host.Status.OperationalStatus)
+	if assert.Len(t, info.events, 1) {
+		assert.Equal(t, "Delayed", info.events[0].Reason)
+		assert.Equal(t, "Waiting for capacity in bucket policy/tenant", info.events[0].Message)
+	}
+}
+
+func newQueuedHost(namespace, name string, priority int, waited time.Duration, bucket string) metal3api.BareMetalHost {
+	host := newConcurrencyHost(name, "ipmi://192.168.122.1", metal3api.StateAvailable)
+	host.Namespace = namespace
+	host.Spec.ProvisioningPriority = priority
+	host.Status.OperationalStatus = metal3api.OperationalStatusDelayed
+	host.Status.ProvisioningQueue = &metal3api.ProvisioningQueueStatus{
+		Since:  metav1.NewTime(time.Now().Add(-waited)),
+		Bucket: bucket,
+	}
+	return *host
+}
+
+func queueNames(queue []queuedHost) []string {
+	names := []string{}
+	for _, entry := range queue {
+		names = append(names, entry.host.Namespace+"/"+entry.host.Name)
+	}
+	return names
+}
+
+func TestProvisioningQueueOrder(t *testing.T) {
+	host := newConcurrencyHost("new", "ipmi://192.168.122.1", metal3api.StateAvailable)
+	host.Namespace = "tenant-b"
+
+	capacity := &provisioningCapacity{
+		host: host,
+		hosts: []metal3api.BareMetalHost{
+			newQueuedHost("tenant-a", "a-1", 0, 50*time.Minute, provisionerBucket),
+			newQueuedHost("tenant-a", "a-2", 0, 40*time.Minute, provisionerBucket),
+			newQueuedHost("tenant-a", "a-3", 0, 30*time.Minute, provisionerBucket),
+			newQueuedHost("tenant-b", "b-1", 0, 10*time.Minute, provisionerBucket),
+			newQueuedHost("tenant-c", "c-urgent", 10, time.Minute, provisionerBucket),
+			*newConcurrencyHost("not-delayed", "ipmi://192.168.122.2", metal3api.StateAvailable),
+		},
+	}
+
+	queue := capacity.queue(metav1.Now())
+
+	assert.Equal(t, []string{
+		"tenant-c/c-urgent",
+		"tenant-a/a-1",
+		"tenant-b/b-1",
+		"tenant-a/a-2",
+		"tenant-b/new",
+		"tenant-a/a-3",
+	}, queueNames(queue))
+}
+
+func TestWaitingAhead(t *testing.T) {
+	policy := newConcurrencyPolicy(metal3api.ProvisioningConcurrencyBucket{
+		Name:       "tenant-a",
+		Limit:      1,
+		Namespaces: []string{"tenant-a"},
+	})
+
+	for _, tc := range []struct {
+		Scenario  string
+		Namespace string
+		Address   string
+		Bucket    string
+		Expected  string
+	}{
+		{
+			Scenario:  "waiting for the provisioner",
+			Namespace: "tenant-b",
+			Address:   "ipmi://192.168.122.1",
+			Bucket:    provisionerBucket,
+			Expected:  provisionerBucket,
+		},
+		{
+			Scenario:  "virtual media not limited by the provisioner",
+			Namespace: "tenant-b",
+			Address:   "redfish-virtualmedia://192.168.122.1/redfish/v1/Systems/1",
+			Bucket:    provisionerBucket,
+		},
+		{
+			Scenario:  "waiting for a matching bucket",
+			Namespace: "tenant-a",
+			Address:   "ipmi://192.168.122.1",
+			Bucket:    "policy/tenant-a",
+			Expected:  "policy/tenant-a",
+		},
+		{
+			Scenario:  "waiting for another bucket",
+			Namespace: "tenant-b",
+			Address:   "ipmi://192.168.122.1",
+			Bucket:    "policy/tenant-a",
+		},
+	} {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			host := newConcurrencyHost("new", tc.Address, metal3api.StateAvailable)
+			host.Namespace = tc.Namespace
+			capacity := &provisioningCapacity{
+				host: host,
+				hosts: []metal3api.BareMetalHost{
+					newQueuedHost("tenant-a", "waiting", 0, time.Minute, tc.Bucket),
+				},
+				policies: []metal3api.ProvisioningConcurrencyPolicy{*policy},
+			}
+
+			assert.Equal(t, tc.Expected, capacity.waitingAhead(capacity.queue(metav1.Now())))
+		})
+	}
+}
+
+func TestWaitingAheadPausedHost(t *testing.T) {
+	paused := newQueuedHost("tenant-a", "paused", 0, time.Hour, provisionerBucket)
+	paused.Annotations = map[string]string{metal3api.PausedAnnotation: ""}
+	deleted := newQueuedHost("tenant-a", "deleted", 0, time.Hour, provisionerBucket)
+	deleted.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	host := newConcurrencyHost("new", "ipmi://192.168.122.1", metal3api.StateAvailable)
+	capacity := &provisioningCapacity{
+		host:  host,
+		hosts: []metal3api.BareMetalHost{paused, deleted},
+	}
+
+	queue := capacity.queue(metav1.Now())
+	assert.Equal(t, []string{host.Namespace + "/new"}, queueNames(queue))
+	assert.Empty(t, capacity.waitingAhead(queue))
+}
+
+func TestProvisioningQueueStatus(t *testing.T) {
+	waiting := newQueuedHost(namespace, "waiting", 0, time.Hour, provisionerBucket)
+	host := newConcurrencyHost("host", "ipmi://192.168.122.1", metal3api.StateRegistering)
+
+	r := newTestReconciler(host, &waiting)
+	prov := newMockProvisioner()
+	prov.setHasCapacity(true)
+	hsm := newHostStateMachine(host, r, prov, true)
+	hsm.NextState = metal3api.StateInspecting
+	info := makeReconcileInfo(host)
+
+	result := hsm.updateHostStateFrom(metal3api.StateRegistering, info)
+
+	assert.IsType(t, actionDelayed{}, result)
+	assert.Equal(t, metal3api.StateRegistering, host.Status.Provisioning.State)
+	if assert.NotNil(t, host.Status.ProvisioningQueue) {
+		assert.Equal(t, 2, host.Status.ProvisioningQueue.Position)
+		assert.Equal(t, provisionerBucket, host.Status.ProvisioningQueue.Bucket)
+	}
+	since := host.Status.ProvisioningQueue.Since
+
+	result = hsm.checkDelayedHost(info)
+
+	assert.IsType(t, actionDelayed{}, result)
+	assert.Equal(t, since, host.Status.ProvisioningQueue.Since)
+
+	waiting.Status.Provisioning.State = metal3api.StateInspecting
+	waiting.Status.OperationalStatus = metal3api.OperationalStatusOK
+	waiting.Status.ProvisioningQueue = nil
+	if err := r.Status().Update(context.TODO(), &waiting); err != nil {
+		t.Fatal(err)
+	}
+
+	result = hsm.checkDelayedHost(info)
+
+	assert.Equal(t, actionUpdate{}, result)
+	assert.NotNil(t, host.Status.ProvisioningQueue, "kept until the host changes state")
+
+	result = hsm.updateHostStateFrom(metal3api.StateRegistering, info)
+
+	assert.Nil(t, result)
+	assert.Equal(t, metal3api.StateInspecting, host.Status.Provisioning.State)
+	assert.Nil(t, host.Status.ProvisioningQueue)
+}
@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// remediationPolicy returns the remediation policy of the host, that of
+// its hardware profile unless it sets its own.
+func remediationPolicy(info *reconcileInfo) (*metal3api.RemediationPolicy, error) {
+	if info.host.Spec.Remediation != nil {
+		return info.host.Spec.Remediation, nil
+	}
+	if info.host.HardwareProfile() == "" {
+		return nil, nil
+	}
+	hwProf, err := info.profiles.get(info.host.HardwareProfile())
+	if err != nil {
+		return nil, err
+	}
+	return hwProf.Remediation, nil
+}
+
+// remediatedErrorCount returns the error count of the host when it was
+// last remediated, or 0 if it has recovered or fails with another error
+// type since then.
+func remediatedErrorCount(host *metal3api.BareMetalHost) int {
+	status := host.Status.Remediation
+	if status == nil || status.ErrorCount == 0 || len(status.Attempts) == 0 {
+		return 0
+	}
+	if host.Status.ErrorCount < status.ErrorCount {
+		return 0
+	}
+	if last := status.Attempts[len(status.Attempts)-1]; host.Status.ErrorType != last.ErrorType {
+		return 0
+	}
+	return status.ErrorCount
+}
+
+// checkRemediation applies the remediation policy of a failing host.
+func (hsm *hostStateMachine) checkRemediation(info *reconcileInfo) actionResult {
+	host := info.host
+	remediated := remediatedErrorCount(host)
+	reset := host.Status.Remediation != nil && host.Status.Remediation.ErrorCount != remediated
+
+	var rule *metal3api.RemediationRule
+	if host.Status.ErrorType != "" {
+		policy, err := remediationPolicy(info)
+		if err != nil {
+			return actionError{err}
+		}
+		if policy != nil {
+			rule = policy.NextRule(host.Status.ErrorType, host.Status.ErrorCount, remediated)
+		}
+	}
+
+	if rule == nil {
+		if reset {
+			host.Status.Remediation.ErrorCount = 0
+			return actionUpdate{}
+		}
+		return nil
+	}
+
+	info.log.Info("remediating host", "action", rule.Action,
+		"errorType", host.Status.ErrorType, "errorCount", host.Status.ErrorCount)
+	attempt := metal3api.RemediationAttempt{
+		Time:       metav1.Now(),
+		ErrorType:  host.Status.ErrorType,
+		ErrorCount: host.Status.ErrorCount,
+		Action:     rule.Action,
+	}
+
+	failure, err := hsm.Reconciler.remediate(info, rule)
+	if err != nil {
+		return actionError{err}
+	}
+
+	if failure != "" {
+		attempt.Error = failure
+		info.publishEvent("RemediationFailed",
+			fmt.Sprintf("Failed to take remediation action %s after %d errors of type %s: %s",
+				rule.Action, attempt.ErrorCount, attempt.ErrorType, failure))
+	} else {
+		info.publishEvent("Remediation",
+			fmt.Sprintf("Took remediation action %s after %d errors of type %s",
+				rule.Action, attempt.ErrorCount, attempt.ErrorType))
+	}
+	host.RecordRemediationAttempt(attempt)
+	return actionUpdate{}
+}
+
+// remediate takes the action of the rule on the host. It returns why the
+// action could not be taken, if it could not.
+func (r *BareMetalHostReconciler) remediate(info *reconcileInfo, rule *metal3api.RemediationRule) (string, error) {
+	host := info.host
+	if host.Annotations == nil {
+		host.Annotations = map[string]string{}
+	}
+
+	switch rule.Action {
+	case metal3api.RemediationReregister:
+		// Forget the node and the credentials, so that the host is
+		// looked up and its BMC details set again.
+		host.Status.Provisioning.ID = ""
+		host.Status.TriedCredentials = metal3api.CredentialsStatus{}
+		return "", nil
+	case metal3api.RemediationPowerCycle:
+		// The reboot annotation is only acted on in the states in which
+		// the power of the host is managed, and would otherwise reboot
+		// the host much later.
+		switch host.Status.Provisioning.State {
+		case metal3api.StateAvailable, metal3api.StateProvisioned, metal3api.StateExternallyProvisioned:
+		default:
+			return fmt.Sprintf("the power of hosts in state %s is not managed", host.Status.Provisioning.State), nil
+		}
+		args, err := json.Marshal(metal3api.RebootAnnotationArguments{
+			Mode:  metal3api.RebootModeHard,
+			Force: true,
+		})
+		if err != nil {
+			return "", errors.Wrap(err, "failed to marshal reboot annotation")
+		}
+		// The suffixless annotation is removed once the host is powered
+		// off, so that it is powered on again.
+		host.Annotations[metal3api.RebootAnnotationPrefix] = string(args)
+	case metal3api.RemediationReinspect:
+		if inspectionDisabled(host) {
+			return "inspection is disabled", nil
+		}
+		host.Annotations[metal3api.InspectAnnotationPrefix] = ""
+	case metal3api.RemediationDetach:
+		host.Annotations[metal3api.DetachedAnnotation] = ""
+	case metal3api.RemediationTaint:
+		effect := rule.TaintEffect
+		if effect == "" {
+			effect = corev1.TaintEffectNoSchedule
+		}
+		for _, taint := range host.Spec.Taints {
+			if taint.Key == metal3api.RemediationTaintKey && taint.Effect == effect {
+				return "", nil
+			}
+		}
+		now := metav1.Now()
+		host.Spec.Taints = append(host.Spec.Taints, corev1.Taint{
+			Key:       metal3api.RemediationTaintKey,
+			Effect:    effect,
+			TimeAdded: &now,
+		})
+	default:
+		return fmt.Sprintf("unknown action %q", rule.Action), nil
+	}
+
+	// The update replaces the status with the stored one, so the
+	// attempt is only recorded afterwards.
+	if err := r.Update(info.ctx, host); err != nil {
+		return "", errors.Wrap(err, "failed to update host for remediation")
+	}
+	return "", nil
+}
@@ -0,0 +1,309 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newRemediationHost(errorType metal3api.ErrorType, errorCount int, rules ...metal3api.RemediationRule) *metal3api.BareMetalHost {
+	host := newHost("host", &metal3api.BareMetalHostSpec{
+		BMC:         metal3api.BMCDetails{Address: "ipmi://192.168.122.1"},
+		Remediation: &metal3api.RemediationPolicy{Rules: rules},
+	})
+	host.Status.Provisioning.State = metal3api.StateAvailable
+	host.Status.Provisioning.ID = "node-uuid"
+	host.Status.OperationalStatus = metal3api.OperationalStatusError
+	host.Status.ErrorType = errorType
+	host.Status.ErrorMessage = "failed"
+	host.Status.ErrorCount = errorCount
+	return host
+}
+
+func checkRemediation(t *testing.T, host *metal3api.BareMetalHost) (*reconcileInfo, actionResult, *metal3api.BareMetalHost) {
+	t.Helper()
+	r := newTestReconciler(host)
+	hsm := newHostStateMachine(host, r, newMockProvisioner(), true)
+	info := makeReconcileInfo(host)
+
+	result := hsm.checkRemediation(info)
+
+	stored := &metal3api.BareMetalHost{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: host.Namespace, Name: host.Name}, stored); err != nil {
+		t.Fatal(err)
+	}
+	return info, result, stored
+}
+
+func TestRemediationActions(t *testing.T) {
+	for _, tc := range []struct {
+		Scenario      string
+		Rule          metal3api.RemediationRule
+		State         metal3api.ProvisioningState
+		Annotations   map[string]string
+		ExpectedError string
+		Check         func(t *testing.T, host, stored *metal3api.BareMetalHost)
+	}{
+		{
+			Scenario: "power cycle",
+			Rule:     metal3api.RemediationRule{ErrorType: metal3api.PowerManagementError, ErrorCount: 2, Action: metal3api.RemediationPowerCycle},
+			Check: func(t *testing.T, _, stored *metal3api.BareMetalHost) {
+				t.Helper()
+				assert.Equal(t, `{"mode":"hard","force":true}`, stored.Annotations[metal3api.RebootAnnotationPrefix])
+			},
+		},
+		{
+			Scenario:      "power cycle while registering",
+			Rule:          metal3api.RemediationRule{ErrorType: metal3api.PowerManagementError, ErrorCount: 2, Action: metal3api.RemediationPowerCycle},
+			State:         metal3api.StateRegistering,
+			ExpectedError: "the power of hosts in state registering is not managed",
+			Check: func(t *testing.T, _, stored *metal3api.BareMetalHost) {
+				t.Helper()
+				assert.NotContains(t, stored.Annotations, metal3api.RebootAnnotationPrefix)
+			},
+		},
+		{
+			Scenario: "re-register",
+			Rule:     metal3api.RemediationRule{ErrorType: metal3api.PowerManagementError, ErrorCount: 2, Action: metal3api.RemediationReregister},
+			Check: func(t *testing.T, host, _ *metal3api.BareMetalHost) {
+				t.Helper()
+				assert.Empty(t, host.Status.Provisioning.ID)
+				assert.Nil(t, host.Status.TriedCredentials.Reference)
+			},
+		},
+		{
+			Scenario: "re-inspect",
+			Rule:     metal3api.RemediationRule{ErrorType: metal3api.PowerManagementError, ErrorCount: 2, Action: metal3api.RemediationReinspect},
+			Check: func(t *testing.T, _, stored *metal3api.BareMetalHost) {
+				t.Helper()
+				assert.True(t, hasInspectAnnotation(stored))
+			},
+		},
+		{
+			Scenario:      "re-inspect with inspection disabled",
+			Rule:          metal3api.RemediationRule{ErrorType: metal3api.PowerManagementError, ErrorCount: 2, Action: metal3api.RemediationReinspect},
+			Annotations:   map[string]string{metal3api.InspectAnnotationPrefix: metal3api.InspectAnnotationValueDisabled},
+			ExpectedError: "inspection is disabled",
+			Check: func(t *testing.T, _, stored *metal3api.BareMetalHost) {
+				t.Helper()
+				assert.True(t, inspectionDisabled(stored))
+			},
+		},
+		{
+			Scenario: "detach",
+			Rule:     metal3api.RemediationRule{ErrorType: metal3api.PowerManagementError, ErrorCount: 2, Action: metal3api.RemediationDetach},
+			Check: func(t *testing.T, _, stored *metal3api.BareMetalHost) {
+				t.Helper()
+				assert.True(t, hasDetachedAnnotation(stored))
+			},
+		},
+		{
+			Scenario: "taint",
+			Rule: metal3api.RemediationRule{ErrorType: metal3api.PowerManagementError, ErrorCount: 2, Action: metal3api.RemediationTaint,
+				TaintEffect: corev1.TaintEffectNoExecute},
+			Check: func(t *testing.T, _, stored *metal3api.BareMetalHost) {
+				t.Helper()
+				if assert.Len(t, stored.Spec.Taints, 1) {
+					assert.Equal(t, metal3api.RemediationTaintKey, stored.Spec.Taints[0].Key)
+					assert.Equal(t, corev1.TaintEffectNoExecute, stored.Spec.Taints[0].Effect)
+				}
+			},
+		},
+	} {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			host := newRemediationHost(metal3api.PowerManagementError, 2, tc.Rule)
+			host.Annotations = tc.Annotations
+			if tc.State != "" {
+				host.Status.Provisioning.State = tc.State
+			}
+
+			info, result, stored := checkRemediation(t, host)
+
+			assert.Equal(t, actionUpdate{}, result)
+			tc.Check(t, host, stored)
+
+			if assert.NotNil(t, host.Status.Remediation) && assert.Len(t, host.Status.Remediation.Attempts, 1) {
+				attempt := host.Status.Remediation.Attempts[0]
+				assert.Equal(t, tc.Rule.Action, attempt.Action)
+				assert.Equal(t, metal3api.PowerManagementError, attempt.ErrorType)
+				assert.Equal(t, 2, attempt.ErrorCount)
+				assert.Equal(t, tc.ExpectedError, attempt.Error)
+				assert.Equal(t, 2, host.Status.Remediation.ErrorCount)
+			}
+			if assert.Len(t, info.events, 1) {
+				if tc.ExpectedError == "" {
+					assert.Equal(t, "Remediation", info.events[0].Reason)
+				} else {
+					assert.Equal(t, "RemediationFailed", info.events[0].Reason)
+				}
+			}
+		})
+	}
+}
+
+func TestRemediationPowerCycle(t *testing.T) {
+	host := newRemediationHost(metal3api.PowerManagementError, 2, metal3api.RemediationRule{
+		ErrorType: metal3api.PowerManagementError, ErrorCount: 2, Action: metal3api.RemediationPowerCycle,
+	})
+	host.Spec.Online = true
+	host.Status.PoweredOn = true
+	r := newTestReconciler(host)
+	prov := newMockProvisioner()
+	hsm := newHostStateMachine(host, r, prov, true)
+
+	assert.Equal(t, actionUpdate{}, hsm.checkRemediation(makeReconcileInfo(host)))
+
+	manageHostPower(t, r, prov, host)
+	assert.True(t, prov.callsNoError["PowerOff"])
+	assert.False(t, host.Status.PoweredOn)
+
+	manageHostPower(t, r, prov, host)
+	assert.NotContains(t, host.Annotations, metal3api.RebootAnnotationPrefix)
+
+	// Removing the annotation replaces the status with the stored one.
+	host.Status.PoweredOn = false
+	manageHostPower(t, r, prov, host)
+	assert.True(t, prov.callsNoError["PowerOn"])
+}
+
+func TestRemediationEscalation(t *testing.T) {
+	rules := []metal3api.RemediationRule{
+		{ErrorType: metal3api.RegistrationError, ErrorCount: 2, Action: metal3api.RemediationReregister},
+		{ErrorType: metal3api.RegistrationError, ErrorCount: 4, Action: metal3api.RemediationTaint},
+	}
+
+	for _, tc := range []struct {
+		Scenario       string
+		ErrorType      metal3api.ErrorType
+		ErrorCount     int
+		Remediated     int
+		LastErrorType  metal3api.ErrorType
+		ExpectedAction metal3api.RemediationAction
+		ExpectedCount  int
+	}{
+		{
+			Scenario:   "below threshold",
+			ErrorType:  metal3api.RegistrationError,
+			ErrorCount: 1,
+		},
+		{
+			Scenario:       "threshold reached",
+			ErrorType:      metal3api.RegistrationError,
+			ErrorCount:     2,
+			ExpectedAction: metal3api.RemediationReregister,
+			ExpectedCount:  2,
+		},
+		{
+			Scenario:      "already remediated",
+			ErrorType:     metal3api.RegistrationError,
+			ErrorCount:    3,
+			Remediated:    2,
+			LastErrorType: metal3api.RegistrationError,
+			ExpectedCount: 2,
+		},
+		{
+			Scenario:       "next threshold reached",
+			ErrorType:      metal3api.RegistrationError,
+			ErrorCount:     4,
+			Remediated:     2,
+			LastErrorType:  metal3api.RegistrationError,
+			ExpectedAction: metal3api.RemediationTaint,
+			ExpectedCount:  4,
+		},
+		{
+			Scenario:       "failing again after recovering",
+			ErrorType:      metal3api.RegistrationError,
+			ErrorCount:     2,
+			Remediated:     4,
+			LastErrorType:  metal3api.RegistrationError,
+			ExpectedAction: metal3api.RemediationReregister,
+			ExpectedCount:  2,
+		},
+		{
+			Scenario:      "recovered",
+			ErrorCount:    0,
+			Remediated:    4,
+			LastErrorType: metal3api.RegistrationError,
+		},
+		{
+			Scenario:      "other error type",
+			ErrorType:     metal3api.PowerManagementError,
+			ErrorCount:    5,
+			Remediated:    4,
+			LastErrorType: metal3api.RegistrationError,
+		},
+	} {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			host := newRemediationHost(tc.ErrorType, tc.ErrorCount, rules...)
+			if tc.Remediated > 0 {
+				host.Status.Remediation = &metal3api.RemediationStatus{
+					ErrorCount: tc.Remediated,
+					Attempts: []metal3api.RemediationAttempt{
+						{ErrorType: tc.LastErrorType, ErrorCount: tc.Remediated, Action: metal3api.RemediationReregister},
+					},
+				}
+			}
+			attempts := 0
+			if host.Status.Remediation != nil {
+				attempts = len(host.Status.Remediation.Attempts)
+			}
+
+			_, result, _ := checkRemediation(t, host)
+
+			if tc.ExpectedAction == "" && tc.Remediated == tc.ExpectedCount {
+				assert.Nil(t, result)
+			} else {
+				assert.Equal(t, actionUpdate{}, result)
+			}
+			if host.Status.Remediation == nil {
+				assert.Empty(t, tc.ExpectedAction)
+				return
+			}
+			assert.Equal(t, tc.ExpectedCount, host.Status.Remediation.ErrorCount)
+			if tc.ExpectedAction != "" {
+				if assert.Len(t, host.Status.Remediation.Attempts, attempts+1) {
+					assert.Equal(t, tc.ExpectedAction, host.Status.Remediation.Attempts[attempts].Action)
+				}
+			} else {
+				assert.Len(t, host.Status.Remediation.Attempts, attempts)
+			}
+		})
+	}
+}
+
+func TestRemediationPolicyFromProfile(t *testing.T) {
+	host := newRemediationHost(metal3api.PowerManagementError, 1)
+	host.Spec.Remediation = nil
+	host.Status.HardwareProfile = "dell"
+	hwProfile := &metal3api.HardwareProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "dell"},
+		Spec: metal3api.HardwareProfileSpec{
+			Remediation: &metal3api.RemediationPolicy{
+				Rules: []metal3api.RemediationRule{
+					{ErrorType: metal3api.PowerManagementError, ErrorCount: 1, Action: metal3api.RemediationDetach},
+				},
+			},
+		},
+	}
+	r := newTestReconciler(host, hwProfile)
+	info := makeReconcileInfo(host)
+	info.profiles = &hardwareProfiles{ctx: context.TODO(), client: r.Client}
+
+	policy, err := remediationPolicy(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, hwProfile.Spec.Remediation, policy)
+
+	host.Spec.Remediation = &metal3api.RemediationPolicy{}
+	policy, err = remediationPolicy(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, host.Spec.Remediation, policy)
+}
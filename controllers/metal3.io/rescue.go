@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"fmt"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const rescuePasswordKey = "password"
+
+// getRescuePassword reads the password of the rescue user from the
+// secret referenced by the rescue request of the host.
+func (r *BareMetalHostReconciler) getRescuePassword(info *reconcileInfo) (password string, failure string, err error) {
+	key := types.NamespacedName{
+		Name:      info.host.Spec.Rescue.PasswordSecretName,
+		Namespace: info.host.Namespace,
+	}
+
+	secretManager := r.secretManager(info.ctx, info.log)
+	secret, err := secretManager.ObtainSecret(key)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", fmt.Sprintf("rescue password secret %s not found", key.Name), nil
+		}
+		return "", "", errors.Wrap(err, "failed to read the rescue password secret")
+	}
+
+	data, ok := secret.Data[rescuePasswordKey]
+	if !ok || len(data) == 0 {
+		return "", fmt.Sprintf("rescue password secret %s has no %s key", key.Name, rescuePasswordKey), nil
+	}
+	return string(data), "", nil
+}
+
+// actionRescuing boots the provisioned host into the rescue ramdisk.
+func (r *BareMetalHostReconciler) actionRescuing(prov provisioner.Provisioner, info *reconcileInfo) actionResult {
+	password, failure, err := r.getRescuePassword(info)
+	if err != nil {
+		return actionError{err}
+	}
+	if failure != "" {
+		return recordActionFailure(info, metal3api.RescueError, failure)
+	}
+
+	ctx, cancel := info.provisionerContext(provisionerCallTimeout)
+	defer cancel()
+
+	restartOnFailure := info.host.Status.ErrorType == metal3api.RescueError
+	provResult, err := prov.Rescue(ctx, provisioner.RescueData{Password: password}, restartOnFailure)
+	if err != nil {
+		return actionError{errors.Wrap(err, "failed to rescue host")}
+	}
+
+	if provResult.ErrorMessage != "" {
+		return recordActionFailure(info, metal3api.RescueError, provResult.ErrorMessage)
+	}
+
+	if provResult.Dirty {
+		if clearError(info.host) {
+			return actionUpdate{actionContinue{provResult.RequeueAfter}}
+		}
+		return actionContinue{provResult.RequeueAfter}
+	}
+
+	info.log.Info("host is rescued")
+	clearError(info.host)
+	return actionComplete{}
+}
+
+// actionUnrescuing boots the rescued host back into its image.
+func (r *BareMetalHostReconciler) actionUnrescuing(prov provisioner.Provisioner, info *reconcileInfo) actionResult {
+	ctx, cancel := info.provisionerContext(provisionerCallTimeout)
+	defer cancel()
+
+	restartOnFailure := info.host.Status.ErrorType == metal3api.RescueError
+	provResult, err := prov.Unrescue(ctx, restartOnFailure)
+	if err != nil {
+		return actionError{errors.Wrap(err, "failed to unrescue host")}
+	}
+
+	if provResult.ErrorMessage != "" {
+		return recordActionFailure(info, metal3api.RescueError, provResult.ErrorMessage)
+	}
+
+	if provResult.Dirty {
+		if clearError(info.host) {
+			return actionUpdate{actionContinue{provResult.RequeueAfter}}
+		}
+		return actionContinue{provResult.RequeueAfter}
+	}
+
+	info.log.Info("host is no longer rescued")
+	clearError(info.host)
+	return actionComplete{}
+}
@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newRescuePasswordSecret(host *metal3api.BareMetalHost, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rescue-password",
+			Namespace: host.Namespace,
+		},
+		Data: data,
+	}
+}
+
+func TestRescue(t *testing.T) {
+	testCases := []struct {
+		Scenario                  string
+		Host                      *metal3api.BareMetalHost
+		Rescue                    bool
+		SecretData                map[string][]byte
+		ProvResult                *provisioner.Result
+		ExpectedState             metal3api.ProvisioningState
+		ExpectedOperationalStatus metal3api.OperationalStatus
+		ExpectedErrorType         metal3api.ErrorType
+	}{
+		{
+			Scenario:                  "rescue requested",
+			Host:                      host(metal3api.StateProvisioned).SetStatusImageURL("not-empty").build(),
+			Rescue:                    true,
+			ExpectedState:             metal3api.StateRescuing,
+			ExpectedOperationalStatus: metal3api.OperationalStatusOK,
+		},
+		{
+			Scenario:                  "rescue in progress",
+			Host:                      host(metal3api.StateRescuing).SetStatusImageURL("not-empty").build(),
+			Rescue:                    true,
+			SecretData:                map[string][]byte{"password": []byte("s3cret")},
+			ProvResult:                &provisioner.Result{Dirty: true},
+			ExpectedState:             metal3api.StateRescuing,
+			ExpectedOperationalStatus: metal3api.OperationalStatusOK,
+		},
+		{
+			Scenario:                  "rescue finished",
+			Host:                      host(metal3api.StateRescuing).SetStatusImageURL("not-empty").build(),
+			Rescue:                    true,
+			SecretData:                map[string][]byte{"password": []byte("s3cret")},
+			ExpectedState:             metal3api.StateRescued,
+			ExpectedOperationalStatus: metal3api.OperationalStatusOK,
+		},
+		{
+			Scenario:                  "rescue failed",
+			Host:                      host(metal3api.StateRescuing).SetStatusImageURL("not-empty").build(),
+			Rescue:                    true,
+			SecretData:                map[string][]byte{"password": []byte("s3cret")},
+			ProvResult:                &provisioner.Result{ErrorMessage: "rescue failed"},
+			ExpectedState:             metal3api.StateRescuing,
+			ExpectedOperationalStatus: metal3api.OperationalStatusError,
+			ExpectedErrorType:         metal3api.RescueError,
+		},
+		{
+			Scenario:                  "missing password secret",
+			Host:                      host(metal3api.StateRescuing).SetStatusImageURL("not-empty").build(),
+			Rescue:                    true,
+			ExpectedState:             metal3api.StateRescuing,
+			ExpectedOperationalStatus: metal3api.OperationalStatusError,
+			ExpectedErrorType:         metal3api.RescueError,
+		},
+		{
+			Scenario:                  "missing password key",
+			Host:                      host(metal3api.StateRescuing).SetStatusImageURL("not-empty").build(),
+			Rescue:                    true,
+			SecretData:                map[string][]byte{"value": []byte("s3cret")},
+			ExpectedState:             metal3api.StateRescuing,
+			ExpectedOperationalStatus: metal3api.OperationalStatusError,
+			ExpectedErrorType:         metal3api.RescueError,
+		},
+		{
+			Scenario:                  "rescue lifted while rescuing",
+			Host:                      host(metal3api.StateRescuing).SetStatusImageURL("not-empty").build(),
+			ExpectedState:             metal3api.StateRescued,
+			ExpectedOperationalStatus: metal3api.OperationalStatusOK,
+		},
+		{
+			Scenario:                  "rescued",
+			Host:                      host(metal3api.StateRescued).SetStatusImageURL("not-empty").build(),
+			Rescue:                    true,
+			ExpectedState:             metal3api.StateRescued,
+			ExpectedOperationalStatus: metal3api.OperationalStatusOK,
+		},
+		{
+			Scenario:                  "unrescue in progress",
+			Host:                      host(metal3api.StateRescued).SetStatusImageURL("not-empty").build(),
+			ProvResult:                &provisioner.Result{Dirty: true},
+			ExpectedState:             metal3api.StateRescued,
+			ExpectedOperationalStatus: metal3api.OperationalStatusOK,
+		},
+		{
+			Scenario:                  "unrescue finished",
+			Host:                      host(metal3api.StateRescued).SetStatusImageURL("not-empty").build(),
+			ExpectedState:             metal3api.StateProvisioned,
+			ExpectedOperationalStatus: metal3api.OperationalStatusOK,
+		},
+		{
+			Scenario:                  "unrescue failed",
+			Host:                      host(metal3api.StateRescued).SetStatusImageURL("not-empty").build(),
+			ProvResult:                &provisioner.Result{ErrorMessage: "unrescue failed"},
+			ExpectedState:             metal3api.StateRescued,
+			ExpectedOperationalStatus: metal3api.OperationalStatusError,
+			ExpectedErrorType:         metal3api.RescueError,
+		},
+		{
+			Scenario:                  "image removed while rescued",
+			Host:                      host(metal3api.StateRescued).SetStatusImageURL("not-empty").SetImageURL("").build(),
+			Rescue:                    true,
+			ExpectedState:             metal3api.StateDeprovisioning,
+			ExpectedOperationalStatus: metal3api.OperationalStatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			if tc.Rescue {
+				tc.Host.Spec.Rescue = &metal3api.RescueConfig{PasswordSecretName: "rescue-password"}
+			}
+			objs := []runtime.Object{tc.Host}
+			if tc.SecretData != nil {
+				objs = append(objs, newRescuePasswordSecret(tc.Host, tc.SecretData))
+			}
+			prov := newMockProvisioner()
+			if tc.ProvResult != nil {
+				prov.nextResults["Rescue"] = *tc.ProvResult
+				prov.nextResults["Unrescue"] = *tc.ProvResult
+			}
+			r := newTestReconciler(objs...)
+			hsm := newHostStateMachine(tc.Host, r, prov, true)
+			info := makeDefaultReconcileInfo(tc.Host)
+			info.request = newRequest(tc.Host)
+
+			hsm.ReconcileState(info)
+
+			assert.Equal(t, tc.ExpectedState, tc.Host.Status.Provisioning.State)
+			assert.Equal(t, tc.ExpectedOperationalStatus, tc.Host.Status.OperationalStatus)
+			assert.Equal(t, tc.ExpectedErrorType, tc.Host.Status.ErrorType)
+		})
+	}
+}
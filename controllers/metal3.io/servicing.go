@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"reflect"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/pkg/errors"
+)
+
+func hasServicingAnnotation(host *metal3api.BareMetalHost) bool {
+	_, ok := host.GetAnnotations()[metal3api.ServicingAnnotation]
+	return ok
+}
+
+// actionServicing applies pending changes of the BIOS configuration,
+// firmware settings and firmware components to a provisioned host. New
+// changes are only started when the host has the servicing annotation,
+// which is removed once they are applied, while servicing in progress or
+// failed is always followed up. It returns nil when there is nothing to
+// service.
+func (r *BareMetalHostReconciler) actionServicing(prov provisioner.Provisioner, info *reconcileInfo) actionResult {
+	inProgress := info.host.OperationalStatus() == metal3api.OperationalStatusServicing
+	failed := info.host.Status.ErrorType == metal3api.ServicingError
+	if !inProgress && !failed && !hasServicingAnnotation(info.host) {
+		return nil
+	}
+
+	_, newStatus, err := getHostProvisioningSettings(info.host, info)
+	if err != nil {
+		return actionError{err}
+	}
+
+	servicingData := provisioner.ServicingData{}
+
+	firmwareDirty := !reflect.DeepEqual(info.host.Status.Provisioning.Firmware, newStatus.Provisioning.Firmware)
+	if firmwareDirty {
+		servicingData.FirmwareConfig = newStatus.Provisioning.Firmware.DeepCopy()
+	}
+
+	hfsDirty, hfs, err := r.getHostFirmwareSettings(info)
+	if err != nil {
+		// wait until hostFirmwareSettings are ready
+		return actionContinue{subResourceNotReadyRetryDelay}
+	}
+	if hfsDirty {
+		servicingData.ActualFirmwareSettings = hfs.Status.Settings.DeepCopy()
+		servicingData.TargetFirmwareSettings = hfs.Spec.Settings.DeepCopy()
+	}
+
+	hfcDirty, hfc, err := r.getHostFirmwareComponents(info)
+	if err != nil {
+		// wait until hostFirmwareComponents are ready
+		return actionContinue{subResourceNotReadyRetryDelay}
+	}
+	if hfcDirty {
+		servicingData.TargetFirmwareComponents = hfc.Spec.Updates
+	}
+
+	dirty := firmwareDirty || hfsDirty || hfcDirty
+	if !inProgress && !failed && !dirty {
+		return nil
+	}
+
+	ctx, cancel := info.provisionerContext(provisionerCallTimeout)
+	defer cancel()
+
+	// Once started, the changes are only applied again after a failure
+	// so that stale firmware settings do not restart servicing.
+	provResult, started, err := prov.Service(ctx, servicingData, dirty && !inProgress, failed)
+	if err != nil {
+		return actionError{errors.Wrap(err, "error servicing host")}
+	}
+
+	if provResult.ErrorMessage != "" {
+		return recordActionFailure(info, metal3api.ServicingError, provResult.ErrorMessage)
+	}
+
+	if started {
+		if hfcDirty {
+			hfcStillDirty, err := r.saveHostFirmwareComponents(prov, info, hfc)
+			if err != nil {
+				return actionError{errors.Wrap(err, "could not save the host firmware components")}
+			}
+
+			if hfcStillDirty {
+				info.log.Info("going to update the host firmware components")
+				if err := r.Status().Update(info.ctx, hfc); err != nil {
+					return actionError{errors.Wrap(err, "failed to update hostfirmwarecomponents status")}
+				}
+			}
+		}
+
+		if firmwareDirty {
+			info.log.Info("saving host firmware configuration")
+			info.host.Status.Provisioning.Firmware = newStatus.Provisioning.Firmware
+		}
+
+		clearError(info.host)
+		info.host.SetOperationalStatus(metal3api.OperationalStatusServicing)
+		info.publishEvent("ServicingStarted", "Started applying changes to the provisioned host")
+		return actionUpdate{actionContinue{provResult.RequeueAfter}}
+	}
+
+	if provResult.Dirty {
+		return actionContinue{provResult.RequeueAfter}
+	}
+
+	if !inProgress && !failed {
+		// The provisioner had nothing to apply
+		return nil
+	}
+
+	// The changes have been applied, so the annotation is removed to
+	// avoid servicing the host again when a stale difference between the
+	// spec and status of its firmware settings remains.
+	if hasServicingAnnotation(info.host) {
+		delete(info.host.Annotations, metal3api.ServicingAnnotation)
+		if err := r.Update(info.ctx, info.host); err != nil {
+			return actionError{errors.Wrap(err, "failed to remove the servicing annotation")}
+		}
+		return actionContinue{}
+	}
+
+	info.log.Info("servicing finished")
+	clearError(info.host)
+	info.host.Status.ErrorCount = 0
+	info.publishEvent("ServicingComplete", "Finished applying changes to the provisioned host")
+	return actionUpdate{}
+}
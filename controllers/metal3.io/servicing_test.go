@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newPendingFirmwareComponents(host *metal3api.BareMetalHost) *metal3api.HostFirmwareComponents {
+	return &metal3api.HostFirmwareComponents{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      host.Name,
+			Namespace: host.Namespace,
+		},
+		Spec: metal3api.HostFirmwareComponentsSpec{
+			Updates: []metal3api.FirmwareUpdate{
+				{
+					Component: "bios",
+					URL:       "https://example.com/bios.bin",
+				},
+			},
+		},
+		Status: metal3api.HostFirmwareComponentsStatus{
+			Conditions: []metav1.Condition{
+				{Type: string(metal3api.HostFirmwareComponentsChangeDetected), Status: metav1.ConditionTrue, Reason: "OK"},
+				{Type: string(metal3api.HostFirmwareComponentsValid), Status: metav1.ConditionTrue, Reason: "OK"},
+			},
+		},
+	}
+}
+
+func TestServicing(t *testing.T) {
+	testCases := []struct {
+		Scenario                  string
+		Host                      *metal3api.BareMetalHost
+		Annotated                 bool
+		PendingUpdates            bool
+		ServiceResult             *provisioner.Result
+		ExpectedServiceCalled     bool
+		ExpectedOperationalStatus metal3api.OperationalStatus
+		ExpectedErrorType         metal3api.ErrorType
+	}{
+		{
+			Scenario:                  "changes without annotation",
+			Host:                      host(metal3api.StateProvisioned).SetStatusImageURL("not-empty").build(),
+			PendingUpdates:            true,
+			ExpectedOperationalStatus: metal3api.OperationalStatusOK,
+		},
+		{
+			Scenario:                  "annotation without changes",
+			Host:                      host(metal3api.StateProvisioned).SetStatusImageURL("not-empty").build(),
+			Annotated:                 true,
+			ExpectedOperationalStatus: metal3api.OperationalStatusOK,
+		},
+		{
+			Scenario:                  "start servicing",
+			Host:                      host(metal3api.StateProvisioned).SetStatusImageURL("not-empty").build(),
+			Annotated:                 true,
+			PendingUpdates:            true,
+			ServiceResult:             &provisioner.Result{Dirty: true},
+			ExpectedServiceCalled:     true,
+			ExpectedOperationalStatus: metal3api.OperationalStatusServicing,
+		},
+		{
+			Scenario: "servicing finished",
+			Host: host(metal3api.StateProvisioned).SetStatusImageURL("not-empty").
+				SetOperationalStatus(metal3api.OperationalStatusServicing).build(),
+			ExpectedServiceCalled:     true,
+			ExpectedOperationalStatus: metal3api.OperationalStatusOK,
+		},
+		{
+			Scenario: "servicing failed",
+			Host: host(metal3api.StateProvisioned).SetStatusImageURL("not-empty").
+				SetOperationalStatus(metal3api.OperationalStatusServicing).build(),
+			ServiceResult:             &provisioner.Result{ErrorMessage: "update failed"},
+			ExpectedServiceCalled:     true,
+			ExpectedOperationalStatus: metal3api.OperationalStatusError,
+			ExpectedErrorType:         metal3api.ServicingError,
+		},
+		{
+			Scenario: "retry after failure",
+			Host: host(metal3api.StateProvisioned).SetStatusImageURL("not-empty").
+				SetStatusError(metal3api.OperationalStatusError, metal3api.ServicingError, "update failed", 1).build(),
+			PendingUpdates:            true,
+			ServiceResult:             &provisioner.Result{Dirty: true},
+			ExpectedServiceCalled:     true,
+			ExpectedOperationalStatus: metal3api.OperationalStatusServicing,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			if tc.Annotated {
+				tc.Host.Annotations = map[string]string{metal3api.ServicingAnnotation: ""}
+			}
+			hfc := newPendingFirmwareComponents(tc.Host)
+			if !tc.PendingUpdates {
+				hfc.Status.Conditions = nil
+			}
+			prov := newMockProvisioner()
+			if tc.ServiceResult != nil {
+				prov.nextResults["Service"] = *tc.ServiceResult
+			}
+			r := newTestReconciler(tc.Host, hfc)
+			hsm := newHostStateMachine(tc.Host, r, prov, true)
+			info := makeDefaultReconcileInfo(tc.Host)
+			info.request = newRequest(tc.Host)
+
+			hsm.ReconcileState(info)
+
+			if tc.ServiceResult == nil {
+				assert.Equal(t, tc.ExpectedServiceCalled, prov.calledNoError("Service"))
+			}
+			assert.Equal(t, tc.ExpectedOperationalStatus, tc.Host.Status.OperationalStatus)
+			assert.Equal(t, tc.ExpectedErrorType, tc.Host.Status.ErrorType)
+			assert.Equal(t, metal3api.StateProvisioned, tc.Host.Status.Provisioning.State)
+		})
+	}
+}
+
+func TestServicingFinishedRemovesAnnotation(t *testing.T) {
+	host := host(metal3api.StateProvisioned).SetStatusImageURL("not-empty").
+		SetOperationalStatus(metal3api.OperationalStatusServicing).build()
+	host.Annotations = map[string]string{metal3api.ServicingAnnotation: ""}
+	hfc := newPendingFirmwareComponents(host)
+	prov := newMockProvisioner()
+	r := newTestReconciler(host, hfc)
+	hsm := newHostStateMachine(host, r, prov, true)
+	info := makeDefaultReconcileInfo(host)
+	info.request = newRequest(host)
+
+	hsm.ReconcileState(info)
+	assert.NotContains(t, host.Annotations, metal3api.ServicingAnnotation)
+	assert.Equal(t, metal3api.OperationalStatusServicing, host.Status.OperationalStatus)
+
+	// The pending changes are not applied again without the annotation
+	hsm.ReconcileState(info)
+	assert.Equal(t, metal3api.OperationalStatusOK, host.Status.OperationalStatus)
+	hsm.ReconcileState(info)
+	assert.Equal(t, metal3api.OperationalStatusOK, host.Status.OperationalStatus)
+}
@@ -25,6 +25,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-logr/logr"
 	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
 	metal3iocontroller "github.com/metal3-io/baremetal-operator/controllers/metal3.io"
 	"github.com/metal3-io/baremetal-operator/pkg/imageprovider"
@@ -32,6 +33,7 @@ import (
 	"github.com/metal3-io/baremetal-operator/pkg/provisioner/demo"
 	"github.com/metal3-io/baremetal-operator/pkg/provisioner/fixture"
 	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/redfish"
 	"github.com/metal3-io/baremetal-operator/pkg/secretutils"
 	"github.com/metal3-io/baremetal-operator/pkg/version"
 	"github.com/pkg/errors"
@@ -122,6 +124,8 @@ func main() {
 	var devLogging bool
 	var runInTestMode bool
 	var runInDemoMode bool
+	var runInRedfishMode bool
+	var extraBackends string
 	var webhookPort int
 	var restConfigQPS float64
 	var restConfigBurst int
@@ -146,6 +150,10 @@ func main() {
 	flag.BoolVar(&runInTestMode, "test-mode", false, "disable ironic communication")
 	flag.BoolVar(&runInDemoMode, "demo-mode", false,
 		"use the demo provisioner to set host states")
+	flag.BoolVar(&runInRedfishMode, "redfish-mode", false,
+		"use the native Redfish provisioner instead of ironic")
+	flag.StringVar(&extraBackends, "provisioning-backends", "",
+		"comma-separated list of additional provisioning backends (ironic, redfish, demo, fixture) that hosts can select")
 	flag.StringVar(&healthAddr, "health-addr", ":9440",
 		"The address the health endpoint binds to.")
 	flag.IntVar(&webhookPort, "webhook-port", 9443,
@@ -268,17 +276,21 @@ func main() {
 		os.Exit(1)
 	}
 
-	var provisionerFactory provisioner.Factory
+	defaultBackend := metal3api.DefaultProvisioningBackend
 	if runInTestMode {
-		ctrl.Log.Info("using test provisioner")
-		provisionerFactory = &fixture.Fixture{}
+		defaultBackend = "fixture"
 	} else if runInDemoMode {
-		ctrl.Log.Info("using demo provisioner")
-		provisionerFactory = &demo.Demo{}
-	} else {
-		provLog := zap.New(zap.UseFlagOptions(&logOpts)).WithName("provisioner")
-		provisionerFactory = ironic.NewProvisionerFactory(provLog, preprovImgEnable)
+		defaultBackend = "demo"
+	} else if runInRedfishMode {
+		defaultBackend = "redfish"
+	}
+	provLog := zap.New(zap.UseFlagOptions(&logOpts)).WithName("provisioner")
+	provisionerFactory, err := buildProvisionerRegistry(provLog, defaultBackend, extraBackends, preprovImgEnable)
+	if err != nil {
+		setupLog.Error(err, "unable to set up provisioning backends")
+		os.Exit(1)
 	}
+	ctrl.Log.Info("using provisioning backends", "default", provisionerFactory.Default(), "enabled", provisionerFactory.Names())
 
 	maxConcurrency, err := getMaxConcurrentReconciles(controllerConcurrency)
 	if err != nil {
@@ -349,6 +361,38 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&metal3iocontroller.BareMetalHostClaimReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("BareMetalHostClaim"),
+	}).SetupWithManager(mgr, maxConcurrency); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BareMetalHostClaim")
+		os.Exit(1)
+	}
+
+	if err = (&metal3iocontroller.NetworkTopologyReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("NetworkTopology"),
+	}).SetupWithManager(mgr, maxConcurrency); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NetworkTopology")
+		os.Exit(1)
+	}
+
+	if err = (&metal3iocontroller.HardwareClassificationReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("HardwareClassification"),
+	}).SetupWithManager(mgr, maxConcurrency); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HardwareClassification")
+		os.Exit(1)
+	}
+
+	if err = (&metal3iocontroller.BareMetalHostOperationReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("BareMetalHostOperation"),
+	}).SetupWithManager(mgr, maxConcurrency); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BareMetalHostOperation")
+		os.Exit(1)
+	}
+
 	setupChecks(mgr)
 
 	if enableWebhook {
@@ -424,6 +468,38 @@ func GetTLSOptionOverrideFuncs(options TLSOptions) ([]func(*tls.Config), error)
 	return tlsOptions, nil
 }
 
+// buildProvisionerRegistry registers the default provisioning backend
+// and any additional ones hosts are allowed to select.
+func buildProvisionerRegistry(provLog logr.Logger, defaultBackend, extraBackends string, preprovImgEnable bool) (*provisioner.Registry, error) {
+	registry := provisioner.NewRegistry(defaultBackend)
+
+	names := []string{defaultBackend}
+	for _, name := range strings.Split(extraBackends, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		var factory provisioner.Factory
+		switch name {
+		case "fixture":
+			factory = &fixture.Fixture{}
+		case "demo":
+			factory = &demo.Demo{}
+		case "redfish":
+			factory = redfish.NewProvisionerFactory(provLog)
+		case "ironic":
+			factory = ironic.NewProvisionerFactory(provLog, preprovImgEnable)
+		default:
+			return nil, provisioner.UnknownBackendError{Name: name}
+		}
+		registry.Register(name, factory)
+	}
+
+	return registry, nil
+}
+
 func getMaxConcurrentReconciles(controllerConcurrency int) (int, error) {
 	if controllerConcurrency > 0 {
 		ctrl.Log.Info(fmt.Sprintf("controller concurrency will be set to %d according to command line flag", controllerConcurrency))
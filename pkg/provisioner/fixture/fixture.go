@@ -79,6 +79,12 @@ type Fixture struct {
 	// state to manage power
 	poweredOn bool
 
+	// state to manage rescue
+	rescued bool
+
+	// BootDevice records the last boot device that was set
+	BootDevice *provisioner.BootDeviceData
+
 	validateError string
 
 	customDeploy *metal3api.CustomDeploy
@@ -104,13 +110,13 @@ func (f *Fixture) SetValidateError(message string) {
 	f.validateError = message
 }
 
-func (p *fixtureProvisioner) HasCapacity() (result bool, err error) {
+func (p *fixtureProvisioner) HasCapacity(_ context.Context) (result bool, err error) {
 	return true, nil
 }
 
 // ValidateManagementAccess tests the connection information for the
 // host to verify that the location and credentials work.
-func (p *fixtureProvisioner) ValidateManagementAccess(_ provisioner.ManagementAccessData, _, _ bool) (result provisioner.Result, provID string, err error) {
+func (p *fixtureProvisioner) ValidateManagementAccess(_ context.Context, _ provisioner.ManagementAccessData, _, _ bool) (result provisioner.Result, provID string, err error) {
 	p.log.Info("testing management access")
 
 	if p.state.validateError != "" {
@@ -130,7 +136,7 @@ func (p *fixtureProvisioner) ValidateManagementAccess(_ provisioner.ManagementAc
 	return
 }
 
-func (p *fixtureProvisioner) PreprovisioningImageFormats() ([]metal3api.ImageFormat, error) {
+func (p *fixtureProvisioner) PreprovisioningImageFormats(_ context.Context) ([]metal3api.ImageFormat, error) {
 	return nil, nil
 }
 
@@ -138,7 +144,7 @@ func (p *fixtureProvisioner) PreprovisioningImageFormats() ([]metal3api.ImageFor
 // details of devices discovered on the hardware. It may be called
 // multiple times, and should return true for its dirty flag until the
 // inspection is completed.
-func (p *fixtureProvisioner) InspectHardware(_ provisioner.InspectData, _, _, _ bool) (result provisioner.Result, started bool, details *metal3api.HardwareDetails, err error) {
+func (p *fixtureProvisioner) InspectHardware(_ context.Context, _ provisioner.InspectData, _, _, _ bool) (result provisioner.Result, started bool, details *metal3api.HardwareDetails, err error) {
 	// The inspection is ongoing. We'll need to check the fixture
 	// status for the server here until it is ready for us to get the
 	// inspection details. Simulate that for now by creating the
@@ -197,22 +203,29 @@ func (p *fixtureProvisioner) InspectHardware(_ provisioner.InspectData, _, _, _
 // and updates the HardwareDetails field of the host with details. It
 // is expected to do this in the least expensive way possible, such as
 // reading from a cache.
-func (p *fixtureProvisioner) UpdateHardwareState() (hwState provisioner.HardwareState, err error) {
+func (p *fixtureProvisioner) UpdateHardwareState(_ context.Context) (hwState provisioner.HardwareState, err error) {
 	hwState.PoweredOn = &p.state.poweredOn
 	p.log.Info("updating hardware state")
 	return
 }
 
 // Prepare remove existing configuration and set new configuration.
-func (p *fixtureProvisioner) Prepare(_ provisioner.PrepareData, unprepared bool, _ bool) (result provisioner.Result, started bool, err error) {
+func (p *fixtureProvisioner) Prepare(_ context.Context, _ provisioner.PrepareData, unprepared bool, _ bool) (result provisioner.Result, started bool, err error) {
 	p.log.Info("preparing host")
 	started = unprepared
 	return
 }
 
+// Service applies firmware settings and updates to a provisioned host.
+func (p *fixtureProvisioner) Service(_ context.Context, _ provisioner.ServicingData, unprepared bool, _ bool) (result provisioner.Result, started bool, err error) {
+	p.log.Info("servicing host")
+	started = unprepared
+	return
+}
+
 // Adopt notifies the provisioner that the state machine believes the host
 // to be currently provisioned, and that it should be managed as such.
-func (p *fixtureProvisioner) Adopt(_ provisioner.AdoptData, _ bool) (result provisioner.Result, err error) {
+func (p *fixtureProvisioner) Adopt(_ context.Context, _ provisioner.AdoptData, _ bool) (result provisioner.Result, err error) {
 	p.log.Info("adopting host")
 	if !p.state.adopted {
 		p.state.adopted = true
@@ -225,7 +238,7 @@ func (p *fixtureProvisioner) Adopt(_ provisioner.AdoptData, _ bool) (result prov
 // Provision writes the image from the host spec to the host. It may
 // be called multiple times, and should return true for its dirty flag
 // until the provisioning operation is completed.
-func (p *fixtureProvisioner) Provision(data provisioner.ProvisionData, _ bool) (result provisioner.Result, err error) {
+func (p *fixtureProvisioner) Provision(_ context.Context, data provisioner.ProvisionData, _ bool) (result provisioner.Result, err error) {
 	p.log.Info("provisioning image to host")
 
 	if data.CustomDeploy != nil && p.state.customDeploy == nil {
@@ -248,10 +261,47 @@ func (p *fixtureProvisioner) Provision(data provisioner.ProvisionData, _ bool) (
 	return result, nil
 }
 
+// Rescue boots the provisioned host into a rescue ramdisk. It may be
+// called multiple times, and should return true for its dirty flag
+// until the host is rescued.
+func (p *fixtureProvisioner) Rescue(_ context.Context, _ provisioner.RescueData, _ bool) (result provisioner.Result, err error) {
+	p.log.Info("ensuring host is rescued")
+
+	if !p.state.rescued {
+		p.publisher("RescueStarted", "Rescue started")
+		p.state.rescued = true
+		result.Dirty = true
+		result.RequeueAfter = provisionRequeueDelay
+	}
+	return result, nil
+}
+
+// Unrescue boots the rescued host back into its image. It may be
+// called multiple times, and should return true for its dirty flag
+// until the host is running its image again.
+func (p *fixtureProvisioner) Unrescue(_ context.Context, _ bool) (result provisioner.Result, err error) {
+	p.log.Info("ensuring host is not rescued")
+
+	if p.state.rescued {
+		p.publisher("UnrescueStarted", "Unrescue started")
+		p.state.rescued = false
+		result.Dirty = true
+		result.RequeueAfter = provisionRequeueDelay
+	}
+	return result, nil
+}
+
+// SetBootDevice records the device the host boots from.
+func (p *fixtureProvisioner) SetBootDevice(_ context.Context, data provisioner.BootDeviceData) (result provisioner.Result, err error) {
+	p.log.Info("setting boot device", "device", data.Device, "persistent", data.Persistent)
+	p.state.BootDevice = &data
+	return result, nil
+}
+
 // Deprovision removes the host from the image. It may be called
 // multiple times, and should return true for its dirty flag until the
 // deprovisioning operation is completed.
-func (p *fixtureProvisioner) Deprovision(_ bool) (result provisioner.Result, err error) {
+func (p *fixtureProvisioner) Deprovision(_ context.Context, data provisioner.DeprovisionData, _ bool) (result provisioner.Result, erasureStarted bool, err error) {
 	p.log.Info("ensuring host is deprovisioned")
 
 	result.RequeueAfter = deprovisionRequeueDelay
@@ -266,7 +316,7 @@ func (p *fixtureProvisioner) Deprovision(_ bool) (result provisioner.Result, err
 		p.log.Info("clearing hardware details")
 		p.state.image = metal3api.Image{}
 		result.Dirty = true
-		return result, nil
+		return result, false, nil
 	}
 
 	if p.state.customDeploy != nil {
@@ -274,17 +324,23 @@ func (p *fixtureProvisioner) Deprovision(_ bool) (result provisioner.Result, err
 		p.log.Info("clearing hardware details")
 		p.state.customDeploy = nil
 		result.Dirty = true
-		return result, nil
+		return result, false, nil
+	}
+
+	if data.EraseDisks && !data.ErasureStarted {
+		p.publisher("DiskErasureStarted", "Disk erasure started")
+		result.Dirty = true
+		return result, true, nil
 	}
 
 	p.publisher("DeprovisionComplete", "Image deprovisioning completed")
-	return result, nil
+	return result, false, nil
 }
 
 // Delete removes the host from the provisioning system. It may be
 // called multiple times, and should return true for its dirty flag
 // until the deprovisioning operation is completed.
-func (p *fixtureProvisioner) Delete() (result provisioner.Result, err error) {
+func (p *fixtureProvisioner) Delete(_ context.Context) (result provisioner.Result, err error) {
 	p.log.Info("deleting host")
 
 	if !p.state.Deleted {
@@ -302,13 +358,13 @@ func (p *fixtureProvisioner) Delete() (result provisioner.Result, err error) {
 // for the target system.  It may be called multiple times,
 // and should return true for its dirty  flag until the
 // deletion operation is completed.
-func (p *fixtureProvisioner) Detach() (result provisioner.Result, err error) {
-	return p.Delete()
+func (p *fixtureProvisioner) Detach(ctx context.Context) (result provisioner.Result, err error) {
+	return p.Delete(ctx)
 }
 
 // PowerOn ensures the server is powered on independently of any image
 // provisioning operation.
-func (p *fixtureProvisioner) PowerOn(_ bool) (result provisioner.Result, err error) {
+func (p *fixtureProvisioner) PowerOn(_ context.Context, _ bool) (result provisioner.Result, err error) {
 	p.log.Info("ensuring host is powered on")
 
 	if !p.state.poweredOn {
@@ -324,7 +380,7 @@ func (p *fixtureProvisioner) PowerOn(_ bool) (result provisioner.Result, err err
 
 // PowerOff ensures the server is powered off independently of any image
 // provisioning operation.
-func (p *fixtureProvisioner) PowerOff(_ metal3api.RebootMode, _ bool) (result provisioner.Result, err error) {
+func (p *fixtureProvisioner) PowerOff(_ context.Context, _ metal3api.RebootMode, _ bool) (result provisioner.Result, err error) {
 	p.log.Info("ensuring host is powered off")
 
 	if p.state.poweredOn {
@@ -339,7 +395,7 @@ func (p *fixtureProvisioner) PowerOff(_ metal3api.RebootMode, _ bool) (result pr
 }
 
 // TryInit returns the current availability status of the provisioner.
-func (p *fixtureProvisioner) TryInit() (result bool, err error) {
+func (p *fixtureProvisioner) TryInit(_ context.Context) (result bool, err error) {
 	p.log.Info("checking provisioner status")
 
 	if p.state.BecomeReadyCounter > 0 {
@@ -349,32 +405,32 @@ func (p *fixtureProvisioner) TryInit() (result bool, err error) {
 	return p.state.BecomeReadyCounter == 0, nil
 }
 
-func (p *fixtureProvisioner) GetFirmwareSettings(_ bool) (settings metal3api.SettingsMap, schema map[string]metal3api.SettingSchema, err error) {
+func (p *fixtureProvisioner) GetFirmwareSettings(_ context.Context, _ bool) (settings metal3api.SettingsMap, schema map[string]metal3api.SettingSchema, err error) {
 	p.log.Info("getting BIOS settings")
 	return p.state.HostFirmwareSettings.Settings, p.state.HostFirmwareSettings.Schema, nil
 }
 
-func (p *fixtureProvisioner) AddBMCEventSubscriptionForNode(_ *metal3api.BMCEventSubscription, _ provisioner.HTTPHeaders) (result provisioner.Result, err error) {
+func (p *fixtureProvisioner) AddBMCEventSubscriptionForNode(_ context.Context, _ *metal3api.BMCEventSubscription, _ provisioner.HTTPHeaders) (result provisioner.Result, err error) {
 	return result, nil
 }
 
-func (p *fixtureProvisioner) RemoveBMCEventSubscriptionForNode(_ metal3api.BMCEventSubscription) (result provisioner.Result, err error) {
+func (p *fixtureProvisioner) RemoveBMCEventSubscriptionForNode(_ context.Context, _ metal3api.BMCEventSubscription) (result provisioner.Result, err error) {
 	return result, nil
 }
 
-func (p *fixtureProvisioner) GetFirmwareComponents() (components []metal3api.FirmwareComponentStatus, err error) {
+func (p *fixtureProvisioner) GetFirmwareComponents(_ context.Context) (components []metal3api.FirmwareComponentStatus, err error) {
 	p.log.Info("getting Firmware components")
 	return p.state.HostFirmwareComponents.Components, nil
 }
 
-func (p *fixtureProvisioner) IsDataImageReady() (isNodeBusy bool, nodeError error) {
+func (p *fixtureProvisioner) IsDataImageReady(_ context.Context) (isNodeBusy bool, nodeError error) {
 	return false, nil
 }
 
-func (p *fixtureProvisioner) AttachDataImage(_ string) (err error) {
+func (p *fixtureProvisioner) AttachDataImage(_ context.Context, _ string) (err error) {
 	return nil
 }
 
-func (p *fixtureProvisioner) DetachDataImage() (err error) {
+func (p *fixtureProvisioner) DetachDataImage(_ context.Context) (err error) {
 	return nil
 }
@@ -1,6 +1,7 @@
 package ironic
 
 import (
+	"context"
 	"testing"
 
 	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
@@ -108,7 +109,7 @@ func TestGetFirmwareSettings(t *testing.T) {
 				t.Fatalf("could not create provisioner: %s", err)
 			}
 
-			settingsMap, schemaMap, err := prov.GetFirmwareSettings(tc.includeSchema)
+			settingsMap, schemaMap, err := prov.GetFirmwareSettings(context.TODO(), tc.includeSchema)
 
 			assert.Equal(t, tc.expectedSettingsMap, settingsMap)
 			assert.Equal(t, tc.expectedSchemaMap, schemaMap)
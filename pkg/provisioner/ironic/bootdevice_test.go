@@ -0,0 +1,78 @@
+package ironic
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/nodes"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/hardwareutils/bmc"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/clients"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetBootDevice(t *testing.T) {
+	nodeUUID := "33ce8659-7400-4c68-9535-d10766f07a58"
+	cases := []struct {
+		name          string
+		code          int
+		expectedDirty bool
+		expectedError bool
+		expectedFail  bool
+	}{
+		{
+			name: "success",
+			code: http.StatusNoContent,
+		},
+		{
+			name:          "node locked",
+			code:          http.StatusConflict,
+			expectedDirty: true,
+		},
+		{
+			name:         "invalid device",
+			code:         http.StatusBadRequest,
+			expectedFail: true,
+		},
+		{
+			name:          "ironic error",
+			code:          http.StatusInternalServerError,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ironic := testserver.NewIronic(t).Node(nodes.Node{
+				ProvisionState: string(nodes.Active),
+				UUID:           nodeUUID,
+			}).WithNodeBootDevice(nodeUUID, tc.code)
+			ironic.Start()
+			defer ironic.Stop()
+
+			host := makeHost()
+			host.Status.Provisioning.ID = nodeUUID
+			publisher := func(reason, message string) {}
+			auth := clients.AuthConfig{Type: clients.NoAuth}
+			prov, err := newProvisionerWithSettings(host, bmc.Credentials{}, publisher, ironic.Endpoint(), auth)
+			if err != nil {
+				t.Fatalf("could not create provisioner: %s", err)
+			}
+
+			result, err := prov.SetBootDevice(context.TODO(), provisioner.BootDeviceData{
+				Device: metal3api.BootDevicePXE,
+			})
+
+			assert.Equal(t, tc.expectedError, err != nil)
+			assert.Equal(t, tc.expectedDirty, result.Dirty)
+			assert.Equal(t, tc.expectedFail, result.ErrorMessage != "")
+
+			body, found := ironic.GetLastRequestFor("/v1/nodes/"+nodeUUID+"/management/boot_device", http.MethodPut)
+			assert.True(t, found)
+			assert.JSONEq(t, `{"boot_device": "pxe", "persistent": false}`, body)
+		})
+	}
+}
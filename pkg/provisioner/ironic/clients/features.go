@@ -43,6 +43,7 @@ func (af AvailableFeatures) Log(logger logr.Logger) {
 		"maxVersion", fmt.Sprintf("1.%d", af.MaxVersion),
 		"chosenVersion", af.ChooseMicroversion(),
 		"firmwareUpdates", af.HasFirmwareUpdates(),
+		"servicing", af.HasServicing(),
 		"dataImage", af.HasDataImage())
 }
 
@@ -50,6 +51,10 @@ func (af AvailableFeatures) HasFirmwareUpdates() bool {
 	return af.MaxVersion >= 86
 }
 
+func (af AvailableFeatures) HasServicing() bool {
+	return af.MaxVersion >= 87
+}
+
 func (af AvailableFeatures) HasDataImage() bool {
 	return af.MaxVersion >= 89
 }
@@ -59,6 +64,10 @@ func (af AvailableFeatures) ChooseMicroversion() string {
 		return "1.89"
 	}
 
+	if af.HasServicing() {
+		return "1.87"
+	}
+
 	if af.HasFirmwareUpdates() {
 		return "1.86"
 	}
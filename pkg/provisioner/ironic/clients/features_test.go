@@ -92,3 +92,47 @@ func TestAvailableFeatures_HasFirmwareUpdates(t *testing.T) {
 		})
 	}
 }
+
+func TestAvailableFeatures_HasServicing(t *testing.T) {
+	maxVersion := 87
+	type fields struct {
+		MaxVersion int
+	}
+	tests := []struct {
+		name    string
+		feature fields
+		want    bool
+	}{
+		{
+			name: fmt.Sprintf("Servicing < %d", maxVersion),
+			feature: fields{
+				MaxVersion: 86,
+			},
+			want: false,
+		},
+		{
+			name: fmt.Sprintf("Servicing = %d", maxVersion),
+			feature: fields{
+				MaxVersion: 87,
+			},
+			want: true,
+		},
+		{
+			name: fmt.Sprintf("Servicing > %d", maxVersion),
+			feature: fields{
+				MaxVersion: 100,
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			af := AvailableFeatures{
+				MaxVersion: tt.feature.MaxVersion,
+			}
+			if got := af.HasServicing(); got != tt.want {
+				t.Errorf("HasServicing() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
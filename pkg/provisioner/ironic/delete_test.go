@@ -1,6 +1,7 @@
 package ironic
 
 import (
+	"context"
 	"net/http"
 	"testing"
 	"time"
@@ -190,9 +191,9 @@ func deleteTest(t *testing.T, detach bool) {
 
 			var result provisioner.Result
 			if detach {
-				result, err = prov.Detach()
+				result, err = prov.Detach(context.TODO())
 			} else {
-				result, err = prov.Delete()
+				result, err = prov.Delete(context.TODO())
 			}
 
 			assert.Equal(t, tc.expectedDirty, result.Dirty)
@@ -2,17 +2,41 @@ package ironic
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/drivers"
 	"github.com/gophercloud/gophercloud/v2/pagination"
 	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/clients"
 )
 
-// TryInit checks if the provisioning backend is available.
-func (p *ironicProvisioner) TryInit() (ready bool, err error) {
+// TryInit checks if the provisioning backend is available. When the API
+// service of the assigned endpoint is unavailable, its standbys are tried
+// in turn.
+func (p *ironicProvisioner) TryInit(ctx context.Context) (ready bool, err error) {
+	ready, err = p.checkEndpoint(ctx)
+	for attempt := 1; !ready && err == nil && attempt < len(p.endpoint.clients); attempt++ {
+		failed := p.client
+		p.client = p.endpoint.failover(failed)
+		p.log.Info("failing over to standby ironic endpoint",
+			"endpointName", p.endpoint.name, "failed", failed.Endpoint, "standby", p.client.Endpoint)
+		if p.publisher != nil {
+			p.publisher("IronicEndpointFailover",
+				fmt.Sprintf("Ironic endpoint %s failed over to %s", p.endpoint.name, p.client.Endpoint))
+		}
+		ready, err = p.checkEndpoint(ctx)
+	}
+	return ready, err
+}
+
+// Endpoint returns the name of the ironic endpoint the host is assigned to.
+func (p *ironicProvisioner) Endpoint() string {
+	return p.endpoint.name
+}
+
+func (p *ironicProvisioner) checkEndpoint(ctx context.Context) (ready bool, err error) {
 	p.debugLog.Info("verifying ironic provisioner dependencies")
 
-	p.availableFeatures, err = clients.GetAvailableFeatures(p.ctx, p.client)
+	p.availableFeatures, err = clients.GetAvailableFeatures(ctx, p.client)
 	if err != nil {
 		p.log.Info("error caught while checking endpoint, will retry", "endpoint", p.client.Endpoint, "error", err)
 		return false, nil
@@ -21,10 +45,10 @@ func (p *ironicProvisioner) TryInit() (ready bool, err error) {
 	p.client.Microversion = p.availableFeatures.ChooseMicroversion()
 	p.availableFeatures.Log(p.debugLog)
 
-	return p.checkIronicConductor()
+	return p.checkIronicConductor(ctx)
 }
 
-func (p *ironicProvisioner) checkIronicConductor() (ready bool, err error) {
+func (p *ironicProvisioner) checkIronicConductor(ctx context.Context) (ready bool, err error) {
 	pager := drivers.ListDrivers(p.client, drivers.ListDriversOpts{
 		Detail: false,
 	})
@@ -35,7 +59,7 @@ func (p *ironicProvisioner) checkIronicConductor() (ready bool, err error) {
 	}
 
 	driverCount := 0
-	_ = pager.EachPage(p.ctx, func(_ context.Context, page pagination.Page) (bool, error) {
+	_ = pager.EachPage(ctx, func(_ context.Context, page pagination.Page) (bool, error) {
 		actual, driverErr := drivers.ExtractDrivers(page)
 		if driverErr != nil {
 			return false, driverErr
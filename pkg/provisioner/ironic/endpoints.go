@@ -0,0 +1,242 @@
+package ironic
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/clients"
+)
+
+// defaultEndpointName is the name of the endpoint configured through
+// IRONIC_ENDPOINT.
+const defaultEndpointName = "default"
+
+// endpointConfig holds the Ironic endpoints read from the environment.
+type endpointConfig struct {
+	// urls maps each endpoint name to its primary URL, followed by
+	// the URLs of its standbys.
+	urls map[string][]string
+	// namespaces maps a namespace to the endpoint serving its hosts.
+	namespaces map[string]string
+}
+
+// ironicEndpoint is a named Ironic instance. The standbys of an
+// endpoint are further API services in front of the same Ironic
+// database, so that a host may be moved between them at any time.
+type ironicEndpoint struct {
+	name    string
+	clients []*gophercloud.ServiceClient
+	// nodes is the cached view of the nodes of the endpoint, or nil if
+	// caching is disabled.
+	nodes *nodeCache
+
+	mu     sync.Mutex
+	active int
+}
+
+// client returns the client for the API service currently in use.
+func (e *ironicEndpoint) client() *gophercloud.ServiceClient {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.clients[e.active]
+}
+
+// failover switches away from the failed client, unless another
+// provisioner already did, and returns the client to use instead.
+func (e *ironicEndpoint) failover(failed *gophercloud.ServiceClient) *gophercloud.ServiceClient {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.clients[e.active] == failed {
+		e.active = (e.active + 1) % len(e.clients)
+	}
+	return e.clients[e.active]
+}
+
+// endpointSet assigns hosts to the configured Ironic endpoints.
+type endpointSet struct {
+	endpoints map[string]*ironicEndpoint
+	// names is the sorted list of endpoint names, used for hashing.
+	names      []string
+	namespaces map[string]string
+}
+
+func newEndpointSet(config endpointConfig, auth clients.AuthConfig, tlsConf clients.TLSConfig, cacheInterval time.Duration) (*endpointSet, error) {
+	set := &endpointSet{
+		endpoints:  map[string]*ironicEndpoint{},
+		namespaces: config.namespaces,
+	}
+	for name, urls := range config.urls {
+		endpoint := &ironicEndpoint{name: name}
+		if cacheInterval > 0 {
+			endpoint.nodes = newNodeCache(name, cacheInterval)
+		}
+		for _, url := range urls {
+			client, err := clients.IronicClient(url, auth, tlsConf)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create client for ironic endpoint %s: %w", name, err)
+			}
+			endpoint.clients = append(endpoint.clients, client)
+		}
+		set.add(endpoint)
+	}
+	return set, nil
+}
+
+// newSingleEndpointSet returns a set made of a single endpoint without
+// standbys.
+func newSingleEndpointSet(client *gophercloud.ServiceClient) *endpointSet {
+	set := &endpointSet{endpoints: map[string]*ironicEndpoint{}}
+	set.add(&ironicEndpoint{
+		name:    defaultEndpointName,
+		clients: []*gophercloud.ServiceClient{client},
+	})
+	return set
+}
+
+func (s *endpointSet) add(endpoint *ironicEndpoint) {
+	s.endpoints[endpoint.name] = endpoint
+	s.names = append(s.names, endpoint.name)
+	sort.Strings(s.names)
+}
+
+// assign returns the endpoint serving the host. A host registered with
+// an endpoint stays with it, as its node only exists there, and cannot
+// be served once that endpoint is removed. Otherwise
+// the endpoint named in the host's label wins over the one configured
+// for its namespace, and the remaining hosts are spread over all
+// endpoints by hashing their name. Hosts registered before endpoints
+// were recorded belong to the default endpoint.
+func (s *endpointSet) assign(hostData provisioner.HostData) (*ironicEndpoint, error) {
+	if hostData.ProvisionerID != "" {
+		if hostData.Endpoint == "" {
+			endpoint, ok := s.endpoints[defaultEndpointName]
+			if !ok {
+				return nil, provisioner.UnknownEndpointError{
+					Endpoint: defaultEndpointName,
+					Reason: fmt.Sprintf("host was registered with the former IRONIC_ENDPOINT, but no ironic endpoint named %q is configured in IRONIC_ENDPOINTS",
+						defaultEndpointName),
+				}
+			}
+			return endpoint, nil
+		}
+		endpoint, ok := s.endpoints[hostData.Endpoint]
+		if !ok {
+			return nil, provisioner.UnknownEndpointError{
+				Endpoint: hostData.Endpoint,
+				Reason: fmt.Sprintf("host was registered with ironic endpoint %q, which is no longer configured in IRONIC_ENDPOINTS",
+					hostData.Endpoint),
+			}
+		}
+		return endpoint, nil
+	}
+
+	if name, ok := hostData.ObjectMeta.Labels[metal3api.IronicEndpointLabel]; ok {
+		endpoint, ok := s.endpoints[name]
+		if !ok {
+			return nil, provisioner.UnknownEndpointError{
+				Endpoint: name,
+				Reason: fmt.Sprintf("ironic endpoint %q requested by label %s is not configured in IRONIC_ENDPOINTS",
+					name, metal3api.IronicEndpointLabel),
+			}
+		}
+		return endpoint, nil
+	}
+
+	if name, ok := s.namespaces[hostData.ObjectMeta.Namespace]; ok {
+		return s.endpoints[name], nil
+	}
+
+	return s.endpoints[s.hash(ironicNodeName(hostData.ObjectMeta))], nil
+}
+
+// hash picks an endpoint for the key by rendezvous hashing, so that
+// adding or removing an endpoint only moves the hosts assigned to it.
+func (s *endpointSet) hash(key string) (chosen string) {
+	var highest uint64
+	for _, name := range s.names {
+		sum := sha256.Sum256([]byte(name + "\x00" + key))
+		if weight := binary.BigEndian.Uint64(sum[:8]); chosen == "" || weight > highest {
+			chosen, highest = name, weight
+		}
+	}
+	return chosen
+}
+
+// loadEndpointsFromEnv reads either the named endpoints in
+// IRONIC_ENDPOINTS, formatted as name=url[|standby-url...] separated by
+// commas, or the single endpoint in IRONIC_ENDPOINT. The namespaces
+// served by an endpoint are listed in IRONIC_ENDPOINT_NAMESPACES as
+// namespace=name separated by commas.
+func loadEndpointsFromEnv() (config endpointConfig, err error) {
+	config.urls, err = parseEndpointList(os.Getenv("IRONIC_ENDPOINTS"))
+	if err != nil {
+		return config, err
+	}
+
+	if len(config.urls) == 0 {
+		ironicEndpoint := os.Getenv("IRONIC_ENDPOINT")
+		if ironicEndpoint == "" {
+			return config, errors.New("no IRONIC_ENDPOINT or IRONIC_ENDPOINTS variable set")
+		}
+		config.urls = map[string][]string{defaultEndpointName: {ironicEndpoint}}
+	}
+
+	config.namespaces, err = parseKeyValueList(os.Getenv("IRONIC_ENDPOINT_NAMESPACES"))
+	if err != nil {
+		return config, fmt.Errorf("invalid value set for variable IRONIC_ENDPOINT_NAMESPACES: %w", err)
+	}
+	for namespace, name := range config.namespaces {
+		if _, ok := config.urls[name]; !ok {
+			return config, fmt.Errorf("ironic endpoint %q configured for namespace %s is not defined in IRONIC_ENDPOINTS", name, namespace)
+		}
+	}
+
+	return config, nil
+}
+
+func parseEndpointList(value string) (map[string][]string, error) {
+	endpoints, err := parseKeyValueList(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value set for variable IRONIC_ENDPOINTS: %w", err)
+	}
+
+	urls := map[string][]string{}
+	for name, list := range endpoints {
+		for _, url := range strings.Split(list, "|") {
+			if url = strings.TrimSpace(url); url == "" {
+				return nil, fmt.Errorf("invalid value set for variable IRONIC_ENDPOINTS: empty URL for endpoint %s", name)
+			}
+			urls[name] = append(urls[name], url)
+		}
+	}
+	return urls, nil
+}
+
+func parseKeyValueList(value string) (map[string]string, error) {
+	result := map[string]string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		key, val, found := strings.Cut(item, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("expected key=value, got %q", item)
+		}
+		if _, ok := result[key]; ok {
+			return nil, fmt.Errorf("%q is listed more than once", key)
+		}
+		result[key] = strings.TrimSpace(val)
+	}
+	return result, nil
+}
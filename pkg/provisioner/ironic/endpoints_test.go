@@ -0,0 +1,205 @@
+package ironic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/hardwareutils/bmc"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/clients"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeTestEndpointSet(t *testing.T, names ...string) *endpointSet {
+	t.Helper()
+	config := endpointConfig{
+		urls:       map[string][]string{},
+		namespaces: map[string]string{"rack1": "west"},
+	}
+	for _, name := range names {
+		config.urls[name] = []string{fmt.Sprintf("http://%s.test/", name)}
+	}
+	set, err := newEndpointSet(config, clients.AuthConfig{Type: clients.NoAuth}, clients.TLSConfig{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return set
+}
+
+func TestEndpointAssignment(t *testing.T) {
+	set := makeTestEndpointSet(t, "east", "west", "north")
+
+	cases := []struct {
+		name          string
+		namespace     string
+		label         string
+		provisionerID string
+		endpoint      string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "label",
+			label:    "east",
+			expected: "east",
+		},
+		{
+			name:      "label over namespace",
+			namespace: "rack1",
+			label:     "north",
+			expected:  "north",
+		},
+		{
+			name:      "namespace",
+			namespace: "rack1",
+			expected:  "west",
+		},
+		{
+			name:          "registered host stays",
+			namespace:     "rack1",
+			label:         "north",
+			provisionerID: "uuid",
+			endpoint:      "east",
+			expected:      "east",
+		},
+		{
+			name:      "unregistered host is reassigned",
+			namespace: "rack1",
+			endpoint:  "east",
+			expected:  "west",
+		},
+		{
+			name:          "registered host on a removed endpoint",
+			namespace:     "rack1",
+			provisionerID: "uuid",
+			endpoint:      "south",
+			expectedError: `host was registered with ironic endpoint "south", which is no longer configured in IRONIC_ENDPOINTS`,
+		},
+		{
+			name:          "host registered before endpoints were recorded",
+			namespace:     "rack1",
+			provisionerID: "uuid",
+			expectedError: `host was registered with the former IRONIC_ENDPOINT, but no ironic endpoint named "default" is configured in IRONIC_ENDPOINTS`,
+		},
+		{
+			name:          "unknown label",
+			label:         "south",
+			expectedError: `ironic endpoint "south" requested by label baremetalhost.metal3.io/ironic-endpoint is not configured in IRONIC_ENDPOINTS`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			host := makeHost()
+			if tc.namespace != "" {
+				host.Namespace = tc.namespace
+			}
+			if tc.label != "" {
+				host.Labels = map[string]string{metal3api.IronicEndpointLabel: tc.label}
+			}
+			hostData := provisioner.BuildHostData(host, bmc.Credentials{})
+			hostData.ProvisionerID = tc.provisionerID
+			hostData.Endpoint = tc.endpoint
+
+			endpoint, err := set.assign(hostData)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.ErrorAs(t, err, &provisioner.UnknownEndpointError{})
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, endpoint.name)
+		})
+	}
+}
+
+func TestEndpointAssignmentUpgrade(t *testing.T) {
+	set := makeTestEndpointSet(t, defaultEndpointName, "east", "west")
+
+	host := makeHost()
+	host.Labels = map[string]string{metal3api.IronicEndpointLabel: "east"}
+	hostData := provisioner.BuildHostData(host, bmc.Credentials{})
+	hostData.ProvisionerID = "uuid"
+
+	endpoint, err := set.assign(hostData)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultEndpointName, endpoint.name)
+}
+
+func TestEndpointHashing(t *testing.T) {
+	set := makeTestEndpointSet(t, "east", "west", "north")
+	smaller := makeTestEndpointSet(t, "east", "west")
+
+	counts := map[string]int{}
+	for i := range 300 {
+		key := fmt.Sprintf("ns~host-%d", i)
+		name := set.hash(key)
+		counts[name]++
+
+		assert.Equal(t, name, set.hash(key), "assignment must be stable")
+		if name != "north" {
+			assert.Equal(t, name, smaller.hash(key), "removing an endpoint must only move its own hosts")
+		}
+	}
+
+	for _, name := range set.names {
+		assert.Greater(t, counts[name], 50, "hosts should be spread over %s", name)
+	}
+}
+
+func TestEndpointFailover(t *testing.T) {
+	primary := testserver.NewIronic(t).NotReady(http.StatusServiceUnavailable)
+	primary.Start()
+	defer primary.Stop()
+	standby := testserver.NewIronic(t).WithDrivers()
+	standby.Start()
+	defer standby.Stop()
+
+	auth := clients.AuthConfig{Type: clients.NoAuth}
+	set, err := newEndpointSet(endpointConfig{
+		urls: map[string][]string{"east": {primary.Endpoint(), standby.Endpoint()}},
+	}, auth, clients.TLSConfig{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	factory := newTestProvisionerFactory()
+	factory.endpoints = set
+	host := makeHost()
+	host.Status.Provisioning.Endpoint = "east"
+
+	var events []string
+	publisher := func(reason, _ string) { events = append(events, reason) }
+
+	prov, err := factory.ironicProvisioner(provisioner.BuildHostData(host, bmc.Credentials{}), publisher)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ready, err := prov.TryInit(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, ready)
+	assert.Equal(t, "east", prov.Endpoint())
+	assert.Equal(t, []string{"IronicEndpointFailover"}, events)
+	assert.Equal(t, "/v1/;", primary.Requests)
+	assert.Equal(t, "/v1/;/v1/drivers;", standby.Requests)
+
+	// Later provisioners start with the standby.
+	prov, err = factory.ironicProvisioner(provisioner.BuildHostData(host, bmc.Credentials{}), publisher)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ready, err = prov.TryInit(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, ready)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "/v1/;", primary.Requests)
+}
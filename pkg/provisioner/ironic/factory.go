@@ -8,9 +8,10 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
-	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/nodes"
 	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
 	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/clients"
 )
@@ -19,9 +20,9 @@ type ironicProvisionerFactory struct {
 	log    logr.Logger
 	config ironicConfig
 
-	// Keep pointers to ironic client configured with the global
-	// auth settings to reuse the connection between reconcilers.
-	clientIronic *gophercloud.ServiceClient
+	// Keep pointers to ironic clients configured with the global
+	// auth settings to reuse the connections between reconcilers.
+	endpoints *endpointSet
 }
 
 func NewProvisionerFactory(logger logr.Logger, havePreprovImgBuilder bool) provisioner.Factory {
@@ -48,7 +49,7 @@ func (f *ironicProvisionerFactory) init(havePreprovImgBuilder bool) error {
 		return err
 	}
 
-	ironicEndpoint, err := loadEndpointsFromEnv()
+	endpointConf, err := loadEndpointsFromEnv()
 	if err != nil {
 		return err
 	}
@@ -56,12 +57,14 @@ func (f *ironicProvisionerFactory) init(havePreprovImgBuilder bool) error {
 	tlsConf := loadTLSConfigFromEnv()
 
 	f.log.Info("ironic settings",
-		"endpoint", ironicEndpoint,
+		"endpoints", endpointConf.urls,
+		"endpointNamespaces", endpointConf.namespaces,
 		"ironicAuthType", ironicAuth.Type,
 		"deployKernelURL", f.config.deployKernelURL,
 		"deployRamdiskURL", f.config.deployRamdiskURL,
 		"deployISOURL", f.config.deployISOURL,
 		"liveISOForcePersistentBootDevice", f.config.liveISOForcePersistentBootDevice,
+		"nodeCacheInterval", f.config.nodeCacheInterval,
 		"CACertFile", tlsConf.TrustedCAFile,
 		"ClientCertFile", tlsConf.ClientCertificateFile,
 		"ClientPrivKeyFile", tlsConf.ClientPrivateKeyFile,
@@ -69,8 +72,7 @@ func (f *ironicProvisionerFactory) init(havePreprovImgBuilder bool) error {
 		"SkipClientSANVerify", tlsConf.SkipClientSANVerify,
 	)
 
-	f.clientIronic, err = clients.IronicClient(
-		ironicEndpoint, ironicAuth, tlsConf)
+	f.endpoints, err = newEndpointSet(endpointConf, ironicAuth, tlsConf, f.config.nodeCacheInterval)
 	if err != nil {
 		return err
 	}
@@ -78,7 +80,12 @@ func (f *ironicProvisionerFactory) init(havePreprovImgBuilder bool) error {
 	return nil
 }
 
-func (f ironicProvisionerFactory) ironicProvisioner(ctx context.Context, hostData provisioner.HostData, publisher provisioner.EventPublisher) (*ironicProvisioner, error) {
+func (f ironicProvisionerFactory) ironicProvisioner(hostData provisioner.HostData, publisher provisioner.EventPublisher) (*ironicProvisioner, error) {
+	endpoint, err := f.endpoints.assign(hostData)
+	if err != nil {
+		return nil, err
+	}
+
 	provisionerLogger := f.log.WithValues("host", ironicNodeName(hostData.ObjectMeta))
 
 	p := &ironicProvisioner{
@@ -89,11 +96,11 @@ func (f ironicProvisionerFactory) ironicProvisioner(ctx context.Context, hostDat
 		bmcAddress:              hostData.BMCAddress,
 		disableCertVerification: hostData.DisableCertificateVerification,
 		bootMACAddress:          hostData.BootMACAddress,
-		client:                  f.clientIronic,
+		endpoint:                endpoint,
+		client:                  endpoint.client(),
 		log:                     provisionerLogger,
 		debugLog:                provisionerLogger.V(1),
 		publisher:               publisher,
-		ctx:                     ctx,
 	}
 
 	return p, nil
@@ -101,8 +108,39 @@ func (f ironicProvisionerFactory) ironicProvisioner(ctx context.Context, hostDat
 
 // NewProvisioner returns a new Ironic Provisioner using the global
 // configuration for finding the Ironic services.
-func (f ironicProvisionerFactory) NewProvisioner(ctx context.Context, hostData provisioner.HostData, publisher provisioner.EventPublisher) (provisioner.Provisioner, error) {
-	return f.ironicProvisioner(ctx, hostData, publisher)
+func (f ironicProvisionerFactory) NewProvisioner(_ context.Context, hostData provisioner.HostData, publisher provisioner.EventPublisher) (provisioner.Provisioner, error) {
+	return f.ironicProvisioner(hostData, publisher)
+}
+
+// PowerStates reads the power state of the nodes of all the ironic
+// endpoints.
+func (f ironicProvisionerFactory) PowerStates(ctx context.Context) (map[string]bool, error) {
+	states := map[string]bool{}
+	var errs []error
+	for _, name := range f.endpoints.names {
+		endpoint := f.endpoints.endpoints[name]
+		var endpointStates map[string]bool
+		var err error
+		if endpoint.nodes != nil {
+			endpointStates, err = endpoint.nodes.powerStates(ctx, endpoint.client())
+		} else {
+			var allNodes []nodes.Node
+			allNodes, err = listNodes(ctx, endpoint.client())
+			endpointStates = powerStates(allNodes)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list nodes of ironic endpoint %s: %w", name, err))
+		}
+		for uuid, poweredOn := range endpointStates {
+			states[uuid] = poweredOn
+		}
+	}
+	return states, errors.Join(errs...)
+}
+
+// PollsPowerState returns true, as PowerStates covers all nodes.
+func (f ironicProvisionerFactory) PollsPowerState(_ provisioner.HostData) bool {
+	return true
 }
 
 func loadConfigFromEnv(havePreprovImgBuilder bool) (ironicConfig, error) {
@@ -143,6 +181,15 @@ func loadConfigFromEnv(havePreprovImgBuilder bool) (ironicConfig, error) {
 		c.liveISOForcePersistentBootDevice = forcePersistentBootDevice
 	}
 
+	c.nodeCacheInterval = 10 * time.Second
+	if intervalStr := os.Getenv("IRONIC_NODE_CACHE_INTERVAL"); intervalStr != "" {
+		value, err := time.ParseDuration(intervalStr)
+		if err != nil || value < 0 {
+			return c, fmt.Errorf("invalid value set for variable IRONIC_NODE_CACHE_INTERVAL=%s", intervalStr)
+		}
+		c.nodeCacheInterval = value
+	}
+
 	c.externalURL = os.Getenv("IRONIC_EXTERNAL_URL_V6")
 
 	// Let's see if externalURL looks like a URL
@@ -157,14 +204,6 @@ func loadConfigFromEnv(havePreprovImgBuilder bool) (ironicConfig, error) {
 	return c, nil
 }
 
-func loadEndpointsFromEnv() (ironicEndpoint string, err error) {
-	ironicEndpoint = os.Getenv("IRONIC_ENDPOINT")
-	if ironicEndpoint == "" {
-		err = errors.New("no IRONIC_ENDPOINT variable set")
-	}
-	return
-}
-
 func loadTLSConfigFromEnv() clients.TLSConfig {
 	ironicCACertFile := os.Getenv("IRONIC_CACERT_FILE")
 	if ironicCACertFile == "" {
@@ -9,6 +9,8 @@ import (
 
 type EnvFixture struct {
 	ironicEndpoint                   string
+	ironicEndpoints                  string
+	ironicEndpointNamespaces         string
 	kernelURL                        string
 	ramdiskURL                       string
 	isoURL                           string
@@ -39,6 +41,8 @@ func (f *EnvFixture) replace(env, value string) {
 func (f *EnvFixture) SetUp() {
 	f.origEnv = map[string]string{}
 	f.replace("IRONIC_ENDPOINT", f.ironicEndpoint)
+	f.replace("IRONIC_ENDPOINTS", f.ironicEndpoints)
+	f.replace("IRONIC_ENDPOINT_NAMESPACES", f.ironicEndpointNamespaces)
 	f.replace("DEPLOY_KERNEL_URL", f.kernelURL)
 	f.replace("DEPLOY_RAMDISK_URL", f.ramdiskURL)
 	f.replace("DEPLOY_ISO_URL", f.isoURL)
@@ -53,11 +57,6 @@ func (f EnvFixture) VerifyConfig(t *testing.T, c ironicConfig, _ string) {
 	assert.Equal(t, f.liveISOForcePersistentBootDevice, c.liveISOForcePersistentBootDevice)
 }
 
-func (f EnvFixture) VerifyEndpoints(t *testing.T, ironic string) {
-	t.Helper()
-	assert.Equal(t, f.ironicEndpoint, ironic)
-}
-
 func TestLoadConfigFromEnv(t *testing.T) {
 	cases := []struct {
 		name                  string
@@ -183,19 +182,59 @@ func TestLoadConfigFromEnv(t *testing.T) {
 
 func TestLoadEndpointsFromEnv(t *testing.T) {
 	cases := []struct {
-		name        string
-		env         EnvFixture
-		expectError bool
+		name               string
+		env                EnvFixture
+		expectedURLs       map[string][]string
+		expectedNamespaces map[string]string
+		expectedError      string
 	}{
 		{
 			name: "with-ironic",
 			env: EnvFixture{
 				ironicEndpoint: "http://ironic.test",
 			},
+			expectedURLs: map[string][]string{"default": {"http://ironic.test"}},
 		}, {
-			name:        "without-ironic",
-			env:         EnvFixture{},
-			expectError: true,
+			name:          "without-ironic",
+			env:           EnvFixture{},
+			expectedError: "no IRONIC_ENDPOINT or IRONIC_ENDPOINTS variable set",
+		}, {
+			name: "named",
+			env: EnvFixture{
+				ironicEndpoint:           "http://ironic.test",
+				ironicEndpoints:          "east=http://east-0.test|http://east-1.test, west=http://west.test",
+				ironicEndpointNamespaces: "rack1=east,rack2=west",
+			},
+			expectedURLs: map[string][]string{
+				"east": {"http://east-0.test", "http://east-1.test"},
+				"west": {"http://west.test"},
+			},
+			expectedNamespaces: map[string]string{"rack1": "east", "rack2": "west"},
+		}, {
+			name: "missing-name",
+			env: EnvFixture{
+				ironicEndpoints: "http://east.test",
+			},
+			expectedError: "invalid value set for variable IRONIC_ENDPOINTS",
+		}, {
+			name: "empty-standby",
+			env: EnvFixture{
+				ironicEndpoints: "east=http://east.test|",
+			},
+			expectedError: "empty URL for endpoint east",
+		}, {
+			name: "duplicate-name",
+			env: EnvFixture{
+				ironicEndpoints: "east=http://east.test,east=http://west.test",
+			},
+			expectedError: `"east" is listed more than once`,
+		}, {
+			name: "unknown-namespace-endpoint",
+			env: EnvFixture{
+				ironicEndpoints:          "east=http://east.test",
+				ironicEndpointNamespaces: "rack1=west",
+			},
+			expectedError: `ironic endpoint "west" configured for namespace rack1 is not defined`,
 		},
 	}
 
@@ -203,12 +242,17 @@ func TestLoadEndpointsFromEnv(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			defer tc.env.TearDown()
 			tc.env.SetUp()
-			i, err := loadEndpointsFromEnv()
-			if tc.expectError {
-				assert.NotNil(t, err)
+			config, err := loadEndpointsFromEnv()
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedURLs, config.urls)
+			if tc.expectedNamespaces == nil {
+				assert.Empty(t, config.namespaces)
 			} else {
-				assert.Nil(t, err)
-				tc.env.VerifyEndpoints(t, i)
+				assert.Equal(t, tc.expectedNamespaces, config.namespaces)
 			}
 		})
 	}
@@ -1,6 +1,7 @@
 package ironic
 
 import (
+	"context"
 	"testing"
 
 	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/nodes"
@@ -67,7 +68,7 @@ func TestFindExistingHost(t *testing.T) {
 				t.Fatalf("could not create provisioner: %s", err)
 			}
 
-			node, err := prov.findExistingHost("")
+			node, err := prov.findExistingHost(context.TODO(), "")
 			t.Logf("requests: %s", tc.ironic.Requests)
 			if err != nil {
 				t.Fatalf("could not look up host: %s", err)
@@ -1,9 +1,11 @@
 package hardwaredetails
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/go-logr/logr"
@@ -28,9 +30,160 @@ func GetHardwareDetails(data *nodes.InventoryData, logger logr.Logger) *metal3ap
 	details.Storage = getStorageDetails(data.Inventory.Disks)
 	details.CPU = getCPUDetails(&data.Inventory.CPU)
 	details.Hostname = data.Inventory.Hostname
+
+	var extra *inventory.ExtraDataType
+	if ironicData != nil {
+		extra = &ironicData.Extra
+	} else if inspectorData != nil {
+		extra = &inspectorData.Extra
+	}
+	if extra != nil {
+		details.SystemUUID = getSystemUUID(extra)
+		details.MemoryModules = getMemoryModules(extra)
+	}
+
+	pciDevices, err := getPCIDevices(data.PluginData.RawMessage)
+	if err != nil {
+		logger.Error(err, "cannot get PCI devices from plugin data")
+	}
+	details.PCIDevices = pciDevices
+	details.Accelerators = getAccelerators(pciDevices)
 	return details
 }
 
+// pciDevicesData is the output of the pci-devices collector of the
+// ramdisk, which is not part of the inventory.
+type pciDevicesData struct {
+	PCIDevices []struct {
+		VendorID  string `json:"vendor_id"`
+		ProductID string `json:"product_id"`
+		Class     string `json:"class"`
+		Revision  string `json:"revision"`
+		Bus       string `json:"bus"`
+	} `json:"pci_devices"`
+}
+
+func getPCIDevices(pluginData json.RawMessage) ([]metal3api.PCIDevice, error) {
+	if len(pluginData) == 0 {
+		return nil, nil
+	}
+	var data pciDevicesData
+	if err := json.Unmarshal(pluginData, &data); err != nil {
+		return nil, err
+	}
+	if len(data.PCIDevices) == 0 {
+		return nil, nil
+	}
+
+	devices := make([]metal3api.PCIDevice, len(data.PCIDevices))
+	for i, dev := range data.PCIDevices {
+		devices[i] = metal3api.PCIDevice{
+			Address:   dev.Bus,
+			VendorID:  dev.VendorID,
+			ProductID: dev.ProductID,
+			Class:     dev.Class,
+			Revision:  dev.Revision,
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].Address < devices[j].Address
+	})
+	return devices, nil
+}
+
+// getAccelerators picks the accelerators out of the PCI devices by their
+// class. VGA compatible controllers are skipped, since on servers they
+// are almost always the graphics of the BMC.
+func getAccelerators(devices []metal3api.PCIDevice) (accelerators []metal3api.Accelerator) {
+	for _, dev := range devices {
+		var accelType metal3api.AcceleratorType
+		switch {
+		case strings.HasPrefix(dev.Class, "0302"), strings.HasPrefix(dev.Class, "0380"):
+			// 3D and other display controllers
+			accelType = metal3api.GPU
+		case strings.HasPrefix(dev.Class, "12"), strings.HasPrefix(dev.Class, "0b40"):
+			// Processing accelerators and co-processors
+			accelType = metal3api.ProcessingAccelerator
+		default:
+			continue
+		}
+		accelerators = append(accelerators, metal3api.Accelerator{
+			Type:       accelType,
+			Model:      fmt.Sprintf("0x%s 0x%s", dev.VendorID, dev.ProductID),
+			PCIAddress: dev.Address,
+		})
+	}
+	return
+}
+
+// extraString returns a value collected by the extra-hardware collector
+// of the ramdisk as a string. Values are usually strings, but numbers
+// are accepted as well.
+func extraString(item inventory.ExtraDataItem, key string) string {
+	switch value := item[key].(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(value)
+	}
+	return ""
+}
+
+func extraInt(item inventory.ExtraDataItem, key string) int64 {
+	value, _ := strconv.ParseFloat(extraString(item, key), 64)
+	return int64(value)
+}
+
+func getSystemUUID(extra *inventory.ExtraDataType) string {
+	return extraString(extra.System["product"], "uuid")
+}
+
+// getMemoryModules returns the memory banks found by the extra-hardware
+// collector, in the order of the banks.
+func getMemoryModules(extra *inventory.ExtraDataType) []metal3api.MemoryModule {
+	type bank struct {
+		index  int
+		module metal3api.MemoryModule
+	}
+	var banks []bank
+	for name, item := range extra.Memory {
+		indexStr, found := strings.CutPrefix(name, "bank:")
+		if !found {
+			continue
+		}
+		index, err := strconv.Atoi(indexStr)
+		if err != nil {
+			continue
+		}
+		banks = append(banks, bank{
+			index: index,
+			module: metal3api.MemoryModule{
+				Slot:           extraString(item, "slot"),
+				SizeMebibytes:  int(extraInt(item, "size") / (1024 * 1024)),
+				Description:    extraString(item, "description"),
+				ClockMegahertz: metal3api.ClockSpeed(extraInt(item, "clock")/1000000) * metal3api.MegaHertz,
+				Vendor:         extraString(item, "vendor"),
+				PartNumber:     extraString(item, "product"),
+				SerialNumber:   extraString(item, "serial"),
+			},
+		})
+	}
+	if len(banks) == 0 {
+		return nil
+	}
+
+	sort.Slice(banks, func(i, j int) bool {
+		return banks[i].index < banks[j].index
+	})
+	modules := make([]metal3api.MemoryModule, len(banks))
+	for i := range banks {
+		modules[i] = banks[i].module
+	}
+	return modules
+}
+
 func getVLANs(lldp map[string]interface{}) (vlans []metal3api.VLAN, vlanid metal3api.VLANID) {
 	if lldp == nil {
 		return
@@ -54,6 +207,34 @@ func getVLANs(lldp map[string]interface{}) (vlans []metal3api.VLAN, vlanid metal
 	return
 }
 
+// getLLDP extracts the switch and port the NIC is connected to from the
+// processed LLDP data, which has the same format for the Ironic and the
+// Inspector plugin data.
+func getLLDP(lldp map[string]interface{}) *metal3api.LLDP {
+	if lldp == nil {
+		return nil
+	}
+	result := metal3api.LLDP{}
+	result.SwitchID, _ = lldp["switch_chassis_id"].(string)
+	result.SwitchSystemName, _ = lldp["switch_system_name"].(string)
+	result.PortID, _ = lldp["switch_port_id"].(string)
+	result.PortDescription, _ = lldp["switch_port_description"].(string)
+	switch addresses := lldp["switch_mgmt_addresses"].(type) {
+	case []string:
+		result.SwitchManagementAddresses = addresses
+	case []interface{}:
+		for _, address := range addresses {
+			if address, ok := address.(string); ok {
+				result.SwitchManagementAddresses = append(result.SwitchManagementAddresses, address)
+			}
+		}
+	}
+	if result.SwitchID == "" && result.PortID == "" {
+		return nil
+	}
+	return &result
+}
+
 func getNICDetails(ifdata []inventory.InterfaceType,
 	ironicData *inventory.StandardPluginData,
 	inspectorData *introspection.Data) []metal3api.NIC {
@@ -70,6 +251,7 @@ func getNICDetails(ifdata []inventory.InterfaceType,
 		}
 
 		vlans, vlanid := getVLANs(lldp)
+		neighbour := getLLDP(lldp)
 		// We still store one nic even if both ips are unset
 		// if both are set, we store two nics with each ip
 		if intf.IPV4Address != "" || intf.IPV6Address == "" {
@@ -83,6 +265,7 @@ func getNICDetails(ifdata []inventory.InterfaceType,
 				VLANID:    vlanid,
 				SpeedGbps: intf.SpeedMbps / 1000,
 				PXE:       pxeEnabled,
+				LLDP:      neighbour,
 			})
 		}
 		if intf.IPV6Address != "" {
@@ -96,6 +279,7 @@ func getNICDetails(ifdata []inventory.InterfaceType,
 				VLANID:    vlanid,
 				SpeedGbps: intf.SpeedMbps / 1000,
 				PXE:       pxeEnabled,
+				LLDP:      neighbour,
 			})
 		}
 	}
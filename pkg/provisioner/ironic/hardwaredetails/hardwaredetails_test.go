@@ -129,6 +129,63 @@ func TestGetVLANsMalformed(t *testing.T) {
 	}
 }
 
+func TestGetLLDP(t *testing.T) {
+	cases := []struct {
+		name     string
+		lldp     map[string]interface{}
+		expected *metal3api.LLDP
+	}{
+		{
+			name:     "no-data",
+			lldp:     nil,
+			expected: nil,
+		},
+		{
+			name: "only-vlans",
+			lldp: map[string]interface{}{
+				"switch_port_untagged_vlan_id": 1,
+			},
+			expected: nil,
+		},
+		{
+			name: "full",
+			lldp: map[string]interface{}{
+				"switch_chassis_id":       "00:1c:73:aa:bb:cc",
+				"switch_system_name":      "tor-r1",
+				"switch_port_id":          "Ethernet12",
+				"switch_port_description": "server r1-u12 eth0",
+				"switch_mgmt_addresses":   []interface{}{"192.0.2.250", "2001:db8::250"},
+			},
+			expected: &metal3api.LLDP{
+				SwitchID:                  "00:1c:73:aa:bb:cc",
+				SwitchSystemName:          "tor-r1",
+				SwitchManagementAddresses: []string{"192.0.2.250", "2001:db8::250"},
+				PortID:                    "Ethernet12",
+				PortDescription:           "server r1-u12 eth0",
+			},
+		},
+		{
+			name: "malformed",
+			lldp: map[string]interface{}{
+				"switch_chassis_id":     "00:1c:73:aa:bb:cc",
+				"switch_system_name":    42,
+				"switch_port_id":        "Ethernet12",
+				"switch_mgmt_addresses": []interface{}{"192.0.2.250", 42},
+			},
+			expected: &metal3api.LLDP{
+				SwitchID:                  "00:1c:73:aa:bb:cc",
+				SwitchManagementAddresses: []string{"192.0.2.250"},
+				PortID:                    "Ethernet12",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, getLLDP(tc.lldp))
+		})
+	}
+}
+
 func TestGetNICDetailsInspector(t *testing.T) {
 	ironicData := inventory.StandardPluginData{
 		AllInterfaces: map[string]inventory.ProcessedInterfaceType{
@@ -144,6 +201,8 @@ func TestGetNICDetailsInspector(t *testing.T) {
 					},
 				},
 				"switch_port_untagged_vlan_id": 1,
+				"switch_chassis_id":            "00:1c:73:aa:bb:cc",
+				"switch_port_id":               "Ethernet12",
 			},
 		},
 	}
@@ -158,6 +217,8 @@ func TestGetNICDetailsInspector(t *testing.T) {
 						},
 					},
 					"switch_port_untagged_vlan_id": 1,
+					"switch_chassis_id":            "00:1c:73:aa:bb:cc",
+					"switch_port_id":               "Ethernet12",
 				},
 			},
 		},
@@ -213,6 +274,10 @@ func TestGetNICDetailsInspector(t *testing.T) {
 					{ID: 1},
 				},
 				VLANID: 1,
+				LLDP: &metal3api.LLDP{
+					SwitchID: "00:1c:73:aa:bb:cc",
+					PortID:   "Ethernet12",
+				},
 			})) {
 				t.Errorf("Unexpected NIC data")
 			}
@@ -260,3 +325,89 @@ func TestGetFirmwareDetails(t *testing.T) {
 		t.Errorf("Expected firmware BIOS vendor to be foobar, but got: %s", firmware)
 	}
 }
+
+func TestGetPCIDevicesAndAccelerators(t *testing.T) {
+	pluginData := []byte(`{
+		"pci_devices": [
+			{"vendor_id": "10de", "product_id": "20b0", "class": "030200", "revision": "a1", "bus": "0000:3b:00.0"},
+			{"vendor_id": "1a03", "product_id": "2000", "class": "030000", "revision": "41", "bus": "0000:03:00.0"},
+			{"vendor_id": "8086", "product_id": "1572", "class": "020000", "revision": "01", "bus": "0000:18:00.0"},
+			{"vendor_id": "1d0f", "product_id": "f010", "class": "120000", "revision": "00", "bus": "0000:af:00.0"}
+		]
+	}`)
+
+	devices, err := getPCIDevices(pluginData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []metal3api.PCIDevice{
+		{Address: "0000:03:00.0", VendorID: "1a03", ProductID: "2000", Class: "030000", Revision: "41"},
+		{Address: "0000:18:00.0", VendorID: "8086", ProductID: "1572", Class: "020000", Revision: "01"},
+		{Address: "0000:3b:00.0", VendorID: "10de", ProductID: "20b0", Class: "030200", Revision: "a1"},
+		{Address: "0000:af:00.0", VendorID: "1d0f", ProductID: "f010", Class: "120000", Revision: "00"},
+	}, devices)
+
+	assert.Equal(t, []metal3api.Accelerator{
+		{Type: metal3api.GPU, Model: "0x10de 0x20b0", PCIAddress: "0000:3b:00.0"},
+		{Type: metal3api.ProcessingAccelerator, Model: "0x1d0f 0xf010", PCIAddress: "0000:af:00.0"},
+	}, getAccelerators(devices))
+
+	devices, err = getPCIDevices([]byte(`{"all_interfaces": {}}`))
+	assert.NoError(t, err)
+	assert.Nil(t, devices)
+
+	_, err = getPCIDevices([]byte(`{"pci_devices": "foo"}`))
+	assert.Error(t, err)
+}
+
+func TestGetMemoryModules(t *testing.T) {
+	extra := inventory.ExtraDataType{
+		Memory: inventory.ExtraDataSection{
+			"total": {"size": "68719476736"},
+			"bank:10": {
+				"slot":        "DIMM_B1",
+				"description": "[empty]",
+			},
+			"bank:2": {
+				"slot":        "DIMM_A1",
+				"size":        "34359738368",
+				"description": "DIMM DDR4 Synchronous 2933 MHz (0.3 ns)",
+				"clock":       "2933000000",
+				"vendor":      "Samsung",
+				"product":     "M393A4K40CB2-CVF",
+				"serial":      "1234ABCD",
+			},
+			"bank:3": {
+				"slot": "DIMM_A2",
+				"size": float64(34359738368),
+			},
+		},
+		System: inventory.ExtraDataSection{
+			"product": {"uuid": "4c4c4544-0047-3510-8054-b4c04f565031"},
+		},
+	}
+
+	assert.Equal(t, []metal3api.MemoryModule{
+		{
+			Slot:           "DIMM_A1",
+			SizeMebibytes:  32768,
+			Description:    "DIMM DDR4 Synchronous 2933 MHz (0.3 ns)",
+			ClockMegahertz: 2933,
+			Vendor:         "Samsung",
+			PartNumber:     "M393A4K40CB2-CVF",
+			SerialNumber:   "1234ABCD",
+		},
+		{
+			Slot:          "DIMM_A2",
+			SizeMebibytes: 32768,
+		},
+		{
+			Slot:        "DIMM_B1",
+			Description: "[empty]",
+		},
+	}, getMemoryModules(&extra))
+	assert.Equal(t, "4c4c4544-0047-3510-8054-b4c04f565031", getSystemUUID(&extra))
+
+	assert.Nil(t, getMemoryModules(&inventory.ExtraDataType{}))
+	assert.Empty(t, getSystemUUID(&inventory.ExtraDataType{}))
+}
@@ -1,6 +1,7 @@
 package ironic
 
 import (
+	"context"
 	"net/http"
 	"testing"
 	"time"
@@ -216,7 +217,7 @@ func TestInspectHardware(t *testing.T) {
 				t.Fatalf("could not create provisioner: %s", err)
 			}
 
-			result, started, details, err := prov.InspectHardware(
+			result, started, details, err := prov.InspectHardware(context.TODO(),
 				provisioner.InspectData{BootMode: metal3api.DefaultBootMode},
 				tc.restartOnFailure, tc.refresh, tc.forceReboot)
 
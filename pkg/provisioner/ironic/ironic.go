@@ -70,6 +70,7 @@ type ironicConfig struct {
 	liveISOForcePersistentBootDevice string
 	maxBusyHosts                     int
 	externalURL                      string
+	nodeCacheInterval                time.Duration
 }
 
 // Provisioner implements the provisioning.Provisioner interface
@@ -89,6 +90,8 @@ type ironicProvisioner struct {
 	bmcCreds bmc.Credentials
 	// the MAC address of the PXE boot interface
 	bootMACAddress string
+	// the ironic endpoint the host is assigned to
+	endpoint *ironicEndpoint
 	// a client for talking to ironic
 	client *gophercloud.ServiceClient
 	// a logger configured for this host
@@ -99,8 +102,6 @@ type ironicProvisioner struct {
 	publisher provisioner.EventPublisher
 	// available API features
 	availableFeatures clients.AvailableFeatures
-	// request context
-	ctx context.Context
 }
 
 func (p *ironicProvisioner) bmcAccess() (bmc.AccessDetails, error) {
@@ -111,11 +112,11 @@ func (p *ironicProvisioner) bmcAccess() (bmc.AccessDetails, error) {
 	return bmcAccess, nil
 }
 
-func (p *ironicProvisioner) validateNode(ironicNode *nodes.Node) (errorMessage string, err error) {
+func (p *ironicProvisioner) validateNode(ctx context.Context, ironicNode *nodes.Node) (errorMessage string, err error) {
 	var validationErrors []string
 
 	p.log.Info("validating node settings in ironic")
-	validateResult, err := nodes.Validate(p.ctx, p.client, ironicNode.UUID).Extract()
+	validateResult, err := nodes.Validate(ctx, p.client, ironicNode.UUID).Extract()
 	if err != nil {
 		return "", err // do not wrap error so we can check type in caller
 	}
@@ -136,7 +137,7 @@ func (p *ironicProvisioner) validateNode(ironicNode *nodes.Node) (errorMessage s
 	return "", nil
 }
 
-func (p *ironicProvisioner) listAllPorts(address string) ([]ports.Port, error) {
+func (p *ironicProvisioner) listAllPorts(ctx context.Context, address string) ([]ports.Port, error) {
 	var allPorts []ports.Port
 
 	opts := ports.ListOpts{
@@ -149,7 +150,7 @@ func (p *ironicProvisioner) listAllPorts(address string) ([]ports.Port, error) {
 
 	pager := ports.List(p.client, opts)
 
-	allPages, err := pager.AllPages(p.ctx)
+	allPages, err := pager.AllPages(ctx)
 
 	if err != nil {
 		return allPorts, err
@@ -158,12 +159,12 @@ func (p *ironicProvisioner) listAllPorts(address string) ([]ports.Port, error) {
 	return ports.ExtractPorts(allPages)
 }
 
-func (p *ironicProvisioner) getNode() (*nodes.Node, error) {
+func (p *ironicProvisioner) getNode(ctx context.Context) (*nodes.Node, error) {
 	if p.nodeID == "" {
 		return nil, provisioner.ErrNeedsRegistration
 	}
 
-	ironicNode, err := nodes.Get(p.ctx, p.client, p.nodeID).Extract()
+	ironicNode, err := nodes.Get(ctx, p.client, p.nodeID).Extract()
 	if err == nil {
 		p.debugLog.Info("found existing node by ID")
 		return ironicNode, nil
@@ -179,7 +180,7 @@ func (p *ironicProvisioner) getNode() (*nodes.Node, error) {
 }
 
 // Verifies that node has port assigned by Ironic.
-func (p *ironicProvisioner) nodeHasAssignedPort(ironicNode *nodes.Node) (bool, error) {
+func (p *ironicProvisioner) nodeHasAssignedPort(ctx context.Context, ironicNode *nodes.Node) (bool, error) {
 	opts := ports.ListOpts{
 		Fields:   []string{"node_uuid"},
 		NodeUUID: ironicNode.UUID,
@@ -187,7 +188,7 @@ func (p *ironicProvisioner) nodeHasAssignedPort(ironicNode *nodes.Node) (bool, e
 
 	pager := ports.List(p.client, opts)
 
-	allPages, err := pager.AllPages(p.ctx)
+	allPages, err := pager.AllPages(ctx)
 	if err != nil {
 		return false, errors.Wrap(err, "failed to page over list of ports")
 	}
@@ -207,8 +208,8 @@ func (p *ironicProvisioner) nodeHasAssignedPort(ironicNode *nodes.Node) (bool, e
 }
 
 // Verify that MAC is already allocated to some node port.
-func (p *ironicProvisioner) isAddressAllocatedToPort(address string) (bool, error) {
-	allPorts, err := p.listAllPorts(address)
+func (p *ironicProvisioner) isAddressAllocatedToPort(ctx context.Context, address string) (bool, error) {
+	allPorts, err := p.listAllPorts(ctx, address)
 	if err != nil {
 		return false, errors.Wrap(err, fmt.Sprintf("failed to list ports for %s", address))
 	}
@@ -223,9 +224,9 @@ func (p *ironicProvisioner) isAddressAllocatedToPort(address string) (bool, erro
 }
 
 // Look for an existing registration for the host in Ironic.
-func (p *ironicProvisioner) findExistingHost(bootMACAddress string) (ironicNode *nodes.Node, err error) {
+func (p *ironicProvisioner) findExistingHost(ctx context.Context, bootMACAddress string) (ironicNode *nodes.Node, err error) {
 	// Try to load the node by UUID
-	ironicNode, err = p.getNode()
+	ironicNode, err = p.getNode(ctx)
 	if !errors.Is(err, provisioner.ErrNeedsRegistration) {
 		return ironicNode, err
 	}
@@ -238,7 +239,7 @@ func (p *ironicProvisioner) findExistingHost(bootMACAddress string) (ironicNode
 
 	for _, nodeName := range nodeSearchList {
 		p.debugLog.Info("looking for existing node by name", "name", nodeName)
-		ironicNode, err = nodes.Get(p.ctx, p.client, nodeName).Extract()
+		ironicNode, err = nodes.Get(ctx, p.client, nodeName).Extract()
 		if err == nil {
 			p.debugLog.Info("found existing node by name", "name", nodeName, "node", ironicNode.UUID)
 			return ironicNode, nil
@@ -253,7 +254,7 @@ func (p *ironicProvisioner) findExistingHost(bootMACAddress string) (ironicNode
 
 	// Try to load the node by port address
 	p.log.Info("looking for existing node by MAC", "MAC", bootMACAddress)
-	allPorts, err := p.listAllPorts(bootMACAddress)
+	allPorts, err := p.listAllPorts(ctx, bootMACAddress)
 
 	if err != nil {
 		p.log.Info("failed to find an existing port with address", "MAC", bootMACAddress)
@@ -262,7 +263,7 @@ func (p *ironicProvisioner) findExistingHost(bootMACAddress string) (ironicNode
 
 	if len(allPorts) > 0 {
 		nodeUUID := allPorts[0].NodeUUID
-		ironicNode, err = nodes.Get(p.ctx, p.client, nodeUUID).Extract()
+		ironicNode, err = nodes.Get(ctx, p.client, nodeUUID).Extract()
 		if err == nil {
 			p.debugLog.Info("found existing node by MAC", "MAC", bootMACAddress, "node", ironicNode.UUID, "name", ironicNode.Name)
 
@@ -285,13 +286,13 @@ func (p *ironicProvisioner) findExistingHost(bootMACAddress string) (ironicNode
 	return nil, nil
 }
 
-func (p *ironicProvisioner) createPXEEnabledNodePort(uuid, macAddress string) error {
+func (p *ironicProvisioner) createPXEEnabledNodePort(ctx context.Context, uuid, macAddress string) error {
 	p.log.Info("creating PXE enabled ironic port for node", "NodeUUID", uuid, "MAC", macAddress)
 
 	enable := true
 
 	_, err := ports.Create(
-		p.ctx,
+		ctx,
 		p.client,
 		ports.CreateOpts{
 			NodeUUID:   uuid,
@@ -311,7 +312,7 @@ func (p *ironicProvisioner) createPXEEnabledNodePort(uuid, macAddress string) er
 //
 // FIXME(dhellmann): We should rename this method to describe what it
 // actually does.
-func (p *ironicProvisioner) ValidateManagementAccess(data provisioner.ManagementAccessData, credentialsChanged, restartOnFailure bool) (result provisioner.Result, provID string, err error) {
+func (p *ironicProvisioner) ValidateManagementAccess(ctx context.Context, data provisioner.ManagementAccessData, credentialsChanged, restartOnFailure bool) (result provisioner.Result, provID string, err error) {
 	bmcAccess, err := p.bmcAccess()
 	if err != nil {
 		result, err = operationFailed(err.Error())
@@ -323,7 +324,7 @@ func (p *ironicProvisioner) ValidateManagementAccess(data provisioner.Management
 
 	p.debugLog.Info("validating management access")
 
-	ironicNode, err = p.findExistingHost(p.bootMACAddress)
+	ironicNode, err = p.findExistingHost(ctx, p.bootMACAddress)
 	if err != nil {
 		switch err.(type) {
 		case macAddressConflictError:
@@ -357,7 +358,7 @@ func (p *ironicProvisioner) ValidateManagementAccess(data provisioner.Management
 			return result, "", err
 		}
 
-		inspectInterface, driverErr := p.getInspectInterface(bmcAccess)
+		inspectInterface, driverErr := p.getInspectInterface(ctx, bmcAccess)
 		if driverErr != nil {
 			result, err = transientError(driverErr)
 			return result, "", err
@@ -384,7 +385,7 @@ func (p *ironicProvisioner) ValidateManagementAccess(data provisioner.Management
 			nodeCreateOpts.FirmwareInterface = bmcAccess.FirmwareInterface()
 		}
 
-		ironicNode, err = nodes.Create(p.ctx, p.client, nodeCreateOpts).Extract()
+		ironicNode, err = nodes.Create(ctx, p.client, nodeCreateOpts).Extract()
 		if err == nil {
 			p.publisher("Registered", "Registered new host")
 		} else if gophercloud.ResponseCodeIs(err, 409) {
@@ -403,7 +404,7 @@ func (p *ironicProvisioner) ValidateManagementAccess(data provisioner.Management
 		// If we know the MAC, create a port. Otherwise we will have
 		// to do this after we run the introspection step.
 		if p.bootMACAddress != "" {
-			err = p.createPXEEnabledNodePort(ironicNode.UUID, p.bootMACAddress)
+			err = p.createPXEEnabledNodePort(ctx, ironicNode.UUID, p.bootMACAddress)
 			if err != nil {
 				result, err = transientError(err)
 				return result, provID, err
@@ -443,21 +444,21 @@ func (p *ironicProvisioner) ValidateManagementAccess(data provisioner.Management
 		if p.bootMACAddress != "" {
 			var nodeHasAssignedPort, addressIsAllocatedToPort bool
 
-			nodeHasAssignedPort, err = p.nodeHasAssignedPort(ironicNode)
+			nodeHasAssignedPort, err = p.nodeHasAssignedPort(ctx, ironicNode)
 			if err != nil {
 				result, err = transientError(err)
 				return result, provID, err
 			}
 
 			if !nodeHasAssignedPort {
-				addressIsAllocatedToPort, err = p.isAddressAllocatedToPort(p.bootMACAddress)
+				addressIsAllocatedToPort, err = p.isAddressAllocatedToPort(ctx, p.bootMACAddress)
 				if err != nil {
 					result, err = transientError(err)
 					return result, provID, err
 				}
 
 				if !addressIsAllocatedToPort {
-					err = p.createPXEEnabledNodePort(ironicNode.UUID, p.bootMACAddress)
+					err = p.createPXEEnabledNodePort(ctx, ironicNode.UUID, p.bootMACAddress)
 					if err != nil {
 						result, err = transientError(err)
 						return result, provID, err
@@ -499,7 +500,7 @@ func (p *ironicProvisioner) ValidateManagementAccess(data provisioner.Management
 		}
 	}
 
-	ironicNode, success, result, err := p.tryUpdateNode(ironicNode, updater)
+	ironicNode, success, result, err := p.tryUpdateNode(ctx, ironicNode, updater)
 	if !success {
 		return result, provID, err
 	}
@@ -527,7 +528,7 @@ func (p *ironicProvisioner) ValidateManagementAccess(data provisioner.Management
 			return result, provID, err
 		}
 
-		result, err = p.changeNodeProvisionState(
+		result, err = p.changeNodeProvisionState(ctx,
 			ironicNode,
 			nodes.ProvisionStateOpts{Target: nodes.TargetManage},
 		)
@@ -555,12 +556,12 @@ func (p *ironicProvisioner) ValidateManagementAccess(data provisioner.Management
 		fallthrough
 
 	default:
-		result, err = p.configureImages(data, ironicNode, bmcAccess)
+		result, err = p.configureImages(ctx, data, ironicNode, bmcAccess)
 		return result, provID, err
 	}
 }
 
-func (p *ironicProvisioner) configureImages(data provisioner.ManagementAccessData, ironicNode *nodes.Node, bmcAccess bmc.AccessDetails) (result provisioner.Result, err error) {
+func (p *ironicProvisioner) configureImages(ctx context.Context, data provisioner.ManagementAccessData, ironicNode *nodes.Node, bmcAccess bmc.AccessDetails) (result provisioner.Result, err error) {
 	updater := clients.UpdateOptsBuilder(p.log)
 
 	deployImageInfo := setDeployImage(p.config, bmcAccess, data.PreprovisioningImage)
@@ -574,7 +575,7 @@ func (p *ironicProvisioner) configureImages(data provisioner.ManagementAccessDat
 		data.AutomatedCleaningMode != metal3api.CleaningModeDisabled,
 		ironicNode.AutomatedClean)
 
-	_, success, result, err := p.tryUpdateNode(ironicNode, updater)
+	_, success, result, err := p.tryUpdateNode(ctx, ironicNode, updater)
 	if !success {
 		return result, err
 	}
@@ -617,7 +618,7 @@ func (p *ironicProvisioner) configureImages(data provisioner.ManagementAccessDat
 // PreprovisioningImageFormats returns a list of acceptable formats for a
 // pre-provisioning image to be built by a PreprovisioningImage object. The
 // list should be nil if no image build is requested.
-func (p *ironicProvisioner) PreprovisioningImageFormats() ([]metal3api.ImageFormat, error) {
+func (p *ironicProvisioner) PreprovisioningImageFormats(_ context.Context) ([]metal3api.ImageFormat, error) {
 	if !p.config.havePreprovImgBuilder {
 		return nil, nil
 	}
@@ -727,7 +728,7 @@ func setDeployImage(config ironicConfig, accessDetails bmc.AccessDetails, hostIm
 	return nil
 }
 
-func (p *ironicProvisioner) tryUpdateNode(ironicNode *nodes.Node, updater *clients.NodeUpdater) (updatedNode *nodes.Node, success bool, result provisioner.Result, err error) {
+func (p *ironicProvisioner) tryUpdateNode(ctx context.Context, ironicNode *nodes.Node, updater *clients.NodeUpdater) (updatedNode *nodes.Node, success bool, result provisioner.Result, err error) {
 	if len(updater.Updates) == 0 {
 		updatedNode = ironicNode
 		success = true
@@ -735,7 +736,7 @@ func (p *ironicProvisioner) tryUpdateNode(ironicNode *nodes.Node, updater *clien
 	}
 
 	p.log.Info("updating node settings in ironic", "updateCount", len(updater.Updates))
-	updatedNode, err = nodes.Update(p.ctx, p.client, ironicNode.UUID, updater.Updates).Extract()
+	updatedNode, err = nodes.Update(ctx, p.client, ironicNode.UUID, updater.Updates).Extract()
 	if err == nil {
 		success = true
 	} else if gophercloud.ResponseCodeIs(err, 409) {
@@ -748,7 +749,7 @@ func (p *ironicProvisioner) tryUpdateNode(ironicNode *nodes.Node, updater *clien
 	return
 }
 
-func (p *ironicProvisioner) tryChangeNodeProvisionState(ironicNode *nodes.Node, opts nodes.ProvisionStateOpts) (success bool, result provisioner.Result, err error) {
+func (p *ironicProvisioner) tryChangeNodeProvisionState(ctx context.Context, ironicNode *nodes.Node, opts nodes.ProvisionStateOpts) (success bool, result provisioner.Result, err error) {
 	p.log.Info("changing provisioning state",
 		"current", ironicNode.ProvisionState,
 		"existing target", ironicNode.TargetProvisionState,
@@ -763,11 +764,11 @@ func (p *ironicProvisioner) tryChangeNodeProvisionState(ironicNode *nodes.Node,
 	}
 	if ironicNode.Maintenance {
 		p.log.Info("trying to change a provision state for a node in maintenance, removing maintenance first", "reason", ironicNode.MaintenanceReason)
-		result, err = p.setMaintenanceFlag(ironicNode, false, "")
+		result, err = p.setMaintenanceFlag(ctx, ironicNode, false, "")
 		return success, result, err
 	}
 
-	changeResult := nodes.ChangeProvisionState(p.ctx, p.client, ironicNode.UUID, opts)
+	changeResult := nodes.ChangeProvisionState(ctx, p.client, ironicNode.UUID, opts)
 	if changeResult.Err == nil {
 		success = true
 	} else if gophercloud.ResponseCodeIs(changeResult.Err, 409) {
@@ -783,8 +784,8 @@ func (p *ironicProvisioner) tryChangeNodeProvisionState(ironicNode *nodes.Node,
 	return success, result, err
 }
 
-func (p *ironicProvisioner) changeNodeProvisionState(ironicNode *nodes.Node, opts nodes.ProvisionStateOpts) (result provisioner.Result, err error) {
-	_, result, err = p.tryChangeNodeProvisionState(ironicNode, opts)
+func (p *ironicProvisioner) changeNodeProvisionState(ctx context.Context, ironicNode *nodes.Node, opts nodes.ProvisionStateOpts) (result provisioner.Result, err error) {
+	_, result, err = p.tryChangeNodeProvisionState(ctx, ironicNode, opts)
 	return
 }
 
@@ -792,12 +793,21 @@ func (p *ironicProvisioner) changeNodeProvisionState(ironicNode *nodes.Node, opt
 // and updates the HardwareDetails field of the host with details. It
 // is expected to do this in the least expensive way possible, such as
 // reading from a cache.
-func (p *ironicProvisioner) UpdateHardwareState() (hwState provisioner.HardwareState, err error) {
+func (p *ironicProvisioner) UpdateHardwareState(ctx context.Context) (hwState provisioner.HardwareState, err error) {
 	p.debugLog.Info("updating hardware state")
 
-	ironicNode, err := p.getNode()
-	if err != nil {
-		return
+	var ironicNode *nodes.Node
+	if p.endpoint.nodes != nil && p.nodeID != "" {
+		ironicNode, err = p.endpoint.nodes.node(ctx, p.client, p.nodeID)
+		if err != nil {
+			return
+		}
+	}
+	if ironicNode == nil {
+		ironicNode, err = p.getNode(ctx)
+		if err != nil {
+			return
+		}
 	}
 
 	switch ironicNode.PowerState {
@@ -951,8 +961,8 @@ func (p *ironicProvisioner) getUpdateOptsForNode(ironicNode *nodes.Node, data pr
 }
 
 // GetFirmwareSettings gets the BIOS settings and optional schema from the host and returns maps.
-func (p *ironicProvisioner) GetFirmwareSettings(includeSchema bool) (settings metal3api.SettingsMap, schema map[string]metal3api.SettingSchema, err error) {
-	ironicNode, err := p.getNode()
+func (p *ironicProvisioner) GetFirmwareSettings(ctx context.Context, includeSchema bool) (settings metal3api.SettingsMap, schema map[string]metal3api.SettingSchema, err error) {
+	ironicNode, err := p.getNode(ctx)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "could not get node for BIOS settings")
 	}
@@ -962,9 +972,9 @@ func (p *ironicProvisioner) GetFirmwareSettings(includeSchema bool) (settings me
 	var biosListErr error
 	if includeSchema {
 		opts := nodes.ListBIOSSettingsOpts{Detail: true}
-		settingsList, biosListErr = nodes.ListBIOSSettings(p.ctx, p.client, ironicNode.UUID, opts).Extract()
+		settingsList, biosListErr = nodes.ListBIOSSettings(ctx, p.client, ironicNode.UUID, opts).Extract()
 	} else {
-		settingsList, biosListErr = nodes.ListBIOSSettings(p.ctx, p.client, ironicNode.UUID, nil).Extract()
+		settingsList, biosListErr = nodes.ListBIOSSettings(ctx, p.client, ironicNode.UUID, nil).Extract()
 	}
 	if biosListErr != nil {
 		return nil, nil, errors.Wrap(biosListErr,
@@ -997,8 +1007,8 @@ func (p *ironicProvisioner) GetFirmwareSettings(includeSchema bool) (settings me
 }
 
 // GetFirmwareComponents gets all available firmware components for a node and return a list.
-func (p *ironicProvisioner) GetFirmwareComponents() ([]metal3api.FirmwareComponentStatus, error) {
-	ironicNode, err := p.getNode()
+func (p *ironicProvisioner) GetFirmwareComponents(ctx context.Context) ([]metal3api.FirmwareComponentStatus, error) {
+	ironicNode, err := p.getNode(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("could not get node to retrieve firmware components: %w", err)
 	}
@@ -1014,7 +1024,7 @@ func (p *ironicProvisioner) GetFirmwareComponents() ([]metal3api.FirmwareCompone
 		return componentsInfo, provisioner.ErrFirmwareUpdateUnsupported
 	}
 	// Get the components from Ironic via Gophercloud
-	componentList, componentListErr := nodes.ListFirmware(p.ctx, p.client, ironicNode.UUID).Extract()
+	componentList, componentListErr := nodes.ListFirmware(ctx, p.client, ironicNode.UUID).Extract()
 
 	if componentListErr != nil {
 		return nil, fmt.Errorf("could not get firmware components for node %s: %w", ironicNode.UUID, componentListErr)
@@ -1045,10 +1055,10 @@ func (p *ironicProvisioner) GetFirmwareComponents() ([]metal3api.FirmwareCompone
 	return componentsInfo, componentListErr
 }
 
-func (p *ironicProvisioner) setUpForProvisioning(ironicNode *nodes.Node, data provisioner.ProvisionData) (result provisioner.Result, err error) {
+func (p *ironicProvisioner) setUpForProvisioning(ctx context.Context, ironicNode *nodes.Node, data provisioner.ProvisionData) (result provisioner.Result, err error) {
 	p.log.Info("starting provisioning", "node properties", ironicNode.Properties)
 
-	ironicNode, success, result, err := p.tryUpdateNode(ironicNode,
+	ironicNode, success, result, err := p.tryUpdateNode(ctx, ironicNode,
 		p.getUpdateOptsForNode(ironicNode, data))
 	if !success {
 		return result, err
@@ -1056,7 +1066,7 @@ func (p *ironicProvisioner) setUpForProvisioning(ironicNode *nodes.Node, data pr
 
 	p.log.Info("validating host settings")
 
-	errorMessage, err := p.validateNode(ironicNode)
+	errorMessage, err := p.validateNode(ctx, ironicNode)
 	if gophercloud.ResponseCodeIs(err, 409) {
 		p.log.Info("could not validate host during registration, busy")
 		return retryAfterDelay(provisionRequeueDelay)
@@ -1092,8 +1102,8 @@ func (p *ironicProvisioner) deployInterface(data provisioner.ManagementAccessDat
 
 // Adopt notifies the provisioner that the state machine believes the host
 // to be currently provisioned, and that it should be managed as such.
-func (p *ironicProvisioner) Adopt(data provisioner.AdoptData, restartOnFailure bool) (result provisioner.Result, err error) {
-	ironicNode, err := p.getNode()
+func (p *ironicProvisioner) Adopt(ctx context.Context, data provisioner.AdoptData, restartOnFailure bool) (result provisioner.Result, err error) {
+	ironicNode, err := p.getNode(ctx)
 	if err != nil {
 		return transientError(err)
 	}
@@ -1116,7 +1126,7 @@ func (p *ironicProvisioner) Adopt(data provisioner.AdoptData, restartOnFailure b
 			p.log.Info("no image info; not adopting", "state", ironicNode.ProvisionState)
 			return operationComplete()
 		}
-		return p.changeNodeProvisionState(
+		return p.changeNodeProvisionState(ctx,
 			ironicNode,
 			nodes.ProvisionStateOpts{
 				Target: nodes.TargetAdopt,
@@ -1126,7 +1136,7 @@ func (p *ironicProvisioner) Adopt(data provisioner.AdoptData, restartOnFailure b
 		return operationContinuing(provisionRequeueDelay)
 	case nodes.AdoptFail:
 		if restartOnFailure {
-			return p.changeNodeProvisionState(
+			return p.changeNodeProvisionState(ctx,
 				ironicNode,
 				nodes.ProvisionStateOpts{
 					Target: nodes.TargetAdopt,
@@ -1139,7 +1149,7 @@ func (p *ironicProvisioner) Adopt(data provisioner.AdoptData, restartOnFailure b
 		// Empty Fault means that maintenance was set manually, not by Ironic
 		if ironicNode.Maintenance && ironicNode.Fault == "" && data.State != metal3api.StateDeleting {
 			p.log.Info("active node was found to be in maintenance, updating", "state", data.State)
-			return p.setMaintenanceFlag(ironicNode, false, "")
+			return p.setMaintenanceFlag(ctx, ironicNode, false, "")
 		}
 	default:
 	}
@@ -1187,6 +1197,25 @@ func (p *ironicProvisioner) buildManualCleaningSteps(bmcAccess bmc.AccessDetails
 	}
 	cleanSteps = append(cleanSteps, raidCleanSteps...)
 
+	firmwareSteps, err := p.buildFirmwareSteps(bmcAccess, provisioner.ServicingData{
+		FirmwareConfig:           data.FirmwareConfig,
+		TargetFirmwareSettings:   data.TargetFirmwareSettings,
+		ActualFirmwareSettings:   data.ActualFirmwareSettings,
+		TargetFirmwareComponents: data.TargetFirmwareComponents,
+	})
+	if err != nil {
+		return nil, err
+	}
+	cleanSteps = append(cleanSteps, firmwareSteps...)
+
+	// TODO: Add manual cleaning steps for host configuration
+
+	return cleanSteps, nil
+}
+
+// buildFirmwareSteps returns the steps applying the BIOS settings and
+// firmware updates, which can run both during cleaning and servicing.
+func (p *ironicProvisioner) buildFirmwareSteps(bmcAccess bmc.AccessDetails, data provisioner.ServicingData) (steps []nodes.CleanStep, err error) {
 	// Get the subset (currently 3) of vendor specific BIOS settings converted from common names
 	var firmwareConfig *bmc.FirmwareConfig
 	if data.FirmwareConfig != nil {
@@ -1234,9 +1263,9 @@ func (p *ironicProvisioner) buildManualCleaningSteps(bmcAccess bmc.AccessDetails
 	}
 
 	if len(newSettings) != 0 {
-		p.log.Info("Applying BIOS config clean steps", "settings", newSettings)
-		cleanSteps = append(
-			cleanSteps,
+		p.log.Info("Applying BIOS config steps", "settings", newSettings)
+		steps = append(
+			steps,
 			nodes.CleanStep{
 				Interface: nodes.InterfaceBIOS,
 				Step:      "apply_configuration",
@@ -1262,9 +1291,9 @@ func (p *ironicProvisioner) buildManualCleaningSteps(bmcAccess bmc.AccessDetails
 	}
 
 	if len(newUpdates) != 0 {
-		p.log.Info("Applying Firmware Update clean steps", "settings", newUpdates)
-		cleanSteps = append(
-			cleanSteps,
+		p.log.Info("Applying Firmware Update steps", "settings", newUpdates)
+		steps = append(
+			steps,
 			nodes.CleanStep{
 				Interface: nodes.InterfaceFirmware,
 				Step:      "update",
@@ -1275,9 +1304,7 @@ func (p *ironicProvisioner) buildManualCleaningSteps(bmcAccess bmc.AccessDetails
 		)
 	}
 
-	// TODO: Add manual cleaning steps for host configuration
-
-	return cleanSteps, nil
+	return steps, nil
 }
 
 func buildFirmwareSettings(settings []map[string]interface{}, name string, value intstr.IntOrString) []map[string]interface{} {
@@ -1297,9 +1324,9 @@ func buildFirmwareSettings(settings []map[string]interface{}, name string, value
 	return settings
 }
 
-func (p *ironicProvisioner) startManualCleaning(bmcAccess bmc.AccessDetails, ironicNode *nodes.Node, data provisioner.PrepareData) (success bool, result provisioner.Result, err error) {
+func (p *ironicProvisioner) startManualCleaning(ctx context.Context, bmcAccess bmc.AccessDetails, ironicNode *nodes.Node, data provisioner.PrepareData) (success bool, result provisioner.Result, err error) {
 	// Set raid configuration
-	result, err = setTargetRAIDCfg(p, bmcAccess.RAIDInterface(), ironicNode, data)
+	result, err = setTargetRAIDCfg(ctx, p, bmcAccess.RAIDInterface(), ironicNode, data)
 	if result.Dirty || result.ErrorMessage != "" || err != nil {
 		return
 	}
@@ -1314,7 +1341,7 @@ func (p *ironicProvisioner) startManualCleaning(bmcAccess bmc.AccessDetails, iro
 	// Start manual clean
 	if len(cleanSteps) != 0 {
 		p.log.Info("remove existing configuration and set new configuration", "clean steps", cleanSteps)
-		return p.tryChangeNodeProvisionState(
+		return p.tryChangeNodeProvisionState(ctx,
 			ironicNode,
 			nodes.ProvisionStateOpts{
 				Target:     nodes.TargetClean,
@@ -1328,14 +1355,14 @@ func (p *ironicProvisioner) startManualCleaning(bmcAccess bmc.AccessDetails, iro
 
 // Prepare remove existing configuration and set new configuration.
 // If `started` is true,  it means that we successfully executed `tryChangeNodeProvisionState`.
-func (p *ironicProvisioner) Prepare(data provisioner.PrepareData, unprepared bool, restartOnFailure bool) (result provisioner.Result, started bool, err error) {
+func (p *ironicProvisioner) Prepare(ctx context.Context, data provisioner.PrepareData, unprepared bool, restartOnFailure bool) (result provisioner.Result, started bool, err error) {
 	bmcAccess, err := p.bmcAccess()
 	if err != nil {
 		result, err = transientError(err)
 		return result, started, err
 	}
 
-	ironicNode, err := p.getNode()
+	ironicNode, err := p.getNode(ctx)
 	if err != nil {
 		result, err = transientError(err)
 		return result, started, err
@@ -1352,7 +1379,7 @@ func (p *ironicProvisioner) Prepare(data provisioner.PrepareData, unprepared boo
 			}
 			if len(cleanSteps) != 0 {
 				p.log.Info("the node needs to be reconfigured", "clean steps", cleanSteps)
-				result, err = p.changeNodeProvisionState(
+				result, err = p.changeNodeProvisionState(ctx,
 					ironicNode,
 					nodes.ProvisionStateOpts{Target: nodes.TargetManage},
 				)
@@ -1367,7 +1394,7 @@ func (p *ironicProvisioner) Prepare(data provisioner.PrepareData, unprepared boo
 
 	case nodes.Manageable:
 		if unprepared {
-			started, result, err = p.startManualCleaning(bmcAccess, ironicNode, data)
+			started, result, err = p.startManualCleaning(ctx, bmcAccess, ironicNode, data)
 			if started || result.Dirty || result.ErrorMessage != "" || err != nil {
 				return result, started, err
 			}
@@ -1387,10 +1414,10 @@ func (p *ironicProvisioner) Prepare(data provisioner.PrepareData, unprepared boo
 		}
 		if ironicNode.Maintenance {
 			p.log.Info("clearing maintenance flag")
-			result, err = p.setMaintenanceFlag(ironicNode, false, "")
+			result, err = p.setMaintenanceFlag(ctx, ironicNode, false, "")
 			return result, started, err
 		}
-		result, err = p.changeNodeProvisionState(
+		result, err = p.changeNodeProvisionState(ctx,
 			ironicNode,
 			nodes.ProvisionStateOpts{Target: nodes.TargetManage},
 		)
@@ -1407,6 +1434,97 @@ func (p *ironicProvisioner) Prepare(data provisioner.PrepareData, unprepared boo
 	return result, started, err
 }
 
+func (p *ironicProvisioner) startServicing(ctx context.Context, bmcAccess bmc.AccessDetails, ironicNode *nodes.Node, data provisioner.ServicingData) (success bool, result provisioner.Result, err error) {
+	serviceSteps, err := p.buildFirmwareSteps(bmcAccess, data)
+	if err != nil {
+		result, err = operationFailed(err.Error())
+		return
+	}
+
+	if len(serviceSteps) != 0 {
+		p.log.Info("applying new configuration to the provisioned host", "service steps", serviceSteps)
+		return p.tryChangeNodeProvisionState(ctx,
+			ironicNode,
+			nodes.ProvisionStateOpts{
+				Target:       nodes.TargetService,
+				ServiceSteps: serviceSteps,
+			},
+		)
+	}
+	result, err = operationComplete()
+	return
+}
+
+// Service applies firmware settings and updates to a provisioned host
+// using the servicing feature of Ironic.
+// If `started` is true,  it means that we successfully executed `tryChangeNodeProvisionState`.
+func (p *ironicProvisioner) Service(ctx context.Context, data provisioner.ServicingData, unprepared bool, restartOnFailure bool) (result provisioner.Result, started bool, err error) {
+	if !p.availableFeatures.HasServicing() {
+		result, err = operationFailed(fmt.Sprintf("servicing requires ironic API version 1.87, available is 1.%d", p.availableFeatures.MaxVersion))
+		return result, started, err
+	}
+
+	bmcAccess, err := p.bmcAccess()
+	if err != nil {
+		result, err = transientError(err)
+		return result, started, err
+	}
+
+	ironicNode, err := p.getNode(ctx)
+	if err != nil {
+		result, err = transientError(err)
+		return result, started, err
+	}
+
+	switch nodes.ProvisionState(ironicNode.ProvisionState) {
+	case nodes.ServiceFail:
+		// The node stays in service fail until servicing is retried.
+		// If restartOnFailure is false, the failure has not been
+		// reported yet.
+		if !restartOnFailure {
+			result, err = operationFailed(ironicNode.LastError)
+			return result, started, err
+		}
+		if ironicNode.Maintenance {
+			p.log.Info("clearing maintenance flag")
+			result, err = p.setMaintenanceFlag(ctx, ironicNode, false, "")
+			return result, started, err
+		}
+		p.log.Info("retrying servicing after a failure")
+		started, result, err = p.startServicing(ctx, bmcAccess, ironicNode, data)
+		if started || result.Dirty || result.ErrorMessage != "" || err != nil {
+			return result, started, err
+		}
+		// Nothing left to apply, but the node must leave service fail
+		result, err = p.changeNodeProvisionState(ctx,
+			ironicNode,
+			nodes.ProvisionStateOpts{Target: nodes.TargetAbort},
+		)
+
+	case nodes.Active:
+		if unprepared {
+			started, result, err = p.startServicing(ctx, bmcAccess, ironicNode, data)
+			if started || result.Dirty || result.ErrorMessage != "" || err != nil {
+				return result, started, err
+			}
+			// Nothing to apply, so servicing is not started at all
+			p.log.Info("no servicing steps to apply")
+		}
+		// Servicing finished
+		result, err = operationComplete()
+
+	case nodes.Servicing, nodes.ServiceWait:
+		p.log.Info("waiting for host to become active",
+			"state", ironicNode.ProvisionState,
+			"service step", ironicNode.ServiceStep)
+		result, err = operationContinuing(provisionRequeueDelay)
+
+	default:
+		result, err = transientError(fmt.Errorf("have unexpected ironic node state %s", ironicNode.ProvisionState))
+	}
+	return result, started, err
+}
+
 func (p *ironicProvisioner) getConfigDrive(data provisioner.ProvisionData) (configDrive nodes.ConfigDrive, err error) {
 	// In theory, Ironic can support configdrive with live ISO by attaching
 	// it to another virtual media slot. However, some hardware does not
@@ -1477,8 +1595,8 @@ func (p *ironicProvisioner) getCustomDeploySteps(customDeploy *metal3api.CustomD
 // Provision writes the image from the host spec to the host. It may
 // be called multiple times, and should return true for its dirty flag
 // until the provisioning operation is completed.
-func (p *ironicProvisioner) Provision(data provisioner.ProvisionData, forceReboot bool) (result provisioner.Result, err error) {
-	ironicNode, err := p.getNode()
+func (p *ironicProvisioner) Provision(ctx context.Context, data provisioner.ProvisionData, forceReboot bool) (result provisioner.Result, err error) {
+	ironicNode, err := p.getNode(ctx)
 	if err != nil {
 		return transientError(err)
 	}
@@ -1507,7 +1625,7 @@ func (p *ironicProvisioner) Provision(data provisioner.ProvisionData, forceReboo
 				ironicNode.LastError))
 		}
 		p.log.Info("recovering from previous failure")
-		if provResult, err := p.setUpForProvisioning(ironicNode, data); err != nil || provResult.Dirty || provResult.ErrorMessage != "" {
+		if provResult, err := p.setUpForProvisioning(ctx, ironicNode, data); err != nil || provResult.Dirty || provResult.ErrorMessage != "" {
 			return provResult, err
 		}
 
@@ -1516,7 +1634,7 @@ func (p *ironicProvisioner) Provision(data provisioner.ProvisionData, forceReboo
 			return transientError(err)
 		}
 
-		return p.changeNodeProvisionState(
+		return p.changeNodeProvisionState(ctx,
 			ironicNode,
 			nodes.ProvisionStateOpts{
 				Target:      nodes.TargetActive,
@@ -1526,21 +1644,21 @@ func (p *ironicProvisioner) Provision(data provisioner.ProvisionData, forceReboo
 		)
 
 	case nodes.Manageable:
-		return p.changeNodeProvisionState(ironicNode,
+		return p.changeNodeProvisionState(ctx, ironicNode,
 			nodes.ProvisionStateOpts{Target: nodes.TargetProvide})
 
 	case nodes.CleanFail:
 		if ironicNode.Maintenance {
 			p.log.Info("clearing maintenance flag")
-			return p.setMaintenanceFlag(ironicNode, false, "")
+			return p.setMaintenanceFlag(ctx, ironicNode, false, "")
 		}
-		return p.changeNodeProvisionState(
+		return p.changeNodeProvisionState(ctx,
 			ironicNode,
 			nodes.ProvisionStateOpts{Target: nodes.TargetManage},
 		)
 
 	case nodes.Available:
-		if provResult, err := p.setUpForProvisioning(ironicNode, data); err != nil || provResult.Dirty || provResult.ErrorMessage != "" {
+		if provResult, err := p.setUpForProvisioning(ctx, ironicNode, data); err != nil || provResult.Dirty || provResult.ErrorMessage != "" {
 			return provResult, err
 		}
 
@@ -1553,7 +1671,7 @@ func (p *ironicProvisioner) Provision(data provisioner.ProvisionData, forceReboo
 			return transientError(err)
 		}
 
-		return p.changeNodeProvisionState(
+		return p.changeNodeProvisionState(ctx,
 			ironicNode,
 			nodes.ProvisionStateOpts{
 				Target:      nodes.TargetActive,
@@ -1572,7 +1690,7 @@ func (p *ironicProvisioner) Provision(data provisioner.ProvisionData, forceReboo
 	case nodes.DeployWait:
 		if forceReboot {
 			p.log.Info("aborting provisioning to force reboot of preprovisioning image")
-			_, result, err = p.tryChangeNodeProvisionState(
+			_, result, err = p.tryChangeNodeProvisionState(ctx,
 				ironicNode,
 				nodes.ProvisionStateOpts{Target: nodes.TargetDeleted},
 			)
@@ -1590,12 +1708,12 @@ func (p *ironicProvisioner) Provision(data provisioner.ProvisionData, forceReboo
 	}
 }
 
-func (p *ironicProvisioner) setMaintenanceFlag(ironicNode *nodes.Node, value bool, reason string) (result provisioner.Result, err error) {
+func (p *ironicProvisioner) setMaintenanceFlag(ctx context.Context, ironicNode *nodes.Node, value bool, reason string) (result provisioner.Result, err error) {
 	p.log.Info("updating maintenance in ironic", "newValue", value, "reason", reason)
 	if value {
-		err = nodes.SetMaintenance(p.ctx, p.client, ironicNode.UUID, nodes.MaintenanceOpts{Reason: reason}).ExtractErr()
+		err = nodes.SetMaintenance(ctx, p.client, ironicNode.UUID, nodes.MaintenanceOpts{Reason: reason}).ExtractErr()
 	} else {
-		err = nodes.UnsetMaintenance(p.ctx, p.client, ironicNode.UUID).ExtractErr()
+		err = nodes.UnsetMaintenance(ctx, p.client, ironicNode.UUID).ExtractErr()
 	}
 
 	if err == nil {
@@ -1610,17 +1728,98 @@ func (p *ironicProvisioner) setMaintenanceFlag(ironicNode *nodes.Node, value boo
 	return
 }
 
+func (p *ironicProvisioner) startDiskErasure(ctx context.Context, ironicNode *nodes.Node, data provisioner.DeprovisionData) (success bool, result provisioner.Result, err error) {
+	// The agent skips the block devices matching these hints when
+	// erasing the disks.
+	var skipDevices interface{}
+	if len(data.PreservedDisks) != 0 {
+		hints := make([]map[string]string, len(data.PreservedDisks))
+		for i := range data.PreservedDisks {
+			hints[i] = devicehints.MakeHintMap(&data.PreservedDisks[i])
+		}
+		skipDevices = hints
+	}
+
+	updater := clients.UpdateOptsBuilder(p.log)
+	updater.SetPropertiesOpts(clients.UpdateOptsData{
+		"skip_block_devices": skipDevices,
+	}, ironicNode)
+	ironicNode, success, result, err = p.tryUpdateNode(ctx, ironicNode, updater)
+	if !success {
+		return
+	}
+
+	p.log.Info("starting disk erasure", "preservedDisks", data.PreservedDisks)
+	p.publisher("DiskErasureStarted", "Disk erasure started")
+	return p.tryChangeNodeProvisionState(ctx,
+		ironicNode,
+		nodes.ProvisionStateOpts{
+			Target: nodes.TargetClean,
+			CleanSteps: []nodes.CleanStep{
+				{
+					Interface: nodes.InterfaceDeploy,
+					Step:      "erase_devices",
+				},
+			},
+		},
+	)
+}
+
 // Deprovision removes the host from the image. It may be called
 // multiple times, and should return true for its dirty flag until the
-// deprovisioning operation is completed.
-func (p *ironicProvisioner) Deprovision(restartOnFailure bool) (result provisioner.Result, err error) {
+// deprovisioning operation is completed. When the disks are to be
+// erased, the node is moved back to manageable once the image is
+// removed and the disks are erased using manual cleaning.
+// If `erasureStarted` is true, it means that we successfully executed
+// `tryChangeNodeProvisionState` for the erasure.
+func (p *ironicProvisioner) Deprovision(ctx context.Context, data provisioner.DeprovisionData, restartOnFailure bool) (result provisioner.Result, erasureStarted bool, err error) {
 	p.log.Info("deprovisioning")
 
-	ironicNode, err := p.getNode()
+	ironicNode, err := p.getNode(ctx)
 	if err != nil {
-		return transientError(err)
+		result, err = transientError(err)
+		return result, false, err
+	}
+
+	if data.EraseDisks && !data.ErasureStarted {
+		switch nodes.ProvisionState(ironicNode.ProvisionState) {
+		case nodes.Available:
+			p.log.Info("moving node to manageable to erase the disks")
+			result, err = p.changeNodeProvisionState(ctx,
+				ironicNode,
+				nodes.ProvisionStateOpts{Target: nodes.TargetManage},
+			)
+			return result, false, err
+
+		case nodes.Manageable:
+			erasureStarted, result, err = p.startDiskErasure(ctx, ironicNode, data)
+			return result, erasureStarted, err
+		}
 	}
 
+	// The preserved disks only apply to the erasure, so they are removed
+	// once it has finished.
+	switch nodes.ProvisionState(ironicNode.ProvisionState) {
+	case nodes.Manageable, nodes.Available:
+		if _, ok := ironicNode.Properties["skip_block_devices"]; ok {
+			updater := clients.UpdateOptsBuilder(p.log)
+			updater.SetPropertiesOpts(clients.UpdateOptsData{
+				"skip_block_devices": nil,
+			}, ironicNode)
+			var success bool
+			_, success, result, err = p.tryUpdateNode(ctx, ironicNode, updater)
+			if success {
+				result, err = operationContinuing(0)
+			}
+			return result, false, err
+		}
+	}
+
+	result, err = p.deprovision(ctx, ironicNode, restartOnFailure)
+	return result, false, err
+}
+
+func (p *ironicProvisioner) deprovision(ctx context.Context, ironicNode *nodes.Node, restartOnFailure bool) (result provisioner.Result, err error) {
 	p.log.Info("deprovisioning host",
 		"ID", ironicNode.UUID,
 		"lastError", ironicNode.LastError,
@@ -1643,7 +1842,7 @@ func (p *ironicProvisioner) Deprovision(restartOnFailure bool) (result provision
 		}
 		p.log.Info("retrying deprovisioning")
 		p.publisher("DeprovisioningStarted", "Image deprovisioning restarted")
-		return p.changeNodeProvisionState(
+		return p.changeNodeProvisionState(ctx,
 			ironicNode,
 			nodes.ProvisionStateOpts{Target: nodes.TargetDeleted},
 		)
@@ -1656,10 +1855,10 @@ func (p *ironicProvisioner) Deprovision(restartOnFailure bool) (result provision
 		p.log.Info("retrying cleaning")
 		if ironicNode.Maintenance {
 			p.log.Info("clearing maintenance flag", "maintenanceReason", ironicNode.MaintenanceReason)
-			return p.setMaintenanceFlag(ironicNode, false, "")
+			return p.setMaintenanceFlag(ctx, ironicNode, false, "")
 		}
 		// Move to manageable for retrying.
-		return p.changeNodeProvisionState(
+		return p.changeNodeProvisionState(ctx,
 			ironicNode,
 			nodes.ProvisionStateOpts{Target: nodes.TargetManage},
 		)
@@ -1669,7 +1868,7 @@ func (p *ironicProvisioner) Deprovision(restartOnFailure bool) (result provision
 		// wants to delete a host without cleaning, they can always set
 		// automatedCleaningMode: disabled.
 		p.log.Info("deprovisioning node is in manageable state", "automatedClean", ironicNode.AutomatedClean)
-		return p.changeNodeProvisionState(
+		return p.changeNodeProvisionState(ctx,
 			ironicNode,
 			nodes.ProvisionStateOpts{Target: nodes.TargetProvide},
 		)
@@ -1697,10 +1896,32 @@ func (p *ironicProvisioner) Deprovision(restartOnFailure bool) (result provision
 		// Deploying cannot be stopped, wait for DeployWait or Active
 		return operationContinuing(deprovisionRequeueDelay)
 
-	case nodes.Active, nodes.DeployFail, nodes.DeployWait:
+	case nodes.Rescuing, nodes.RescueWait, nodes.Unrescuing:
+		p.log.Info("previous rescue operation running")
+		return operationContinuing(deprovisionRequeueDelay)
+
+	case nodes.Servicing, nodes.ServiceWait:
+		p.log.Info("previous servicing running")
+		return operationContinuing(deprovisionRequeueDelay)
+
+	case nodes.ServiceFail:
+		// Servicing has to be aborted before the node can be torn down.
+		// Transitions to Active upon completion.
+		if ironicNode.Maintenance {
+			p.log.Info("clearing maintenance flag", "maintenanceReason", ironicNode.MaintenanceReason)
+			return p.setMaintenanceFlag(ctx, ironicNode, false, "")
+		}
+		p.log.Info("aborting failed servicing before deprovisioning")
+		return p.changeNodeProvisionState(ctx,
+			ironicNode,
+			nodes.ProvisionStateOpts{Target: nodes.TargetAbort},
+		)
+
+	case nodes.Active, nodes.DeployFail, nodes.DeployWait,
+		nodes.Rescue, nodes.RescueFail, nodes.UnrescueFail:
 		p.log.Info("starting deprovisioning", "automatedClean", ironicNode.AutomatedClean)
 		p.publisher("DeprovisioningStarted", "Image deprovisioning started")
-		return p.changeNodeProvisionState(
+		return p.changeNodeProvisionState(ctx,
 			ironicNode,
 			nodes.ProvisionStateOpts{Target: nodes.TargetDeleted},
 		)
@@ -1711,11 +1932,133 @@ func (p *ironicProvisioner) Deprovision(restartOnFailure bool) (result provision
 	}
 }
 
+// Rescue boots the provisioned host into a rescue ramdisk. It may be
+// called multiple times, and should return true for its dirty flag
+// until the host is rescued.
+func (p *ironicProvisioner) Rescue(ctx context.Context, data provisioner.RescueData, restartOnFailure bool) (result provisioner.Result, err error) {
+	ironicNode, err := p.getNode(ctx)
+	if err != nil {
+		return transientError(err)
+	}
+
+	switch nodes.ProvisionState(ironicNode.ProvisionState) {
+	case nodes.Rescue:
+		p.log.Info("host is rescued")
+		return operationComplete()
+
+	case nodes.Rescuing, nodes.RescueWait:
+		p.log.Info("waiting for host to be rescued", "state", ironicNode.ProvisionState)
+		return operationContinuing(provisionRequeueDelay)
+
+	case nodes.RescueFail, nodes.UnrescueFail:
+		if !restartOnFailure {
+			p.log.Info("rescue failed", "lastError", ironicNode.LastError)
+			return operationFailed(fmt.Sprintf("Rescue failed: %s", ironicNode.LastError))
+		}
+		p.log.Info("retrying rescue")
+		fallthrough
+
+	case nodes.Active:
+		// The agent ramdisk doubles as the rescue ramdisk.
+		updater := clients.UpdateOptsBuilder(p.log)
+		if ironicNode.RescueInterface == "" || ironicNode.RescueInterface == "no-rescue" {
+			updater.SetTopLevelOpt("rescue_interface", "agent", ironicNode.RescueInterface)
+		}
+		_, success, result, err := p.tryUpdateNode(ctx, ironicNode, updater)
+		if !success {
+			return result, err
+		}
+
+		p.log.Info("starting rescue")
+		p.publisher("RescueStarted", "Rescue started")
+		return p.changeNodeProvisionState(ctx,
+			ironicNode,
+			nodes.ProvisionStateOpts{
+				Target:         nodes.TargetRescue,
+				RescuePassword: data.Password,
+			},
+		)
+
+	default:
+		return transientError(fmt.Errorf("have unexpected ironic node state %s", ironicNode.ProvisionState))
+	}
+}
+
+// Unrescue boots the rescued host back into its image. It may be
+// called multiple times, and should return true for its dirty flag
+// until the host is running its image again.
+func (p *ironicProvisioner) Unrescue(ctx context.Context, restartOnFailure bool) (result provisioner.Result, err error) {
+	ironicNode, err := p.getNode(ctx)
+	if err != nil {
+		return transientError(err)
+	}
+
+	switch nodes.ProvisionState(ironicNode.ProvisionState) {
+	case nodes.Active:
+		p.log.Info("host is not rescued")
+		return operationComplete()
+
+	case nodes.Unrescuing:
+		p.log.Info("waiting for host to be unrescued", "state", ironicNode.ProvisionState)
+		return operationContinuing(provisionRequeueDelay)
+
+	case nodes.UnrescueFail:
+		if !restartOnFailure {
+			p.log.Info("unrescue failed", "lastError", ironicNode.LastError)
+			return operationFailed(fmt.Sprintf("Unrescue failed: %s", ironicNode.LastError))
+		}
+		p.log.Info("retrying unrescue")
+		fallthrough
+
+	case nodes.Rescue, nodes.RescueFail:
+		p.log.Info("starting unrescue")
+		p.publisher("UnrescueStarted", "Unrescue started")
+		return p.changeNodeProvisionState(ctx,
+			ironicNode,
+			nodes.ProvisionStateOpts{Target: nodes.TargetUnrescue},
+		)
+
+	case nodes.Rescuing, nodes.RescueWait:
+		// Rescue cannot be stopped, wait for it to finish or fail
+		p.log.Info("waiting for rescue to finish", "state", ironicNode.ProvisionState)
+		return operationContinuing(provisionRequeueDelay)
+
+	default:
+		return transientError(fmt.Errorf("have unexpected ironic node state %s", ironicNode.ProvisionState))
+	}
+}
+
+// SetBootDevice sets the device the host boots from using the
+// management interface of the node.
+func (p *ironicProvisioner) SetBootDevice(ctx context.Context, data provisioner.BootDeviceData) (result provisioner.Result, err error) {
+	ironicNode, err := p.getNode(ctx)
+	if err != nil {
+		return transientError(err)
+	}
+
+	p.log.Info("setting boot device", "device", data.Device, "persistent", data.Persistent)
+	err = nodes.SetBootDevice(ctx, p.client, ironicNode.UUID, nodes.BootDeviceOpts{
+		BootDevice: string(data.Device),
+		Persistent: data.Persistent,
+	}).ExtractErr()
+	switch {
+	case err == nil:
+		return operationComplete()
+	case gophercloud.ResponseCodeIs(err, 409):
+		p.log.Info("could not set boot device, host is busy")
+		return retryAfterDelay(powerRequeueDelay)
+	case gophercloud.ResponseCodeIs(err, 400):
+		return operationFailed(fmt.Sprintf("failed to set boot device: %s", err))
+	default:
+		return transientError(fmt.Errorf("failed to set boot device: %w", err))
+	}
+}
+
 // Delete removes the host from the provisioning system. It may be
 // called multiple times, and should return true for its dirty flag
 // until the deprovisioning operation is completed.
-func (p *ironicProvisioner) Delete() (result provisioner.Result, err error) {
-	ironicNode, err := p.getNode()
+func (p *ironicProvisioner) Delete(ctx context.Context) (result provisioner.Result, err error) {
+	ironicNode, err := p.getNode(ctx)
 	if err != nil {
 		if errors.Is(err, provisioner.ErrNeedsRegistration) {
 			p.log.Info("no node found, already deleted")
@@ -1739,7 +2082,7 @@ func (p *ironicProvisioner) Delete() (result provisioner.Result, err error) {
 			p.log.Info("removing stale instance UUID before deletion", "instanceUUID", ironicNode.InstanceUUID)
 			updater := clients.UpdateOptsBuilder(p.log)
 			updater.SetTopLevelOpt("instance_uuid", nil, ironicNode.InstanceUUID)
-			_, success, result, err := p.tryUpdateNode(ironicNode, updater)
+			_, success, result, err := p.tryUpdateNode(ctx, ironicNode, updater)
 			if !success {
 				return result, err
 			}
@@ -1757,11 +2100,11 @@ func (p *ironicProvisioner) Delete() (result provisioner.Result, err error) {
 		// delete while bypassing Ironic's internal checks related to
 		// Nova.
 		p.log.Info("setting host maintenance flag to force image delete")
-		return p.setMaintenanceFlag(ironicNode, true, "forcing deletion in baremetal-operator")
+		return p.setMaintenanceFlag(ctx, ironicNode, true, "forcing deletion in baremetal-operator")
 	}
 
 	p.log.Info("host ready to be removed")
-	err = nodes.Delete(p.ctx, p.client, ironicNode.UUID).ExtractErr()
+	err = nodes.Delete(ctx, p.client, ironicNode.UUID).ExtractErr()
 	if err == nil {
 		p.log.Info("removed")
 	} else if gophercloud.ResponseCodeIs(err, 409) {
@@ -1781,9 +2124,9 @@ func (p *ironicProvisioner) Delete() (result provisioner.Result, err error) {
 // for the target system.  It may be called multiple times,
 // and should return true for its dirty  flag until the
 // deletion operation is completed.
-func (p *ironicProvisioner) Detach() (result provisioner.Result, err error) {
+func (p *ironicProvisioner) Detach(ctx context.Context) (result provisioner.Result, err error) {
 	// Currently the same behavior as Delete()
-	return p.Delete()
+	return p.Delete(ctx)
 }
 
 // softPowerOffUnsupportedError is returned when the BMC does not
@@ -1800,7 +2143,7 @@ func (e softPowerOffUnsupportedError) Error() string {
 	return "soft power off is unsupported on BMC"
 }
 
-func (p *ironicProvisioner) changePower(ironicNode *nodes.Node, target nodes.TargetPowerState) (result provisioner.Result, err error) {
+func (p *ironicProvisioner) changePower(ctx context.Context, ironicNode *nodes.Node, target nodes.TargetPowerState) (result provisioner.Result, err error) {
 	p.log.Info("changing power state")
 
 	if ironicNode.TargetProvisionState != "" {
@@ -1819,13 +2162,16 @@ func (p *ironicProvisioner) changePower(ironicNode *nodes.Node, target nodes.Tar
 	}
 
 	changeResult := nodes.ChangePowerState(
-		p.ctx,
+		ctx,
 		p.client,
 		ironicNode.UUID,
 		powerStateOpts)
 
 	if changeResult.Err == nil {
 		p.log.Info("power change OK")
+		if p.endpoint.nodes != nil {
+			p.endpoint.nodes.forget(ironicNode.UUID)
+		}
 		event := map[nodes.TargetPowerState]struct{ Event, Reason string }{
 			nodes.PowerOn:      {Event: "PowerOn", Reason: "Host powered on"},
 			nodes.PowerOff:     {Event: "PowerOff", Reason: "Host powered off"},
@@ -1848,10 +2194,10 @@ func (p *ironicProvisioner) changePower(ironicNode *nodes.Node, target nodes.Tar
 
 // PowerOn ensures the server is powered on independently of any image
 // provisioning operation.
-func (p *ironicProvisioner) PowerOn(force bool) (result provisioner.Result, err error) {
+func (p *ironicProvisioner) PowerOn(ctx context.Context, force bool) (result provisioner.Result, err error) {
 	p.log.Info("ensuring host is powered on")
 
-	ironicNode, err := p.getNode()
+	ironicNode, err := p.getNode(ctx)
 	if err != nil {
 		return transientError(err)
 	}
@@ -1869,17 +2215,17 @@ func (p *ironicProvisioner) PowerOn(force bool) (result provisioner.Result, err
 			return operationFailed(fmt.Sprintf("PowerOn operation failed: %s",
 				ironicNode.LastError))
 		}
-		return p.changePower(ironicNode, nodes.PowerOn)
+		return p.changePower(ctx, ironicNode, nodes.PowerOn)
 	}
 	return result, nil
 }
 
 // PowerOff ensures the server is powered off independently of any image
 // provisioning operation.
-func (p *ironicProvisioner) PowerOff(rebootMode metal3api.RebootMode, force bool) (result provisioner.Result, err error) {
+func (p *ironicProvisioner) PowerOff(ctx context.Context, rebootMode metal3api.RebootMode, force bool) (result provisioner.Result, err error) {
 	p.log.Info(fmt.Sprintf("ensuring host is powered off (mode: %s)", rebootMode))
 
-	ironicNode, err := p.getNode()
+	ironicNode, err := p.getNode(ctx)
 	if err != nil {
 		return transientError(err)
 	}
@@ -1899,13 +2245,13 @@ func (p *ironicProvisioner) PowerOff(rebootMode metal3api.RebootMode, force bool
 		}
 
 		if rebootMode == metal3api.RebootModeSoft && !force {
-			result, err = p.changePower(ironicNode, nodes.SoftPowerOff)
+			result, err = p.changePower(ctx, ironicNode, nodes.SoftPowerOff)
 			if !errors.As(err, &softPowerOffUnsupportedError{}) {
 				return result, err
 			}
 		}
 		// Reboot mode is hard, force flag is set, or soft power off is not supported
-		return p.changePower(ironicNode, nodes.PowerOff)
+		return p.changePower(ctx, ironicNode, nodes.PowerOff)
 	}
 
 	return operationComplete()
@@ -1915,7 +2261,7 @@ func ironicNodeName(objMeta metav1.ObjectMeta) string {
 	return objMeta.Namespace + nameSeparator + objMeta.Name
 }
 
-func (p *ironicProvisioner) HasCapacity() (result bool, err error) {
+func (p *ironicProvisioner) HasCapacity(ctx context.Context) (result bool, err error) {
 	bmcAccess, err := p.bmcAccess()
 	if err != nil {
 		return false, err // shouldn't actually happen so late in the process
@@ -1927,7 +2273,7 @@ func (p *ironicProvisioner) HasCapacity() (result bool, err error) {
 		return true, nil
 	}
 
-	hosts, err := p.loadBusyHosts()
+	hosts, err := p.loadBusyHosts(ctx)
 	if err != nil {
 		p.log.Error(err, "Unable to get hosts for determining current provisioner capacity")
 		return false, err
@@ -1938,45 +2284,34 @@ func (p *ironicProvisioner) HasCapacity() (result bool, err error) {
 		return true, nil
 	}
 
-	return len(hosts) < p.config.maxBusyHosts, nil
-}
+	if len(hosts) >= p.config.maxBusyHosts {
+		return false, nil
+	}
 
-func (p *ironicProvisioner) loadBusyHosts() (hosts map[string]struct{}, err error) {
-	hosts = make(map[string]struct{})
-	pager := nodes.List(p.client, nodes.ListOpts{
-		Fields: []string{"uuid,name,provision_state,boot_interface"},
-	})
+	// The cached view only shows the host as busy once refreshed, so
+	// count it against the capacity until then.
+	if p.endpoint.nodes != nil {
+		p.endpoint.nodes.reserve(ironicNodeName(p.objectMeta))
+	}
+	return true, nil
+}
 
-	page, err := pager.AllPages(p.ctx)
-	if err != nil {
-		return nil, err
+func (p *ironicProvisioner) loadBusyHosts(ctx context.Context) (hosts map[string]struct{}, err error) {
+	if p.endpoint.nodes != nil {
+		return p.endpoint.nodes.busyHosts(ctx, p.client)
 	}
 
-	allNodes, err := nodes.ExtractNodes(page)
+	allNodes, err := listNodes(ctx, p.client)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, node := range allNodes {
-		switch nodes.ProvisionState(node.ProvisionState) {
-		case nodes.Cleaning, nodes.CleanWait,
-			nodes.Inspecting, nodes.InspectWait,
-			nodes.Deploying, nodes.DeployWait,
-			nodes.Deleting:
-			// FIXME(dtantsur): this is a bit silly, but we don't have an easy way
-			// to reconstruct AccessDetails from a DriverInfo.
-			if !strings.Contains(node.BootInterface, "virtual-media") {
-				hosts[node.Name] = struct{}{}
-			}
-		}
-	}
-
-	return hosts, nil
+	return busyHosts(allNodes), nil
 }
 
-func (p *ironicProvisioner) AddBMCEventSubscriptionForNode(subscription *metal3api.BMCEventSubscription, httpHeaders provisioner.HTTPHeaders) (result provisioner.Result, err error) {
+func (p *ironicProvisioner) AddBMCEventSubscriptionForNode(ctx context.Context, subscription *metal3api.BMCEventSubscription, httpHeaders provisioner.HTTPHeaders) (result provisioner.Result, err error) {
 	newSubscription, err := nodes.CreateSubscription(
-		p.ctx,
+		ctx,
 		p.client,
 		p.nodeID,
 		nodes.CallVendorPassthruOpts{
@@ -1995,14 +2330,14 @@ func (p *ironicProvisioner) AddBMCEventSubscriptionForNode(subscription *metal3a
 	return operationComplete()
 }
 
-func (p *ironicProvisioner) RemoveBMCEventSubscriptionForNode(subscription metal3api.BMCEventSubscription) (result provisioner.Result, err error) {
+func (p *ironicProvisioner) RemoveBMCEventSubscriptionForNode(ctx context.Context, subscription metal3api.BMCEventSubscription) (result provisioner.Result, err error) {
 	method := nodes.CallVendorPassthruOpts{
 		Method: "delete_subscription",
 	}
 	opts := nodes.DeleteSubscriptionOpts{
 		Id: subscription.Status.SubscriptionID,
 	}
-	err = nodes.DeleteSubscription(p.ctx, p.client, p.nodeID, method, opts).ExtractErr()
+	err = nodes.DeleteSubscription(ctx, p.client, p.nodeID, method, opts).ExtractErr()
 
 	if err != nil {
 		return provisioner.Result{RequeueAfter: subscriptionRequeueDelay}, err
@@ -2014,7 +2349,7 @@ func (p *ironicProvisioner) RemoveBMCEventSubscriptionForNode(subscription metal
 // api is available.
 // Checks if the last VirtualMedia action(attach/detach) to a BareMetalHost was
 // successful of not.
-func (p *ironicProvisioner) IsDataImageReady() (isNodeBusy bool, nodeError error) {
+func (p *ironicProvisioner) IsDataImageReady(ctx context.Context) (isNodeBusy bool, nodeError error) {
 	// TODO(hroyrh)
 	// Get BareMetalHost VirtualMedia details using a GET api to vmedia
 
@@ -2024,7 +2359,7 @@ func (p *ironicProvisioner) IsDataImageReady() (isNodeBusy bool, nodeError error
 		return true, fmt.Errorf("ironic version=%d doesn't support DataImage API, needs version>=1.89", p.availableFeatures.MaxVersion)
 	}
 
-	node, err := p.getNode()
+	node, err := p.getNode(ctx)
 	if err != nil {
 		return true, err
 	}
@@ -2040,14 +2375,14 @@ func (p *ironicProvisioner) IsDataImageReady() (isNodeBusy bool, nodeError error
 	return isNodeBusy, fmt.Errorf("last dataImage action failed, %s", node.LastError)
 }
 
-func (p *ironicProvisioner) AttachDataImage(url string) (err error) {
+func (p *ironicProvisioner) AttachDataImage(ctx context.Context, url string) (err error) {
 	// Check if Ironic API version supports DataImage API
 	// Needs version >= 1.89
 	if !p.availableFeatures.HasDataImage() {
 		return fmt.Errorf("ironic version=%d doesn't support DataImage API, needs version>=1.89", p.availableFeatures.MaxVersion)
 	}
 
-	err = nodes.AttachVirtualMedia(p.ctx, p.client, p.nodeID, nodes.AttachVirtualMediaOpts{
+	err = nodes.AttachVirtualMedia(ctx, p.client, p.nodeID, nodes.AttachVirtualMediaOpts{
 		DeviceType: nodes.VirtualMediaCD,
 		ImageURL:   url,
 	}).ExtractErr()
@@ -2058,14 +2393,14 @@ func (p *ironicProvisioner) AttachDataImage(url string) (err error) {
 	return nil
 }
 
-func (p *ironicProvisioner) DetachDataImage() (err error) {
+func (p *ironicProvisioner) DetachDataImage(ctx context.Context) (err error) {
 	// Check if Ironic API version supports DataImage API
 	// Needs version >= 1.89
 	if !p.availableFeatures.HasDataImage() {
 		return fmt.Errorf("ironic version=%d doesn't support DataImage API, needs version>=1.89", p.availableFeatures.MaxVersion)
 	}
 
-	err = nodes.DetachVirtualMedia(p.ctx, p.client, p.nodeID, nodes.DetachVirtualMediaOpts{
+	err = nodes.DetachVirtualMedia(ctx, p.client, p.nodeID, nodes.DetachVirtualMediaOpts{
 		DeviceTypes: []nodes.VirtualMediaDeviceType{nodes.VirtualMediaCD},
 	}).ExtractErr()
 	if err != nil {
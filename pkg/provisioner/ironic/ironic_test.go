@@ -32,6 +32,7 @@ func newTestProvisionerFactory() ironicProvisionerFactory {
 			deployISOURL:     "http://deploy.test/ipa.iso",
 			maxBusyHosts:     20,
 		},
+		endpoints: newSingleEndpointSet(nil),
 	}
 }
 
@@ -47,8 +48,8 @@ func newProvisionerWithSettings(host metal3api.BareMetalHost, bmcCreds bmc.Crede
 	}
 
 	factory := newTestProvisionerFactory()
-	factory.clientIronic = clientIronic
-	return factory.ironicProvisioner(context.TODO(), hostData, publisher)
+	factory.endpoints = newSingleEndpointSet(clientIronic)
+	return factory.ironicProvisioner(hostData, publisher)
 }
 
 func makeHost() metal3api.BareMetalHost {
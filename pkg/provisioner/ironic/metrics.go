@@ -0,0 +1,23 @@
+package ironic
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const labelEndpoint = "endpoint"
+
+var nodeCacheHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "metal3_ironic_node_cache_hits_total",
+	Help: "Number of node lookups answered from the cached view of the ironic nodes",
+}, []string{labelEndpoint})
+var nodeCacheMisses = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "metal3_ironic_node_cache_misses_total",
+	Help: "Number of node lookups that required a request to ironic",
+}, []string{labelEndpoint})
+
+func init() {
+	metrics.Registry.MustRegister(
+		nodeCacheHits,
+		nodeCacheMisses)
+}
@@ -0,0 +1,207 @@
+package ironic
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/nodes"
+)
+
+// nodeCache is a view of all the nodes of an ironic endpoint, shared by
+// the provisioners of all hosts assigned to it. The view is refreshed by
+// listing the nodes once it is older than the refresh interval, so that
+// polling every host does not mean a request per host.
+type nodeCache struct {
+	endpoint string
+	interval time.Duration
+
+	// refreshMu serialises the refreshes, so that callers arriving
+	// during one wait for its result instead of listing again.
+	refreshMu sync.Mutex
+
+	mu        sync.Mutex
+	nodes     map[string]nodes.Node
+	refreshed time.Time
+	// forgotten holds the time at which nodes known to have changed were
+	// dropped, so that a refresh started earlier does not restore them.
+	forgotten map[string]time.Time
+	// reserved holds the time at which hosts were granted provisioning
+	// capacity, so that they count as busy until the view shows them.
+	reserved map[string]time.Time
+}
+
+func newNodeCache(endpoint string, interval time.Duration) *nodeCache {
+	return &nodeCache{
+		endpoint:  endpoint,
+		interval:  interval,
+		nodes:     map[string]nodes.Node{},
+		forgotten: map[string]time.Time{},
+		reserved:  map[string]time.Time{},
+	}
+}
+
+// refresh lists the nodes again if the view is too old. It returns
+// whether the view was fresh enough to be used as is.
+func (c *nodeCache) refresh(ctx context.Context, client *gophercloud.ServiceClient) (fresh bool, err error) {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	c.mu.Lock()
+	fresh = !c.refreshed.IsZero() && time.Since(c.refreshed) < c.interval
+	c.mu.Unlock()
+	if fresh {
+		return true, nil
+	}
+
+	start := time.Now()
+	allNodes, err := listNodes(ctx, client)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nodes = make(map[string]nodes.Node, len(allNodes))
+	for _, node := range allNodes {
+		if forgotten, ok := c.forgotten[node.UUID]; ok && forgotten.After(start) {
+			continue
+		}
+		c.nodes[node.UUID] = node
+	}
+	for uuid, forgotten := range c.forgotten {
+		if forgotten.Before(start) {
+			delete(c.forgotten, uuid)
+		}
+	}
+	for name, reserved := range c.reserved {
+		if reserved.Before(start) {
+			delete(c.reserved, name)
+		}
+	}
+	c.refreshed = start
+	return false, nil
+}
+
+// busyHosts returns the names of the hosts counting against the
+// provisioning capacity of the endpoint.
+func (c *nodeCache) busyHosts(ctx context.Context, client *gophercloud.ServiceClient) (map[string]struct{}, error) {
+	fresh, err := c.refresh(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	c.record(fresh)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	allNodes := make([]nodes.Node, 0, len(c.nodes))
+	for _, node := range c.nodes {
+		allNodes = append(allNodes, node)
+	}
+	hosts := busyHosts(allNodes)
+	for name := range c.reserved {
+		hosts[name] = struct{}{}
+	}
+	return hosts, nil
+}
+
+// reserve counts the host as busy until the view has been refreshed.
+func (c *nodeCache) reserve(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reserved[name] = time.Now()
+}
+
+// node returns the cached copy of a node, or nil if the node is not
+// in the view.
+func (c *nodeCache) node(ctx context.Context, client *gophercloud.ServiceClient, uuid string) (*nodes.Node, error) {
+	fresh, err := c.refresh(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	node, ok := c.nodes[uuid]
+	c.record(fresh && ok)
+	if !ok {
+		return nil, nil
+	}
+	return &node, nil
+}
+
+// powerStates returns the power state of all nodes in the view.
+func (c *nodeCache) powerStates(ctx context.Context, client *gophercloud.ServiceClient) (map[string]bool, error) {
+	if _, err := c.refresh(ctx, client); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	allNodes := make([]nodes.Node, 0, len(c.nodes))
+	for _, node := range c.nodes {
+		allNodes = append(allNodes, node)
+	}
+	return powerStates(allNodes), nil
+}
+
+// forget drops a node whose state is known to be changing, so that it
+// is read from ironic until the next refresh.
+func (c *nodeCache) forget(uuid string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.nodes, uuid)
+	c.forgotten[uuid] = time.Now()
+}
+
+func (c *nodeCache) record(hit bool) {
+	if hit {
+		nodeCacheHits.WithLabelValues(c.endpoint).Inc()
+	} else {
+		nodeCacheMisses.WithLabelValues(c.endpoint).Inc()
+	}
+}
+
+func listNodes(ctx context.Context, client *gophercloud.ServiceClient) ([]nodes.Node, error) {
+	pager := nodes.List(client, nodes.ListOpts{
+		Fields: []string{"uuid,name,provision_state,power_state,boot_interface"},
+	})
+
+	page, err := pager.AllPages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return nodes.ExtractNodes(page)
+}
+
+func busyHosts(allNodes []nodes.Node) map[string]struct{} {
+	hosts := make(map[string]struct{})
+	for _, node := range allNodes {
+		switch nodes.ProvisionState(node.ProvisionState) {
+		case nodes.Cleaning, nodes.CleanWait,
+			nodes.Inspecting, nodes.InspectWait,
+			nodes.Deploying, nodes.DeployWait,
+			nodes.Deleting:
+			// FIXME(dtantsur): this is a bit silly, but we don't have an easy way
+			// to reconstruct AccessDetails from a DriverInfo.
+			if !strings.Contains(node.BootInterface, "virtual-media") {
+				hosts[node.Name] = struct{}{}
+			}
+		}
+	}
+	return hosts
+}
+
+func powerStates(allNodes []nodes.Node) map[string]bool {
+	states := make(map[string]bool, len(allNodes))
+	for _, node := range allNodes {
+		switch node.PowerState {
+		case powerOn, powerOff:
+			states[node.UUID] = node.PowerState == powerOn
+		}
+	}
+	return states
+}
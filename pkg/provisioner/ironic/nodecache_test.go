@@ -0,0 +1,137 @@
+package ironic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/nodes"
+	"github.com/metal3-io/baremetal-operator/pkg/hardwareutils/bmc"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/clients"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/testserver"
+	promutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCachingTestFactory(t *testing.T, ironicURL string) ironicProvisionerFactory {
+	t.Helper()
+	set, err := newEndpointSet(endpointConfig{
+		urls: map[string][]string{t.Name(): {ironicURL}},
+	}, clients.AuthConfig{Type: clients.NoAuth}, clients.TLSConfig{}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	factory := newTestProvisionerFactory()
+	factory.endpoints = set
+	return factory
+}
+
+func TestNodeCacheCapacity(t *testing.T) {
+	ironic := testserver.NewIronic(t).Nodes([]nodes.Node{
+		{UUID: "uuid-busy", Name: "myns" + nameSeparator + "busy", ProvisionState: string(nodes.Deploying)},
+		{UUID: "uuid-idle", Name: "myns" + nameSeparator + "idle", ProvisionState: string(nodes.Active)},
+	}).Start()
+	defer ironic.Stop()
+
+	factory := newCachingTestFactory(t, ironic.Endpoint())
+	factory.config.maxBusyHosts = 2
+
+	hasCapacity := func(name string) bool {
+		host := makeHost()
+		host.Name = name
+		host.Status.Provisioning.Endpoint = t.Name()
+		prov, err := factory.ironicProvisioner(provisioner.BuildHostData(host, bmc.Credentials{}), nullEventPublisher)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := prov.HasCapacity(context.TODO())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	assert.True(t, hasCapacity("first"))
+	// The first host now counts as busy, even though the nodes have not
+	// been listed again.
+	assert.False(t, hasCapacity("second"))
+	assert.True(t, hasCapacity("busy"))
+	assert.True(t, hasCapacity("first"))
+
+	assert.Equal(t, "/v1/nodes;", ironic.Requests)
+	assert.InDelta(t, 1, promutil.ToFloat64(nodeCacheMisses.WithLabelValues(t.Name())), 0)
+	assert.InDelta(t, 3, promutil.ToFloat64(nodeCacheHits.WithLabelValues(t.Name())), 0)
+}
+
+func TestNodeCachePowerState(t *testing.T) {
+	nodeUUID := "33ce8659-7400-4c68-9535-d10766f07a58"
+	ironic := testserver.NewIronic(t).Nodes([]nodes.Node{
+		{UUID: nodeUUID, Name: "myns" + nameSeparator + "myhost", PowerState: powerOff},
+	}).Node(nodes.Node{
+		UUID:       nodeUUID,
+		PowerState: powerOff,
+	}).WithNodeStatesPowerUpdate(nodeUUID, http.StatusAccepted)
+	ironic.Start()
+	defer ironic.Stop()
+
+	factory := newCachingTestFactory(t, ironic.Endpoint())
+	host := makeHost()
+	host.Status.Provisioning.ID = nodeUUID
+	host.Status.Provisioning.Endpoint = t.Name()
+	prov, err := factory.ironicProvisioner(provisioner.BuildHostData(host, bmc.Credentials{}), nullEventPublisher)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 {
+		hwState, err := prov.UpdateHardwareState(context.TODO())
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.False(t, *hwState.PoweredOn)
+	}
+	assert.Equal(t, "/v1/nodes;", ironic.Requests)
+
+	// Changing the power state drops the node from the cache until it is
+	// refreshed, so that the new state is read from ironic.
+	result, err := prov.PowerOn(context.TODO(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, result.Dirty)
+
+	ironic.Requests = ""
+	_, err = prov.UpdateHardwareState(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmt.Sprintf("/v1/nodes/%s;", nodeUUID), ironic.Requests)
+}
+
+func TestPowerStates(t *testing.T) {
+	ironic := testserver.NewIronic(t).Nodes([]nodes.Node{
+		{UUID: "uuid-on", PowerState: powerOn},
+		{UUID: "uuid-off", PowerState: powerOff},
+		{UUID: "uuid-unknown", PowerState: powerNone},
+	}).Start()
+	defer ironic.Stop()
+
+	for _, interval := range []time.Duration{0, time.Hour} {
+		set, err := newEndpointSet(endpointConfig{
+			urls: map[string][]string{"default": {ironic.Endpoint()}},
+		}, clients.AuthConfig{Type: clients.NoAuth}, clients.TLSConfig{}, interval)
+		if err != nil {
+			t.Fatal(err)
+		}
+		factory := newTestProvisionerFactory()
+		factory.endpoints = set
+
+		states, err := factory.PowerStates(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]bool{"uuid-on": true, "uuid-off": false}, states)
+	}
+}
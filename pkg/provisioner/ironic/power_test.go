@@ -1,6 +1,7 @@
 package ironic
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"testing"
@@ -117,7 +118,7 @@ func TestPowerOn(t *testing.T) {
 				t.Fatalf("could not create provisioner: %s", err)
 			}
 
-			result, err := prov.PowerOn(tc.force)
+			result, err := prov.PowerOn(context.TODO(), tc.force)
 
 			assert.Equal(t, tc.expectedDirty, result.Dirty)
 			assert.Equal(t, time.Second*time.Duration(tc.expectedRequestAfter), result.RequeueAfter)
@@ -267,7 +268,7 @@ func TestPowerOff(t *testing.T) {
 			}
 
 			// We pass the RebootMode type here to define the reboot action
-			result, err := prov.PowerOff(tc.rebootMode, tc.force)
+			result, err := prov.PowerOff(context.TODO(), tc.rebootMode, tc.force)
 
 			assert.Equal(t, tc.expectedDirty, result.Dirty)
 			assert.Equal(t, time.Second*time.Duration(tc.expectedRequestAfter), result.RequeueAfter)
@@ -311,15 +312,15 @@ func TestSoftPowerOffFallback(t *testing.T) {
 		t.Fatalf("could not create provisioner: %s", err)
 	}
 
-	_, err = prov.PowerOff(metal3api.RebootModeSoft, false)
+	_, err = prov.PowerOff(context.TODO(), metal3api.RebootModeSoft, false)
 	assert.Error(t, err)
 	assert.False(t, errors.As(err, &softPowerOffUnsupportedError{}))
 
-	_, err = prov.changePower(&node, nodes.PowerOff)
+	_, err = prov.changePower(context.TODO(), &node, nodes.PowerOff)
 	assert.Error(t, err)
 	assert.False(t, errors.As(err, &softPowerOffUnsupportedError{}))
 
-	_, err = prov.changePower(&node, nodes.SoftPowerOff)
+	_, err = prov.changePower(context.TODO(), &node, nodes.SoftPowerOff)
 	assert.Error(t, err)
 	assert.ErrorAs(t, err, &softPowerOffUnsupportedError{})
 }
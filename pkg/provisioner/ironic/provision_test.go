@@ -1,7 +1,9 @@
 package ironic
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"net/url"
 	"testing"
 	"time"
@@ -174,7 +176,7 @@ func TestProvision(t *testing.T) {
 				t.Fatalf("could not create provisioner: %s", err)
 			}
 
-			result, err := prov.Provision(provisioner.ProvisionData{
+			result, err := prov.Provision(context.TODO(), provisioner.ProvisionData{
 				Image:      testImage,
 				HostConfig: fixture.NewHostConfigData("testUserData", "test: NetworkData", "test: Meta"),
 				BootMode:   metal3api.DefaultBootMode,
@@ -284,6 +286,37 @@ func TestDeprovision(t *testing.T) {
 			expectedRequestAfter: 10,
 			expectedDirty:        true,
 		},
+		{
+			name: "image removed after servicing failure",
+			ironic: testserver.NewIronic(t).WithDefaultResponses().Node(nodes.Node{
+				ProvisionState: string(nodes.ServiceFail),
+				UUID:           nodeUUID,
+				LastError:      "update failed",
+			}),
+			expectedRequestAfter: 10,
+			expectedDirty:        true,
+		},
+		{
+			name: "image removed after servicing failure in maintenance",
+			ironic: testserver.NewIronic(t).WithDefaultResponses().Node(nodes.Node{
+				ProvisionState: string(nodes.ServiceFail),
+				UUID:           nodeUUID,
+				Maintenance:    true,
+			}).NodeMaintenance(nodes.Node{
+				UUID: nodeUUID,
+			}, false),
+			expectedRequestAfter: 0,
+			expectedDirty:        true,
+		},
+		{
+			name: "servicing state",
+			ironic: testserver.NewIronic(t).Node(nodes.Node{
+				ProvisionState: string(nodes.ServiceWait),
+				UUID:           nodeUUID,
+			}),
+			expectedRequestAfter: 10,
+			expectedDirty:        true,
+		},
 	}
 
 	for _, tc := range cases {
@@ -302,7 +335,7 @@ func TestDeprovision(t *testing.T) {
 				t.Fatalf("could not create provisioner: %s", err)
 			}
 
-			result, err := prov.Deprovision(false)
+			result, _, err := prov.Deprovision(context.TODO(), provisioner.DeprovisionData{}, false)
 
 			assert.Equal(t, tc.expectedDirty, result.Dirty)
 			assert.Equal(t, tc.expectedErrorMessage, result.ErrorMessage != "")
@@ -316,6 +349,135 @@ func TestDeprovision(t *testing.T) {
 	}
 }
 
+func TestDeprovisionEraseDisks(t *testing.T) {
+	nodeUUID := "33ce8659-7400-4c68-9535-d10766f07a58"
+	cases := []struct {
+		name                   string
+		state                  nodes.ProvisionState
+		properties             map[string]interface{}
+		erasureStarted         bool
+		preservedDisks         []metal3api.RootDeviceHints
+		expectedErasureStarted bool
+		expectedDirty          bool
+		expectedTarget         nodes.TargetProvisionState
+		expectedSkipDevices    string
+		expectedRemoveDevices  bool
+	}{
+		{
+			name:           "active state",
+			state:          nodes.Active,
+			expectedDirty:  true,
+			expectedTarget: nodes.TargetDeleted,
+		},
+		{
+			name:           "available state",
+			state:          nodes.Available,
+			expectedDirty:  true,
+			expectedTarget: nodes.TargetManage,
+		},
+		{
+			name:                   "manageable state",
+			state:                  nodes.Manageable,
+			expectedErasureStarted: true,
+			expectedDirty:          true,
+			expectedTarget:         nodes.TargetClean,
+		},
+		{
+			name:  "manageable state(preserved disks)",
+			state: nodes.Manageable,
+			preservedDisks: []metal3api.RootDeviceHints{
+				{SerialNumber: "S3EVNX0K"},
+			},
+			expectedErasureStarted: true,
+			expectedDirty:          true,
+			expectedTarget:         nodes.TargetClean,
+			expectedSkipDevices:    `"serial":"s== S3EVNX0K"`,
+		},
+		{
+			name:           "cleaning state",
+			state:          nodes.CleanWait,
+			erasureStarted: true,
+			expectedDirty:  true,
+		},
+		{
+			name:           "manageable state(erasure finished)",
+			state:          nodes.Manageable,
+			erasureStarted: true,
+			expectedDirty:  true,
+			expectedTarget: nodes.TargetProvide,
+		},
+		{
+			name:  "manageable state(erasure finished with preserved disks)",
+			state: nodes.Manageable,
+			properties: map[string]interface{}{
+				"skip_block_devices": []map[string]string{{"serial": "s== S3EVNX0K"}},
+			},
+			erasureStarted:        true,
+			expectedDirty:         true,
+			expectedRemoveDevices: true,
+		},
+		{
+			name:           "available state(erasure finished)",
+			state:          nodes.Available,
+			erasureStarted: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ironic := testserver.NewIronic(t).WithDefaultResponses().Node(nodes.Node{
+				ProvisionState: string(tc.state),
+				UUID:           nodeUUID,
+				Properties:     tc.properties,
+			})
+			ironic.Start()
+			defer ironic.Stop()
+
+			host := makeHost()
+			host.Status.Provisioning.ID = nodeUUID
+			publisher := func(reason, message string) {}
+			auth := clients.AuthConfig{Type: clients.NoAuth}
+			prov, err := newProvisionerWithSettings(host, bmc.Credentials{}, publisher, ironic.Endpoint(), auth)
+			if err != nil {
+				t.Fatalf("could not create provisioner: %s", err)
+			}
+
+			data := provisioner.DeprovisionData{
+				EraseDisks:     true,
+				PreservedDisks: tc.preservedDisks,
+				ErasureStarted: tc.erasureStarted,
+			}
+			result, erasureStarted, err := prov.Deprovision(context.TODO(), data, false)
+
+			assert.NoError(t, err)
+			assert.Empty(t, result.ErrorMessage)
+			assert.Equal(t, tc.expectedDirty, result.Dirty)
+			assert.Equal(t, tc.expectedErasureStarted, erasureStarted)
+
+			body, found := ironic.GetLastRequestFor("/v1/nodes/"+nodeUUID+"/states/provision", http.MethodPut)
+			assert.Equal(t, tc.expectedTarget != "", found)
+			if tc.expectedTarget != "" {
+				assert.Contains(t, body, fmt.Sprintf(`"target":"%s"`, tc.expectedTarget))
+			}
+			if tc.expectedTarget == nodes.TargetClean {
+				assert.Contains(t, body, "erase_devices")
+			}
+
+			update, _ := ironic.GetLastRequestFor("/v1/nodes/"+nodeUUID, http.MethodPatch)
+			switch {
+			case tc.expectedSkipDevices != "":
+				assert.Contains(t, update, "/properties/skip_block_devices")
+				assert.Contains(t, update, tc.expectedSkipDevices)
+			case tc.expectedRemoveDevices:
+				assert.Contains(t, update, `"op":"remove"`)
+				assert.Contains(t, update, "/properties/skip_block_devices")
+			default:
+				assert.NotContains(t, update, "skip_block_devices")
+			}
+		})
+	}
+}
+
 func TestIronicHasSameImage(t *testing.T) {
 	nodeUUID := "33ce8659-7400-4c68-9535-d10766f07a58"
 	cases := []struct {
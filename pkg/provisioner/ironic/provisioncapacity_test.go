@@ -1,6 +1,7 @@
 package ironic
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -103,7 +104,7 @@ func TestHasCapacity(t *testing.T) {
 			}
 			prov.config.maxBusyHosts = tc.provisioningLimit
 
-			result, err := prov.HasCapacity()
+			result, err := prov.HasCapacity(context.TODO())
 
 			assert.Equal(t, tc.expectedHasCapacity, result)
 
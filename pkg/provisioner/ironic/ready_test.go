@@ -1,6 +1,7 @@
 package ironic
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
@@ -63,7 +64,7 @@ func TestProvisionerIsReady(t *testing.T) {
 				t.Fatalf("could not create provisioner: %s", err)
 			}
 
-			ready, err := prov.TryInit()
+			ready, err := prov.TryInit(context.TODO())
 			if err != nil {
 				t.Fatalf("could not determine ready state: %s", err)
 			}
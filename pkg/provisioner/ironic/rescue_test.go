@@ -0,0 +1,211 @@
+package ironic
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/nodes"
+	"github.com/metal3-io/baremetal-operator/pkg/hardwareutils/bmc"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/clients"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRescue(t *testing.T) {
+	nodeUUID := "33ce8659-7400-4c68-9535-d10766f07a58"
+	cases := []struct {
+		name                 string
+		state                nodes.ProvisionState
+		rescueInterface      string
+		restartOnFailure     bool
+		expectedDirty        bool
+		expectedError        bool
+		expectedRequestAfter int
+		expectedTarget       nodes.TargetProvisionState
+		expectedUpdate       bool
+	}{
+		{
+			name:                 "active state",
+			state:                nodes.Active,
+			rescueInterface:      "agent",
+			expectedDirty:        true,
+			expectedRequestAfter: 10,
+			expectedTarget:       nodes.TargetRescue,
+		},
+		{
+			name:                 "active state(no rescue interface)",
+			state:                nodes.Active,
+			rescueInterface:      "no-rescue",
+			expectedDirty:        true,
+			expectedRequestAfter: 10,
+			expectedTarget:       nodes.TargetRescue,
+			expectedUpdate:       true,
+		},
+		{
+			name:                 "rescuing state",
+			state:                nodes.Rescuing,
+			expectedDirty:        true,
+			expectedRequestAfter: 10,
+		},
+		{
+			name:                 "rescueWait state",
+			state:                nodes.RescueWait,
+			expectedDirty:        true,
+			expectedRequestAfter: 10,
+		},
+		{
+			name:  "rescue state",
+			state: nodes.Rescue,
+		},
+		{
+			name:          "rescueFail state",
+			state:         nodes.RescueFail,
+			expectedError: true,
+		},
+		{
+			name:                 "rescueFail state(restart)",
+			state:                nodes.RescueFail,
+			rescueInterface:      "agent",
+			restartOnFailure:     true,
+			expectedDirty:        true,
+			expectedRequestAfter: 10,
+			expectedTarget:       nodes.TargetRescue,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ironic := testserver.NewIronic(t).WithDefaultResponses().Node(nodes.Node{
+				ProvisionState:  string(tc.state),
+				UUID:            nodeUUID,
+				RescueInterface: tc.rescueInterface,
+			})
+			ironic.Start()
+			defer ironic.Stop()
+
+			host := makeHost()
+			host.Status.Provisioning.ID = nodeUUID
+
+			publisher := func(reason, message string) {}
+			auth := clients.AuthConfig{Type: clients.NoAuth}
+			prov, err := newProvisionerWithSettings(host, bmc.Credentials{}, publisher, ironic.Endpoint(), auth)
+			if err != nil {
+				t.Fatalf("could not create provisioner: %s", err)
+			}
+
+			result, err := prov.Rescue(context.TODO(), provisioner.RescueData{Password: "s3cret"}, tc.restartOnFailure)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedDirty, result.Dirty)
+			assert.Equal(t, tc.expectedError, result.ErrorMessage != "")
+			assert.Equal(t, time.Second*time.Duration(tc.expectedRequestAfter), result.RequeueAfter)
+
+			body, found := ironic.GetLastRequestFor("/v1/nodes/"+nodeUUID+"/states/provision", http.MethodPut)
+			assert.Equal(t, tc.expectedTarget != "", found)
+			if tc.expectedTarget != "" {
+				assert.Contains(t, body, string(tc.expectedTarget))
+				assert.Contains(t, body, "s3cret")
+			}
+
+			update, _ := ironic.GetLastRequestFor("/v1/nodes/"+nodeUUID, http.MethodPatch)
+			assert.Equal(t, tc.expectedUpdate, update != "")
+			if tc.expectedUpdate {
+				assert.Contains(t, update, "/rescue_interface")
+			}
+		})
+	}
+}
+
+func TestUnrescue(t *testing.T) {
+	nodeUUID := "33ce8659-7400-4c68-9535-d10766f07a58"
+	cases := []struct {
+		name                 string
+		state                nodes.ProvisionState
+		restartOnFailure     bool
+		expectedDirty        bool
+		expectedError        bool
+		expectedRequestAfter int
+		expectedTarget       nodes.TargetProvisionState
+	}{
+		{
+			name:                 "rescue state",
+			state:                nodes.Rescue,
+			expectedDirty:        true,
+			expectedRequestAfter: 10,
+			expectedTarget:       nodes.TargetUnrescue,
+		},
+		{
+			name:                 "rescueFail state",
+			state:                nodes.RescueFail,
+			expectedDirty:        true,
+			expectedRequestAfter: 10,
+			expectedTarget:       nodes.TargetUnrescue,
+		},
+		{
+			name:                 "unrescuing state",
+			state:                nodes.Unrescuing,
+			expectedDirty:        true,
+			expectedRequestAfter: 10,
+		},
+		{
+			name:                 "rescueWait state",
+			state:                nodes.RescueWait,
+			expectedDirty:        true,
+			expectedRequestAfter: 10,
+		},
+		{
+			name:  "active state",
+			state: nodes.Active,
+		},
+		{
+			name:          "unrescueFail state",
+			state:         nodes.UnrescueFail,
+			expectedError: true,
+		},
+		{
+			name:                 "unrescueFail state(restart)",
+			state:                nodes.UnrescueFail,
+			restartOnFailure:     true,
+			expectedDirty:        true,
+			expectedRequestAfter: 10,
+			expectedTarget:       nodes.TargetUnrescue,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ironic := testserver.NewIronic(t).WithDefaultResponses().Node(nodes.Node{
+				ProvisionState: string(tc.state),
+				UUID:           nodeUUID,
+			})
+			ironic.Start()
+			defer ironic.Stop()
+
+			host := makeHost()
+			host.Status.Provisioning.ID = nodeUUID
+
+			publisher := func(reason, message string) {}
+			auth := clients.AuthConfig{Type: clients.NoAuth}
+			prov, err := newProvisionerWithSettings(host, bmc.Credentials{}, publisher, ironic.Endpoint(), auth)
+			if err != nil {
+				t.Fatalf("could not create provisioner: %s", err)
+			}
+
+			result, err := prov.Unrescue(context.TODO(), tc.restartOnFailure)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedDirty, result.Dirty)
+			assert.Equal(t, tc.expectedError, result.ErrorMessage != "")
+			assert.Equal(t, time.Second*time.Duration(tc.expectedRequestAfter), result.RequeueAfter)
+
+			body, found := ironic.GetLastRequestFor("/v1/nodes/"+nodeUUID+"/states/provision", http.MethodPut)
+			assert.Equal(t, tc.expectedTarget != "", found)
+			if tc.expectedTarget != "" {
+				assert.Contains(t, body, string(tc.expectedTarget))
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package ironic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/nodes"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/hardwareutils/bmc"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/clients"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/ironic/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService(t *testing.T) {
+	nodeUUID := "33ce8659-7400-4c68-9535-d10766f07a58"
+	cases := []struct {
+		name                 string
+		ironic               *testserver.IronicMock
+		maxVersion           int
+		unprepared           bool
+		restartOnFailure     bool
+		withUpdates          bool
+		expectedStarted      bool
+		expectedDirty        bool
+		expectedError        bool
+		expectedRequestAfter int
+	}{
+		{
+			name: "servicing not supported",
+			ironic: testserver.NewIronic(t).WithDefaultResponses().Node(nodes.Node{
+				ProvisionState: string(nodes.Active),
+				UUID:           nodeUUID,
+			}),
+			maxVersion:    86,
+			unprepared:    true,
+			withUpdates:   true,
+			expectedError: true,
+		},
+		{
+			name: "active state(haven't service steps)",
+			ironic: testserver.NewIronic(t).WithDefaultResponses().Node(nodes.Node{
+				ProvisionState: string(nodes.Active),
+				UUID:           nodeUUID,
+			}),
+			unprepared: true,
+		},
+		{
+			name: "active state(have service steps)",
+			ironic: testserver.NewIronic(t).WithDefaultResponses().Node(nodes.Node{
+				ProvisionState: string(nodes.Active),
+				UUID:           nodeUUID,
+			}),
+			unprepared:           true,
+			withUpdates:          true,
+			expectedStarted:      true,
+			expectedDirty:        true,
+			expectedRequestAfter: 10,
+		},
+		{
+			name: "active state(servicing finished)",
+			ironic: testserver.NewIronic(t).WithDefaultResponses().Node(nodes.Node{
+				ProvisionState: string(nodes.Active),
+				UUID:           nodeUUID,
+			}),
+			withUpdates: true,
+		},
+		{
+			name: "servicing state",
+			ironic: testserver.NewIronic(t).WithDefaultResponses().Node(nodes.Node{
+				ProvisionState: string(nodes.Servicing),
+				UUID:           nodeUUID,
+			}),
+			withUpdates:          true,
+			expectedDirty:        true,
+			expectedRequestAfter: 10,
+		},
+		{
+			name: "serviceWait state",
+			ironic: testserver.NewIronic(t).WithDefaultResponses().Node(nodes.Node{
+				ProvisionState: string(nodes.ServiceWait),
+				UUID:           nodeUUID,
+			}),
+			withUpdates:          true,
+			expectedDirty:        true,
+			expectedRequestAfter: 10,
+		},
+		{
+			name: "serviceFail state(failure not reported)",
+			ironic: testserver.NewIronic(t).WithDefaultResponses().Node(nodes.Node{
+				ProvisionState: string(nodes.ServiceFail),
+				UUID:           nodeUUID,
+				LastError:      "update failed",
+			}),
+			unprepared:    true,
+			withUpdates:   true,
+			expectedError: true,
+		},
+		{
+			name: "serviceFail state(restart servicing)",
+			ironic: testserver.NewIronic(t).WithDefaultResponses().Node(nodes.Node{
+				ProvisionState: string(nodes.ServiceFail),
+				UUID:           nodeUUID,
+			}),
+			unprepared:           true,
+			restartOnFailure:     true,
+			withUpdates:          true,
+			expectedStarted:      true,
+			expectedDirty:        true,
+			expectedRequestAfter: 10,
+		},
+		{
+			name: "serviceFail state(nothing left to apply)",
+			ironic: testserver.NewIronic(t).WithDefaultResponses().Node(nodes.Node{
+				ProvisionState: string(nodes.ServiceFail),
+				UUID:           nodeUUID,
+			}),
+			unprepared:           true,
+			restartOnFailure:     true,
+			expectedDirty:        true,
+			expectedRequestAfter: 10,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.ironic != nil {
+				tc.ironic.Start()
+				defer tc.ironic.Stop()
+			}
+
+			host := makeHost()
+			host.Status.Provisioning.ID = nodeUUID
+			serviceData := provisioner.ServicingData{}
+			if tc.withUpdates {
+				serviceData.TargetFirmwareComponents = []metal3api.FirmwareUpdate{
+					{
+						Component: "bios",
+						URL:       "https://example.com/bios.bin",
+					},
+				}
+			}
+
+			publisher := func(reason, message string) {}
+			auth := clients.AuthConfig{Type: clients.NoAuth}
+			prov, err := newProvisionerWithSettings(host, bmc.Credentials{}, publisher, tc.ironic.Endpoint(), auth)
+			if err != nil {
+				t.Fatalf("could not create provisioner: %s", err)
+			}
+			prov.availableFeatures = clients.AvailableFeatures{MaxVersion: 87}
+			if tc.maxVersion != 0 {
+				prov.availableFeatures.MaxVersion = tc.maxVersion
+			}
+
+			result, started, err := prov.Service(context.TODO(), serviceData, tc.unprepared, tc.restartOnFailure)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStarted, started)
+			assert.Equal(t, tc.expectedDirty, result.Dirty)
+			assert.Equal(t, tc.expectedError, result.ErrorMessage != "")
+			assert.Equal(t, time.Second*time.Duration(tc.expectedRequestAfter), result.RequeueAfter)
+		})
+	}
+}
@@ -284,6 +284,12 @@ func (m *IronicMock) WithNodeStatesPowerUpdate(nodeUUID string, code int) *Ironi
 	return m.withNodeStatesPower(nodeUUID, code, http.MethodPut)
 }
 
+// WithNodeBootDevice configures the server with a response for [PUT] /v1/nodes/<node>/management/boot_device.
+func (m *IronicMock) WithNodeBootDevice(nodeUUID string, code int) *IronicMock {
+	m.ResponseWithCode(m.buildURL(v1node+nodeUUID+"/management/boot_device", http.MethodPut), "", code)
+	return m
+}
+
 // WithNodeValidate configures the server with a valid response for /v1/nodes/<node>/validate.
 func (m *IronicMock) WithNodeValidate(nodeUUID string) *IronicMock {
 	m.ResponseWithCode(v1node+nodeUUID+"/validate", validateResult, http.StatusOK)
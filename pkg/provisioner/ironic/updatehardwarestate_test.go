@@ -1,6 +1,7 @@
 package ironic
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
@@ -127,7 +128,7 @@ func TestUpdateHardwareState(t *testing.T) {
 				t.Fatalf("could not create provisioner: %s", err)
 			}
 
-			hwStatus, err := prov.UpdateHardwareState()
+			hwStatus, err := prov.UpdateHardwareState(context.TODO())
 
 			assert.Equal(t, tc.expectUnreadablePower, hwStatus.PoweredOn == nil)
 
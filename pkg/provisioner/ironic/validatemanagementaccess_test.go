@@ -1,6 +1,7 @@
 package ironic
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
@@ -33,7 +34,7 @@ func TestValidateManagementAccessNoMAC(t *testing.T) {
 		t.Fatalf("could not create provisioner: %s", err)
 	}
 
-	result, _, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{}, false, false)
+	result, _, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{}, false, false)
 	if err != nil {
 		t.Fatalf("error from ValidateManagementAccess: %s", err)
 	}
@@ -64,7 +65,7 @@ func TestValidateManagementAccessMACOptional(t *testing.T) {
 		t.Fatalf("could not create provisioner: %s", err)
 	}
 
-	result, _, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{}, false, false)
+	result, _, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{}, false, false)
 	if err != nil {
 		t.Fatalf("error from ValidateManagementAccess: %s", err)
 	}
@@ -96,7 +97,7 @@ func TestValidateManagementAccessCreateNodeNoImage(t *testing.T) {
 		t.Fatalf("could not create provisioner: %s", err)
 	}
 
-	result, provID, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{}, false, false)
+	result, provID, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{}, false, false)
 	if err != nil {
 		t.Fatalf("error from ValidateManagementAccess: %s", err)
 	}
@@ -135,7 +136,7 @@ func TestValidateManagementAccessCreateNodeOldInspection(t *testing.T) {
 		t.Fatalf("could not create provisioner: %s", err)
 	}
 
-	result, provID, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{}, false, false)
+	result, provID, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{}, false, false)
 	if err != nil {
 		t.Fatalf("error from ValidateManagementAccess: %s", err)
 	}
@@ -170,7 +171,7 @@ func TestValidateManagementAccessCreateWithImage(t *testing.T) {
 		t.Fatalf("could not create provisioner: %s", err)
 	}
 
-	result, provID, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{CurrentImage: host.Spec.Image.DeepCopy()}, false, false)
+	result, provID, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{CurrentImage: host.Spec.Image.DeepCopy()}, false, false)
 	if err != nil {
 		t.Fatalf("error from ValidateManagementAccess: %s", err)
 	}
@@ -206,7 +207,7 @@ func TestValidateManagementAccessCreateWithLiveIso(t *testing.T) {
 		t.Fatalf("could not create provisioner: %s", err)
 	}
 
-	result, provID, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{CurrentImage: host.Spec.Image.DeepCopy()}, false, false)
+	result, provID, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{CurrentImage: host.Spec.Image.DeepCopy()}, false, false)
 	if err != nil {
 		t.Fatalf("error from ValidateManagementAccess: %s", err)
 	}
@@ -245,7 +246,7 @@ func TestValidateManagementAccessExistingNode(t *testing.T) {
 		t.Fatalf("could not create provisioner: %s", err)
 	}
 
-	result, provID, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{}, false, false)
+	result, provID, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{}, false, false)
 	if err != nil {
 		t.Fatalf("error from ValidateManagementAccess: %s", err)
 	}
@@ -280,7 +281,7 @@ func TestValidateManagementAccessExistingNodeNameUpdate(t *testing.T) {
 		t.Fatalf("could not create provisioner: %s", err)
 	}
 
-	result, _, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{}, false, false)
+	result, _, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{}, false, false)
 	if err != nil {
 		t.Fatalf("error from ValidateManagementAccess: %s", err)
 	}
@@ -353,7 +354,7 @@ func TestValidateManagementAccessExistingNodeContinue(t *testing.T) {
 				t.Fatalf("could not create provisioner: %s", err)
 			}
 
-			result, _, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{}, false, false)
+			result, _, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{}, false, false)
 			if err != nil {
 				t.Fatalf("error from ValidateManagementAccess: %s", err)
 			}
@@ -533,7 +534,7 @@ func TestValidateManagementAccessExistingSteadyStateNoUpdate(t *testing.T) {
 			}
 
 			data := provisioner.ManagementAccessData{CurrentImage: imageType.Image, HasCustomDeploy: imageType.HasCustomDeploy}
-			result, _, err := prov.ValidateManagementAccess(data, false, false)
+			result, _, err := prov.ValidateManagementAccess(context.TODO(), data, false, false)
 			if err != nil {
 				t.Fatalf("error from ValidateManagementAccess: %s", err)
 			}
@@ -589,7 +590,7 @@ func TestValidateManagementAccessExistingNodeWaiting(t *testing.T) {
 				t.Fatalf("could not create provisioner: %s", err)
 			}
 
-			result, _, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{}, false, false)
+			result, _, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{}, false, false)
 			if err != nil {
 				t.Fatalf("error from ValidateManagementAccess: %s", err)
 			}
@@ -645,7 +646,7 @@ func TestValidateManagementAccessNewCredentials(t *testing.T) {
 		t.Fatalf("could not create provisioner: %s", err)
 	}
 
-	result, provID, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{}, true, false)
+	result, provID, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{}, true, false)
 	if err != nil {
 		t.Fatalf("error from ValidateManagementAccess: %s", err)
 	}
@@ -692,7 +693,7 @@ func TestValidateManagementAccessLinkExistingIronicNodeByMAC(t *testing.T) {
 		t.Fatalf("could not create provisioner: %s", err)
 	}
 
-	result, provID, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{}, false, false)
+	result, provID, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{}, false, false)
 	if err != nil {
 		t.Fatalf("error from ValidateManagementAccess: %s", err)
 	}
@@ -734,7 +735,7 @@ func TestValidateManagementAccessExistingPortWithWrongUUID(t *testing.T) {
 		t.Fatalf("could not create provisioner: %s", err)
 	}
 
-	_, _, err = prov.ValidateManagementAccess(provisioner.ManagementAccessData{}, false, false)
+	_, _, err = prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{}, false, false)
 	expected := "failed to find existing host: port 11:11:11:11:11:11 exists but linked node random-wrong-id doesn't:"
 	assert.ErrorContains(t, err, expected)
 }
@@ -776,7 +777,7 @@ func TestValidateManagementAccessExistingPortButHasName(t *testing.T) {
 		t.Fatalf("could not create provisioner: %s", err)
 	}
 
-	res, _, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{}, false, false)
+	res, _, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{}, false, false)
 	assert.Nil(t, err)
 	assert.Equal(t, res.ErrorMessage, "MAC address 11:11:11:11:11:11 conflicts with existing node wrong-name")
 }
@@ -814,7 +815,7 @@ func TestValidateManagementAccessAddTwoHostsWithSameMAC(t *testing.T) {
 	}
 
 	// MAC address value is different than the port that actually exists
-	result, provID, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{}, false, false)
+	result, provID, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{}, false, false)
 	if err != nil {
 		t.Fatalf("error from ValidateManagementAccess: %s", err)
 	}
@@ -838,7 +839,7 @@ func TestValidateManagementAccessUnsupportedSecureBoot(t *testing.T) {
 		t.Fatalf("could not create provisioner: %s", err)
 	}
 
-	result, _, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{BootMode: metal3api.UEFISecureBoot}, false, false)
+	result, _, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{BootMode: metal3api.UEFISecureBoot}, false, false)
 	if err != nil {
 		t.Fatalf("error from ValidateManagementAccess: %s", err)
 	}
@@ -859,7 +860,7 @@ func TestValidateManagementAccessNoBMCDetails(t *testing.T) {
 		t.Fatalf("could not create provisioner: %s", err)
 	}
 
-	result, _, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{}, false, false)
+	result, _, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{}, false, false)
 	if err != nil {
 		t.Fatalf("error from ValidateManagementAccess: %s", err)
 	}
@@ -882,7 +883,7 @@ func TestValidateManagementAccessMalformedBMCAddress(t *testing.T) {
 		t.Fatalf("could not create provisioner: %s", err)
 	}
 
-	result, _, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{}, false, false)
+	result, _, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{}, false, false)
 	if err != nil {
 		t.Fatalf("error from ValidateManagementAccess: %s", err)
 	}
@@ -930,7 +931,7 @@ func TestValidateManagementAccessUpdateBMCAddressIP(t *testing.T) {
 		t.Fatalf("could not create provisioner: %s", err)
 	}
 
-	result, provID, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{}, false, false)
+	result, provID, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{}, false, false)
 	if err != nil {
 		t.Fatalf("error from ValidateManagementAccess: %s", err)
 	}
@@ -991,7 +992,7 @@ func TestValidateManagementAccessUpdateBMCAddressProtocol(t *testing.T) {
 		t.Fatalf("could not create provisioner: %s", err)
 	}
 
-	result, provID, err := prov.ValidateManagementAccess(provisioner.ManagementAccessData{}, false, false)
+	result, provID, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{}, false, false)
 	if err != nil {
 		t.Fatalf("error from ValidateManagementAccess: %s", err)
 	}
@@ -1046,7 +1047,7 @@ func TestPreprovisioningImageFormats(t *testing.T) {
 			prov, _ := newProvisionerWithSettings(host, bmc.Credentials{}, nil, ironicEndpoint, auth)
 			prov.config.havePreprovImgBuilder = tc.PreprovImgEnabled
 
-			fmts, err := prov.PreprovisioningImageFormats()
+			fmts, err := prov.PreprovisioningImageFormats(context.TODO())
 
 			assert.NoError(t, err)
 			assert.Equal(t, tc.Expected, fmts)
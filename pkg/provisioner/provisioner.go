@@ -27,6 +27,12 @@ type HostData struct {
 	DisableCertificateVerification bool
 	BootMACAddress                 string
 	ProvisionerID                  string
+	// Backend is the name of the provisioning backend requested for
+	// the host. An empty string means the default backend.
+	Backend string
+	// Endpoint is the name of the backend endpoint the host was last
+	// assigned to, for backends with more than one.
+	Endpoint string
 }
 
 func BuildHostData(host metal3api.BareMetalHost, bmcCreds bmc.Credentials) HostData {
@@ -37,6 +43,8 @@ func BuildHostData(host metal3api.BareMetalHost, bmcCreds bmc.Credentials) HostD
 		DisableCertificateVerification: host.Spec.BMC.DisableCertificateVerification,
 		BootMACAddress:                 host.Spec.BootMACAddress,
 		ProvisionerID:                  host.Status.Provisioning.ID,
+		Backend:                        host.ProvisioningBackend(),
+		Endpoint:                       host.Status.Provisioning.Endpoint,
 	}
 }
 
@@ -45,6 +53,8 @@ func BuildHostDataNoBMC(host metal3api.BareMetalHost) HostData {
 	return HostData{
 		ObjectMeta:    *host.ObjectMeta.DeepCopy(),
 		ProvisionerID: host.Status.Provisioning.ID,
+		Backend:       host.ProvisioningBackend(),
+		Endpoint:      host.Status.Provisioning.Endpoint,
 	}
 }
 
@@ -53,6 +63,19 @@ type Factory interface {
 	NewProvisioner(ctx context.Context, hostData HostData, publish EventPublisher) (Provisioner, error)
 }
 
+// PowerStatePoller is implemented by Factories able to read the power
+// state of all their hosts at once, sparing a request per host.
+type PowerStatePoller interface {
+	// PowerStates returns whether each host is powered on, keyed by
+	// provisioner ID. Hosts whose power state is unknown are left out.
+	// On error, the states that could be read are still returned.
+	PowerStates(ctx context.Context) (map[string]bool, error)
+
+	// PollsPowerState returns whether the power state of the host is
+	// included in PowerStates.
+	PollsPowerState(hostData HostData) bool
+}
+
 // HostConfigData retrieves host configuration data.
 type HostConfigData interface {
 	// UserData is the interface for a function to retrieve user
@@ -107,6 +130,40 @@ type PrepareData struct {
 	TargetFirmwareComponents []metal3api.FirmwareUpdate
 }
 
+// ServicingData holds the changes applied to a provisioned host.
+// FirmwareConfig contains the common BIOS settings of the host, and
+// ActualFirmwareSettings and TargetFirmwareSettings have the same meaning
+// as in PrepareData.
+type ServicingData struct {
+	FirmwareConfig           *metal3api.FirmwareConfig
+	TargetFirmwareSettings   metal3api.DesiredSettingsMap
+	ActualFirmwareSettings   metal3api.SettingsMap
+	TargetFirmwareComponents []metal3api.FirmwareUpdate
+}
+
+// RescueData holds the details of a rescue request.
+type RescueData struct {
+	// Password of the rescue user.
+	Password string
+}
+
+// BootDeviceData holds the details of a boot device request.
+type BootDeviceData struct {
+	Device     metal3api.BootDevice
+	Persistent bool
+}
+
+// DeprovisionData holds the details of a deprovisioning request.
+// When EraseDisks is set, all disks except the ones matching
+// PreservedDisks are securely erased after the image is removed.
+// ErasureStarted is set once the erasure has been started, so that it
+// is not started again.
+type DeprovisionData struct {
+	EraseDisks     bool
+	PreservedDisks []metal3api.RootDeviceHints
+	ErasureStarted bool
+}
+
 type ProvisionData struct {
 	Image           metal3api.Image
 	HostConfig      HostConfigData
@@ -128,90 +185,131 @@ type Provisioner interface {
 	// of credentials it has are different from the credentials it has
 	// previously been using, without implying that either set of
 	// credentials is correct.
-	ValidateManagementAccess(data ManagementAccessData, credentialsChanged, restartOnFailure bool) (result Result, provID string, err error)
+	ValidateManagementAccess(ctx context.Context, data ManagementAccessData, credentialsChanged, restartOnFailure bool) (result Result, provID string, err error)
 
 	// PreprovisioningImageFormats returns a list of acceptable formats for a
 	// pre-provisioning image to be built by a PreprovisioningImage object. The
 	// list should be nil if no image build is requested.
-	PreprovisioningImageFormats() ([]metal3api.ImageFormat, error)
+	PreprovisioningImageFormats(ctx context.Context) ([]metal3api.ImageFormat, error)
 
 	// InspectHardware updates the HardwareDetails field of the host with
 	// details of devices discovered on the hardware. It may be called
 	// multiple times, and should return true for its dirty flag until the
 	// inspection is completed.
-	InspectHardware(data InspectData, restartOnFailure, refresh, forceReboot bool) (result Result, started bool, details *metal3api.HardwareDetails, err error)
+	InspectHardware(ctx context.Context, data InspectData, restartOnFailure, refresh, forceReboot bool) (result Result, started bool, details *metal3api.HardwareDetails, err error)
 
 	// UpdateHardwareState fetches the latest hardware state of the
 	// server and updates the HardwareDetails field of the host with
 	// details. It is expected to do this in the least expensive way
 	// possible, such as reading from a cache.
-	UpdateHardwareState() (hwState HardwareState, err error)
+	UpdateHardwareState(ctx context.Context) (hwState HardwareState, err error)
 
 	// Adopt brings an externally-provisioned host under management by
 	// the provisioner.
-	Adopt(data AdoptData, restartOnFailure bool) (result Result, err error)
+	Adopt(ctx context.Context, data AdoptData, restartOnFailure bool) (result Result, err error)
 
 	// Prepare remove existing configuration and set new configuration
-	Prepare(data PrepareData, unprepared bool, restartOnFailure bool) (result Result, started bool, err error)
+	Prepare(ctx context.Context, data PrepareData, unprepared bool, restartOnFailure bool) (result Result, started bool, err error)
+
+	// Service applies firmware settings and updates to a provisioned
+	// host without removing its image. If `started` is true, the
+	// changes were passed to the provisioning backend.
+	Service(ctx context.Context, data ServicingData, unprepared bool, restartOnFailure bool) (result Result, started bool, err error)
 
 	// Provision writes the image from the host spec to the host. It
 	// may be called multiple times, and should return true for its
 	// dirty flag until the provisioning operation is completed.
-	Provision(data ProvisionData, forceReboot bool) (result Result, err error)
+	Provision(ctx context.Context, data ProvisionData, forceReboot bool) (result Result, err error)
+
+	// Rescue boots the provisioned host into a rescue ramdisk. It may
+	// be called multiple times, and should return true for its dirty
+	// flag until the host is rescued.
+	Rescue(ctx context.Context, data RescueData, restartOnFailure bool) (result Result, err error)
+
+	// Unrescue boots the rescued host back into its image. It may be
+	// called multiple times, and should return true for its dirty flag
+	// until the host is running its image again.
+	Unrescue(ctx context.Context, restartOnFailure bool) (result Result, err error)
 
 	// Deprovision removes the host from the image. It may be called
 	// multiple times, and should return true for its dirty flag until
-	// the deprovisioning operation is completed.
-	Deprovision(restartOnFailure bool) (result Result, err error)
+	// the deprovisioning operation is completed. If `erasureStarted`
+	// is true, the erasure of the disks was passed to the provisioning
+	// backend.
+	Deprovision(ctx context.Context, data DeprovisionData, restartOnFailure bool) (result Result, erasureStarted bool, err error)
+
+	// SetBootDevice sets the device the host boots from, either for
+	// the next boot only or persistently.
+	SetBootDevice(ctx context.Context, data BootDeviceData) (result Result, err error)
 
 	// Delete removes the host from the provisioning system. It may be
 	// called multiple times, and should return true for its dirty
 	// flag until the deletion operation is completed.
-	Delete() (result Result, err error)
+	Delete(ctx context.Context) (result Result, err error)
 
 	// Detach removes the host from the provisioning system.
 	// Similar to Delete, but ensures non-interruptive behavior
 	// for the target system.  It may be called multiple times,
 	// and should return true for its dirty  flag until the
 	// deletion operation is completed.
-	Detach() (result Result, err error)
+	Detach(ctx context.Context) (result Result, err error)
 
 	// PowerOn ensures the server is powered on independently of any image
 	// provisioning operation.
-	PowerOn(force bool) (result Result, err error)
+	PowerOn(ctx context.Context, force bool) (result Result, err error)
 
 	// PowerOff ensures the server is powered off independently of any image
 	// provisioning operation. The boolean argument may be used to specify
 	// if a hard reboot (force power off) is required - true if so.
-	PowerOff(rebootMode metal3api.RebootMode, force bool) (result Result, err error)
+	PowerOff(ctx context.Context, rebootMode metal3api.RebootMode, force bool) (result Result, err error)
 
 	// TryInit checks if the provisioning backend is available to accept
 	// all the incoming requests and configures the available features.
-	TryInit() (ready bool, err error)
+	TryInit(ctx context.Context) (ready bool, err error)
 
 	// HasCapacity checks if the backend has a free (de)provisioning slot for the current host
-	HasCapacity() (result bool, err error)
+	HasCapacity(ctx context.Context) (result bool, err error)
 
 	// GetFirmwareSettings gets the BIOS settings and optional schema from the host and returns maps
-	GetFirmwareSettings(includeSchema bool) (settings metal3api.SettingsMap, schema map[string]metal3api.SettingSchema, err error)
+	GetFirmwareSettings(ctx context.Context, includeSchema bool) (settings metal3api.SettingsMap, schema map[string]metal3api.SettingSchema, err error)
 
 	// AddBMCEventSubscriptionForNode creates the subscription, and updates Status.SubscriptionID
-	AddBMCEventSubscriptionForNode(subscription *metal3api.BMCEventSubscription, httpHeaders HTTPHeaders) (result Result, err error)
+	AddBMCEventSubscriptionForNode(ctx context.Context, subscription *metal3api.BMCEventSubscription, httpHeaders HTTPHeaders) (result Result, err error)
 
 	// RemoveBMCEventSubscriptionForNode delete the subscription
-	RemoveBMCEventSubscriptionForNode(subscription metal3api.BMCEventSubscription) (result Result, err error)
+	RemoveBMCEventSubscriptionForNode(ctx context.Context, subscription metal3api.BMCEventSubscription) (result Result, err error)
 
 	// GetFirmwareComponents gets all firmware components available from a note
-	GetFirmwareComponents() (components []metal3api.FirmwareComponentStatus, err error)
+	GetFirmwareComponents(ctx context.Context) (components []metal3api.FirmwareComponentStatus, err error)
 
 	// Check if DataImage attach/detach was successful
-	IsDataImageReady() (isNodeBusy bool, nodeError error)
+	IsDataImageReady(ctx context.Context) (isNodeBusy bool, nodeError error)
 
 	// Attach DataImage
-	AttachDataImage(URL string) (err error)
+	AttachDataImage(ctx context.Context, URL string) (err error)
 
 	// Detach DataImage
-	DetachDataImage() (err error)
+	DetachDataImage(ctx context.Context) (err error)
+}
+
+// EndpointReporter is implemented by Provisioners spreading hosts over
+// several instances of their backend.
+type EndpointReporter interface {
+	// Endpoint returns the name of the backend instance serving the
+	// host. It is only meaningful once TryInit has succeeded.
+	Endpoint() string
+}
+
+// UnknownEndpointError is returned when a host is assigned to a backend
+// instance that is not configured, so that no Provisioner can serve it
+// until either the host or the configuration of the operator changes.
+type UnknownEndpointError struct {
+	Endpoint string
+	Reason   string
+}
+
+func (e UnknownEndpointError) Error() string {
+	return e.Reason
 }
 
 // Result holds the response from a call in the Provsioner API.
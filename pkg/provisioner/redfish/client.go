@@ -0,0 +1,360 @@
+package redfish
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/metal3-io/baremetal-operator/pkg/hardwareutils/bmc"
+)
+
+const (
+	systemsCollection = "/redfish/v1/Systems"
+
+	powerStateOn  = "On"
+	powerStateOff = "Off"
+
+	powerStatePoweringOn  = "PoweringOn"
+	powerStatePoweringOff = "PoweringOff"
+
+	resetOn               = "On"
+	resetForceOff         = "ForceOff"
+	resetForceRestart     = "ForceRestart"
+	resetGracefulShutdown = "GracefulShutdown"
+
+	bootTargetCd        = "Cd"
+	bootTargetNone      = "None"
+	bootTargetPxe       = "Pxe"
+	bootTargetHdd       = "Hdd"
+	bootTargetBiosSetup = "BiosSetup"
+
+	bootOverrideOnce       = "Once"
+	bootOverrideContinuous = "Continuous"
+	bootOverrideDisabled   = "Disabled"
+
+	mediaTypeCD       = "CD"
+	mediaTypeDVD      = "DVD"
+	mediaTypeUSBStick = "USBStick"
+)
+
+// requestTimeout bounds a single HTTP request to the BMC.
+var requestTimeout = 60 * time.Second
+
+// HTTPError is returned when the BMC responds with a non-successful
+// status code.
+type HTTPError struct {
+	StatusCode int
+	Method     string
+	URL        string
+}
+
+func (e HTTPError) Error() string {
+	return fmt.Sprintf("redfish request %s %s failed with status %d", e.Method, e.URL, e.StatusCode)
+}
+
+// errNoVirtualMedia is returned when no virtual media device supports
+// any of the requested media types.
+var errNoVirtualMedia = errors.New("no virtual media device")
+
+func responseCodeIs(err error, code int) bool {
+	var httpErr HTTPError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	return httpErr.StatusCode == code
+}
+
+type odataID struct {
+	ID string `json:"@odata.id"`
+}
+
+type collection struct {
+	Members []odataID `json:"Members"`
+}
+
+type bootSettings struct {
+	BootSourceOverrideTarget  string `json:"BootSourceOverrideTarget,omitempty"`
+	BootSourceOverrideEnabled string `json:"BootSourceOverrideEnabled,omitempty"`
+}
+
+// ComputerSystem is the subset of the Redfish ComputerSystem resource
+// used by the provisioner.
+type ComputerSystem struct {
+	ID           string `json:"Id"`
+	UUID         string `json:"UUID"`
+	Manufacturer string `json:"Manufacturer"`
+	Model        string `json:"Model"`
+	SerialNumber string `json:"SerialNumber"`
+	HostName     string `json:"HostName"`
+	PowerState   string `json:"PowerState"`
+	BiosVersion  string `json:"BiosVersion"`
+
+	ProcessorSummary struct {
+		Count int    `json:"Count"`
+		Model string `json:"Model"`
+	} `json:"ProcessorSummary"`
+	MemorySummary struct {
+		TotalSystemMemoryGiB float64 `json:"TotalSystemMemoryGiB"`
+	} `json:"MemorySummary"`
+
+	Boot bootSettings `json:"Boot"`
+
+	Bios               odataID `json:"Bios"`
+	EthernetInterfaces odataID `json:"EthernetInterfaces"`
+
+	Links struct {
+		ManagedBy []odataID `json:"ManagedBy"`
+	} `json:"Links"`
+}
+
+// EthernetInterface is the subset of the Redfish EthernetInterface
+// resource used by the provisioner.
+type EthernetInterface struct {
+	ID            string `json:"Id"`
+	Name          string `json:"Name"`
+	MACAddress    string `json:"MACAddress"`
+	SpeedMbps     int    `json:"SpeedMbps"`
+	IPv4Addresses []struct {
+		Address string `json:"Address"`
+	} `json:"IPv4Addresses"`
+}
+
+// VirtualMedia is the subset of the Redfish VirtualMedia resource used
+// by the provisioner.
+type VirtualMedia struct {
+	ID         string   `json:"Id"`
+	Image      string   `json:"Image"`
+	Inserted   bool     `json:"Inserted"`
+	MediaTypes []string `json:"MediaTypes"`
+}
+
+type manager struct {
+	VirtualMedia odataID `json:"VirtualMedia"`
+}
+
+type bios struct {
+	Attributes map[string]interface{} `json:"Attributes"`
+}
+
+// client talks to a single Redfish service.
+type client struct {
+	endpoint   string
+	systemPath string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+func newClient(address string, disableCertificateVerification bool, creds bmc.Credentials) (*client, error) {
+	parsedURL, err := bmc.GetParsedURL(address)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := "https"
+	if parts := strings.SplitN(parsedURL.Scheme, "+", 2); len(parts) > 1 {
+		scheme = parts[1]
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if disableCertificateVerification {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} // #nosec
+	}
+
+	return &client{
+		endpoint:   fmt.Sprintf("%s://%s", scheme, parsedURL.Host),
+		systemPath: strings.TrimSuffix(parsedURL.Path, "/"),
+		username:   creds.Username,
+		password:   creds.Password,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   requestTimeout,
+		},
+	}, nil
+}
+
+func (c *client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(content)
+	}
+
+	url := c.endpoint + path
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return HTTPError{StatusCode: resp.StatusCode, Method: method, URL: url}
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// resolveSystem finds the path of the ComputerSystem to manage. When the
+// BMC address does not include one, the first member of the Systems
+// collection is used.
+func (c *client) resolveSystem(ctx context.Context) (string, error) {
+	if c.systemPath != "" && c.systemPath != systemsCollection {
+		return c.systemPath, nil
+	}
+
+	systems := collection{}
+	if err := c.do(ctx, http.MethodGet, systemsCollection, nil, &systems); err != nil {
+		return "", err
+	}
+	if len(systems.Members) == 0 {
+		return "", fmt.Errorf("no systems found at %s", systemsCollection)
+	}
+	c.systemPath = systems.Members[0].ID
+	return c.systemPath, nil
+}
+
+func (c *client) getSystem(ctx context.Context) (*ComputerSystem, error) {
+	path, err := c.resolveSystem(ctx)
+	if err != nil {
+		return nil, err
+	}
+	system := &ComputerSystem{}
+	if err := c.do(ctx, http.MethodGet, path, nil, system); err != nil {
+		return nil, err
+	}
+	return system, nil
+}
+
+func (c *client) reset(ctx context.Context, resetType string) error {
+	path, err := c.resolveSystem(ctx)
+	if err != nil {
+		return err
+	}
+	body := map[string]string{"ResetType": resetType}
+	return c.do(ctx, http.MethodPost, path+"/Actions/ComputerSystem.Reset", body, nil)
+}
+
+func (c *client) setBootOverride(ctx context.Context, target, enabled string) error {
+	path, err := c.resolveSystem(ctx)
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"Boot": bootSettings{
+			BootSourceOverrideTarget:  target,
+			BootSourceOverrideEnabled: enabled,
+		},
+	}
+	return c.do(ctx, http.MethodPatch, path, body, nil)
+}
+
+func (c *client) getEthernetInterfaces(ctx context.Context, system *ComputerSystem) ([]EthernetInterface, error) {
+	if system.EthernetInterfaces.ID == "" {
+		return nil, nil
+	}
+
+	members := collection{}
+	if err := c.do(ctx, http.MethodGet, system.EthernetInterfaces.ID, nil, &members); err != nil {
+		return nil, err
+	}
+
+	interfaces := make([]EthernetInterface, 0, len(members.Members))
+	for _, member := range members.Members {
+		nic := EthernetInterface{}
+		if err := c.do(ctx, http.MethodGet, member.ID, nil, &nic); err != nil {
+			return nil, err
+		}
+		interfaces = append(interfaces, nic)
+	}
+	return interfaces, nil
+}
+
+func (c *client) getBiosAttributes(ctx context.Context, system *ComputerSystem) (map[string]interface{}, error) {
+	if system.Bios.ID == "" {
+		return nil, nil
+	}
+	settings := bios{}
+	if err := c.do(ctx, http.MethodGet, system.Bios.ID, nil, &settings); err != nil {
+		return nil, err
+	}
+	return settings.Attributes, nil
+}
+
+// findVirtualMedia returns the path and current state of the virtual
+// media device of the system's manager supporting the first of the
+// requested media types available.
+func (c *client) findVirtualMedia(ctx context.Context, system *ComputerSystem, mediaTypes ...string) (string, *VirtualMedia, error) {
+	if len(system.Links.ManagedBy) == 0 {
+		return "", nil, fmt.Errorf("system %s has no manager", system.ID)
+	}
+
+	mgr := manager{}
+	if err := c.do(ctx, http.MethodGet, system.Links.ManagedBy[0].ID, nil, &mgr); err != nil {
+		return "", nil, err
+	}
+	if mgr.VirtualMedia.ID == "" {
+		return "", nil, fmt.Errorf("manager of system %s does not support virtual media", system.ID)
+	}
+
+	members := collection{}
+	if err := c.do(ctx, http.MethodGet, mgr.VirtualMedia.ID, nil, &members); err != nil {
+		return "", nil, err
+	}
+
+	devices := make([]*VirtualMedia, len(members.Members))
+	for i, member := range members.Members {
+		devices[i] = &VirtualMedia{}
+		if err := c.do(ctx, http.MethodGet, member.ID, nil, devices[i]); err != nil {
+			return "", nil, err
+		}
+	}
+
+	// The media types are in order of preference.
+	for _, wanted := range mediaTypes {
+		for i, media := range devices {
+			for _, supported := range media.MediaTypes {
+				if supported == wanted {
+					return members.Members[i].ID, media, nil
+				}
+			}
+		}
+	}
+
+	return "", nil, fmt.Errorf("%w of type %s found", errNoVirtualMedia, strings.Join(mediaTypes, "/"))
+}
+
+func (c *client) insertMedia(ctx context.Context, mediaPath, image string) error {
+	body := map[string]interface{}{
+		"Image":          image,
+		"Inserted":       true,
+		"WriteProtected": true,
+	}
+	return c.do(ctx, http.MethodPost, mediaPath+"/Actions/VirtualMedia.InsertMedia", body, nil)
+}
+
+func (c *client) ejectMedia(ctx context.Context, mediaPath string) error {
+	return c.do(ctx, http.MethodPost, mediaPath+"/Actions/VirtualMedia.EjectMedia", map[string]interface{}{}, nil)
+}
@@ -0,0 +1,41 @@
+package redfish
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+)
+
+type redfishProvisionerFactory struct {
+	log logr.Logger
+}
+
+// NewProvisionerFactory returns a factory for provisioners talking to
+// the BMC of each host directly over Redfish, without Ironic.
+func NewProvisionerFactory(logger logr.Logger) provisioner.Factory {
+	return redfishProvisionerFactory{
+		log: logger.WithName("redfish"),
+	}
+}
+
+// NewProvisioner returns a new Redfish Provisioner.
+func (f redfishProvisionerFactory) NewProvisioner(_ context.Context, hostData provisioner.HostData, publisher provisioner.EventPublisher) (provisioner.Provisioner, error) {
+	p := &redfishProvisioner{
+		objectMeta:              hostData.ObjectMeta,
+		provID:                  hostData.ProvisionerID,
+		bmcAddress:              hostData.BMCAddress,
+		disableCertVerification: hostData.DisableCertificateVerification,
+		bmcCreds:                hostData.BMCCredentials,
+		bootMACAddress:          hostData.BootMACAddress,
+		log:                     f.log.WithValues("host", hostData.ObjectMeta.Namespace+"~"+hostData.ObjectMeta.Name),
+		publisher:               publisher,
+	}
+
+	// An invalid address is reported by ValidateManagementAccess, so
+	// that the host ends up with a registration error rather than the
+	// reconcile failing.
+	p.client, p.clientErr = newClient(p.bmcAddress, p.disableCertVerification, p.bmcCreds)
+
+	return p, nil
+}
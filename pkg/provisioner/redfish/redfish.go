@@ -0,0 +1,561 @@
+package redfish
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/hardwareutils/bmc"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	powerRequeueDelay     = time.Second * 10
+	provisionRequeueDelay = time.Second * 10
+)
+
+// ErrEventSubscriptionUnsupported is returned when a BMCEventSubscription
+// is requested for a host managed by the Redfish provisioner.
+var ErrEventSubscriptionUnsupported = errors.New("BMC event subscriptions are not supported by the redfish provisioner")
+
+// ErrNoDataImageMedia is returned when the BMC of a host has no USB stick
+// virtual media device to attach a DataImage to. The CD device is never
+// used, as it holds the live ISO.
+var ErrNoDataImageMedia = errors.New("no virtual media device for data image")
+
+// redfishProvisioner implements the provisioner.Provisioner interface
+// and talks to the BMC of the host directly using Redfish.
+type redfishProvisioner struct {
+	// the object metadata of the BareMetalHost resource
+	objectMeta metav1.ObjectMeta
+	// the provisioning ID for this host
+	provID string
+	// the address of the BMC
+	bmcAddress string
+	// whether to verify SSL certificates
+	disableCertVerification bool
+	// the bmc credentials
+	bmcCreds bmc.Credentials
+	// the MAC address of the PXE boot interface
+	bootMACAddress string
+	// the Redfish client for the BMC, and the error building it
+	client    *client
+	clientErr error
+	// a logger configured for this host
+	log logr.Logger
+	// an event publisher for recording significant events
+	publisher provisioner.EventPublisher
+}
+
+func operationContinuing(delay time.Duration) (provisioner.Result, error) {
+	return provisioner.Result{
+		Dirty:        true,
+		RequeueAfter: delay,
+	}, nil
+}
+
+func operationComplete() (provisioner.Result, error) {
+	return provisioner.Result{}, nil
+}
+
+func operationFailed(message string) (provisioner.Result, error) {
+	return provisioner.Result{ErrorMessage: message}, nil
+}
+
+func transientError(err error) (provisioner.Result, error) {
+	return provisioner.Result{}, err
+}
+
+// isRedfishAccess checks whether the BMC type can be driven over Redfish.
+func isRedfishAccess(accessDetails bmc.AccessDetails) bool {
+	bmcType := strings.SplitN(accessDetails.Type(), "+", 2)[0]
+	return strings.HasSuffix(bmcType, "redfish") || strings.HasSuffix(bmcType, "virtualmedia")
+}
+
+func (p *redfishProvisioner) getSystem(ctx context.Context) (*ComputerSystem, error) {
+	if p.clientErr != nil {
+		return nil, p.clientErr
+	}
+	return p.client.getSystem(ctx)
+}
+
+// ValidateManagementAccess tests the connection information for the
+// host to verify that the location and credentials work.
+func (p *redfishProvisioner) ValidateManagementAccess(ctx context.Context, _ provisioner.ManagementAccessData, _, _ bool) (result provisioner.Result, provID string, err error) {
+	p.log.Info("testing management access")
+
+	accessDetails, err := bmc.NewAccessDetails(p.bmcAddress, p.disableCertVerification)
+	if err != nil {
+		result, err = operationFailed(err.Error())
+		return
+	}
+	if !isRedfishAccess(accessDetails) {
+		result, err = operationFailed(fmt.Sprintf("BMC type %s is not supported by the redfish provisioner", accessDetails.Type()))
+		return
+	}
+
+	system, err := p.getSystem(ctx)
+	if err != nil {
+		if responseCodeIs(err, http.StatusUnauthorized) || responseCodeIs(err, http.StatusForbidden) {
+			result, err = operationFailed("failed to authenticate with the BMC")
+			return
+		}
+		if responseCodeIs(err, http.StatusNotFound) {
+			result, err = operationFailed(fmt.Sprintf("system not found at %s", p.bmcAddress))
+			return
+		}
+		result, err = transientError(fmt.Errorf("failed to contact BMC: %w", err))
+		return
+	}
+
+	provID = system.UUID
+	if provID == "" {
+		provID = p.client.systemPath
+	}
+	if provID != p.provID {
+		p.log.Info("registered host", "provID", provID)
+		p.publisher("Registered", "Registered new host")
+		result.Dirty = true
+	}
+	return result, provID, nil
+}
+
+// PreprovisioningImageFormats returns a list of acceptable formats for a
+// pre-provisioning image to be built by a PreprovisioningImage object.
+// The Redfish provisioner does not boot a ramdisk, so it never needs one.
+func (p *redfishProvisioner) PreprovisioningImageFormats(_ context.Context) ([]metal3api.ImageFormat, error) {
+	return nil, nil
+}
+
+// InspectHardware updates the HardwareDetails field of the host with
+// details of devices discovered on the hardware. The details are read
+// out-of-band from the BMC, so the host is never booted for inspection.
+func (p *redfishProvisioner) InspectHardware(ctx context.Context, _ provisioner.InspectData, _, _, _ bool) (result provisioner.Result, started bool, details *metal3api.HardwareDetails, err error) {
+	p.log.Info("inspecting hardware")
+
+	system, err := p.getSystem(ctx)
+	if err != nil {
+		result, err = transientError(fmt.Errorf("failed to read system: %w", err))
+		return
+	}
+
+	nics, err := p.client.getEthernetInterfaces(ctx, system)
+	if err != nil {
+		result, err = transientError(fmt.Errorf("failed to read ethernet interfaces: %w", err))
+		return
+	}
+
+	details = &metal3api.HardwareDetails{
+		SystemVendor: metal3api.HardwareSystemVendor{
+			Manufacturer: system.Manufacturer,
+			ProductName:  system.Model,
+			SerialNumber: system.SerialNumber,
+		},
+		Firmware: metal3api.Firmware{
+			BIOS: metal3api.BIOS{
+				Version: system.BiosVersion,
+			},
+		},
+		RAMMebibytes: int(system.MemorySummary.TotalSystemMemoryGiB * 1024),
+		CPU: metal3api.CPU{
+			Model: system.ProcessorSummary.Model,
+			Count: system.ProcessorSummary.Count,
+		},
+		Hostname: system.HostName,
+	}
+
+	for _, nic := range nics {
+		detail := metal3api.NIC{
+			Name:      nic.ID,
+			MAC:       strings.ToLower(nic.MACAddress),
+			SpeedGbps: nic.SpeedMbps / 1000,
+			PXE:       p.bootMACAddressMatches(nic.MACAddress),
+		}
+		if len(nic.IPv4Addresses) > 0 {
+			detail.IP = nic.IPv4Addresses[0].Address
+		}
+		details.NIC = append(details.NIC, detail)
+	}
+
+	p.publisher("InspectionComplete", "Hardware inspection completed")
+	return result, true, details, nil
+}
+
+func (p *redfishProvisioner) bootMACAddressMatches(mac string) bool {
+	return mac != "" && strings.EqualFold(mac, p.bootMACAddress)
+}
+
+// UpdateHardwareState fetches the latest hardware state of the server.
+func (p *redfishProvisioner) UpdateHardwareState(ctx context.Context) (hwState provisioner.HardwareState, err error) {
+	system, err := p.getSystem(ctx)
+	if err != nil {
+		return hwState, err
+	}
+
+	switch system.PowerState {
+	case powerStateOn:
+		poweredOn := true
+		hwState.PoweredOn = &poweredOn
+	case powerStateOff:
+		poweredOn := false
+		hwState.PoweredOn = &poweredOn
+	default:
+		p.log.Info("unknown power state", "powerState", system.PowerState)
+	}
+	return hwState, nil
+}
+
+// Adopt brings an externally-provisioned host under management. There
+// is no backend state to create, so it always succeeds immediately.
+func (p *redfishProvisioner) Adopt(_ context.Context, _ provisioner.AdoptData, _ bool) (result provisioner.Result, err error) {
+	return operationComplete()
+}
+
+// Prepare remove existing configuration and set new configuration.
+// RAID and firmware changes require a ramdisk and are not supported.
+func (p *redfishProvisioner) Prepare(_ context.Context, data provisioner.PrepareData, _ bool, _ bool) (result provisioner.Result, started bool, err error) {
+	if data.TargetRAIDConfig != nil &&
+		(len(data.TargetRAIDConfig.HardwareRAIDVolumes) > 0 || len(data.TargetRAIDConfig.SoftwareRAIDVolumes) > 0) {
+		result, err = operationFailed("RAID configuration is not supported by the redfish provisioner")
+		return
+	}
+	if len(data.TargetFirmwareSettings) > 0 || len(data.TargetFirmwareComponents) > 0 {
+		result, err = operationFailed("firmware changes are not supported by the redfish provisioner")
+		return
+	}
+	return
+}
+
+// Service applies firmware settings and updates to a provisioned host.
+// Firmware changes require a ramdisk and are not supported.
+func (p *redfishProvisioner) Service(_ context.Context, data provisioner.ServicingData, _ bool, _ bool) (result provisioner.Result, started bool, err error) {
+	if len(data.TargetFirmwareSettings) > 0 || len(data.TargetFirmwareComponents) > 0 {
+		result, err = operationFailed("firmware changes are not supported by the redfish provisioner")
+		return
+	}
+	return
+}
+
+// Provision boots the host from the live ISO given in the image, using
+// virtual media. Other image formats require a deployment ramdisk and
+// are not supported.
+func (p *redfishProvisioner) Provision(ctx context.Context, data provisioner.ProvisionData, _ bool) (result provisioner.Result, err error) {
+	if data.CustomDeploy != nil || !data.Image.IsLiveISO() {
+		return operationFailed("only live-iso images are supported by the redfish provisioner")
+	}
+
+	system, err := p.getSystem(ctx)
+	if err != nil {
+		return transientError(err)
+	}
+
+	mediaPath, media, err := p.client.findVirtualMedia(ctx, system, mediaTypeCD, mediaTypeDVD)
+	if err != nil {
+		return operationFailed(err.Error())
+	}
+
+	if !media.Inserted || media.Image != data.Image.URL {
+		p.log.Info("inserting live ISO", "image", data.Image.URL)
+		if media.Inserted {
+			if err = p.client.ejectMedia(ctx, mediaPath); err != nil {
+				return transientError(fmt.Errorf("failed to eject virtual media: %w", err))
+			}
+		}
+		if err = p.client.insertMedia(ctx, mediaPath, data.Image.URL); err != nil {
+			return transientError(fmt.Errorf("failed to insert virtual media: %w", err))
+		}
+		p.publisher("ProvisioningStarted", "Image provisioning started")
+		return operationContinuing(0)
+	}
+
+	if system.Boot.BootSourceOverrideTarget != bootTargetCd || system.Boot.BootSourceOverrideEnabled != bootOverrideContinuous {
+		if err = p.client.setBootOverride(ctx, bootTargetCd, bootOverrideContinuous); err != nil {
+			return transientError(fmt.Errorf("failed to set boot device: %w", err))
+		}
+		// Reboot so the new boot device takes effect.
+		resetType := resetOn
+		if system.PowerState != powerStateOff {
+			resetType = resetForceRestart
+		}
+		if err = p.client.reset(ctx, resetType); err != nil {
+			return transientError(fmt.Errorf("failed to boot host: %w", err))
+		}
+		return operationContinuing(provisionRequeueDelay)
+	}
+
+	if system.PowerState != powerStateOn {
+		p.log.Info("waiting for host to boot", "powerState", system.PowerState)
+		return operationContinuing(provisionRequeueDelay)
+	}
+
+	p.publisher("ProvisioningComplete", "Image provisioning completed")
+	return operationComplete()
+}
+
+// Rescue boots the provisioned host into a rescue ramdisk, which is
+// not supported.
+func (p *redfishProvisioner) Rescue(_ context.Context, _ provisioner.RescueData, _ bool) (result provisioner.Result, err error) {
+	return operationFailed("rescue is not supported by the redfish provisioner")
+}
+
+// Unrescue boots the rescued host back into its image. Hosts are never
+// rescued, so there is nothing to do.
+func (p *redfishProvisioner) Unrescue(_ context.Context, _ bool) (result provisioner.Result, err error) {
+	return operationComplete()
+}
+
+// SetBootDevice sets the boot source override of the system.
+func (p *redfishProvisioner) SetBootDevice(ctx context.Context, data provisioner.BootDeviceData) (result provisioner.Result, err error) {
+	target, ok := map[metal3api.BootDevice]string{
+		metal3api.BootDevicePXE:   bootTargetPxe,
+		metal3api.BootDeviceCDROM: bootTargetCd,
+		metal3api.BootDeviceDisk:  bootTargetHdd,
+		metal3api.BootDeviceBIOS:  bootTargetBiosSetup,
+	}[data.Device]
+	if !ok {
+		return operationFailed(fmt.Sprintf("unsupported boot device %s", data.Device))
+	}
+
+	enabled := bootOverrideOnce
+	if data.Persistent {
+		enabled = bootOverrideContinuous
+	}
+
+	if p.clientErr != nil {
+		return transientError(p.clientErr)
+	}
+
+	p.log.Info("setting boot device", "device", data.Device, "persistent", data.Persistent)
+	if err = p.client.setBootOverride(ctx, target, enabled); err != nil {
+		return transientError(fmt.Errorf("failed to set boot device: %w", err))
+	}
+	return operationComplete()
+}
+
+// Deprovision ejects the live ISO and powers the host off. Disks can not
+// be erased, since there is no ramdisk to run the erasure.
+func (p *redfishProvisioner) Deprovision(ctx context.Context, data provisioner.DeprovisionData, _ bool) (result provisioner.Result, erasureStarted bool, err error) {
+	if data.EraseDisks {
+		result, err = operationFailed("erasing disks is not supported by the redfish provisioner")
+		return result, false, err
+	}
+	result, err = p.deprovision(ctx)
+	return result, false, err
+}
+
+func (p *redfishProvisioner) deprovision(ctx context.Context) (result provisioner.Result, err error) {
+	p.log.Info("ensuring host is deprovisioned")
+
+	system, err := p.getSystem(ctx)
+	if err != nil {
+		return transientError(err)
+	}
+
+	mediaPath, media, err := p.client.findVirtualMedia(ctx, system, mediaTypeCD, mediaTypeDVD)
+	if err != nil {
+		return operationFailed(err.Error())
+	}
+
+	if media.Inserted {
+		p.publisher("DeprovisionStarted", "Image deprovisioning started")
+		if err = p.client.ejectMedia(ctx, mediaPath); err != nil {
+			return transientError(fmt.Errorf("failed to eject virtual media: %w", err))
+		}
+		return operationContinuing(0)
+	}
+
+	if system.Boot.BootSourceOverrideEnabled != "" && system.Boot.BootSourceOverrideEnabled != bootOverrideDisabled {
+		if err = p.client.setBootOverride(ctx, bootTargetNone, bootOverrideDisabled); err != nil {
+			return transientError(fmt.Errorf("failed to clear boot device: %w", err))
+		}
+		return operationContinuing(0)
+	}
+
+	if system.PowerState != powerStateOff {
+		return p.PowerOff(ctx, metal3api.RebootModeHard, true)
+	}
+
+	p.publisher("DeprovisionComplete", "Image deprovisioning completed")
+	return operationComplete()
+}
+
+// Delete removes the host from the provisioning system. No state is kept
+// outside of the BMC, so there is nothing to remove.
+func (p *redfishProvisioner) Delete(_ context.Context) (result provisioner.Result, err error) {
+	p.log.Info("deleting host")
+	return operationComplete()
+}
+
+// Detach removes the host from the provisioning system.
+// Similar to Delete, but ensures non-interruptive behavior
+// for the target system.
+func (p *redfishProvisioner) Detach(ctx context.Context) (result provisioner.Result, err error) {
+	return p.Delete(ctx)
+}
+
+func (p *redfishProvisioner) changePower(ctx context.Context, resetType string) (result provisioner.Result, err error) {
+	p.log.Info("changing power state", "resetType", resetType)
+
+	err = p.client.reset(ctx, resetType)
+	if err != nil {
+		return transientError(fmt.Errorf("failed to %s host: %w", resetType, err))
+	}
+
+	event := map[string]struct{ Event, Reason string }{
+		resetOn:               {Event: "PowerOn", Reason: "Host powered on"},
+		resetForceOff:         {Event: "PowerOff", Reason: "Host powered off"},
+		resetGracefulShutdown: {Event: "PowerOff", Reason: "Host soft powered off"},
+	}[resetType]
+	p.publisher(event.Event, event.Reason)
+	return operationContinuing(powerRequeueDelay)
+}
+
+// PowerOn ensures the server is powered on independently of any image
+// provisioning operation.
+func (p *redfishProvisioner) PowerOn(ctx context.Context, _ bool) (result provisioner.Result, err error) {
+	p.log.Info("ensuring host is powered on")
+
+	system, err := p.getSystem(ctx)
+	if err != nil {
+		return transientError(err)
+	}
+
+	switch system.PowerState {
+	case powerStateOn:
+		return operationComplete()
+	case powerStatePoweringOn:
+		p.log.Info("waiting for power status to change")
+		return operationContinuing(powerRequeueDelay)
+	default:
+		return p.changePower(ctx, resetOn)
+	}
+}
+
+// PowerOff ensures the server is powered off independently of any image
+// provisioning operation.
+func (p *redfishProvisioner) PowerOff(ctx context.Context, rebootMode metal3api.RebootMode, force bool) (result provisioner.Result, err error) {
+	p.log.Info(fmt.Sprintf("ensuring host is powered off (mode: %s)", rebootMode))
+
+	system, err := p.getSystem(ctx)
+	if err != nil {
+		return transientError(err)
+	}
+
+	switch system.PowerState {
+	case powerStateOff:
+		return operationComplete()
+	case powerStatePoweringOff:
+		if !force {
+			p.log.Info("waiting for power status to change")
+			return operationContinuing(powerRequeueDelay)
+		}
+	}
+
+	if rebootMode == metal3api.RebootModeSoft && !force {
+		result, err = p.changePower(ctx, resetGracefulShutdown)
+		// A BMC without support for a graceful shutdown rejects the
+		// request; fall back to a hard power off.
+		if !responseCodeIs(err, http.StatusBadRequest) {
+			return result, err
+		}
+	}
+	return p.changePower(ctx, resetForceOff)
+}
+
+// TryInit checks if the provisioning backend is available. There is no
+// shared backend service, so it is always ready.
+func (p *redfishProvisioner) TryInit(_ context.Context) (ready bool, err error) {
+	return true, nil
+}
+
+// HasCapacity checks if the backend has a free (de)provisioning slot for
+// the current host. Virtual media boots do not share any resources.
+func (p *redfishProvisioner) HasCapacity(_ context.Context) (result bool, err error) {
+	return true, nil
+}
+
+// GetFirmwareSettings gets the BIOS attributes of the host. The attribute
+// registry is not read, so no schema is returned.
+func (p *redfishProvisioner) GetFirmwareSettings(ctx context.Context, _ bool) (settings metal3api.SettingsMap, schema map[string]metal3api.SettingSchema, err error) {
+	system, err := p.getSystem(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attributes, err := p.client.getBiosAttributes(ctx, system)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get BIOS settings for host %s: %w", p.objectMeta.Name, err)
+	}
+
+	settings = make(metal3api.SettingsMap, len(attributes))
+	for name, value := range attributes {
+		settings[name] = fmt.Sprint(value)
+	}
+	return settings, nil, nil
+}
+
+func (p *redfishProvisioner) AddBMCEventSubscriptionForNode(_ context.Context, _ *metal3api.BMCEventSubscription, _ provisioner.HTTPHeaders) (result provisioner.Result, err error) {
+	return transientError(ErrEventSubscriptionUnsupported)
+}
+
+func (p *redfishProvisioner) RemoveBMCEventSubscriptionForNode(_ context.Context, _ metal3api.BMCEventSubscription) (result provisioner.Result, err error) {
+	return operationComplete()
+}
+
+func (p *redfishProvisioner) GetFirmwareComponents(_ context.Context) (components []metal3api.FirmwareComponentStatus, err error) {
+	return nil, provisioner.ErrFirmwareUpdateUnsupported
+}
+
+// IsDataImageReady reports whether the last DataImage action has
+// completed. Virtual media actions are synchronous in Redfish.
+func (p *redfishProvisioner) IsDataImageReady(_ context.Context) (isNodeBusy bool, nodeError error) {
+	return false, nil
+}
+
+func (p *redfishProvisioner) findDataImageMedia(ctx context.Context) (string, *VirtualMedia, error) {
+	system, err := p.getSystem(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	mediaPath, media, err := p.client.findVirtualMedia(ctx, system, mediaTypeUSBStick)
+	if errors.Is(err, errNoVirtualMedia) {
+		return "", nil, fmt.Errorf("%w: system %s has no virtual media device of type %s",
+			ErrNoDataImageMedia, system.ID, mediaTypeUSBStick)
+	}
+	return mediaPath, media, err
+}
+
+func (p *redfishProvisioner) AttachDataImage(ctx context.Context, url string) (err error) {
+	mediaPath, media, err := p.findDataImageMedia(ctx)
+	if err != nil {
+		return err
+	}
+
+	if media.Inserted {
+		if media.Image == url {
+			return nil
+		}
+		if err = p.client.ejectMedia(ctx, mediaPath); err != nil {
+			return err
+		}
+	}
+	return p.client.insertMedia(ctx, mediaPath, url)
+}
+
+func (p *redfishProvisioner) DetachDataImage(ctx context.Context) (err error) {
+	mediaPath, media, err := p.findDataImageMedia(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !media.Inserted {
+		return nil
+	}
+	return p.client.ejectMedia(ctx, mediaPath)
+}
@@ -0,0 +1,343 @@
+package redfish
+
+import (
+	"context"
+	"testing"
+
+	metal3api "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	"github.com/metal3-io/baremetal-operator/pkg/hardwareutils/bmc"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/redfish/testserver"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logz "sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func nullEventPublisher(_, _ string) {}
+
+func newTestProvisioner(t *testing.T, address string, creds bmc.Credentials, provID string) *redfishProvisioner {
+	t.Helper()
+	factory := NewProvisionerFactory(logz.New())
+	prov, err := factory.NewProvisioner(context.TODO(), provisioner.HostData{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myhost",
+			Namespace: "myns",
+		},
+		BMCAddress:     address,
+		BMCCredentials: creds,
+		BootMACAddress: "00:11:22:33:44:55",
+		ProvisionerID:  provID,
+	}, nullEventPublisher)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return prov.(*redfishProvisioner)
+}
+
+func defaultCreds() bmc.Credentials {
+	return bmc.Credentials{Username: "admin", Password: "password"}
+}
+
+func TestValidateManagementAccess(t *testing.T) {
+	fake := testserver.New(t).Start()
+	defer fake.Stop()
+
+	cases := []struct {
+		name          string
+		address       string
+		creds         bmc.Credentials
+		provID        string
+		expectedID    string
+		expectedDirty bool
+		expectedError string
+	}{
+		{
+			name:          "new host",
+			address:       fake.Address(),
+			creds:         defaultCreds(),
+			expectedID:    fake.UUID,
+			expectedDirty: true,
+		},
+		{
+			name:       "registered host",
+			address:    fake.Address(),
+			creds:      defaultCreds(),
+			provID:     fake.UUID,
+			expectedID: fake.UUID,
+		},
+		{
+			name:          "bad credentials",
+			address:       fake.Address(),
+			creds:         bmc.Credentials{Username: "admin", Password: "wrong"},
+			expectedError: "failed to authenticate with the BMC",
+		},
+		{
+			name:          "unknown system",
+			address:       fake.Address() + "0",
+			creds:         defaultCreds(),
+			expectedError: "system not found at " + fake.Address() + "0",
+		},
+		{
+			name:          "unsupported driver",
+			address:       "ipmi://192.168.122.1:6233",
+			creds:         defaultCreds(),
+			expectedError: "BMC type ipmi is not supported by the redfish provisioner",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			prov := newTestProvisioner(t, tc.address, tc.creds, tc.provID)
+
+			result, provID, err := prov.ValidateManagementAccess(context.TODO(), provisioner.ManagementAccessData{}, false, false)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.expectedError, result.ErrorMessage)
+			assert.Equal(t, tc.expectedDirty, result.Dirty)
+			assert.Equal(t, tc.expectedID, provID)
+		})
+	}
+}
+
+func TestInspectHardware(t *testing.T) {
+	fake := testserver.New(t).Start()
+	defer fake.Stop()
+	prov := newTestProvisioner(t, fake.Address(), defaultCreds(), fake.UUID)
+
+	_, started, details, err := prov.InspectHardware(context.TODO(), provisioner.InspectData{}, false, false, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, started)
+	if details == nil {
+		t.Fatal("no hardware details returned")
+	}
+	assert.Equal(t, "Fake", details.SystemVendor.Manufacturer)
+	assert.Equal(t, 16*1024, details.RAMMebibytes)
+	assert.Equal(t, 2, details.CPU.Count)
+	if !assert.Len(t, details.NIC, 1) {
+		return
+	}
+	assert.Equal(t, "00:11:22:33:44:55", details.NIC[0].MAC)
+	assert.True(t, details.NIC[0].PXE)
+	assert.Equal(t, 10, details.NIC[0].SpeedGbps)
+}
+
+func TestPower(t *testing.T) {
+	fake := testserver.New(t)
+	fake.PowerTransition = true
+	fake.UnsupportedResetTypes = []string{"GracefulShutdown"}
+	fake.Start()
+	defer fake.Stop()
+	prov := newTestProvisioner(t, fake.Address(), defaultCreds(), fake.UUID)
+
+	result, err := prov.PowerOn(context.TODO(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, result.Dirty)
+	powerState, _, _ := fake.State()
+	assert.Equal(t, "PoweringOn", powerState)
+
+	result, err = prov.PowerOn(context.TODO(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, result.Dirty, "should wait for the power transition")
+
+	fake.FinishPowerTransition()
+	result, err = prov.PowerOn(context.TODO(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, result.Dirty)
+
+	hwState, err := prov.UpdateHardwareState(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hwState.PoweredOn == nil {
+		t.Fatal("power state unknown")
+	}
+	assert.True(t, *hwState.PoweredOn)
+
+	// Soft power off is rejected by the BMC, so a hard one is used.
+	result, err = prov.PowerOff(context.TODO(), metal3api.RebootModeSoft, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, result.Dirty)
+	powerState, _, _ = fake.State()
+	assert.Equal(t, "PoweringOff", powerState)
+
+	fake.FinishPowerTransition()
+	result, err = prov.PowerOff(context.TODO(), metal3api.RebootModeSoft, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, result.Dirty)
+}
+
+func TestProvisionLiveISO(t *testing.T) {
+	fake := testserver.New(t).Start()
+	defer fake.Stop()
+	prov := newTestProvisioner(t, fake.Address(), defaultCreds(), fake.UUID)
+
+	liveISO := "live-iso"
+	data := provisioner.ProvisionData{
+		Image: metal3api.Image{
+			URL:        "http://images.test/live.iso",
+			DiskFormat: &liveISO,
+		},
+	}
+
+	for i := 0; ; i++ {
+		if i >= 10 {
+			t.Fatal("provisioning did not complete")
+		}
+		result, err := prov.Provision(context.TODO(), data, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Empty(t, result.ErrorMessage)
+		if !result.Dirty {
+			break
+		}
+	}
+
+	powerState, bootTarget, bootEnabled := fake.State()
+	assert.Equal(t, "On", powerState)
+	assert.Equal(t, "Cd", bootTarget)
+	assert.Equal(t, "Continuous", bootEnabled)
+	media := fake.MediaState("Cd")
+	assert.True(t, media.Inserted)
+	assert.Equal(t, data.Image.URL, media.Image)
+
+	for i := 0; ; i++ {
+		if i >= 10 {
+			t.Fatal("deprovisioning did not complete")
+		}
+		result, _, err := prov.Deprovision(context.TODO(), provisioner.DeprovisionData{}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Empty(t, result.ErrorMessage)
+		if !result.Dirty {
+			break
+		}
+	}
+
+	powerState, _, bootEnabled = fake.State()
+	assert.Equal(t, "Off", powerState)
+	assert.Equal(t, "Disabled", bootEnabled)
+	assert.False(t, fake.MediaState("Cd").Inserted)
+}
+
+func TestProvisionUnsupportedImage(t *testing.T) {
+	fake := testserver.New(t).Start()
+	defer fake.Stop()
+	prov := newTestProvisioner(t, fake.Address(), defaultCreds(), fake.UUID)
+
+	result, err := prov.Provision(context.TODO(), provisioner.ProvisionData{
+		Image: metal3api.Image{URL: "http://images.test/disk.qcow2"},
+	}, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "only live-iso images are supported by the redfish provisioner", result.ErrorMessage)
+}
+
+func TestSetBootDevice(t *testing.T) {
+	fake := testserver.New(t).Start()
+	defer fake.Stop()
+	prov := newTestProvisioner(t, fake.Address(), defaultCreds(), fake.UUID)
+
+	result, err := prov.SetBootDevice(context.TODO(), provisioner.BootDeviceData{Device: metal3api.BootDevicePXE})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, result.ErrorMessage)
+	_, bootTarget, bootEnabled := fake.State()
+	assert.Equal(t, "Pxe", bootTarget)
+	assert.Equal(t, "Once", bootEnabled)
+
+	result, err = prov.SetBootDevice(context.TODO(), provisioner.BootDeviceData{Device: metal3api.BootDeviceDisk, Persistent: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, result.ErrorMessage)
+	_, bootTarget, bootEnabled = fake.State()
+	assert.Equal(t, "Hdd", bootTarget)
+	assert.Equal(t, "Continuous", bootEnabled)
+}
+
+func TestSetBootDeviceInvalidAddress(t *testing.T) {
+	prov := newTestProvisioner(t, "redfish://[fe80::1", defaultCreds(), "")
+
+	_, err := prov.SetBootDevice(context.TODO(), provisioner.BootDeviceData{Device: metal3api.BootDevicePXE})
+	assert.Error(t, err)
+}
+
+func TestDataImage(t *testing.T) {
+	fake := testserver.New(t).Start()
+	defer fake.Stop()
+	prov := newTestProvisioner(t, fake.Address(), defaultCreds(), fake.UUID)
+
+	assert.NoError(t, prov.AttachDataImage(context.TODO(), "http://images.test/data.img"))
+	media := fake.MediaState("Usb")
+	assert.True(t, media.Inserted)
+	assert.Equal(t, "http://images.test/data.img", media.Image)
+	assert.False(t, fake.MediaState("Cd").Inserted)
+
+	// Attaching the same image again is a no-op.
+	assert.NoError(t, prov.AttachDataImage(context.TODO(), "http://images.test/data.img"))
+
+	busy, err := prov.IsDataImageReady(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, busy)
+
+	assert.NoError(t, prov.DetachDataImage(context.TODO()))
+	assert.False(t, fake.MediaState("Usb").Inserted)
+}
+
+func TestDataImageWithoutUSBStick(t *testing.T) {
+	fake := testserver.New(t)
+	delete(fake.Media, "Usb")
+	fake.Media["Cd"].Inserted = true
+	fake.Media["Cd"].Image = "http://images.test/live.iso"
+	fake.Start()
+	defer fake.Stop()
+	prov := newTestProvisioner(t, fake.Address(), defaultCreds(), fake.UUID)
+
+	err := prov.AttachDataImage(context.TODO(), "http://images.test/data.img")
+	assert.ErrorIs(t, err, ErrNoDataImageMedia)
+	media := fake.MediaState("Cd")
+	assert.True(t, media.Inserted)
+	assert.Equal(t, "http://images.test/live.iso", media.Image)
+
+	assert.ErrorIs(t, prov.DetachDataImage(context.TODO()), ErrNoDataImageMedia)
+	assert.True(t, fake.MediaState("Cd").Inserted)
+}
+
+func TestGetFirmwareSettings(t *testing.T) {
+	fake := testserver.New(t).Start()
+	defer fake.Stop()
+	prov := newTestProvisioner(t, fake.Address(), defaultCreds(), fake.UUID)
+
+	settings, schema, err := prov.GetFirmwareSettings(context.TODO(), true)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, schema)
+	assert.Equal(t, metal3api.SettingsMap{
+		"ProcVirtualization": "Enabled",
+		"NumCores":           "8",
+	}, settings)
+}
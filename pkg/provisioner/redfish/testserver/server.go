@@ -0,0 +1,328 @@
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	systemPath       = "/redfish/v1/Systems/1"
+	managerPath      = "/redfish/v1/Managers/1"
+	virtualMediaPath = managerPath + "/VirtualMedia"
+)
+
+// VirtualMedia is the state of a virtual media device of the fake BMC.
+type VirtualMedia struct {
+	MediaTypes []string
+	Image      string
+	Inserted   bool
+}
+
+// RedfishMock is an in-process fake of a Redfish BMC managing a single
+// system. It keeps the power, boot and virtual media state so that tests
+// can drive a full provisioning cycle against it.
+type RedfishMock struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu sync.Mutex
+
+	Username string
+	Password string
+
+	UUID         string
+	Manufacturer string
+	Model        string
+	SerialNumber string
+	PowerState   string
+	// PowerTransition, when set, leaves the system in PoweringOn or
+	// PoweringOff after a reset instead of switching immediately.
+	PowerTransition bool
+
+	BootSourceOverrideTarget  string
+	BootSourceOverrideEnabled string
+
+	// UnsupportedResetTypes are rejected with 400 Bad Request.
+	UnsupportedResetTypes []string
+
+	BiosAttributes map[string]interface{}
+	MACAddresses   []string
+	Media          map[string]*VirtualMedia
+
+	// Requests records "METHOD path" for every request received.
+	Requests []string
+}
+
+// New returns a RedfishMock with a powered off system, a CD and a USB
+// virtual media device and "admin"/"password" credentials.
+func New(t *testing.T) *RedfishMock {
+	t.Helper()
+	return &RedfishMock{
+		t:            t,
+		Username:     "admin",
+		Password:     "password",
+		UUID:         "5fa0fbbb-b2b1-4eb8-a4e7-3c5e4df2fa3c",
+		Manufacturer: "Fake",
+		Model:        "Fake Server",
+		SerialNumber: "FK-0001",
+		PowerState:   "Off",
+		BiosAttributes: map[string]interface{}{
+			"ProcVirtualization": "Enabled",
+			"NumCores":           8,
+		},
+		MACAddresses: []string{"00:11:22:33:44:55"},
+		Media: map[string]*VirtualMedia{
+			"Cd":  {MediaTypes: []string{"CD", "DVD"}},
+			"Usb": {MediaTypes: []string{"USBStick"}},
+		},
+	}
+}
+
+// Start runs the server.
+func (m *RedfishMock) Start() *RedfishMock {
+	m.server = httptest.NewServer(http.HandlerFunc(m.handle))
+	return m
+}
+
+// Stop closes the server down.
+func (m *RedfishMock) Stop() {
+	m.server.Close()
+}
+
+// Address returns a BMC address pointing at the system of the fake.
+func (m *RedfishMock) Address() string {
+	return "redfish+" + m.server.URL + systemPath
+}
+
+// State returns a copy of the current power state, under lock.
+func (m *RedfishMock) State() (powerState, bootTarget, bootEnabled string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.PowerState, m.BootSourceOverrideTarget, m.BootSourceOverrideEnabled
+}
+
+// MediaState returns a copy of the named virtual media device, under lock.
+func (m *RedfishMock) MediaState(name string) VirtualMedia {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return *m.Media[name]
+}
+
+// FinishPowerTransition completes a pending PoweringOn or PoweringOff.
+func (m *RedfishMock) FinishPowerTransition() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.PowerState = strings.TrimPrefix(m.PowerState, "Powering")
+}
+
+func (m *RedfishMock) handle(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Requests = append(m.Requests, r.Method+" "+r.URL.Path)
+	m.t.Logf("redfish: %s %s", r.Method, r.URL.Path)
+
+	username, password, ok := r.BasicAuth()
+	if !ok || username != m.Username || password != m.Password {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case path == "/redfish/v1/Systems" && r.Method == http.MethodGet:
+		m.sendJSON(w, collection(systemPath))
+	case path == systemPath && r.Method == http.MethodGet:
+		m.sendJSON(w, m.system())
+	case path == systemPath && r.Method == http.MethodPatch:
+		m.patchSystem(w, r)
+	case path == systemPath+"/Actions/ComputerSystem.Reset" && r.Method == http.MethodPost:
+		m.reset(w, r)
+	case path == systemPath+"/Bios" && r.Method == http.MethodGet:
+		m.sendJSON(w, map[string]interface{}{"Attributes": m.BiosAttributes})
+	case path == systemPath+"/EthernetInterfaces" && r.Method == http.MethodGet:
+		members := []string{}
+		for i := range m.MACAddresses {
+			members = append(members, fmt.Sprintf("%s/EthernetInterfaces/%d", systemPath, i))
+		}
+		m.sendJSON(w, collection(members...))
+	case strings.HasPrefix(path, systemPath+"/EthernetInterfaces/") && r.Method == http.MethodGet:
+		m.ethernetInterface(w, strings.TrimPrefix(path, systemPath+"/EthernetInterfaces/"))
+	case path == managerPath && r.Method == http.MethodGet:
+		m.sendJSON(w, map[string]interface{}{
+			"Id":           "1",
+			"VirtualMedia": map[string]string{"@odata.id": virtualMediaPath},
+		})
+	case path == virtualMediaPath && r.Method == http.MethodGet:
+		members := []string{}
+		for _, name := range []string{"Cd", "Usb"} {
+			if _, ok := m.Media[name]; ok {
+				members = append(members, virtualMediaPath+"/"+name)
+			}
+		}
+		m.sendJSON(w, collection(members...))
+	case strings.HasPrefix(path, virtualMediaPath+"/"):
+		m.virtualMedia(w, r, strings.TrimPrefix(path, virtualMediaPath+"/"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func collection(members ...string) map[string]interface{} {
+	refs := []map[string]string{}
+	for _, member := range members {
+		refs = append(refs, map[string]string{"@odata.id": member})
+	}
+	return map[string]interface{}{"Members": refs}
+}
+
+func (m *RedfishMock) sendJSON(w http.ResponseWriter, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		m.t.Error(err)
+	}
+}
+
+func (m *RedfishMock) decode(w http.ResponseWriter, r *http.Request, body interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func (m *RedfishMock) system() map[string]interface{} {
+	return map[string]interface{}{
+		"@odata.id":    systemPath,
+		"Id":           "1",
+		"UUID":         m.UUID,
+		"Manufacturer": m.Manufacturer,
+		"Model":        m.Model,
+		"SerialNumber": m.SerialNumber,
+		"HostName":     "fake-host",
+		"PowerState":   m.PowerState,
+		"BiosVersion":  "1.2.3",
+		"ProcessorSummary": map[string]interface{}{
+			"Count": 2,
+			"Model": "Fake CPU",
+		},
+		"MemorySummary": map[string]interface{}{
+			"TotalSystemMemoryGiB": 16,
+		},
+		"Boot": map[string]string{
+			"BootSourceOverrideTarget":  m.BootSourceOverrideTarget,
+			"BootSourceOverrideEnabled": m.BootSourceOverrideEnabled,
+		},
+		"Bios":               map[string]string{"@odata.id": systemPath + "/Bios"},
+		"EthernetInterfaces": map[string]string{"@odata.id": systemPath + "/EthernetInterfaces"},
+		"Links": map[string]interface{}{
+			"ManagedBy": []map[string]string{{"@odata.id": managerPath}},
+		},
+	}
+}
+
+func (m *RedfishMock) patchSystem(w http.ResponseWriter, r *http.Request) {
+	body := struct {
+		Boot struct {
+			BootSourceOverrideTarget  string
+			BootSourceOverrideEnabled string
+		}
+	}{}
+	if !m.decode(w, r, &body) {
+		return
+	}
+	if body.Boot.BootSourceOverrideTarget != "" {
+		m.BootSourceOverrideTarget = body.Boot.BootSourceOverrideTarget
+	}
+	if body.Boot.BootSourceOverrideEnabled != "" {
+		m.BootSourceOverrideEnabled = body.Boot.BootSourceOverrideEnabled
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *RedfishMock) reset(w http.ResponseWriter, r *http.Request) {
+	body := struct{ ResetType string }{}
+	if !m.decode(w, r, &body) {
+		return
+	}
+	for _, unsupported := range m.UnsupportedResetTypes {
+		if body.ResetType == unsupported {
+			http.Error(w, "unsupported reset type", http.StatusBadRequest)
+			return
+		}
+	}
+
+	target := ""
+	switch body.ResetType {
+	case "On", "ForceOn", "ForceRestart", "GracefulRestart":
+		target = "On"
+	case "ForceOff", "GracefulShutdown":
+		target = "Off"
+	default:
+		http.Error(w, "unknown reset type", http.StatusBadRequest)
+		return
+	}
+
+	if m.PowerTransition && m.PowerState != target {
+		m.PowerState = "Powering" + target
+	} else {
+		m.PowerState = target
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *RedfishMock) ethernetInterface(w http.ResponseWriter, index string) {
+	for i, mac := range m.MACAddresses {
+		if fmt.Sprint(i) == index {
+			m.sendJSON(w, map[string]interface{}{
+				"Id":         fmt.Sprintf("eth%d", i),
+				"MACAddress": mac,
+				"SpeedMbps":  10000,
+			})
+			return
+		}
+	}
+	http.Error(w, "not found", http.StatusNotFound)
+}
+
+func (m *RedfishMock) virtualMedia(w http.ResponseWriter, r *http.Request, path string) {
+	name, action, _ := strings.Cut(path, "/Actions/")
+	media, ok := m.Media[name]
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		m.sendJSON(w, map[string]interface{}{
+			"Id":         name,
+			"Image":      media.Image,
+			"Inserted":   media.Inserted,
+			"MediaTypes": media.MediaTypes,
+		})
+	case action == "VirtualMedia.InsertMedia" && r.Method == http.MethodPost:
+		body := struct{ Image string }{}
+		if !m.decode(w, r, &body) {
+			return
+		}
+		if media.Inserted {
+			http.Error(w, "media already inserted", http.StatusConflict)
+			return
+		}
+		media.Image = body.Image
+		media.Inserted = true
+		w.WriteHeader(http.StatusNoContent)
+	case action == "VirtualMedia.EjectMedia" && r.Method == http.MethodPost:
+		media.Image = ""
+		media.Inserted = false
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
@@ -0,0 +1,113 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// UnknownBackendError is returned when a host requests a provisioning
+// backend that is not registered.
+type UnknownBackendError struct {
+	Name string
+}
+
+func (e UnknownBackendError) Error() string {
+	return fmt.Sprintf("unknown provisioning backend %q", e.Name)
+}
+
+// Registry maps provisioning backend names to the Factory creating
+// Provisioners for them. It is itself a Factory, dispatching each host
+// to the backend named in its HostData, or to the default backend.
+type Registry struct {
+	factories   map[string]Factory
+	defaultName string
+}
+
+// NewRegistry returns a Registry using the named backend for hosts that
+// do not request one. The default backend must be registered before the
+// Registry is used.
+func NewRegistry(defaultName string) *Registry {
+	return &Registry{
+		factories:   map[string]Factory{},
+		defaultName: defaultName,
+	}
+}
+
+// Register maps a backend name to a Factory, replacing any Factory
+// previously registered with that name.
+func (r *Registry) Register(name string, factory Factory) *Registry {
+	r.factories[name] = factory
+	return r
+}
+
+// Default returns the name of the default backend.
+func (r *Registry) Default() string {
+	return r.defaultName
+}
+
+// Names returns the sorted names of the registered backends.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FactoryFor returns the Factory for the named backend, or for the
+// default backend if the name is empty.
+func (r *Registry) FactoryFor(name string) (Factory, error) {
+	if name == "" {
+		name = r.defaultName
+	}
+	factory, ok := r.factories[name]
+	if !ok {
+		return nil, UnknownBackendError{Name: name}
+	}
+	return factory, nil
+}
+
+// NewProvisioner returns a Provisioner from the backend requested for
+// the host.
+func (r *Registry) NewProvisioner(ctx context.Context, hostData HostData, publish EventPublisher) (Provisioner, error) {
+	factory, err := r.FactoryFor(hostData.Backend)
+	if err != nil {
+		return nil, err
+	}
+	return factory.NewProvisioner(ctx, hostData, publish)
+}
+
+// PowerStates returns the power states read from all the backends able
+// to poll them.
+func (r *Registry) PowerStates(ctx context.Context) (map[string]bool, error) {
+	states := map[string]bool{}
+	var errs []error
+	for _, name := range r.Names() {
+		poller, ok := r.factories[name].(PowerStatePoller)
+		if !ok {
+			continue
+		}
+		backendStates, err := poller.PowerStates(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read power states from %s: %w", name, err))
+		}
+		for id, poweredOn := range backendStates {
+			states[id] = poweredOn
+		}
+	}
+	return states, errors.Join(errs...)
+}
+
+// PollsPowerState returns whether the backend requested for the host
+// is able to poll its power state.
+func (r *Registry) PollsPowerState(hostData HostData) bool {
+	factory, err := r.FactoryFor(hostData.Backend)
+	if err != nil {
+		return false
+	}
+	poller, ok := factory.(PowerStatePoller)
+	return ok && poller.PollsPowerState(hostData)
+}
@@ -0,0 +1,64 @@
+package provisioner_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/demo"
+	"github.com/metal3-io/baremetal-operator/pkg/provisioner/fixture"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRegistry(t *testing.T) {
+	fix := &fixture.Fixture{}
+	registry := provisioner.NewRegistry("fixture").
+		Register("fixture", fix).
+		Register("demo", demo.Demo{})
+
+	assert.Equal(t, []string{"demo", "fixture"}, registry.Names())
+
+	cases := []struct {
+		name          string
+		backend       string
+		expected      provisioner.Factory
+		expectedError string
+	}{
+		{
+			name:     "default",
+			expected: fix,
+		},
+		{
+			name:     "explicit",
+			backend:  "demo",
+			expected: demo.Demo{},
+		},
+		{
+			name:          "unknown",
+			backend:       "ironic",
+			expectedError: `unknown provisioning backend "ironic"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			factory, err := registry.FactoryFor(tc.backend)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.True(t, errors.As(err, &provisioner.UnknownBackendError{}))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, factory)
+
+			prov, err := registry.NewProvisioner(context.TODO(), provisioner.HostData{
+				ObjectMeta: metav1.ObjectMeta{Name: "host", Namespace: "ns"},
+				Backend:    tc.backend,
+			}, func(_, _ string) {})
+			assert.NoError(t, err)
+			assert.NotNil(t, prov)
+		})
+	}
+}